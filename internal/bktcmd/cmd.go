@@ -9,6 +9,7 @@ import (
 	"syscall"
 
 	"github.com/avivsinai/bitbucket-cli/internal/build"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/extension"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmd/factory"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmd/root"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
@@ -36,6 +37,7 @@ func Main() int {
 		_, _ = fmt.Fprintf(ios.ErrOut, "failed to create root command: %v\n", err)
 		return 1
 	}
+	extension.RegisterInstalled(rootCmd, f)
 	rootCmd.SetContext(ctx)
 
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
@@ -47,6 +47,12 @@ type Host struct {
 	Token              string `yaml:"token,omitempty"`
 	AuthMethod         string `yaml:"auth_method,omitempty"` // "basic" (default) or "bearer"
 	AllowInsecureStore bool   `yaml:"allow_insecure_store,omitempty"`
+	// TokenCommand is a shell command that prints the token on stdout. When
+	// set it replaces the keyring as the token source for this host.
+	TokenCommand string `yaml:"token_command,omitempty"`
+	// TokenID is the ID of the Data Center personal access token stored for
+	// this host, recorded by "auth login --token-id" and "--rotate".
+	TokenID string `yaml:"token_id,omitempty"`
 
 	// OAuthExpiresAt is runtime-only metadata loaded from an OAuth token blob.
 	OAuthExpiresAt time.Time `yaml:"-"`
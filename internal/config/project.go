@@ -0,0 +1,95 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFileName is the per-directory configuration file teams commit at a
+// repository root to share defaults.
+const ProjectFileName = ".bkt.yaml"
+
+// ProjectConfig models a committed .bkt.yaml. Values here sit below command
+// flags and environment variables but above the user's global config and git
+// remote detection.
+type ProjectConfig struct {
+	// Context names the bkt context to use in this directory tree.
+	Context string `yaml:"context,omitempty"`
+	// Host selects a configured host key when no context is named.
+	Host      string `yaml:"host,omitempty"`
+	Project   string `yaml:"project,omitempty"`
+	Workspace string `yaml:"workspace,omitempty"`
+	Repo      string `yaml:"repo,omitempty"`
+
+	PR       ProjectPRDefaults       `yaml:"pr,omitempty"`
+	Pipeline ProjectPipelineDefaults `yaml:"pipeline,omitempty"`
+
+	path string
+}
+
+// ProjectPRDefaults configures pull request commands.
+type ProjectPRDefaults struct {
+	TargetBranch  string   `yaml:"target_branch,omitempty"`
+	Reviewers     []string `yaml:"reviewers,omitempty"`
+	MergeStrategy string   `yaml:"merge_strategy,omitempty"`
+	// TitleTemplate is a Go template rendered with .Source, .Target, and
+	// .Subject (the first unique commit subject).
+	TitleTemplate string `yaml:"title_template,omitempty"`
+}
+
+// ProjectPipelineDefaults configures pipeline commands.
+type ProjectPipelineDefaults struct {
+	Ref       string            `yaml:"ref,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+}
+
+// Path returns the file the project config was read from.
+func (p *ProjectConfig) Path() string {
+	if p == nil {
+		return ""
+	}
+	return p.path
+}
+
+// FindProjectConfig walks up from dir looking for .bkt.yaml and decodes the
+// first one found. It returns nil without error when no file exists. Unknown
+// keys are rejected so typos surface instead of being silently ignored.
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve project config dir: %w", err)
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			return decodeProjectConfig(path, data)
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func decodeProjectConfig(path string, data []byte) (*ProjectConfig, error) {
+	pc := &ProjectConfig{path: path}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(pc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return pc, nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindProjectConfigWalksUp(t *testing.T) {
+	root := t.TempDir()
+	body := `context: work
+repo: api
+pr:
+  target_branch: develop
+  reviewers: [alice, bob]
+  title_template: "{{.Source}}: {{.Subject}}"
+pipeline:
+  ref: release
+  variables:
+    ENV: staging
+`
+	if err := os.WriteFile(filepath.Join(root, ProjectFileName), []byte(body), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	pc, err := FindProjectConfig(nested)
+	if err != nil {
+		t.Fatalf("FindProjectConfig: %v", err)
+	}
+	if pc == nil {
+		t.Fatal("expected project config")
+	}
+	if pc.Path() != filepath.Join(root, ProjectFileName) {
+		t.Fatalf("path = %q", pc.Path())
+	}
+	if pc.Context != "work" || pc.Repo != "api" {
+		t.Fatalf("unexpected identity fields: %+v", pc)
+	}
+	if pc.PR.TargetBranch != "develop" || len(pc.PR.Reviewers) != 2 {
+		t.Fatalf("unexpected pr defaults: %+v", pc.PR)
+	}
+	if pc.Pipeline.Ref != "release" || pc.Pipeline.Variables["ENV"] != "staging" {
+		t.Fatalf("unexpected pipeline defaults: %+v", pc.Pipeline)
+	}
+}
+
+func TestFindProjectConfigMissing(t *testing.T) {
+	pc, err := FindProjectConfig(t.TempDir())
+	if err != nil {
+		t.Fatalf("FindProjectConfig: %v", err)
+	}
+	if pc != nil {
+		// A .bkt.yaml above the temp dir would be unusual but not an error.
+		if !strings.HasSuffix(pc.Path(), ProjectFileName) {
+			t.Fatalf("unexpected config: %+v", pc)
+		}
+	}
+}
+
+func TestFindProjectConfigRejectsUnknownKeys(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ProjectFileName), []byte("projet: TYPO\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, err := FindProjectConfig(root)
+	if err == nil || !strings.Contains(err.Error(), "projet") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestFindProjectConfigEmptyFile(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ProjectFileName), nil, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	pc, err := FindProjectConfig(root)
+	if err != nil {
+		t.Fatalf("FindProjectConfig: %v", err)
+	}
+	if pc == nil || pc.Context != "" {
+		t.Fatalf("unexpected config: %+v", pc)
+	}
+}
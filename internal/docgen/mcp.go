@@ -33,8 +33,9 @@ func appendMCPRegistry(path string) error {
 func writeMCPRegistry(file io.Writer, inventory mcpserver.Inventory) error {
 	fmt.Fprintln(file, "## MCP tool registry")
 	fmt.Fprintln(file)
-	fmt.Fprintln(file, "The read-only v1 server registers eight Bitbucket read tools plus `bkt_get_context`.")
+	fmt.Fprintln(file, "`bkt mcp serve` registers the read tools below by default. Write tools are served only when their gate is passed to `--allow-writes`, and every write call is recorded in a local audit log.")
 	fmt.Fprintln(file, "Every tool below is available on Data Center and Cloud unless a capability note says otherwise.")
+	fmt.Fprintln(file, "A server started with several `--context` flags or `--all-contexts` routes each call by its optional `context` argument; calls without it use the default context.")
 	fmt.Fprintln(file)
 
 	fmt.Fprintln(file, "### Platform capabilities")
@@ -80,6 +81,9 @@ func writeMCPRegistry(file io.Writer, inventory mcpserver.Inventory) error {
 		fmt.Fprintln(file)
 		fmt.Fprintf(file, "- Availability: %s\n", formatPlatforms(tool.Platforms))
 		fmt.Fprintf(file, "- Read-only: %t\n", tool.ReadOnly)
+		if tool.WriteGate != "" {
+			fmt.Fprintf(file, "- Write gate: `%s` (destructive: %t, idempotent: %t)\n", tool.WriteGate, tool.Destructive, tool.Idempotent)
+		}
 		fmt.Fprintf(file, "- Structured errors: %s\n", formatErrorCodes(tool.Errors))
 		for _, note := range tool.Notes {
 			fmt.Fprintf(file, "- Note: %s\n", note)
@@ -92,6 +96,33 @@ func writeMCPRegistry(file io.Writer, inventory mcpserver.Inventory) error {
 			return fmt.Errorf("render output schema for %s: %w", tool.Name, err)
 		}
 	}
+
+	fmt.Fprintln(file, "### Resources")
+	fmt.Fprintln(file)
+	fmt.Fprintln(file, "Resource templates let a client attach Bitbucket context directly. Reads use the same frozen contexts, bounds, and untrusted-content rules as the tools; the optional `context` query selects a served context.")
+	fmt.Fprintln(file)
+	fmt.Fprintln(file, "| URI template | Name | Description |")
+	fmt.Fprintln(file, "|---|---|---|")
+	for _, resource := range inventory.Resources {
+		fmt.Fprintf(file, "| `%s` | `%s` | %s |\n", resource.URITemplate, resource.Name, resource.Description)
+	}
+	fmt.Fprintln(file)
+
+	fmt.Fprintln(file, "### Prompts")
+	fmt.Fprintln(file)
+	for _, prompt := range inventory.Prompts {
+		fmt.Fprintf(file, "#### `%s`\n\n", prompt.Name)
+		fmt.Fprintln(file, prompt.Description)
+		fmt.Fprintln(file)
+		for _, argument := range prompt.Arguments {
+			required := "optional"
+			if argument.Required {
+				required = "required"
+			}
+			fmt.Fprintf(file, "- `%s` (%s): %s\n", argument.Name, required, argument.Description)
+		}
+		fmt.Fprintln(file)
+	}
 	return nil
 }
 
@@ -29,7 +29,9 @@ func TestGenerateAllAddsSelfContainedMCPRegistryReference(t *testing.T) {
 	got := string(content)
 	for _, want := range []string{
 		"## MCP tool registry",
-		"eight Bitbucket read tools plus `bkt_get_context`",
+		"registers the read tools below by default",
+		"Write gate: `comment` (destructive: false, idempotent: false)",
+		"Write gate: `approve` (destructive: false, idempotent: true)",
 		"`bkt_get_context`",
 		"`bkt_get_pull_request_checks`",
 		"`bkt_list_repositories`",
@@ -40,9 +42,13 @@ func TestGenerateAllAddsSelfContainedMCPRegistryReference(t *testing.T) {
 		"Input schema",
 		"Output schema",
 		"role",
-		"restart the MCP server",
+		"persisted to the bkt credential store",
 		"bearer-only",
 		"Content-Length",
+		"### Resources",
+		"`bkt://pr/{scope}/{slug}/{id}{?context}`",
+		"`review_pull_request`",
+		"`id` (required)",
 	} {
 		if !strings.Contains(got, want) {
 			t.Errorf("generated mcp.md missing %q", want)
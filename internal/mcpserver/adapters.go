@@ -222,6 +222,87 @@ func adaptDiff(content, sourceCommit, targetCommit string) Diff {
 	}
 }
 
+func adaptCloudPipeline(raw bbcloud.Pipeline) (Pipeline, error) {
+	createdAt, err := formatOptionalCloudTimestamp("created_on", raw.CreatedOn)
+	if err != nil {
+		return Pipeline{}, err
+	}
+	completedAt, err := formatOptionalCloudTimestamp("completed_on", raw.CompletedOn)
+	if err != nil {
+		return Pipeline{}, err
+	}
+	return Pipeline{
+		UUID:        raw.UUID,
+		BuildNumber: raw.BuildNumber,
+		State:       stateFromMap(raw.State.Name+" "+raw.State.Result.Name, cloudCheckStates),
+		Ref:         raw.Target.Ref.Name,
+		CreatedAt:   createdAt,
+		CompletedAt: completedAt,
+	}, nil
+}
+
+func adaptCloudPipelineStep(raw bbcloud.PipelineStep) PipelineStep {
+	return PipelineStep{
+		UUID:  raw.UUID,
+		Name:  raw.Name,
+		State: stateFromMap(raw.Status(), cloudCheckStates),
+	}
+}
+
+func adaptDCBranch(raw bbdc.Branch) Branch {
+	return Branch{
+		Name:         firstNonEmpty(raw.DisplayID, strings.TrimPrefix(raw.ID, "refs/heads/")),
+		LatestCommit: raw.LatestCommit,
+		IsDefault:    raw.IsDefault,
+	}
+}
+
+func adaptCloudBranch(raw bbcloud.Branch) Branch {
+	return Branch{
+		Name:         raw.Name,
+		LatestCommit: raw.Target.Hash,
+		IsDefault:    raw.IsDefault,
+	}
+}
+
+func adaptCloudIssue(raw bbcloud.Issue, full bool) (Issue, error) {
+	createdAt, err := formatOptionalCloudTimestamp("created_on", raw.CreatedOn)
+	if err != nil {
+		return Issue{}, err
+	}
+	updatedAt, err := formatOptionalCloudTimestamp("updated_on", raw.UpdatedOn)
+	if err != nil {
+		return Issue{}, err
+	}
+	result := Issue{
+		ID:        raw.ID,
+		Title:     raw.Title,
+		State:     raw.State,
+		Kind:      raw.Kind,
+		Priority:  raw.Priority,
+		Reporter:  adaptCloudAccount(raw.Reporter),
+		Assignee:  adaptCloudAccount(raw.Assignee),
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		URL:       stripURLQuery(raw.Links.HTML.Href),
+	}
+	if full {
+		content := boundBitbucketText(raw.Content.Raw, IssueContentLimit)
+		result.Content = &content
+	}
+	return result, nil
+}
+
+func adaptCloudAccount(account *bbcloud.Account) *User {
+	if account == nil {
+		return nil
+	}
+	return &User{
+		Name:        firstNonEmpty(account.Nickname, account.AccountID, account.UUID),
+		DisplayName: account.DisplayName,
+	}
+}
+
 func adaptDCReviewers(raw []bbdc.PullRequestReviewer) ([]Reviewer, error) {
 	reviewers := make([]Reviewer, 0, len(raw))
 	for _, reviewer := range raw {
@@ -334,6 +415,13 @@ func formatCloudTimestamp(field, value string) (string, error) {
 	return parsed.UTC().Format(time.RFC3339Nano), nil
 }
 
+func formatOptionalCloudTimestamp(field, value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	return formatCloudTimestamp(field, value)
+}
+
 func stateFromMap(raw string, states map[string]CheckState) CheckState {
 	normalized := strings.Join(strings.Fields(strings.ToUpper(raw)), " ")
 	if state, ok := states[normalized]; ok {
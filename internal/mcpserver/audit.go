@@ -0,0 +1,78 @@
+package mcpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// auditLog appends one JSON line per write tool event. Writes are serialized
+// so concurrent tool calls never interleave records.
+type auditLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// auditRecord is the on-disk audit line. Event is "attempt" before the
+// upstream call and "ok" or "error" after it.
+type auditRecord struct {
+	Time      string          `json:"time"`
+	Event     string          `json:"event"`
+	Tool      string          `json:"tool"`
+	Gate      WriteGate       `json:"gate"`
+	Context   string          `json:"context,omitempty"`
+	Platform  string          `json:"platform"`
+	HostLabel string          `json:"host_label"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	ErrorCode ErrorCode       `json:"error_code,omitempty"`
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	return &auditLog{w: w, now: time.Now}
+}
+
+// record appends one event for a call routed to snap.
+func (a *auditLog) record(snap *Snapshot, tool string, gate WriteGate, event string, args any, callErr error) error {
+	rec := auditRecord{
+		Time:  a.now().UTC().Format(time.RFC3339Nano),
+		Event: event,
+		Tool:  tool,
+		Gate:  gate,
+	}
+	if snap != nil {
+		rec.Context = snap.ContextName
+		rec.Platform = snap.Platform
+		rec.HostLabel = snap.HostLabel
+	}
+	if args != nil {
+		raw, err := json.Marshal(args)
+		if err != nil {
+			return fmt.Errorf("encode audit arguments: %w", err)
+		}
+		rec.Arguments = raw
+	}
+	if callErr != nil {
+		rec.ErrorCode = ErrorUpstream
+		var toolErr *structuredToolError
+		if errors.As(callErr, &toolErr) {
+			rec.ErrorCode = toolErr.payload.Code
+		}
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.w.Write(line); err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+	return nil
+}
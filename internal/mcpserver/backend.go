@@ -2,7 +2,9 @@ package mcpserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
@@ -33,9 +35,76 @@ type pullRequestReadBackend interface {
 	getPullRequestChecks(context.Context, RepositoryRef, int) ([]Check, bool, error)
 }
 
+// pipelineReadBackend, branchReadBackend, commitReadBackend, and
+// issueReadBackend back the repository content tools. Pipelines and issues
+// exist only on Cloud; the Data Center backend reports them unsupported.
+type pipelineReadBackend interface {
+	listPipelines(context.Context, RepositoryRef, int) ([]Pipeline, bool, error)
+	getPipelineStepLog(context.Context, RepositoryRef, string, string) (PipelineStepLog, error)
+}
+
+type branchReadBackend interface {
+	listBranches(context.Context, RepositoryRef, string, int) ([]Branch, bool, error)
+}
+
+type commitReadBackend interface {
+	getCommitDiff(context.Context, RepositoryRef, string, string) (Diff, error)
+}
+
+type issueReadBackend interface {
+	listIssues(context.Context, RepositoryRef, string, int) ([]Issue, bool, error)
+	getIssue(context.Context, RepositoryRef, int) (Issue, error)
+}
+
+// fileReadBackend backs the file resource template.
+type fileReadBackend interface {
+	getFileContent(context.Context, RepositoryRef, string, string) (BoundedText, error)
+}
+
+type repositoryContentBackend interface {
+	pipelineReadBackend
+	branchReadBackend
+	commitReadBackend
+	issueReadBackend
+	fileReadBackend
+}
+
 type fullPlatformBackend interface {
 	platformBackend
 	pullRequestReadBackend
+	repositoryContentBackend
+}
+
+// pullRequestWriteBackend is the normalized seam for opt-in write tools.
+type pullRequestWriteBackend interface {
+	addPullRequestComment(context.Context, RepositoryRef, int, commentInput) error
+	approvePullRequest(context.Context, RepositoryRef, int) error
+	createPullRequest(context.Context, RepositoryRef, pullRequestInput) (PullRequest, error)
+	setPullRequestThreadResolved(context.Context, RepositoryRef, int, int, bool) error
+}
+
+type writablePlatformBackend interface {
+	fullPlatformBackend
+	pullRequestWriteBackend
+}
+
+// commentInput is a validated comment request. Line anchors the comment to
+// the new side of the diff and requires Path.
+type commentInput struct {
+	Body     string
+	ParentID int
+	Path     string
+	Line     int
+}
+
+// pullRequestInput is a validated pull request creation request.
+type pullRequestInput struct {
+	Title        string
+	Description  string
+	SourceBranch string
+	TargetBranch string
+	Reviewers    []string
+	Draft        bool
 }
 
 type dcBackend struct {
@@ -48,24 +117,26 @@ type cloudBackend struct {
 }
 
 var (
-	_ pullRequestReadBackend = (*dcBackend)(nil)
-	_ pullRequestReadBackend = (*cloudBackend)(nil)
+	_ writablePlatformBackend = (*dcBackend)(nil)
+	_ writablePlatformBackend = (*cloudBackend)(nil)
 )
 
-func newPlatformBackend(snap *Snapshot) (fullPlatformBackend, error) {
+func newPlatformBackend(snap *Snapshot) (writablePlatformBackend, error) {
 	if snap == nil {
 		return nil, fmt.Errorf("MCP snapshot is required")
 	}
-	host := snap.Host
 	switch snap.Platform {
 	case "dc":
+		host := snap.Host
 		client, err := cmdutil.NewDCClient(&host)
 		if err != nil {
 			return nil, fmt.Errorf("create Bitbucket Data Center client: %w", err)
 		}
 		return &dcBackend{client: client, username: host.Username}, nil
 	case "cloud":
-		client, err := cmdutil.NewFrozenCloudClient(&host)
+		// The client's OAuth refresher persists a refreshed token to the
+		// secret store and writes it back into snap.Host.
+		client, err := cmdutil.NewCloudClient(&snap.Host)
 		if err != nil {
 			return nil, fmt.Errorf("create Bitbucket Cloud client: %w", err)
 		}
@@ -263,6 +334,282 @@ func (b *cloudBackend) getPullRequestChecks(ctx context.Context, locator Reposit
 	return items, page.Next != "", nil
 }
 
+func (b *dcBackend) addPullRequestComment(ctx context.Context, locator RepositoryRef, id int, input commentInput) error {
+	return b.client.CommentPullRequest(ctx, locator.Scope, locator.Slug, id, bbdc.CommentOptions{
+		Text:     input.Body,
+		ParentID: input.ParentID,
+		File:     input.Path,
+		ToLine:   input.Line,
+	})
+}
+
+func (b *cloudBackend) addPullRequestComment(ctx context.Context, locator RepositoryRef, id int, input commentInput) error {
+	return b.client.CommentPullRequest(ctx, locator.Scope, locator.Slug, id, bbcloud.CommentOptions{
+		Text:     input.Body,
+		ParentID: input.ParentID,
+		File:     input.Path,
+		ToLine:   input.Line,
+	})
+}
+
+func (b *dcBackend) approvePullRequest(ctx context.Context, locator RepositoryRef, id int) error {
+	return b.client.ApprovePullRequest(ctx, locator.Scope, locator.Slug, id)
+}
+
+func (b *cloudBackend) approvePullRequest(ctx context.Context, locator RepositoryRef, id int) error {
+	return b.client.ApprovePullRequest(ctx, locator.Scope, locator.Slug, id)
+}
+
+func (b *dcBackend) createPullRequest(ctx context.Context, locator RepositoryRef, input pullRequestInput) (PullRequest, error) {
+	raw, err := b.client.CreatePullRequest(ctx, locator.Scope, locator.Slug, bbdc.CreatePROptions{
+		Title:        input.Title,
+		Description:  input.Description,
+		SourceBranch: input.SourceBranch,
+		TargetBranch: input.TargetBranch,
+		Reviewers:    input.Reviewers,
+		Draft:        input.Draft,
+	})
+	if err != nil {
+		return PullRequest{}, err
+	}
+	return adaptDCPullRequest(*raw, true)
+}
+
+func (b *cloudBackend) createPullRequest(ctx context.Context, locator RepositoryRef, input pullRequestInput) (PullRequest, error) {
+	raw, err := b.client.CreatePullRequest(ctx, locator.Scope, locator.Slug, bbcloud.CreatePullRequestInput{
+		Title:       input.Title,
+		Description: input.Description,
+		Source:      input.SourceBranch,
+		Destination: input.TargetBranch,
+		Reviewers:   input.Reviewers,
+		Draft:       input.Draft,
+	})
+	if err != nil {
+		return PullRequest{}, err
+	}
+	return adaptCloudPullRequest(*raw, true)
+}
+
+func (b *dcBackend) setPullRequestThreadResolved(ctx context.Context, locator RepositoryRef, id, commentID int, resolved bool) error {
+	_, err := b.client.SetPullRequestCommentThreadResolved(ctx, locator.Scope, locator.Slug, id, commentID, resolved)
+	if errors.Is(err, bbdc.ErrPullRequestCommentNotTopLevel) {
+		return newToolError(ErrorInvalidInput, "only top-level comments start a thread; pass the thread's root comment id", false)
+	}
+	return err
+}
+
+func (b *cloudBackend) setPullRequestThreadResolved(ctx context.Context, locator RepositoryRef, id, commentID int, resolved bool) error {
+	// Cloud rejects resolving a resolved thread, so check first to keep the
+	// tool idempotent as advertised.
+	current, err := b.client.GetPullRequestComment(ctx, locator.Scope, locator.Slug, id, commentID)
+	if err != nil {
+		return err
+	}
+	if (current.Resolution != nil) == resolved {
+		return nil
+	}
+	_, err = b.client.SetPullRequestCommentThreadResolved(ctx, locator.Scope, locator.Slug, id, commentID, resolved)
+	return err
+}
+
+var (
+	errPipelinesUnsupported = newToolError(ErrorUnsupportedOnPlatform, "pipelines are not available on Bitbucket Data Center", false)
+	errIssuesUnsupported    = newToolError(ErrorUnsupportedOnPlatform, "issues are not available on Bitbucket Data Center", false)
+)
+
+func (b *dcBackend) listPipelines(context.Context, RepositoryRef, int) ([]Pipeline, bool, error) {
+	return nil, false, errPipelinesUnsupported
+}
+
+func (b *dcBackend) getPipelineStepLog(context.Context, RepositoryRef, string, string) (PipelineStepLog, error) {
+	return PipelineStepLog{}, errPipelinesUnsupported
+}
+
+func (b *cloudBackend) listPipelines(ctx context.Context, locator RepositoryRef, limit int) ([]Pipeline, bool, error) {
+	limit = normalizedListLimit(limit)
+	raw, err := b.client.ListPipelines(ctx, locator.Scope, locator.Slug, limit+1)
+	if err != nil {
+		return nil, false, err
+	}
+	items := make([]Pipeline, 0, len(raw))
+	for _, pipeline := range raw {
+		item, err := adaptCloudPipeline(pipeline)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, item)
+	}
+	return items, len(items) > limit, nil
+}
+
+// getPipelineStepLog resolves pipelineRef as a UUID or build number. An empty
+// stepRef selects the first failed step, falling back to the last step.
+func (b *cloudBackend) getPipelineStepLog(ctx context.Context, locator RepositoryRef, pipelineRef, stepRef string) (PipelineStepLog, error) {
+	var (
+		raw *bbcloud.Pipeline
+		err error
+	)
+	if bbcloud.LooksLikeUUID(pipelineRef) {
+		raw, err = b.client.GetPipeline(ctx, locator.Scope, locator.Slug, pipelineRef)
+	} else {
+		buildNumber, convErr := strconv.Atoi(pipelineRef)
+		if convErr != nil || buildNumber <= 0 {
+			return PipelineStepLog{}, newToolError(ErrorInvalidInput, "pipeline must be a UUID or a positive build number", false)
+		}
+		raw, err = b.client.GetPipelineByBuildNumber(ctx, locator.Scope, locator.Slug, buildNumber)
+	}
+	if err != nil {
+		return PipelineStepLog{}, err
+	}
+	pipeline, err := adaptCloudPipeline(*raw)
+	if err != nil {
+		return PipelineStepLog{}, err
+	}
+
+	rawSteps, err := b.client.ListPipelineSteps(ctx, locator.Scope, locator.Slug, raw.UUID)
+	if err != nil {
+		return PipelineStepLog{}, err
+	}
+	steps := make([]PipelineStep, 0, len(rawSteps))
+	for _, step := range rawSteps {
+		steps = append(steps, adaptCloudPipelineStep(step))
+	}
+	step, ok := selectPipelineStep(steps, stepRef)
+	if !ok {
+		return PipelineStepLog{}, newToolError(ErrorNotFound, "pipeline step not found", false)
+	}
+
+	log, err := b.client.GetPipelineLogs(ctx, locator.Scope, locator.Slug, raw.UUID, step.UUID)
+	if err != nil {
+		return PipelineStepLog{}, err
+	}
+	return PipelineStepLog{
+		Pipeline: pipeline,
+		Step:     step,
+		Steps:    steps,
+		Log:      boundBitbucketText(string(log), PipelineLogLimit),
+	}, nil
+}
+
+func selectPipelineStep(steps []PipelineStep, ref string) (PipelineStep, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		for _, step := range steps {
+			if step.State == CheckFailed {
+				return step, true
+			}
+		}
+		if len(steps) == 0 {
+			return PipelineStep{}, false
+		}
+		return steps[len(steps)-1], true
+	}
+	uuid := bbcloud.NormalizeUUID(ref)
+	for _, step := range steps {
+		if (uuid != "" && bbcloud.NormalizeUUID(step.UUID) == uuid) || strings.EqualFold(step.Name, ref) {
+			return step, true
+		}
+	}
+	return PipelineStep{}, false
+}
+
+func (b *dcBackend) listBranches(ctx context.Context, locator RepositoryRef, filter string, limit int) ([]Branch, bool, error) {
+	limit = normalizedListLimit(limit)
+	raw, err := b.client.ListBranches(ctx, locator.Scope, locator.Slug, bbdc.BranchListOptions{Filter: filter, Limit: limit + 1})
+	if err != nil {
+		return nil, false, err
+	}
+	items := make([]Branch, 0, len(raw))
+	for _, branch := range raw {
+		items = append(items, adaptDCBranch(branch))
+	}
+	return items, len(items) > limit, nil
+}
+
+func (b *cloudBackend) listBranches(ctx context.Context, locator RepositoryRef, filter string, limit int) ([]Branch, bool, error) {
+	limit = normalizedListLimit(limit)
+	raw, err := b.client.ListBranches(ctx, locator.Scope, locator.Slug, bbcloud.BranchListOptions{Filter: filter, Limit: limit + 1})
+	if err != nil {
+		return nil, false, err
+	}
+	items := make([]Branch, 0, len(raw))
+	for _, branch := range raw {
+		items = append(items, adaptCloudBranch(branch))
+	}
+	return items, len(items) > limit, nil
+}
+
+// getCommitDiff shows the changes on from that are not on to, matching
+// `bkt commit diff <from> <to>` on both platforms.
+func (b *dcBackend) getCommitDiff(ctx context.Context, locator RepositoryRef, from, to string) (Diff, error) {
+	sink := newBoundedTextSink(DiffContentLimit)
+	if err := b.client.CommitDiff(ctx, locator.Scope, locator.Slug, from, to, sink); err != nil {
+		return Diff{}, err
+	}
+	return Diff{Content: sink.boundedText(), SourceCommit: from, TargetCommit: to}, nil
+}
+
+func (b *cloudBackend) getCommitDiff(ctx context.Context, locator RepositoryRef, from, to string) (Diff, error) {
+	sink := newBoundedTextSink(DiffContentLimit)
+	if err := b.client.CommitDiff(ctx, locator.Scope, locator.Slug, from+".."+to, sink); err != nil {
+		return Diff{}, err
+	}
+	return Diff{Content: sink.boundedText(), SourceCommit: from, TargetCommit: to}, nil
+}
+
+func (b *dcBackend) getFileContent(ctx context.Context, locator RepositoryRef, path, ref string) (BoundedText, error) {
+	sink := newBoundedTextSink(FileContentLimit)
+	if err := b.client.FileContent(ctx, locator.Scope, locator.Slug, path, ref, sink); err != nil {
+		return BoundedText{}, err
+	}
+	return sink.boundedText(), nil
+}
+
+func (b *cloudBackend) getFileContent(ctx context.Context, locator RepositoryRef, path, ref string) (BoundedText, error) {
+	sink := newBoundedTextSink(FileContentLimit)
+	if err := b.client.FileContent(ctx, locator.Scope, locator.Slug, ref, path, sink); err != nil {
+		return BoundedText{}, err
+	}
+	return sink.boundedText(), nil
+}
+
+func (b *dcBackend) listIssues(context.Context, RepositoryRef, string, int) ([]Issue, bool, error) {
+	return nil, false, errIssuesUnsupported
+}
+
+func (b *dcBackend) getIssue(context.Context, RepositoryRef, int) (Issue, error) {
+	return Issue{}, errIssuesUnsupported
+}
+
+func (b *cloudBackend) listIssues(ctx context.Context, locator RepositoryRef, state string, limit int) ([]Issue, bool, error) {
+	limit = normalizedListLimit(limit)
+	raw, err := b.client.ListIssues(ctx, locator.Scope, locator.Slug, bbcloud.IssueListOptions{
+		State: state,
+		Sort:  "-updated_on",
+		Limit: limit + 1,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	items := make([]Issue, 0, len(raw))
+	for _, issue := range raw {
+		item, err := adaptCloudIssue(issue, false)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, item)
+	}
+	return items, len(items) > limit, nil
+}
+
+func (b *cloudBackend) getIssue(ctx context.Context, locator RepositoryRef, id int) (Issue, error) {
+	raw, err := b.client.GetIssue(ctx, locator.Scope, locator.Slug, id)
+	if err != nil {
+		return Issue{}, err
+	}
+	return adaptCloudIssue(*raw, true)
+}
+
 type boundedTextSink struct {
 	limit int
 	text  strings.Builder
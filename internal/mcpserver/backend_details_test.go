@@ -386,3 +386,159 @@ func TestCloudBackendPullRequestDetailAndDiffCommits(t *testing.T) {
 		t.Fatalf("checks=%#v hasMore=%v", checks, hasMore)
 	}
 }
+
+func TestCloudBackendResolveThreadIsIdempotent(t *testing.T) {
+	var methods []string
+	resolved := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repositories/team/api/pullrequests/7/comments/9":
+			payload := map[string]any{"id": 9, "content": map[string]any{"raw": "note"}}
+			if resolved {
+				payload["resolution"] = map[string]any{"type": "comment_resolution"}
+			}
+			_ = json.NewEncoder(w).Encode(payload)
+		case r.URL.Path == "/repositories/team/api/pullrequests/7/comments/9/resolve":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := bbcloud.New(bbcloud.Options{BaseURL: server.URL, Token: "token", Retry: httpx.RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &cloudBackend{client: client}
+	locator := RepositoryRef{Scope: "team", Slug: "api"}
+
+	if err := backend.setPullRequestThreadResolved(context.Background(), locator, 7, 9, true); err != nil {
+		t.Fatalf("resolve resolved thread: %v", err)
+	}
+	if len(methods) != 1 {
+		t.Fatalf("requests = %v, want only the state lookup", methods)
+	}
+
+	methods = nil
+	if err := backend.setPullRequestThreadResolved(context.Background(), locator, 7, 9, false); err != nil {
+		t.Fatalf("reopen thread: %v", err)
+	}
+	if len(methods) != 2 || methods[1] != "DELETE /repositories/team/api/pullrequests/7/comments/9/resolve" {
+		t.Fatalf("requests = %v, want lookup then DELETE", methods)
+	}
+}
+
+func TestCloudBackendPipelineStepLogSelectsFailedStepAndBoundsLog(t *testing.T) {
+	const pipelineUUID = "{11111111-1111-1111-1111-111111111111}"
+	const buildStep = "{22222222-2222-2222-2222-222222222222}"
+	const testStep = "{33333333-3333-3333-3333-333333333333}"
+	logText := strings.Repeat("y", PipelineLogLimit+5)
+	var logPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repositories/team/api/pipelines/42":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"uuid":         pipelineUUID,
+				"build_number": 42,
+				"state":        map[string]any{"name": "COMPLETED", "result": map[string]any{"name": "FAILED"}},
+				"target":       map[string]any{"ref": map[string]any{"name": "main"}},
+				"created_on":   "2024-01-01T00:00:00Z",
+			})
+		case strings.HasSuffix(r.URL.Path, "/steps/"):
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"values": []any{
+				map[string]any{"uuid": buildStep, "name": "build", "state": map[string]any{"name": "COMPLETED", "result": map[string]any{"name": "SUCCESSFUL"}}},
+				map[string]any{"uuid": testStep, "name": "test", "state": map[string]any{"name": "COMPLETED", "result": map[string]any{"name": "FAILED"}}},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/log"):
+			logPath = r.URL.Path
+			_, _ = w.Write([]byte(logText))
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := bbcloud.New(bbcloud.Options{BaseURL: server.URL, Token: "token", Retry: httpx.RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &cloudBackend{client: client}
+	locator := RepositoryRef{Scope: "team", Slug: "api"}
+
+	result, err := backend.getPipelineStepLog(context.Background(), locator, "42", "")
+	if err != nil {
+		t.Fatalf("getPipelineStepLog: %v", err)
+	}
+	if result.Pipeline.State != CheckFailed || result.Pipeline.Ref != "main" || result.Pipeline.CompletedAt != "" {
+		t.Fatalf("pipeline = %+v", result.Pipeline)
+	}
+	if result.Step.Name != "test" || len(result.Steps) != 2 || result.Steps[0].State != CheckSuccessful {
+		t.Fatalf("step = %+v steps = %+v", result.Step, result.Steps)
+	}
+	if !strings.Contains(logPath, "33333333") {
+		t.Fatalf("log path = %q, want the failed step", logPath)
+	}
+	if len(result.Log.Text) != PipelineLogLimit || !result.Log.Truncated || *result.Log.OriginalSize != len(logText) {
+		t.Fatalf("bounded log = truncated:%v len:%d", result.Log.Truncated, len(result.Log.Text))
+	}
+
+	if _, err := backend.getPipelineStepLog(context.Background(), locator, "42", "BUILD"); err != nil || !strings.Contains(logPath, "22222222") {
+		t.Fatalf("named step err=%v path=%q", err, logPath)
+	}
+	if _, err := backend.getPipelineStepLog(context.Background(), locator, "42", "deploy"); mapToolError(err).(*structuredToolError).payload.Code != ErrorNotFound {
+		t.Fatalf("unknown step err = %v", err)
+	}
+	if _, err := backend.getPipelineStepLog(context.Background(), locator, "latest", ""); mapToolError(err).(*structuredToolError).payload.Code != ErrorInvalidInput {
+		t.Fatalf("bad pipeline ref err = %v", err)
+	}
+}
+
+func TestDCBackendBranchesAndCommitDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos/api/branches":
+			if r.URL.Query().Get("limit") != "3" || r.URL.Query().Get("filterText") != "feat" {
+				t.Fatalf("branch query = %q", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"isLastPage": true, "values": []any{
+				map[string]any{"id": "refs/heads/feat-a", "displayId": "feat-a", "latestCommit": "aaa"},
+				map[string]any{"id": "refs/heads/feat-b", "displayId": "feat-b", "latestCommit": "bbb"},
+				map[string]any{"id": "refs/heads/feat-c", "latestCommit": "ccc"},
+			}})
+		case "/rest/api/1.0/projects/PROJ/repos/api/compare/diff":
+			if r.URL.Query().Get("from") != "feature" || r.URL.Query().Get("to") != "main" {
+				t.Fatalf("diff query = %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte("diff --git a/a b/a\n"))
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := bbdc.New(bbdc.Options{BaseURL: server.URL, Token: "token", AuthMethod: "bearer", Retry: httpx.RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &dcBackend{client: client}
+	locator := RepositoryRef{Scope: "PROJ", Slug: "api"}
+
+	branches, hasMore, err := backend.listBranches(context.Background(), locator, "feat", 2)
+	if err != nil {
+		t.Fatalf("listBranches: %v", err)
+	}
+	if !hasMore || len(branches) != 3 || branches[2].Name != "feat-c" || branches[0].LatestCommit != "aaa" {
+		t.Fatalf("branches = %+v hasMore = %v", branches, hasMore)
+	}
+
+	diff, err := backend.getCommitDiff(context.Background(), locator, "feature", "main")
+	if err != nil {
+		t.Fatalf("getCommitDiff: %v", err)
+	}
+	if diff.Content.Text != "diff --git a/a b/a\n" || diff.Content.Truncated || diff.SourceCommit != "feature" || diff.TargetCommit != "main" {
+		t.Fatalf("diff = %+v", diff)
+	}
+}
@@ -13,16 +13,19 @@ import (
 	"time"
 
 	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/internal/secret"
 	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
 	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
+	"github.com/avivsinai/bitbucket-cli/pkg/oauth"
 )
 
 func TestNewPlatformBackendDoesNotMutateFrozenSnapshot(t *testing.T) {
 	snap := &Snapshot{
 		Platform:  "cloud",
 		HostLabel: "cloud",
-		Host:      config.Host{Kind: "cloud", Token: "token"},
+		Host:      config.Host{Kind: "cloud", BaseURL: "https://api.bitbucket.org/2.0", Token: "token"},
 	}
 	want := *snap
 	if _, err := newPlatformBackend(snap); err != nil {
@@ -33,44 +36,108 @@ func TestNewPlatformBackendDoesNotMutateFrozenSnapshot(t *testing.T) {
 	}
 }
 
-func TestNewPlatformBackendCloudOAuthUsesOnlyFrozenCredential(t *testing.T) {
+// oauthKeyringSnapshot puts stored in a file-backed keyring for the Cloud
+// OAuth host at serverURL and returns a snapshot still holding the stale
+// startup token.
+func oauthKeyringSnapshot(t *testing.T, serverURL string, stored oauth.Token) *Snapshot {
+	t.Helper()
 	t.Setenv("BKT_TOKEN", "")
 	t.Setenv("BKT_OAUTH_CLIENT_ID", "")
 	t.Setenv("BKT_OAUTH_CLIENT_SECRET", "")
+	t.Setenv("BKT_ALLOW_INSECURE_STORE", "1")
+	t.Setenv("BKT_KEYRING_PASSPHRASE", "test-pass")
+	t.Setenv("KEYRING_BACKEND", "file")
+	fileDir := t.TempDir()
+	t.Setenv("KEYRING_FILE_DIR", fileDir)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	hostKey, err := cmdutil.HostKeyFromURL(serverURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob, err := stored.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := secret.Open(secret.WithAllowFileFallback(true), secret.WithPassphrase("test-pass"), secret.WithFileDir(fileDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set(secret.TokenKey(hostKey), blob); err != nil {
+		t.Fatal(err)
+	}
+	return &Snapshot{
+		Platform: "cloud",
+		Host: config.Host{
+			Kind:               "cloud",
+			BaseURL:            serverURL,
+			AuthMethod:         "oauth",
+			Token:              "startup-token",
+			OAuthExpiresAt:     time.Now().Add(time.Minute),
+			AllowInsecureStore: true,
+		},
+	}
+}
+
+func TestCloudBackendRefreshesOAuthTokenIntoSnapshot(t *testing.T) {
 	var requests atomic.Int32
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requests.Add(1)
-		if got := r.Header.Get("Authorization"); got != "Bearer startup-token" {
-			t.Errorf("Authorization = %q, want startup bearer token", got)
+		if r.Header.Get("Authorization") != "Bearer refreshed-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
 		}
-		w.WriteHeader(http.StatusUnauthorized)
+		_ = json.NewEncoder(w).Encode(map[string]any{"values": []any{}})
 	}))
 	t.Cleanup(server.Close)
-	snap := &Snapshot{
-		Platform: "cloud",
-		Host: config.Host{
-			Kind:           "cloud",
-			BaseURL:        server.URL,
-			AuthMethod:     "oauth",
-			Token:          "startup-token",
-			OAuthExpiresAt: time.Now().Add(-time.Minute),
-		},
+	snap := oauthKeyringSnapshot(t, server.URL, *oauth.FromResponse("refreshed-token", "refresh", 7200))
+
+	backend, err := newPlatformBackend(snap)
+	if err != nil {
+		t.Fatalf("newPlatformBackend: %v", err)
+	}
+	if _, _, err := backend.listRepositories(context.Background(), "team", 25); err != nil {
+		t.Fatalf("listRepositories: %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("requests = %d, want the stale attempt plus one retry", got)
+	}
+	if snap.Host.Token != "refreshed-token" || time.Until(snap.Host.OAuthExpiresAt) < time.Hour {
+		t.Fatalf("snapshot host = token:%q expires:%v, want the refreshed credential", snap.Host.Token, snap.Host.OAuthExpiresAt)
 	}
 
+	if _, _, err := backend.listRepositories(context.Background(), "team", 25); err != nil {
+		t.Fatalf("second listRepositories: %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("requests = %d, want the refreshed token reused without another refresh", got)
+	}
+}
+
+func TestCloudBackendReportsFailedOAuthRefreshAsAuthFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	// The keyring holds the same token the server rejects and no OAuth
+	// consumer is configured, so the refresher cannot recover.
+	snap := oauthKeyringSnapshot(t, server.URL, *oauth.FromResponse("startup-token", "refresh", 7200))
+
 	backend, err := newPlatformBackend(snap)
 	if err != nil {
 		t.Fatalf("newPlatformBackend: %v", err)
 	}
 	_, _, err = backend.listRepositories(context.Background(), "team", 25)
-	var httpErr *httpx.HTTPError
-	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
-		t.Fatalf("listRepositories error = %T %v, want frozen 401 HTTPError", err, err)
+	var refreshErr *httpx.TokenRefreshError
+	if !errors.As(err, &refreshErr) {
+		t.Fatalf("listRepositories error = %T %v, want TokenRefreshError", err, err)
 	}
-	if got := requests.Load(); got != 1 {
-		t.Fatalf("requests = %d, want one request with no refresh retry", got)
+	var toolErr *structuredToolError
+	if !errors.As(mapToolError(err), &toolErr) || toolErr.payload.Code != ErrorAuthFailed || toolErr.payload.Retryable {
+		t.Fatalf("mapped error = %v, want non-retryable auth_failed", mapToolError(err))
 	}
 	if snap.Host.Token != "startup-token" {
-		t.Fatalf("snapshot token mutated to %q", snap.Host.Token)
+		t.Fatalf("snapshot token changed to %q after a failed refresh", snap.Host.Token)
 	}
 }
 
@@ -12,6 +12,9 @@ const (
 	CommentBodyLimit            = 16 * 1024
 	PullRequestDescriptionLimit = 16 * 1024
 	DiffContentLimit            = 256 * 1024
+	PipelineLogLimit            = 256 * 1024
+	IssueContentLimit           = 16 * 1024
+	FileContentLimit            = 256 * 1024
 )
 
 const (
@@ -24,11 +27,15 @@ const (
 // ContextInfo is the bkt_get_context result DTO. Field shapes are part of
 // the frozen v1 contract; never include credentials.
 type ContextInfo struct {
+	Name         string   `json:"name,omitempty" jsonschema:"the bkt context name"`
+	Default      bool     `json:"default,omitempty" jsonschema:"true for the context used when a tool call omits context"`
 	Platform     string   `json:"platform" jsonschema:"the pinned Bitbucket platform: dc (Data Center) or cloud"`
 	HostLabel    string   `json:"host_label" jsonschema:"the bkt config host entry this server is pinned to"`
 	DefaultScope string   `json:"default_scope,omitempty" jsonschema:"default scope (DC project key or Cloud workspace) used when a tool call omits the repository locator"`
 	DefaultRepo  string   `json:"default_repo,omitempty" jsonschema:"default repository slug used when a tool call omits the repository locator"`
 	Capabilities []string `json:"capabilities" jsonschema:"capability identifiers for the tools and roles this server supports on the pinned platform"`
+	// Contexts lists every served context; it is set only at the top level.
+	Contexts []ContextInfo `json:"contexts,omitempty" jsonschema:"every context this server serves"`
 }
 
 // Repository is the platform-neutral repository result.
@@ -116,6 +123,64 @@ type Diff struct {
 	TargetCommit string      `json:"target_commit,omitempty"`
 }
 
+// Pipeline is a normalized Bitbucket Cloud pipeline run. State folds the
+// run phase and its result into the shared check vocabulary.
+type Pipeline struct {
+	UUID        string     `json:"uuid"`
+	BuildNumber int        `json:"build_number"`
+	State       CheckState `json:"state"`
+	Ref         string     `json:"ref,omitempty"`
+	CreatedAt   string     `json:"created_at,omitempty"`
+	CompletedAt string     `json:"completed_at,omitempty"`
+}
+
+// PipelineStep is one step of a pipeline run.
+type PipelineStep struct {
+	UUID  string     `json:"uuid"`
+	Name  string     `json:"name"`
+	State CheckState `json:"state"`
+}
+
+// PipelineStepLog carries the bounded log of one step plus the run's other
+// steps so a caller can ask for a different one.
+type PipelineStepLog struct {
+	Pipeline Pipeline       `json:"pipeline"`
+	Step     PipelineStep   `json:"step"`
+	Steps    []PipelineStep `json:"steps"`
+	Log      BoundedText    `json:"log"`
+}
+
+// Branch is the shared DC/Cloud branch result.
+type Branch struct {
+	Name         string `json:"name"`
+	LatestCommit string `json:"latest_commit"`
+	IsDefault    bool   `json:"is_default"`
+}
+
+// Issue is a normalized Bitbucket Cloud issue. Content is set only by
+// full-detail adapters.
+type Issue struct {
+	ID        int          `json:"id"`
+	Title     string       `json:"title"`
+	State     string       `json:"state"`
+	Kind      string       `json:"kind,omitempty"`
+	Priority  string       `json:"priority,omitempty"`
+	Reporter  *User        `json:"reporter,omitempty"`
+	Assignee  *User        `json:"assignee,omitempty"`
+	CreatedAt string       `json:"created_at,omitempty"`
+	UpdatedAt string       `json:"updated_at,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	Content   *BoundedText `json:"content,omitempty"`
+}
+
+// WriteResult acknowledges a completed write tool call.
+type WriteResult struct {
+	Action        string        `json:"action"`
+	Repo          RepositoryRef `json:"repo"`
+	PullRequestID int           `json:"pull_request_id"`
+	CommentID     *int          `json:"comment_id,omitempty"`
+}
+
 // TextProvenance identifies where externally authored content came from and
 // how consumers must treat it.
 type TextProvenance struct {
@@ -0,0 +1,112 @@
+package mcpserver
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// HTTPEndpoint is the streamable HTTP MCP endpoint.
+	HTTPEndpoint = "/mcp"
+	// HealthEndpoint answers liveness probes without authentication.
+	HealthEndpoint = "/healthz"
+	// InventoryEndpoint describes the served contexts and tools.
+	InventoryEndpoint = "/inventory"
+)
+
+// ServedInventory is the /inventory response: the default context with every
+// served context listed under it, plus only the tools this server actually
+// serves. It never includes credentials.
+type ServedInventory struct {
+	Context    ContextInfo         `json:"context"`
+	WriteGates []WriteGate         `json:"write_gates"`
+	Tools      []ToolInventoryItem `json:"tools"`
+}
+
+// GenerateHTTPToken returns a random bearer token for the HTTP transport.
+func GenerateHTTPToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate MCP HTTP token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// NewHTTPHandler serves server over the go-sdk streamable HTTP transport at
+// /mcp, plus /healthz and /inventory. Every route except /healthz requires
+// "Authorization: Bearer <token>". All sessions share the same frozen
+// snapshots and tool set.
+func NewHTTPHandler(server *mcp.Server, snaps []*Snapshot, opts Options, token string) (http.Handler, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, fmt.Errorf("MCP HTTP transport requires a bearer token")
+	}
+
+	if len(snaps) == 0 {
+		return nil, fmt.Errorf("no context to serve")
+	}
+	inventory := servedInventory(snaps, opts)
+	streamable := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+HealthEndpoint, func(w http.ResponseWriter, _ *http.Request) {
+		writeHTTPJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle(InventoryEndpoint, requireBearer(token, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeHTTPJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+			return
+		}
+		writeHTTPJSON(w, http.StatusOK, inventory)
+	})))
+	mux.Handle(HTTPEndpoint, requireBearer(token, streamable))
+	return mux, nil
+}
+
+func servedInventory(snaps []*Snapshot, opts Options) ServedInventory {
+	gates := slices.Clone(opts.AllowWrites)
+	if gates == nil {
+		gates = []WriteGate{}
+	}
+	all := InventorySnapshot().Tools
+	tools := make([]ToolInventoryItem, 0, len(all))
+	for _, tool := range all {
+		if tool.WriteGate == "" || slices.Contains(gates, tool.WriteGate) {
+			tools = append(tools, tool)
+		}
+	}
+	return ServedInventory{
+		Context:    servedContextInfo(snaps, 0),
+		WriteGates: gates,
+		Tools:      tools,
+	}
+}
+
+// requireBearer rejects requests whose Authorization header does not carry
+// the expected token. Comparison is constant-time.
+func requireBearer(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="bkt-mcp"`)
+			writeHTTPJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeHTTPJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,131 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (b bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return b.base.RoundTrip(req)
+}
+
+func newLoopbackHTTPServer(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	snap := &Snapshot{ContextName: "work", Platform: "dc", HostLabel: "dc-host", DefaultScope: "PROJ", DefaultRepo: "api"}
+	server := newWritableServer(snap, "test", &fakeWriteBackend{}, opts)
+	handler, err := NewHTTPHandler(server, []*Snapshot{snap}, opts, "s3cret")
+	if err != nil {
+		t.Fatalf("NewHTTPHandler: %v", err)
+	}
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestHTTPHandlerRequiresToken(t *testing.T) {
+	if _, err := NewHTTPHandler(mcp.NewServer(&mcp.Implementation{Name: "bkt"}, nil), []*Snapshot{{}}, Options{}, " "); err == nil {
+		t.Fatal("expected an error for an empty token")
+	}
+}
+
+func TestHTTPHealthIsUnauthenticatedAndInventoryIsNot(t *testing.T) {
+	ts := newLoopbackHTTPServer(t, Options{})
+
+	resp, err := http.Get(ts.URL + HealthEndpoint)
+	if err != nil {
+		t.Fatalf("GET healthz: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("healthz status = %d", resp.StatusCode)
+	}
+
+	for _, path := range []string{InventoryEndpoint, HTTPEndpoint} {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Fatalf("%s with a bad token: status %d, want 401 with challenge", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestHTTPInventoryListsOnlyServedTools(t *testing.T) {
+	ts := newLoopbackHTTPServer(t, Options{AllowWrites: []WriteGate{WriteGateApprove}, AuditLog: io.Discard})
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+InventoryEndpoint, nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET inventory: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("inventory status = %d", resp.StatusCode)
+	}
+	var inventory ServedInventory
+	if err := json.NewDecoder(resp.Body).Decode(&inventory); err != nil {
+		t.Fatalf("decode inventory: %v", err)
+	}
+	if inventory.Context.HostLabel != "dc-host" || len(inventory.Context.Contexts) != 1 || inventory.Context.Contexts[0].Name != "work" || len(inventory.WriteGates) != 1 {
+		t.Fatalf("inventory = %+v", inventory)
+	}
+	names := map[string]bool{}
+	for _, tool := range inventory.Tools {
+		names[tool.Name] = true
+	}
+	if !names["bkt_get_context"] || !names["bkt_approve_pull_request"] || names["bkt_add_pull_request_comment"] {
+		t.Fatalf("served tools = %v", names)
+	}
+}
+
+func TestHTTPStreamableTransportRoundTrip(t *testing.T) {
+	ts := newLoopbackHTTPServer(t, Options{})
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "http-test", Version: "0"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:             ts.URL + HTTPEndpoint,
+		HTTPClient:           &http.Client{Transport: bearerTransport{token: "s3cret", base: http.DefaultTransport}},
+		DisableStandaloneSSE: true,
+		MaxRetries:           -1,
+	}, nil)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "bkt_get_context"})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	var info ContextInfo
+	decodeStructuredContent(t, res, &info)
+	if info.Platform != "dc" || info.DefaultScope != "PROJ" {
+		t.Fatalf("context = %+v", info)
+	}
+
+	unauthenticated := mcp.NewClient(&mcp.Implementation{Name: "http-test", Version: "0"}, nil)
+	if _, err := unauthenticated.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   ts.URL + HTTPEndpoint,
+		MaxRetries: -1,
+	}, nil); err == nil {
+		t.Fatal("expected connect without a bearer token to fail")
+	}
+}
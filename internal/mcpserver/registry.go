@@ -1,11 +1,13 @@
 package mcpserver
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"slices"
 	"sort"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -22,15 +24,76 @@ type toolDocumentation struct {
 type registeredTool struct {
 	tool          *mcp.Tool
 	documentation toolDocumentation
+	gate          WriteGate
 }
 
 type toolRegistry struct {
-	server *mcp.Server
-	tools  []registeredTool
+	server    *mcp.Server
+	tools     []registeredTool
+	resources []*mcp.ResourceTemplate
+	prompts   []*mcp.Prompt
+	// contexts are the served snapshots, default first; write tool audit
+	// records name the one each call was routed to.
+	contexts []*Snapshot
+	// writes and audit are set only when the operator opted in with
+	// --allow-writes; a registry without them serves no write tools.
+	writes map[WriteGate]bool
+	audit  *auditLog
 }
 
-func newToolRegistry(server *mcp.Server) *toolRegistry {
-	return &toolRegistry{server: server}
+// WriteGate names an opt-in class of write tools. Nothing that mutates
+// Bitbucket is served unless its gate is passed to --allow-writes.
+type WriteGate string
+
+const (
+	WriteGateComment  WriteGate = "comment"
+	WriteGateApprove  WriteGate = "approve"
+	WriteGateCreatePR WriteGate = "create-pr"
+	WriteGateResolve  WriteGate = "resolve"
+)
+
+// WriteGates lists every gate accepted by --allow-writes, in display order.
+func WriteGates() []WriteGate {
+	return []WriteGate{WriteGateComment, WriteGateApprove, WriteGateCreatePR, WriteGateResolve}
+}
+
+// ParseWriteGates validates and de-duplicates --allow-writes values.
+func ParseWriteGates(values []string) ([]WriteGate, error) {
+	known := WriteGates()
+	var gates []WriteGate
+	for _, value := range values {
+		gate := WriteGate(strings.ToLower(strings.TrimSpace(value)))
+		if gate == "" {
+			continue
+		}
+		if !slices.Contains(known, gate) {
+			names := make([]string, 0, len(known))
+			for _, k := range known {
+				names = append(names, string(k))
+			}
+			return nil, fmt.Errorf("unknown write gate %q (valid: %s)", value, strings.Join(names, ", "))
+		}
+		if !slices.Contains(gates, gate) {
+			gates = append(gates, gate)
+		}
+	}
+	return gates, nil
+}
+
+// writeHints are the honest behavioral annotations every write tool declares.
+type writeHints struct {
+	Destructive bool
+	Idempotent  bool
+}
+
+func newToolRegistry(server *mcp.Server, contexts []*Snapshot) *toolRegistry {
+	return &toolRegistry{server: server, contexts: contexts}
+}
+
+// contextNamed is implemented by every tool argument type through its
+// embedded contextSelector.
+type contextNamed interface {
+	contextName() string
 }
 
 func standardReadErrors() []ErrorCode {
@@ -55,7 +118,7 @@ func schemaFor[T any]() *jsonschema.Schema {
 	return schema
 }
 
-// addReadOnlyTool is the single registration path for v1 tools. It materializes
+// addReadOnlyTool is the registration path for read tools. It materializes
 // the inferred schemas before handing the tool to the SDK so runtime serving,
 // schema goldens, and generated documentation share one typed registry.
 func addReadOnlyTool[In, Out any](registry *toolRegistry, tool *mcp.Tool, documentation toolDocumentation, handler mcp.ToolHandlerFor[In, Out]) {
@@ -84,6 +147,85 @@ func addReadOnlyTool[In, Out any](registry *toolRegistry, tool *mcp.Tool, docume
 	}
 }
 
+// addWriteTool is the registration path for opt-in write tools. Tools whose
+// gate was not enabled are recorded for documentation but never served. Each
+// call is audited before it reaches Bitbucket; if the audit record cannot be
+// written the call is refused, so no write goes unrecorded.
+func addWriteTool[In, Out any](registry *toolRegistry, gate WriteGate, hints writeHints, tool *mcp.Tool, documentation toolDocumentation, handler mcp.ToolHandlerFor[In, Out]) {
+	if tool.Annotations == nil {
+		tool.Annotations = &mcp.ToolAnnotations{}
+	}
+	tool.Annotations.ReadOnlyHint = false
+	tool.Annotations.DestructiveHint = &hints.Destructive
+	tool.Annotations.IdempotentHint = hints.Idempotent
+	if tool.InputSchema == nil {
+		tool.InputSchema = schemaFor[In]()
+	}
+	if tool.OutputSchema == nil {
+		tool.OutputSchema = schemaFor[Out]()
+	}
+	if len(documentation.Platforms) == 0 {
+		documentation.Platforms = slices.Clone(allPlatforms)
+	}
+	if documentation.Errors == nil {
+		documentation.Errors = []ErrorCode{}
+	}
+	if documentation.Notes == nil {
+		documentation.Notes = []string{}
+	}
+
+	if registry.server == nil {
+		registry.tools = append(registry.tools, registeredTool{tool: tool, documentation: documentation, gate: gate})
+		return
+	}
+	if !registry.writes[gate] || registry.audit == nil {
+		return
+	}
+	registry.tools = append(registry.tools, registeredTool{tool: tool, documentation: documentation, gate: gate})
+
+	audit := registry.audit
+	contexts := registry.contexts
+	mcp.AddTool(registry.server, tool, func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, Out, error) {
+		var zero Out
+		name := ""
+		if named, ok := any(args).(contextNamed); ok {
+			name = named.contextName()
+		}
+		i, err := findContext(contexts, name)
+		if err != nil {
+			return nil, zero, err
+		}
+		snap := contexts[i]
+		if err := audit.record(snap, tool.Name, gate, "attempt", args, nil); err != nil {
+			return nil, zero, newToolError(ErrorUpstream, "write refused: the audit log could not be written", false)
+		}
+		result, out, err := handler(ctx, req, args)
+		event := "ok"
+		if err != nil {
+			event = "error"
+		}
+		_ = audit.record(snap, tool.Name, gate, event, nil, err)
+		return result, out, err
+	})
+}
+
+// addResourceTemplate registers a read-only resource template so that the
+// served templates and generated documentation stay in step.
+func addResourceTemplate(registry *toolRegistry, template *mcp.ResourceTemplate, handler mcp.ResourceHandler) {
+	registry.resources = append(registry.resources, template)
+	if registry.server != nil {
+		registry.server.AddResourceTemplate(template, handler)
+	}
+}
+
+// addPrompt registers a prompt template alongside the tools it draws on.
+func addPrompt(registry *toolRegistry, prompt *mcp.Prompt, handler mcp.PromptHandler) {
+	registry.prompts = append(registry.prompts, prompt)
+	if registry.server != nil {
+		registry.server.AddPrompt(prompt, handler)
+	}
+}
+
 // Inventory is a serializable snapshot of the complete MCP registry. It is the
 // source for both schema drift tests and the generated standalone skill rule.
 type Inventory struct {
@@ -91,6 +233,8 @@ type Inventory struct {
 	Bounds    []BoundInventory    `json:"bounds"`
 	Errors    []ErrorInventory    `json:"errors"`
 	Tools     []ToolInventoryItem `json:"tools"`
+	Resources []ResourceInventory `json:"resources"`
+	Prompts   []PromptInventory   `json:"prompts"`
 }
 
 type PlatformInventory struct {
@@ -120,19 +264,42 @@ type ToolInventoryItem struct {
 	Description  string          `json:"description"`
 	Platforms    []string        `json:"platforms"`
 	ReadOnly     bool            `json:"read_only"`
+	WriteGate    WriteGate       `json:"write_gate,omitempty"`
+	Destructive  bool            `json:"destructive,omitempty"`
+	Idempotent   bool            `json:"idempotent,omitempty"`
 	Errors       []ErrorCode     `json:"errors"`
 	Notes        []string        `json:"notes"`
 	InputSchema  json.RawMessage `json:"input_schema"`
 	OutputSchema json.RawMessage `json:"output_schema"`
 }
 
-// InventorySnapshot returns the nine-tool v1 registry without constructing a
-// platform client or reading user configuration.
+type ResourceInventory struct {
+	URITemplate string `json:"uri_template"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mime_type,omitempty"`
+}
+
+type PromptInventory struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Arguments   []PromptArgumentInventory `json:"arguments"`
+}
+
+type PromptArgumentInventory struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// InventorySnapshot returns the complete registry, including every opt-in
+// write tool, without constructing a platform client or reading user
+// configuration.
 func InventorySnapshot() Inventory {
-	registry := newToolRegistry(nil)
-	snapshot := &Snapshot{Platform: "dc"}
-	var backend fullPlatformBackend
-	registerFullTools(registry, snapshot, backend)
+	contexts := singleContext[writablePlatformBackend](&Snapshot{Platform: "dc"}, nil)
+	registry := newToolRegistry(nil, contexts.snapshots())
+	registerFullTools(registry, contexts)
+	registerWriteTools(registry, contexts)
 
 	tools := make([]ToolInventoryItem, 0, len(registry.tools))
 	for _, registered := range registry.tools {
@@ -144,19 +311,48 @@ func InventorySnapshot() Inventory {
 		if err != nil {
 			panic(fmt.Sprintf("marshal output schema for %s: %v", registered.tool.Name, err))
 		}
-		tools = append(tools, ToolInventoryItem{
+		annotations := registered.tool.Annotations
+		item := ToolInventoryItem{
 			Name:         registered.tool.Name,
 			Description:  registered.tool.Description,
 			Platforms:    slices.Clone(registered.documentation.Platforms),
-			ReadOnly:     registered.tool.Annotations != nil && registered.tool.Annotations.ReadOnlyHint,
+			ReadOnly:     annotations != nil && annotations.ReadOnlyHint,
+			WriteGate:    registered.gate,
 			Errors:       slices.Clone(registered.documentation.Errors),
 			Notes:        slices.Clone(registered.documentation.Notes),
 			InputSchema:  inputSchema,
 			OutputSchema: outputSchema,
-		})
+		}
+		if annotations != nil && !annotations.ReadOnlyHint {
+			item.Destructive = annotations.DestructiveHint == nil || *annotations.DestructiveHint
+			item.Idempotent = annotations.IdempotentHint
+		}
+		tools = append(tools, item)
 	}
 	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
 
+	resources := make([]ResourceInventory, 0, len(registry.resources))
+	for _, template := range registry.resources {
+		resources = append(resources, ResourceInventory{
+			URITemplate: template.URITemplate,
+			Name:        template.Name,
+			Description: template.Description,
+			MIMEType:    template.MIMEType,
+		})
+	}
+	prompts := make([]PromptInventory, 0, len(registry.prompts))
+	for _, prompt := range registry.prompts {
+		arguments := make([]PromptArgumentInventory, 0, len(prompt.Arguments))
+		for _, argument := range prompt.Arguments {
+			arguments = append(arguments, PromptArgumentInventory{
+				Name:        argument.Name,
+				Description: argument.Description,
+				Required:    argument.Required,
+			})
+		}
+		prompts = append(prompts, PromptInventory{Name: prompt.Name, Description: prompt.Description, Arguments: arguments})
+	}
+
 	return Inventory{
 		Platforms: PlatformInventory{
 			DataCenter: PlatformInventoryItem{Name: "Data Center", Capabilities: capabilities(&Snapshot{Platform: "dc"})},
@@ -168,15 +364,20 @@ func InventorySnapshot() Inventory {
 			{Name: "comment_body_limit", Value: CommentBodyLimit, Unit: "bytes", Description: "maximum retained comment body"},
 			{Name: "pull_request_description_limit", Value: PullRequestDescriptionLimit, Unit: "bytes", Description: "maximum retained pull request description"},
 			{Name: "diff_content_limit", Value: DiffContentLimit, Unit: "bytes", Description: "maximum retained unified diff content"},
+			{Name: "pipeline_log_limit", Value: PipelineLogLimit, Unit: "bytes", Description: "maximum retained pipeline step log"},
+			{Name: "issue_content_limit", Value: IssueContentLimit, Unit: "bytes", Description: "maximum retained issue content"},
+			{Name: "file_content_limit", Value: FileContentLimit, Unit: "bytes", Description: "maximum retained file resource content"},
 		},
 		Errors: []ErrorInventory{
 			{Code: ErrorInvalidInput, Description: "the tool arguments or frozen context are incomplete or invalid"},
 			{Code: ErrorNotFound, Description: "the requested Bitbucket resource was not found"},
-			{Code: ErrorAuthFailed, Description: "Bitbucket rejected the frozen credential"},
+			{Code: ErrorAuthFailed, Description: "Bitbucket rejected the context's credential, and a Cloud OAuth refresh did not recover it"},
 			{Code: ErrorUnsupportedOnPlatform, Description: "the requested operation is unavailable on the pinned platform"},
 			{Code: ErrorRateLimited, Description: "Bitbucket rate-limited the request; retryable is true"},
 			{Code: ErrorUpstream, Description: "Bitbucket or the transport failed; retryable reflects the failure class"},
 		},
-		Tools: tools,
+		Tools:     tools,
+		Resources: resources,
+		Prompts:   prompts,
 	}
 }
@@ -12,15 +12,25 @@ func TestInventorySnapshotMatchesGolden(t *testing.T) {
 	inventory := InventorySnapshot()
 
 	wantNames := []string{
+		"bkt_add_pull_request_comment",
+		"bkt_approve_pull_request",
+		"bkt_create_pull_request",
+		"bkt_get_commit_diff",
 		"bkt_get_context",
+		"bkt_get_issue",
+		"bkt_get_pipeline_step_log",
 		"bkt_get_pull_request",
 		"bkt_get_pull_request_checks",
 		"bkt_get_pull_request_diff",
 		"bkt_get_repository",
+		"bkt_list_branches",
+		"bkt_list_issues",
 		"bkt_list_my_pull_requests",
+		"bkt_list_pipelines",
 		"bkt_list_pull_request_comments",
 		"bkt_list_pull_requests",
 		"bkt_list_repositories",
+		"bkt_resolve_pull_request_thread",
 	}
 	if len(inventory.Tools) != len(wantNames) {
 		t.Fatalf("registered tool count = %d, want %d", len(inventory.Tools), len(wantNames))
@@ -29,9 +39,12 @@ func TestInventorySnapshotMatchesGolden(t *testing.T) {
 	gotNames := make([]string, 0, len(inventory.Tools))
 	for _, tool := range inventory.Tools {
 		gotNames = append(gotNames, tool.Name)
-		if !tool.ReadOnly {
+		if tool.WriteGate == "" && !tool.ReadOnly {
 			t.Errorf("tool %q readOnly = false, want true", tool.Name)
 		}
+		if tool.WriteGate != "" && tool.ReadOnly {
+			t.Errorf("write tool %q readOnly = true, want false", tool.Name)
+		}
 		if len(tool.InputSchema) == 0 || len(tool.OutputSchema) == 0 {
 			t.Errorf("tool %q must freeze both input and output schemas", tool.Name)
 		}
@@ -87,7 +100,28 @@ func TestInventoryPinsPlatformCapabilities(t *testing.T) {
 	if !slices.Equal(inventory.Platforms.DataCenter.Capabilities, []string{"my_prs.role.reviewer"}) {
 		t.Fatalf("Data Center capabilities = %v", inventory.Platforms.DataCenter.Capabilities)
 	}
-	if len(inventory.Platforms.Cloud.Capabilities) != 0 {
-		t.Fatalf("Cloud capabilities = %v, want none", inventory.Platforms.Cloud.Capabilities)
+	if !slices.Equal(inventory.Platforms.Cloud.Capabilities, []string{"pipelines", "issues"}) {
+		t.Fatalf("Cloud capabilities = %v", inventory.Platforms.Cloud.Capabilities)
+	}
+}
+
+func TestInventoryListsResourcesAndPrompts(t *testing.T) {
+	inventory := InventorySnapshot()
+	var templates []string
+	for _, resource := range inventory.Resources {
+		templates = append(templates, resource.URITemplate)
+	}
+	if !slices.Equal(templates, []string{PullRequestResourceTemplate, PullRequestChecksResourceTemplate, FileResourceTemplate}) {
+		t.Fatalf("resource templates = %v", templates)
+	}
+	var prompts []string
+	for _, prompt := range inventory.Prompts {
+		prompts = append(prompts, prompt.Name)
+		if len(prompt.Arguments) == 0 || prompt.Arguments[0].Name != "id" || !prompt.Arguments[0].Required {
+			t.Errorf("prompt %q must require id first, got %+v", prompt.Name, prompt.Arguments)
+		}
+	}
+	if !slices.Equal(prompts, []string{"review_pull_request", "summarize_failing_checks"}) {
+		t.Fatalf("prompts = %v", prompts)
 	}
 }
@@ -0,0 +1,190 @@
+package mcpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
+)
+
+func TestPullRequestResourceAttachesDetailsAndBoundedDiff(t *testing.T) {
+	description := boundBitbucketText("full description", PullRequestDescriptionLimit)
+	backend := &fakeC2BBackend{
+		getPRResult: PullRequest{ID: 7, Title: "Detail", Description: &description, Reviewers: []Reviewer{}},
+		diffResult:  adaptDiff(strings.Repeat("d", DiffContentLimit+1), "source-sha", "target-sha"),
+	}
+	session := connectPair(t, newFullServer(&Snapshot{Platform: "dc", HostLabel: "dc"}, "test", backend))
+
+	templates, err := session.ListResourceTemplates(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListResourceTemplates: %v", err)
+	}
+	if len(templates.ResourceTemplates) != 3 {
+		t.Fatalf("resource templates = %d, want 3", len(templates.ResourceTemplates))
+	}
+
+	res, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "bkt://pr/PROJ/api/7"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(res.Contents) != 2 {
+		t.Fatalf("contents = %d, want details and diff", len(res.Contents))
+	}
+	var pr PullRequest
+	if err := json.Unmarshal([]byte(res.Contents[0].Text), &pr); err != nil {
+		t.Fatalf("decode details: %v", err)
+	}
+	if pr.ID != 7 || pr.Description == nil || pr.Description.Text != "full description" {
+		t.Fatalf("details = %+v", pr)
+	}
+	diff := res.Contents[1]
+	if diff.MIMEType != "text/x-diff" || len(diff.Text) != DiffContentLimit || diff.Meta["truncated"] != true || diff.Meta["source_commit"] != "source-sha" {
+		t.Fatalf("diff content = mime:%q len:%d meta:%v", diff.MIMEType, len(diff.Text), diff.Meta)
+	}
+	if backend.getPRLocator != (RepositoryRef{Scope: "PROJ", Slug: "api"}) || backend.diffID != 7 {
+		t.Fatalf("backend args = locator:%+v id:%d", backend.getPRLocator, backend.diffID)
+	}
+}
+
+func TestFileResourceParsesPathAndRef(t *testing.T) {
+	backend := &fakeC2BBackend{fakeContentBackend: fakeContentBackend{
+		fileResult: boundBitbucketText("package main\n", FileContentLimit),
+	}}
+	session := connectPair(t, newFullServer(&Snapshot{Platform: "cloud", HostLabel: "cloud"}, "test", backend))
+
+	res, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "bkt://repo/team/api/file/cmd/app/main.go@feature/login"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if len(res.Contents) != 1 || res.Contents[0].Text != "package main\n" || res.Contents[0].Meta["truncated"] != false {
+		t.Fatalf("contents = %+v", res.Contents)
+	}
+	if backend.fileLocator != (RepositoryRef{Scope: "team", Slug: "api"}) || backend.filePath != "cmd/app/main.go" || backend.fileRef != "feature/login" {
+		t.Fatalf("file args = locator:%+v path:%q ref:%q", backend.fileLocator, backend.filePath, backend.fileRef)
+	}
+}
+
+func TestResourcesAndPromptsRouteByContext(t *testing.T) {
+	work := &fakeC2BBackend{fakeContentBackend: fakeContentBackend{fileResult: boundBitbucketText("work", FileContentLimit)}}
+	oss := &fakeC2BBackend{
+		fakeContentBackend: fakeContentBackend{fileResult: boundBitbucketText("oss", FileContentLimit)},
+		checksItems:        []Check{{Key: "ci", State: CheckFailed}},
+	}
+	contexts := singleContext[fullPlatformBackend](&Snapshot{ContextName: "work", Platform: "dc", HostLabel: "dc"}, work)
+	contexts.add(&Snapshot{ContextName: "oss", Platform: "cloud", HostLabel: "cloud", DefaultScope: "team", DefaultRepo: "lib"}, oss)
+	server := mcp.NewServer(&mcp.Implementation{Name: "bkt", Version: "test"}, nil)
+	registerFullTools(newToolRegistry(server, contexts.snapshots()), contexts)
+	session := connectPair(t, server)
+
+	res, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "bkt://repo/team/lib/file/README.md@main?context=oss"})
+	if err != nil {
+		t.Fatalf("ReadResource: %v", err)
+	}
+	if res.Contents[0].Text != "oss" || oss.fileRef != "main" || work.fileRef != "" {
+		t.Fatalf("contents = %q, oss ref = %q, work ref = %q", res.Contents[0].Text, oss.fileRef, work.fileRef)
+	}
+
+	if _, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "bkt://repo/team/lib/file/README.md@main?context=personal"}); err == nil || !strings.Contains(err.Error(), string(ErrorInvalidInput)) {
+		t.Fatalf("unknown context err = %v, want invalid_input", err)
+	}
+
+	checks, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "summarize_failing_checks",
+		Arguments: map[string]string{"id": "7", "context": "oss"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	embedded, ok := checks.Messages[1].Content.(*mcp.EmbeddedResource)
+	if !ok || embedded.Resource.URI != "bkt://pr/team/lib/7/checks?context=oss" {
+		t.Fatalf("embedded checks = %#v", checks.Messages[1].Content)
+	}
+	if oss.checksLocator != (RepositoryRef{Scope: "team", Slug: "lib"}) || work.checksLocator != (RepositoryRef{}) {
+		t.Fatalf("checks locators = oss:%+v work:%+v", oss.checksLocator, work.checksLocator)
+	}
+}
+
+func TestResourcesReportMissingTargetsAsNotFound(t *testing.T) {
+	backend := &fakeC2BBackend{fakeContentBackend: fakeContentBackend{
+		fileErr: &httpx.HTTPError{StatusCode: 404},
+	}}
+	session := connectPair(t, newFullServer(&Snapshot{Platform: "dc", HostLabel: "dc"}, "test", backend))
+
+	for _, uri := range []string{
+		"bkt://pr/PROJ/api/zero",
+		"bkt://repo/PROJ/api/file/README.md@main",
+	} {
+		_, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: uri})
+		if err == nil || !strings.Contains(err.Error(), "Resource not found") {
+			t.Fatalf("ReadResource(%q) err = %v, want resource not found", uri, err)
+		}
+	}
+	if backend.getPRCalls != 0 {
+		t.Fatalf("malformed pull request URI reached the backend")
+	}
+}
+
+func TestPromptsEmbedPullRequestResources(t *testing.T) {
+	backend := &fakeC2BBackend{
+		getPRResult: PullRequest{ID: 7, Title: "Detail", Reviewers: []Reviewer{}},
+		diffResult:  adaptDiff("diff --git a/a b/a\n", "source-sha", "target-sha"),
+		checksItems: []Check{{Key: "ci", State: CheckFailed}},
+	}
+	snap := &Snapshot{Platform: "cloud", HostLabel: "cloud", DefaultScope: "team", DefaultRepo: "api"}
+	session := connectPair(t, newFullServer(snap, "test", backend))
+
+	review, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "review_pull_request",
+		Arguments: map[string]string{"id": "7"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt review: %v", err)
+	}
+	if len(review.Messages) != 3 {
+		t.Fatalf("review messages = %d, want instructions plus two resources", len(review.Messages))
+	}
+	text, ok := review.Messages[0].Content.(*mcp.TextContent)
+	if !ok || !strings.Contains(text.Text, "team/api#7") || !strings.Contains(text.Text, "rather than instructions") {
+		t.Fatalf("review instructions = %#v", review.Messages[0].Content)
+	}
+	embedded, ok := review.Messages[2].Content.(*mcp.EmbeddedResource)
+	if !ok || embedded.Resource.URI != "bkt://pr/team/api/7" || embedded.Resource.Text != "diff --git a/a b/a\n" {
+		t.Fatalf("embedded diff = %#v", review.Messages[2].Content)
+	}
+
+	checks, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "summarize_failing_checks",
+		Arguments: map[string]string{"id": "7", "scope": "other", "slug": "repo"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt checks: %v", err)
+	}
+	embedded, ok = checks.Messages[1].Content.(*mcp.EmbeddedResource)
+	if !ok || embedded.Resource.URI != "bkt://pr/other/repo/7/checks" || !strings.Contains(embedded.Resource.Text, `"state":"failed"`) {
+		t.Fatalf("embedded checks = %#v", checks.Messages[1].Content)
+	}
+	if backend.checksLocator != (RepositoryRef{Scope: "other", Slug: "repo"}) {
+		t.Fatalf("checks locator = %+v", backend.checksLocator)
+	}
+
+	_, err = session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "review_pull_request",
+		Arguments: map[string]string{"id": "7", "scope": "team"},
+	})
+	if err == nil || !strings.Contains(err.Error(), string(ErrorInvalidInput)) {
+		t.Fatalf("partial locator err = %v, want invalid_input", err)
+	}
+}
+
+func TestResourceErrorKeepsNonNotFoundCodes(t *testing.T) {
+	err := resourceError("bkt://pr/PROJ/api/7", &httpx.HTTPError{StatusCode: 401})
+	var toolErr *structuredToolError
+	if !errors.As(err, &toolErr) || toolErr.payload.Code != ErrorAuthFailed {
+		t.Fatalf("err = %v, want auth_failed", err)
+	}
+}
@@ -1,11 +1,17 @@
 // Package mcpserver implements bkt's Model Context Protocol server: a typed
-// tool registry over the bbdc/bbcloud clients, serving one context frozen at
-// startup. v1 is read-only and stdio-only; see docs/plans for the full spec.
+// registry of tools, resources, and prompts over the bbdc/bbcloud clients,
+// serving one or more contexts frozen at startup. Every call is routed to a
+// single context, and each context keeps its own credentials. Tools are
+// read-only unless the operator opts into write gates.
 package mcpserver
 
 import (
 	"context"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -15,9 +21,11 @@ import (
 )
 
 // Snapshot is the frozen effective target resolved once at server startup.
-// It is a deep copy: later config or credential edits require a server restart,
-// and the working directory never influences it (resolution skips git-remote
-// default detection). Cloud OAuth access tokens do not refresh after startup.
+// It is a deep copy: later config edits require a server restart, and the
+// working directory never influences it (resolution skips git-remote default
+// detection). The one live field is a Cloud OAuth credential: when Bitbucket
+// rejects the access token, the backend refreshes it through the secret store
+// like any other bkt command and updates Host in place.
 type Snapshot struct {
 	ContextName  string
 	Platform     string // "dc" or "cloud"
@@ -53,101 +61,324 @@ func ResolveSnapshot(f *cmdutil.Factory, contextOverride string) (*Snapshot, err
 	}, nil
 }
 
-// New builds the MCP server for the given frozen snapshot. All registered
-// tools are read-only in v1; addReadOnlyTool is the single registration path
-// and stamps truthful annotations.
-func New(snap *Snapshot, version string) (*mcp.Server, error) {
-	backend, err := newPlatformBackend(snap)
-	if err != nil {
+// ResolveSnapshots resolves and freezes every served context, default first.
+// With all set, every configured context is served and the active one is the
+// default; otherwise names are served in order, and no names means the
+// active context alone. Each snapshot carries only its own credentials.
+func ResolveSnapshots(f *cmdutil.Factory, names []string, all bool) ([]*Snapshot, error) {
+	if all {
+		cfg, err := f.ResolveConfig()
+		if err != nil {
+			return nil, err
+		}
+		if len(cfg.Contexts) == 0 {
+			return nil, fmt.Errorf("no contexts configured; create one with `bkt context create`")
+		}
+		names = slices.Sorted(maps.Keys(cfg.Contexts))
+		if i := slices.Index(names, cfg.ActiveContext); i > 0 {
+			names = append([]string{cfg.ActiveContext}, slices.Delete(names, i, i+1)...)
+		}
+	}
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	snaps := make([]*Snapshot, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		snap, err := ResolveSnapshot(f, name)
+		if err != nil {
+			if name != "" {
+				return nil, fmt.Errorf("context %q: %w", name, err)
+			}
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// Options configures optional server behavior. The zero value serves only the
+// read-only tools.
+type Options struct {
+	// AllowWrites enables the write tools behind each gate.
+	AllowWrites []WriteGate
+	// AuditLog receives one JSON line per write tool event. It is required
+	// when AllowWrites is non-empty.
+	AuditLog io.Writer
+}
+
+// New builds the MCP server for the given frozen snapshots. The first
+// snapshot is the default for calls that omit context. Each snapshot gets its
+// own backend, so credentials never cross contexts. Read tools are registered
+// through addReadOnlyTool; write tools go through addWriteTool and are served
+// only for the gates named in opts.AllowWrites.
+func New(snaps []*Snapshot, version string, opts Options) (*mcp.Server, error) {
+	if len(opts.AllowWrites) > 0 && opts.AuditLog == nil {
+		return nil, fmt.Errorf("write tools require an audit log")
+	}
+	if err := validateSnapshots(snaps); err != nil {
 		return nil, err
 	}
-	return newFullServer(snap, version, backend), nil
+	contexts := &contextSet[writablePlatformBackend]{}
+	for _, snap := range snaps {
+		backend, err := newPlatformBackend(snap)
+		if err != nil {
+			return nil, err
+		}
+		contexts.add(snap, backend)
+	}
+	return newWritableContextServer(contexts, version, opts), nil
+}
+
+// validateSnapshots rejects an empty set and names that could not route a
+// call unambiguously.
+func validateSnapshots(snaps []*Snapshot) error {
+	if len(snaps) == 0 {
+		return fmt.Errorf("no context to serve")
+	}
+	seen := make(map[string]bool, len(snaps))
+	for _, snap := range snaps {
+		if len(snaps) > 1 && snap.ContextName == "" {
+			return fmt.Errorf("serving several contexts requires each to be a named context")
+		}
+		if seen[snap.ContextName] {
+			return fmt.Errorf("context %q is listed more than once", snap.ContextName)
+		}
+		seen[snap.ContextName] = true
+	}
+	return nil
+}
+
+// servedContext pairs a frozen snapshot with the backend built from its
+// credentials.
+type servedContext[B any] struct {
+	snap    *Snapshot
+	backend B
+}
+
+// contextSet routes each tool call to one served context by name. The first
+// entry is the default used when a call omits context.
+type contextSet[B any] struct {
+	entries []servedContext[B]
+}
+
+func singleContext[B any](snap *Snapshot, backend B) *contextSet[B] {
+	contexts := &contextSet[B]{}
+	contexts.add(snap, backend)
+	return contexts
+}
+
+func (s *contextSet[B]) add(snap *Snapshot, backend B) {
+	s.entries = append(s.entries, servedContext[B]{snap: snap, backend: backend})
+}
+
+func (s *contextSet[B]) snapshots() []*Snapshot {
+	snaps := make([]*Snapshot, 0, len(s.entries))
+	for _, entry := range s.entries {
+		snaps = append(snaps, entry.snap)
+	}
+	return snaps
+}
+
+// resolve returns the snapshot and backend for a call's context argument.
+func (s *contextSet[B]) resolve(name string) (*Snapshot, B, error) {
+	i, err := findContext(s.snapshots(), name)
+	if err != nil {
+		var zero B
+		return nil, zero, err
+	}
+	return s.entries[i].snap, s.entries[i].backend, nil
 }
 
+// findContext returns the index of the named context; empty selects the
+// default.
+func findContext(snaps []*Snapshot, name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" && len(snaps) > 0 {
+		return 0, nil
+	}
+	for i, snap := range snaps {
+		if snap.ContextName == name {
+			return i, nil
+		}
+	}
+	return -1, newToolError(ErrorInvalidInput, fmt.Sprintf("unknown context %q; bkt_get_context lists the served contexts", name), false)
+}
+
+// contextSelector is embedded in every tool's arguments so a call can pick
+// one of the served contexts.
+type contextSelector struct {
+	Context string `json:"context,omitempty" jsonschema:"served context name; omit to use the server's default context (bkt_get_context lists them)"`
+}
+
+func (c contextSelector) contextName() string { return c.Context }
+
 func newServer(snap *Snapshot, version string, backend platformBackend) *mcp.Server {
 	server := mcp.NewServer(&mcp.Implementation{Name: "bkt", Version: version}, nil)
-	registry := newToolRegistry(server)
-	registerBaseTools(registry, snap, backend)
+	contexts := singleContext(snap, backend)
+	registerBaseTools(newToolRegistry(server, contexts.snapshots()), contexts)
 	return server
 }
 
-func registerBaseTools(registry *toolRegistry, snap *Snapshot, backend platformBackend) {
-	registerGetContext(registry, snap)
-	registerRepositoryTools(registry, snap, backend)
-	registerPullRequestListTools(registry, snap, backend)
+func registerBaseTools[B platformBackend](registry *toolRegistry, contexts *contextSet[B]) {
+	registerGetContext(registry, contexts)
+	registerRepositoryTools(registry, contexts)
+	registerPullRequestListTools(registry, contexts)
 }
 
 func newFullServer(snap *Snapshot, version string, backend fullPlatformBackend) *mcp.Server {
 	server := mcp.NewServer(&mcp.Implementation{Name: "bkt", Version: version}, nil)
-	registerFullTools(newToolRegistry(server), snap, backend)
+	contexts := singleContext(snap, backend)
+	registerFullTools(newToolRegistry(server, contexts.snapshots()), contexts)
 	return server
 }
 
-func registerFullTools(registry *toolRegistry, snap *Snapshot, backend fullPlatformBackend) {
-	registerBaseTools(registry, snap, backend)
-	registerPullRequestDetailTools(registry, snap, backend)
+// registerFullTools registers every read tool together with the resources
+// and prompts built on the same backends.
+func registerFullTools[B fullPlatformBackend](registry *toolRegistry, contexts *contextSet[B]) {
+	registerBaseTools(registry, contexts)
+	registerPullRequestDetailTools(registry, contexts)
+	registerRepositoryContentTools(registry, contexts)
+	registerResources(registry, contexts)
+	registerPrompts(registry, contexts)
+}
+
+func newWritableServer(snap *Snapshot, version string, backend writablePlatformBackend, opts Options) *mcp.Server {
+	return newWritableContextServer(singleContext(snap, backend), version, opts)
+}
+
+func newWritableContextServer(contexts *contextSet[writablePlatformBackend], version string, opts Options) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "bkt", Version: version}, nil)
+	registry := newToolRegistry(server, contexts.snapshots())
+	if len(opts.AllowWrites) > 0 {
+		registry.writes = make(map[WriteGate]bool, len(opts.AllowWrites))
+		for _, gate := range opts.AllowWrites {
+			registry.writes[gate] = true
+		}
+		registry.audit = newAuditLog(opts.AuditLog)
+	}
+	registerFullTools(registry, contexts)
+	registerWriteTools(registry, contexts)
+	return server
 }
 
 // capabilities enumerates only discriminating platform features. Universal
 // behavior is implied by the registered tool and is not repeated here.
 func capabilities(snap *Snapshot) []string {
-	if snap != nil && snap.Platform == "dc" {
+	if snap == nil {
+		return []string{}
+	}
+	switch snap.Platform {
+	case "dc":
 		return []string{"my_prs.role.reviewer"}
+	case "cloud":
+		return []string{capabilityPipelines, capabilityIssues}
+	default:
+		return []string{}
 	}
-	return []string{}
 }
 
 // contextInfoSchema is the hand-frozen output contract for bkt_get_context.
 // Inference from the struct would under-specify it (no platform enum, nullable
-// capabilities), so the schema is explicit and golden-tested.
-var contextInfoSchema = &jsonschema.Schema{
-	Type:     "object",
-	Required: []string{"platform", "host_label", "capabilities"},
-	Properties: map[string]*jsonschema.Schema{
-		"platform": {
-			Type:        "string",
-			Enum:        []any{"dc", "cloud"},
-			Description: "the pinned Bitbucket platform",
-		},
-		"host_label": {
-			Type:        "string",
-			Description: "the bkt config host entry this server is pinned to",
-		},
-		"default_scope": {
-			Type:        "string",
-			Description: "default scope (DC project key or Cloud workspace) used when a tool call omits the repository locator",
-		},
-		"default_repo": {
-			Type:        "string",
-			Description: "default repository slug used when a tool call omits the repository locator",
-		},
-		"capabilities": {
-			Type:        "array",
-			Items:       &jsonschema.Schema{Type: "string"},
-			Description: "platform feature identifiers this server supports",
+// capabilities), so the schema is explicit and golden-tested. The top level
+// describes the selected context; contexts lists every served one.
+var contextInfoSchema = func() *jsonschema.Schema {
+	schema := contextEntrySchema()
+	schema.Properties["contexts"] = &jsonschema.Schema{
+		Type:        "array",
+		Items:       contextEntrySchema(),
+		Description: "every context this server serves, in order; pass a name as a tool's context argument to target it",
+	}
+	return schema
+}()
+
+func contextEntrySchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"platform", "host_label", "capabilities"},
+		Properties: map[string]*jsonschema.Schema{
+			"name": {
+				Type:        "string",
+				Description: "the bkt context name; empty only for a single context synthesized from environment variables",
+			},
+			"default": {
+				Type:        "boolean",
+				Description: "true for the context used when a tool call omits context",
+			},
+			"platform": {
+				Type:        "string",
+				Enum:        []any{"dc", "cloud"},
+				Description: "the pinned Bitbucket platform",
+			},
+			"host_label": {
+				Type:        "string",
+				Description: "the bkt config host entry this context is pinned to",
+			},
+			"default_scope": {
+				Type:        "string",
+				Description: "default scope (DC project key or Cloud workspace) used when a tool call omits the repository locator",
+			},
+			"default_repo": {
+				Type:        "string",
+				Description: "default repository slug used when a tool call omits the repository locator",
+			},
+			"capabilities": {
+				Type:        "array",
+				Items:       &jsonschema.Schema{Type: "string"},
+				Description: "platform feature identifiers this context supports",
+			},
 		},
-	},
+	}
 }
 
-type getContextArgs struct{}
+type getContextArgs struct {
+	contextSelector
+}
 
-func registerGetContext(registry *toolRegistry, snap *Snapshot) {
+func registerGetContext[B any](registry *toolRegistry, contexts *contextSet[B]) {
 	addReadOnlyTool(registry, &mcp.Tool{
 		Name: "bkt_get_context",
-		Description: "Describe the Bitbucket target this server is pinned to: " +
-			"platform (dc or cloud), host label, default repository scope/slug, " +
-			"and the capabilities available here. Never returns credentials. " +
-			"For Cloud OAuth, the access token is frozen at startup; restart the server after it expires.",
+		Description: "Describe the Bitbucket contexts this server serves: for the selected context (the default unless context is given), " +
+			"platform (dc or cloud), host label, default repository scope/slug, and the capabilities available there, plus the same for every served context. " +
+			"Never returns credentials.",
 		OutputSchema: contextInfoSchema,
 	}, toolDocumentation{
-		Notes: []string{"Cloud OAuth access tokens are frozen at startup; after expiry, restart the MCP server."},
+		Notes: []string{"Cloud OAuth access tokens are refreshed on expiry and persisted to the bkt credential store, so long sessions need no restart."},
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args getContextArgs) (*mcp.CallToolResult, ContextInfo, error) {
-		return nil, ContextInfo{
-			Platform:     snap.Platform,
-			HostLabel:    snap.HostLabel,
-			DefaultScope: snap.DefaultScope,
-			DefaultRepo:  snap.DefaultRepo,
-			Capabilities: capabilities(snap),
-		}, nil
+		snaps := contexts.snapshots()
+		i, err := findContext(snaps, args.Context)
+		if err != nil {
+			return nil, ContextInfo{}, err
+		}
+		return nil, servedContextInfo(snaps, i), nil
 	})
 }
+
+// servedContextInfo describes snaps[selected] at the top level and lists
+// every served context under it.
+func servedContextInfo(snaps []*Snapshot, selected int) ContextInfo {
+	info := newContextInfo(snaps[selected], selected == 0)
+	info.Contexts = make([]ContextInfo, 0, len(snaps))
+	for i, snap := range snaps {
+		info.Contexts = append(info.Contexts, newContextInfo(snap, i == 0))
+	}
+	return info
+}
+
+func newContextInfo(snap *Snapshot, isDefault bool) ContextInfo {
+	return ContextInfo{
+		Name:         snap.ContextName,
+		Default:      isDefault,
+		Platform:     snap.Platform,
+		HostLabel:    snap.HostLabel,
+		DefaultScope: snap.DefaultScope,
+		DefaultRepo:  snap.DefaultRepo,
+		Capabilities: capabilities(snap),
+	}
+}
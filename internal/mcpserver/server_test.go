@@ -159,8 +159,8 @@ func TestGetContextToolRoundTrip(t *testing.T) {
 		t.Fatalf("tools = %+v, missing bkt_get_context", tools.Tools)
 		return
 	}
-	if !strings.Contains(tool.Description, "Cloud OAuth") || !strings.Contains(tool.Description, "restart") {
-		t.Fatalf("bkt_get_context description must document frozen Cloud OAuth expiry: %q", tool.Description)
+	if strings.Contains(tool.Description, "frozen at startup") {
+		t.Fatalf("bkt_get_context description still claims Cloud OAuth tokens are frozen: %q", tool.Description)
 	}
 	schemaJSON, err := json.Marshal(tool.OutputSchema)
 	if err != nil {
@@ -225,3 +225,94 @@ func containsInsensitive(b []byte, sub string) bool {
 	}
 	return false
 }
+
+func TestServerRoutesEachCallToItsNamedContext(t *testing.T) {
+	work := &fakeRepositoryBackend{getResult: Repository{Slug: "api"}}
+	oss := &fakeRepositoryBackend{getResult: Repository{Slug: "lib"}}
+	contexts := singleContext[platformBackend](&Snapshot{ContextName: "work", Platform: "dc", HostLabel: "dc-host", DefaultScope: "PROJ", DefaultRepo: "api"}, work)
+	contexts.add(&Snapshot{ContextName: "oss", Platform: "cloud", HostLabel: "cloud-host", DefaultScope: "team", DefaultRepo: "lib"}, oss)
+	server := mcp.NewServer(&mcp.Implementation{Name: "bkt", Version: "test"}, nil)
+	registerBaseTools(newToolRegistry(server, contexts.snapshots()), contexts)
+	session := connectPair(t, server)
+
+	var repo Repository
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_get_repository", map[string]any{"context": "oss"}), &repo)
+	if repo.Slug != "lib" || oss.getLocator != (RepositoryRef{Scope: "team", Slug: "lib"}) || work.getCalls != 0 {
+		t.Fatalf("context oss: repo=%+v oss locator=%+v work calls=%d", repo, oss.getLocator, work.getCalls)
+	}
+
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_get_repository", map[string]any{}), &repo)
+	if repo.Slug != "api" || work.getLocator != (RepositoryRef{Scope: "PROJ", Slug: "api"}) || oss.getCalls != 1 {
+		t.Fatalf("default context: repo=%+v work locator=%+v oss calls=%d", repo, work.getLocator, oss.getCalls)
+	}
+
+	got := decodeStructuredToolError(t, callContentTool(t, session, "bkt_get_repository", map[string]any{"context": "personal"}))
+	if got.Code != ErrorInvalidInput || !strings.Contains(got.Message, "bkt_get_context") {
+		t.Fatalf("unknown context error = %+v", got)
+	}
+	if work.getCalls != 1 || oss.getCalls != 1 {
+		t.Fatalf("unknown context reached a backend: work=%d oss=%d", work.getCalls, oss.getCalls)
+	}
+
+	var info ContextInfo
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_get_context", map[string]any{"context": "oss"}), &info)
+	if info.Name != "oss" || info.Default || info.Platform != "cloud" || len(info.Capabilities) != 2 {
+		t.Fatalf("selected context = %+v", info)
+	}
+	if len(info.Contexts) != 2 || info.Contexts[0].Name != "work" || !info.Contexts[0].Default || info.Contexts[1].Name != "oss" || info.Contexts[1].Contexts != nil {
+		t.Fatalf("served contexts = %+v", info.Contexts)
+	}
+}
+
+func TestNewRejectsAmbiguousContextSets(t *testing.T) {
+	dc := func(name string) *Snapshot {
+		return &Snapshot{ContextName: name, Platform: "dc", HostLabel: "dc", Host: config.Host{Kind: "dc", BaseURL: "https://bitbucket.example.com", Token: "t"}}
+	}
+	for name, snaps := range map[string][]*Snapshot{
+		"empty":     nil,
+		"duplicate": {dc("work"), dc("work")},
+		"unnamed":   {dc("work"), dc("")},
+	} {
+		if _, err := New(snaps, "test", Options{}); err == nil {
+			t.Fatalf("%s: New accepted %d contexts", name, len(snaps))
+		}
+	}
+	if _, err := New([]*Snapshot{dc("work"), dc("oss")}, "test", Options{}); err != nil {
+		t.Fatalf("New: %v", err)
+	}
+}
+
+func TestResolveSnapshotsServesAllContextsActiveFirst(t *testing.T) {
+	cfg := dcConfig()
+	cfg.ActiveContext = "work"
+	cfg.Contexts["archive"] = &config.Context{Host: "dc-host", ProjectKey: "OLD"}
+	cfg.Contexts["oss"] = &config.Context{Host: "cloud-host", Workspace: "team"}
+	cfg.Hosts["cloud-host"] = &config.Host{Kind: "cloud", BaseURL: "https://api.bitbucket.org/2.0", Username: "u", Token: "t"}
+
+	snaps, err := ResolveSnapshots(testFactory(cfg), nil, true)
+	if err != nil {
+		t.Fatalf("ResolveSnapshots: %v", err)
+	}
+	var names []string
+	for _, snap := range snaps {
+		names = append(names, snap.ContextName)
+	}
+	if strings.Join(names, ",") != "work,archive,oss" {
+		t.Fatalf("served contexts = %v, want the active context first then the rest by name", names)
+	}
+	if snaps[2].Platform != "cloud" || snaps[2].DefaultScope != "team" {
+		t.Fatalf("oss snapshot = %+v", snaps[2])
+	}
+
+	snaps, err = ResolveSnapshots(testFactory(cfg), []string{"oss", "work", "oss"}, false)
+	if err != nil {
+		t.Fatalf("ResolveSnapshots: %v", err)
+	}
+	if len(snaps) != 2 || snaps[0].ContextName != "oss" || snaps[1].ContextName != "work" {
+		t.Fatalf("named contexts = %+v, want oss then work without duplicates", snaps)
+	}
+
+	if _, err := ResolveSnapshots(testFactory(cfg), []string{"missing"}, false); err == nil || !strings.Contains(err.Error(), `context "missing"`) {
+		t.Fatalf("missing context err = %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 type fakeC2BBackend struct {
 	fakeC2ABackend
+	fakeContentBackend
 
 	getPRLocator RepositoryRef
 	getPRID      int
@@ -88,8 +89,8 @@ func TestPullRequestDetailToolsRoundTrip(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListTools: %v", err)
 	}
-	if len(tools.Tools) != 9 {
-		t.Fatalf("tool count = %d, want 9 after C.2b registration", len(tools.Tools))
+	if len(tools.Tools) != 15 {
+		t.Fatalf("tool count = %d, want 15 after repository content registration", len(tools.Tools))
 	}
 	c2bTools := map[string]bool{
 		"bkt_get_pull_request":           false,
@@ -0,0 +1,164 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type addPullRequestCommentArgs struct {
+	ID       int                `json:"id,omitempty" jsonschema:"required positive pull request id; omission is returned as invalid_input"`
+	Locator  *RepositoryLocator `json:"locator,omitempty" jsonschema:"repository locator; omit to use the frozen context default"`
+	Body     string             `json:"body,omitempty" jsonschema:"required comment text (markdown)"`
+	ParentID int                `json:"parent_id,omitempty" jsonschema:"comment id to reply to; omit for a new top-level comment"`
+	Path     string             `json:"path,omitempty" jsonschema:"file path for an inline comment"`
+	Line     int                `json:"line,omitempty" jsonschema:"line on the new side of the diff for an inline comment; requires path"`
+	contextSelector
+}
+
+type createPullRequestArgs struct {
+	Locator      *RepositoryLocator `json:"locator,omitempty" jsonschema:"repository locator; omit to use the frozen context default"`
+	Title        string             `json:"title,omitempty" jsonschema:"required pull request title"`
+	SourceBranch string             `json:"source_branch,omitempty" jsonschema:"required branch containing the changes"`
+	TargetBranch string             `json:"target_branch,omitempty" jsonschema:"required branch to merge into"`
+	Description  string             `json:"description,omitempty" jsonschema:"pull request description (markdown)"`
+	Reviewers    []string           `json:"reviewers,omitempty" jsonschema:"reviewer usernames (Data Center) or account UUIDs/nicknames (Cloud)"`
+	Draft        bool               `json:"draft,omitempty" jsonschema:"create the pull request as a draft"`
+	contextSelector
+}
+
+type resolvePullRequestThreadArgs struct {
+	ID        int                `json:"id,omitempty" jsonschema:"required positive pull request id; omission is returned as invalid_input"`
+	Locator   *RepositoryLocator `json:"locator,omitempty" jsonschema:"repository locator; omit to use the frozen context default"`
+	CommentID int                `json:"comment_id,omitempty" jsonschema:"required id of the thread's top-level comment"`
+	Reopen    bool               `json:"reopen,omitempty" jsonschema:"reopen a resolved thread instead of resolving it"`
+	contextSelector
+}
+
+// registerWriteTools registers the opt-in write tools. Each one is served only
+// when its gate was passed to --allow-writes, and every call is audited.
+func registerWriteTools[B pullRequestWriteBackend](registry *toolRegistry, contexts *contextSet[B]) {
+	addWriteTool(registry, WriteGateComment, writeHints{Destructive: false, Idempotent: false}, &mcp.Tool{
+		Name: "bkt_add_pull_request_comment",
+		Description: "Add a comment to a pull request as the authenticated user: top-level, a reply (parent_id), or inline (path and line). " +
+			"Not idempotent: repeating the call posts another comment.",
+	}, toolDocumentation{
+		Errors: standardReadErrors(),
+		Notes:  []string{"Served only with `bkt mcp serve --allow-writes=comment`."},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, args addPullRequestCommentArgs) (*mcp.CallToolResult, WriteResult, error) {
+		snap, backend, err := contexts.resolve(args.Context)
+		if err != nil {
+			return nil, WriteResult{}, err
+		}
+		locator, err := resolvePullRequestTarget(snap, args.ID, args.Locator)
+		if err != nil {
+			return nil, WriteResult{}, err
+		}
+		input := commentInput{
+			Body:     args.Body,
+			ParentID: args.ParentID,
+			Path:     strings.TrimSpace(args.Path),
+			Line:     args.Line,
+		}
+		switch {
+		case strings.TrimSpace(input.Body) == "":
+			return nil, WriteResult{}, newToolError(ErrorInvalidInput, "body is required", false)
+		case input.ParentID < 0 || input.Line < 0:
+			return nil, WriteResult{}, newToolError(ErrorInvalidInput, "parent_id and line must be positive when supplied", false)
+		case input.Line > 0 && input.Path == "":
+			return nil, WriteResult{}, newToolError(ErrorInvalidInput, "line requires path", false)
+		}
+		if err := backend.addPullRequestComment(ctx, locator, args.ID, input); err != nil {
+			return nil, WriteResult{}, mapToolError(err)
+		}
+		return nil, WriteResult{Action: "commented", Repo: locator, PullRequestID: args.ID}, nil
+	})
+
+	addWriteTool(registry, WriteGateApprove, writeHints{Destructive: false, Idempotent: true}, &mcp.Tool{
+		Name:        "bkt_approve_pull_request",
+		Description: "Approve a pull request as the authenticated user. Idempotent: approving an already-approved pull request has no further effect.",
+	}, toolDocumentation{
+		Errors: standardReadErrors(),
+		Notes:  []string{"Served only with `bkt mcp serve --allow-writes=approve`."},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, args pullRequestIDArgs) (*mcp.CallToolResult, WriteResult, error) {
+		snap, backend, err := contexts.resolve(args.Context)
+		if err != nil {
+			return nil, WriteResult{}, err
+		}
+		locator, err := resolvePullRequestTarget(snap, args.ID, args.Locator)
+		if err != nil {
+			return nil, WriteResult{}, err
+		}
+		if err := backend.approvePullRequest(ctx, locator, args.ID); err != nil {
+			return nil, WriteResult{}, mapToolError(err)
+		}
+		return nil, WriteResult{Action: "approved", Repo: locator, PullRequestID: args.ID}, nil
+	})
+
+	addWriteTool(registry, WriteGateCreatePR, writeHints{Destructive: false, Idempotent: false}, &mcp.Tool{
+		Name: "bkt_create_pull_request",
+		Description: "Open a pull request from source_branch into target_branch in one repository. " +
+			"Not idempotent: Bitbucket rejects a second open pull request for the same branches, which surfaces as upstream_error.",
+	}, toolDocumentation{
+		Errors: standardReadErrors(),
+		Notes:  []string{"Served only with `bkt mcp serve --allow-writes=create-pr`."},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, args createPullRequestArgs) (*mcp.CallToolResult, PullRequest, error) {
+		snap, backend, err := contexts.resolve(args.Context)
+		if err != nil {
+			return nil, PullRequest{}, err
+		}
+		locator, err := resolveLocator(snap, args.Locator)
+		if err != nil {
+			return nil, PullRequest{}, err
+		}
+		input := pullRequestInput{
+			Title:        strings.TrimSpace(args.Title),
+			Description:  args.Description,
+			SourceBranch: strings.TrimSpace(args.SourceBranch),
+			TargetBranch: strings.TrimSpace(args.TargetBranch),
+			Reviewers:    args.Reviewers,
+			Draft:        args.Draft,
+		}
+		if input.Title == "" || input.SourceBranch == "" || input.TargetBranch == "" {
+			return nil, PullRequest{}, newToolError(ErrorInvalidInput, "title, source_branch, and target_branch are required", false)
+		}
+		if input.SourceBranch == input.TargetBranch {
+			return nil, PullRequest{}, newToolError(ErrorInvalidInput, "source_branch and target_branch must differ", false)
+		}
+		result, err := backend.createPullRequest(ctx, locator, input)
+		if err != nil {
+			return nil, PullRequest{}, mapToolError(err)
+		}
+		return nil, result, nil
+	})
+
+	addWriteTool(registry, WriteGateResolve, writeHints{Destructive: false, Idempotent: true}, &mcp.Tool{
+		Name: "bkt_resolve_pull_request_thread",
+		Description: "Resolve, or with reopen=true reopen, the comment thread rooted at comment_id. " +
+			"Idempotent: a thread already in the requested state is left unchanged.",
+	}, toolDocumentation{
+		Errors: standardReadErrors(),
+		Notes:  []string{"Served only with `bkt mcp serve --allow-writes=resolve`."},
+	}, func(ctx context.Context, _ *mcp.CallToolRequest, args resolvePullRequestThreadArgs) (*mcp.CallToolResult, WriteResult, error) {
+		snap, backend, err := contexts.resolve(args.Context)
+		if err != nil {
+			return nil, WriteResult{}, err
+		}
+		locator, err := resolvePullRequestTarget(snap, args.ID, args.Locator)
+		if err != nil {
+			return nil, WriteResult{}, err
+		}
+		if args.CommentID <= 0 {
+			return nil, WriteResult{}, newToolError(ErrorInvalidInput, "comment_id must be a positive integer", false)
+		}
+		if err := backend.setPullRequestThreadResolved(ctx, locator, args.ID, args.CommentID, !args.Reopen); err != nil {
+			return nil, WriteResult{}, mapToolError(err)
+		}
+		action := "resolved"
+		if args.Reopen {
+			action = "reopened"
+		}
+		return nil, WriteResult{Action: action, Repo: locator, PullRequestID: args.ID, CommentID: intPointer(args.CommentID)}, nil
+	})
+}
@@ -0,0 +1,295 @@
+package mcpserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type fakeWriteBackend struct {
+	fakeC2BBackend
+
+	commentLocator RepositoryRef
+	commentInput   commentInput
+	commentCalls   int
+
+	approveID    int
+	approveCalls int
+	approveErr   error
+
+	createInput  pullRequestInput
+	createResult PullRequest
+
+	resolveCommentID int
+	resolveResolved  bool
+	resolveCalls     int
+}
+
+func (f *fakeWriteBackend) addPullRequestComment(_ context.Context, locator RepositoryRef, _ int, input commentInput) error {
+	f.commentCalls++
+	f.commentLocator = locator
+	f.commentInput = input
+	return nil
+}
+
+func (f *fakeWriteBackend) approvePullRequest(_ context.Context, _ RepositoryRef, id int) error {
+	f.approveCalls++
+	f.approveID = id
+	return f.approveErr
+}
+
+func (f *fakeWriteBackend) createPullRequest(_ context.Context, _ RepositoryRef, input pullRequestInput) (PullRequest, error) {
+	f.createInput = input
+	return f.createResult, nil
+}
+
+func (f *fakeWriteBackend) setPullRequestThreadResolved(_ context.Context, _ RepositoryRef, _, commentID int, resolved bool) error {
+	f.resolveCalls++
+	f.resolveCommentID = commentID
+	f.resolveResolved = resolved
+	return nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func writeTestSnapshot() *Snapshot {
+	return &Snapshot{ContextName: "work", Platform: "dc", HostLabel: "dc", DefaultScope: "PROJ", DefaultRepo: "api"}
+}
+
+func auditLines(t *testing.T, buf *bytes.Buffer) []auditRecord {
+	t.Helper()
+	var records []auditRecord
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec auditRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("audit line is not JSON: %v\n%s", err, line)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestWriteToolsAreNotServedWithoutOptIn(t *testing.T) {
+	session := connectPair(t, newWritableServer(writeTestSnapshot(), "test", &fakeWriteBackend{}, Options{}))
+
+	tools, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	for _, tool := range tools.Tools {
+		if tool.Annotations == nil || !tool.Annotations.ReadOnlyHint {
+			t.Fatalf("tool %q served without --allow-writes", tool.Name)
+		}
+	}
+}
+
+func TestWriteToolsServeOnlyEnabledGatesWithHonestHints(t *testing.T) {
+	var audit bytes.Buffer
+	session := connectPair(t, newWritableServer(writeTestSnapshot(), "test", &fakeWriteBackend{}, Options{
+		AllowWrites: []WriteGate{WriteGateComment, WriteGateApprove},
+		AuditLog:    &audit,
+	}))
+
+	tools, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	found := map[string]*mcp.ToolAnnotations{}
+	for _, tool := range tools.Tools {
+		found[tool.Name] = tool.Annotations
+	}
+	for _, name := range []string{"bkt_create_pull_request", "bkt_resolve_pull_request_thread"} {
+		if _, ok := found[name]; ok {
+			t.Fatalf("tool %q served although its gate is disabled", name)
+		}
+	}
+	comment := found["bkt_add_pull_request_comment"]
+	if comment == nil || comment.ReadOnlyHint || comment.DestructiveHint == nil || *comment.DestructiveHint || comment.IdempotentHint {
+		t.Fatalf("comment annotations = %+v, want non-destructive, non-idempotent write", comment)
+	}
+	approve := found["bkt_approve_pull_request"]
+	if approve == nil || approve.ReadOnlyHint || approve.DestructiveHint == nil || *approve.DestructiveHint || !approve.IdempotentHint {
+		t.Fatalf("approve annotations = %+v, want non-destructive, idempotent write", approve)
+	}
+}
+
+func TestWriteToolCallsAreAudited(t *testing.T) {
+	var audit bytes.Buffer
+	backend := &fakeWriteBackend{}
+	session := connectPair(t, newWritableServer(writeTestSnapshot(), "test", backend, Options{
+		AllowWrites: WriteGates(),
+		AuditLog:    &audit,
+	}))
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "bkt_add_pull_request_comment",
+		Arguments: map[string]any{"id": 4, "body": "LGTM", "path": "main.go", "line": 12},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	var result WriteResult
+	decodeStructuredContent(t, res, &result)
+	if result.Action != "commented" || result.PullRequestID != 4 || result.Repo != (RepositoryRef{Scope: "PROJ", Slug: "api"}) {
+		t.Fatalf("result = %+v", result)
+	}
+	if backend.commentInput != (commentInput{Body: "LGTM", Path: "main.go", Line: 12}) {
+		t.Fatalf("comment input = %+v", backend.commentInput)
+	}
+
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "bkt_add_pull_request_comment",
+		Arguments: map[string]any{"id": 4, "body": "x", "line": 3},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := decodeStructuredToolError(t, res); got.Code != ErrorInvalidInput {
+		t.Fatalf("error = %+v, want invalid_input", got)
+	}
+
+	records := auditLines(t, &audit)
+	if len(records) != 4 {
+		t.Fatalf("audit records = %d, want attempt+result for two calls:\n%s", len(records), audit.String())
+	}
+	first, second := records[0], records[1]
+	if first.Event != "attempt" || first.Tool != "bkt_add_pull_request_comment" || first.Gate != WriteGateComment ||
+		first.Context != "work" || first.HostLabel != "dc" || !strings.Contains(string(first.Arguments), `"body":"LGTM"`) {
+		t.Fatalf("attempt record = %+v", first)
+	}
+	if second.Event != "ok" || second.ErrorCode != "" {
+		t.Fatalf("result record = %+v", second)
+	}
+	if records[3].Event != "error" || records[3].ErrorCode != ErrorInvalidInput {
+		t.Fatalf("failed call record = %+v", records[3])
+	}
+	if backend.commentCalls != 1 {
+		t.Fatalf("backend comment calls = %d, want 1", backend.commentCalls)
+	}
+}
+
+func TestWriteToolRefusedWhenAuditFails(t *testing.T) {
+	backend := &fakeWriteBackend{}
+	session := connectPair(t, newWritableServer(writeTestSnapshot(), "test", backend, Options{
+		AllowWrites: []WriteGate{WriteGateApprove},
+		AuditLog:    failingWriter{},
+	}))
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "bkt_approve_pull_request",
+		Arguments: map[string]any{"id": 2},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := decodeStructuredToolError(t, res); got.Code != ErrorUpstream || !strings.Contains(got.Message, "audit log") {
+		t.Fatalf("error = %+v", got)
+	}
+	if backend.approveCalls != 0 {
+		t.Fatalf("approve reached the backend without an audit record")
+	}
+}
+
+func TestWriteToolAuditNamesTheRoutedContext(t *testing.T) {
+	var audit bytes.Buffer
+	work, oss := &fakeWriteBackend{}, &fakeWriteBackend{}
+	contexts := singleContext[writablePlatformBackend](writeTestSnapshot(), work)
+	contexts.add(&Snapshot{ContextName: "oss", Platform: "cloud", HostLabel: "cloud", DefaultScope: "team", DefaultRepo: "lib"}, oss)
+	session := connectPair(t, newWritableContextServer(contexts, "test", Options{
+		AllowWrites: []WriteGate{WriteGateApprove},
+		AuditLog:    &audit,
+	}))
+
+	var result WriteResult
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_approve_pull_request", map[string]any{"id": 2, "context": "oss"}), &result)
+	if result.Repo != (RepositoryRef{Scope: "team", Slug: "lib"}) || oss.approveCalls != 1 || work.approveCalls != 0 {
+		t.Fatalf("result = %+v, approvals work=%d oss=%d", result, work.approveCalls, oss.approveCalls)
+	}
+
+	got := decodeStructuredToolError(t, callContentTool(t, session, "bkt_approve_pull_request", map[string]any{"id": 2, "context": "personal"}))
+	if got.Code != ErrorInvalidInput {
+		t.Fatalf("unknown context error = %+v", got)
+	}
+
+	records := auditLines(t, &audit)
+	if len(records) != 2 {
+		t.Fatalf("audit records = %d, want attempt+result for the routed call only:\n%s", len(records), audit.String())
+	}
+	for _, rec := range records {
+		if rec.Context != "oss" || rec.Platform != "cloud" || rec.HostLabel != "cloud" {
+			t.Fatalf("audit record = %+v, want the oss context", rec)
+		}
+	}
+}
+
+func TestResolveAndCreateToolsMapArguments(t *testing.T) {
+	var audit bytes.Buffer
+	backend := &fakeWriteBackend{createResult: PullRequest{ID: 31, Title: "Add thing", Reviewers: []Reviewer{}}}
+	session := connectPair(t, newWritableServer(writeTestSnapshot(), "test", backend, Options{
+		AllowWrites: []WriteGate{WriteGateResolve, WriteGateCreatePR},
+		AuditLog:    &audit,
+	}))
+
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "bkt_resolve_pull_request_thread",
+		Arguments: map[string]any{"id": 5, "comment_id": 77, "reopen": true},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	var result WriteResult
+	decodeStructuredContent(t, res, &result)
+	if result.Action != "reopened" || result.CommentID == nil || *result.CommentID != 77 {
+		t.Fatalf("result = %+v", result)
+	}
+	if backend.resolveCommentID != 77 || backend.resolveResolved {
+		t.Fatalf("resolve args = comment:%d resolved:%t", backend.resolveCommentID, backend.resolveResolved)
+	}
+
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "bkt_create_pull_request",
+		Arguments: map[string]any{"title": " Add thing ", "source_branch": "feature", "target_branch": "main", "reviewers": []string{"alice"}},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	var pr PullRequest
+	decodeStructuredContent(t, res, &pr)
+	if pr.ID != 31 || backend.createInput.Title != "Add thing" || backend.createInput.Reviewers[0] != "alice" {
+		t.Fatalf("pr = %+v input = %+v", pr, backend.createInput)
+	}
+
+	res, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "bkt_create_pull_request",
+		Arguments: map[string]any{"title": "x", "source_branch": "main", "target_branch": "main"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	if got := decodeStructuredToolError(t, res); got.Code != ErrorInvalidInput {
+		t.Fatalf("error = %+v, want invalid_input", got)
+	}
+}
+
+func TestParseWriteGates(t *testing.T) {
+	gates, err := ParseWriteGates([]string{"Comment", "approve", "comment", " "})
+	if err != nil {
+		t.Fatalf("ParseWriteGates: %v", err)
+	}
+	if len(gates) != 2 || gates[0] != WriteGateComment || gates[1] != WriteGateApprove {
+		t.Fatalf("gates = %v", gates)
+	}
+	if _, err := ParseWriteGates([]string{"merge"}); err == nil || !strings.Contains(err.Error(), "create-pr") {
+		t.Fatalf("expected unknown gate error listing valid gates, got %v", err)
+	}
+}
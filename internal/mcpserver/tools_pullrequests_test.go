@@ -245,13 +245,16 @@ func TestListMyPullRequestsToolRequiresRoleAndCloudScope(t *testing.T) {
 	}
 }
 
-func TestCapabilitiesAdvertiseOnlyCrossRepoReviewerDifference(t *testing.T) {
+func TestCapabilitiesAdvertiseOnlyPlatformDifferences(t *testing.T) {
 	dc := capabilities(&Snapshot{Platform: "dc"})
 	if len(dc) != 1 || dc[0] != "my_prs.role.reviewer" {
 		t.Fatalf("DC capabilities = %v", dc)
 	}
 	cloud := capabilities(&Snapshot{Platform: "cloud"})
-	if cloud == nil || len(cloud) != 0 {
-		t.Fatalf("Cloud capabilities = %#v, want non-nil empty", cloud)
+	if len(cloud) != 2 || cloud[0] != "pipelines" || cloud[1] != "issues" {
+		t.Fatalf("Cloud capabilities = %#v, want pipelines and issues", cloud)
+	}
+	if unknown := capabilities(nil); unknown == nil || len(unknown) != 0 {
+		t.Fatalf("nil snapshot capabilities = %#v, want non-nil empty", unknown)
 	}
 }
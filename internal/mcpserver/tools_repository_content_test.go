@@ -0,0 +1,224 @@
+package mcpserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type fakeContentBackend struct {
+	pipelinesLocator RepositoryRef
+	pipelinesLimit   int
+	pipelinesItems   []Pipeline
+	pipelinesMore    bool
+	pipelinesCalls   int
+
+	stepLogPipeline string
+	stepLogStep     string
+	stepLogResult   PipelineStepLog
+	stepLogCalls    int
+
+	branchesFilter string
+	branchesLimit  int
+	branchesItems  []Branch
+	branchesMore   bool
+
+	commitDiffFrom   string
+	commitDiffTo     string
+	commitDiffResult Diff
+	commitDiffCalls  int
+
+	issuesState string
+	issuesItems []Issue
+	issuesCalls int
+
+	issueID     int
+	issueResult Issue
+	issueCalls  int
+
+	fileLocator RepositoryRef
+	filePath    string
+	fileRef     string
+	fileResult  BoundedText
+	fileErr     error
+}
+
+func (f *fakeContentBackend) listPipelines(_ context.Context, locator RepositoryRef, limit int) ([]Pipeline, bool, error) {
+	f.pipelinesCalls++
+	f.pipelinesLocator = locator
+	f.pipelinesLimit = limit
+	return f.pipelinesItems, f.pipelinesMore, nil
+}
+
+func (f *fakeContentBackend) getPipelineStepLog(_ context.Context, _ RepositoryRef, pipeline, step string) (PipelineStepLog, error) {
+	f.stepLogCalls++
+	f.stepLogPipeline = pipeline
+	f.stepLogStep = step
+	return f.stepLogResult, nil
+}
+
+func (f *fakeContentBackend) listBranches(_ context.Context, _ RepositoryRef, filter string, limit int) ([]Branch, bool, error) {
+	f.branchesFilter = filter
+	f.branchesLimit = limit
+	return f.branchesItems, f.branchesMore, nil
+}
+
+func (f *fakeContentBackend) getCommitDiff(_ context.Context, _ RepositoryRef, from, to string) (Diff, error) {
+	f.commitDiffCalls++
+	f.commitDiffFrom = from
+	f.commitDiffTo = to
+	return f.commitDiffResult, nil
+}
+
+func (f *fakeContentBackend) listIssues(_ context.Context, _ RepositoryRef, state string, _ int) ([]Issue, bool, error) {
+	f.issuesCalls++
+	f.issuesState = state
+	return f.issuesItems, false, nil
+}
+
+func (f *fakeContentBackend) getIssue(_ context.Context, _ RepositoryRef, id int) (Issue, error) {
+	f.issueCalls++
+	f.issueID = id
+	return f.issueResult, nil
+}
+
+func (f *fakeContentBackend) getFileContent(_ context.Context, locator RepositoryRef, path, ref string) (BoundedText, error) {
+	f.fileLocator = locator
+	f.filePath = path
+	f.fileRef = ref
+	return f.fileResult, f.fileErr
+}
+
+func callContentTool(t *testing.T, session *mcp.ClientSession, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return res
+}
+
+func TestRepositoryContentToolsRoundTripOnCloud(t *testing.T) {
+	content := boundBitbucketText("steps to reproduce", IssueContentLimit)
+	backend := &fakeC2BBackend{fakeContentBackend: fakeContentBackend{
+		pipelinesItems: []Pipeline{{UUID: "{p1}", BuildNumber: 42, State: CheckFailed, Ref: "main"}},
+		pipelinesMore:  true,
+		stepLogResult: PipelineStepLog{
+			Pipeline: Pipeline{UUID: "{p1}", BuildNumber: 42, State: CheckFailed},
+			Step:     PipelineStep{UUID: "{s1}", Name: "test", State: CheckFailed},
+			Steps:    []PipelineStep{{UUID: "{s1}", Name: "test", State: CheckFailed}},
+			Log:      boundBitbucketText("FAIL: TestThing", PipelineLogLimit),
+		},
+		branchesItems:    []Branch{{Name: "main", LatestCommit: "abc", IsDefault: true}},
+		commitDiffResult: adaptDiff("diff --git a/a b/a\n", "feature", "main"),
+		issuesItems:      []Issue{{ID: 3, Title: "Crash", State: "open"}},
+		issueResult:      Issue{ID: 3, Title: "Crash", State: "open", Content: &content},
+	}}
+	snap := &Snapshot{Platform: "cloud", HostLabel: "cloud", DefaultScope: "team", DefaultRepo: "api"}
+	session := connectPair(t, newFullServer(snap, "test", backend))
+
+	var pipelines ListEnvelope[Pipeline]
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_list_pipelines", map[string]any{"limit": 5}), &pipelines)
+	if pipelines.Count != 1 || !pipelines.Truncated || pipelines.Items[0].BuildNumber != 42 || pipelines.Items[0].State != CheckFailed {
+		t.Fatalf("pipelines = %+v", pipelines)
+	}
+	if backend.pipelinesLimit != 5 || backend.pipelinesLocator != (RepositoryRef{Scope: "team", Slug: "api"}) {
+		t.Fatalf("pipeline args = locator:%+v limit:%d", backend.pipelinesLocator, backend.pipelinesLimit)
+	}
+
+	var stepLog PipelineStepLog
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_get_pipeline_step_log", map[string]any{"pipeline": " 42 "}), &stepLog)
+	if stepLog.Step.Name != "test" || stepLog.Log.Text != "FAIL: TestThing" || stepLog.Log.Provenance.Trust != ProvenanceTrustUntrusted {
+		t.Fatalf("step log = %+v", stepLog)
+	}
+	if backend.stepLogPipeline != "42" || backend.stepLogStep != "" {
+		t.Fatalf("step log args = pipeline:%q step:%q", backend.stepLogPipeline, backend.stepLogStep)
+	}
+
+	var branches ListEnvelope[Branch]
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_list_branches", map[string]any{"filter": " feat "}), &branches)
+	if branches.Count != 1 || branches.Truncated || !branches.Items[0].IsDefault {
+		t.Fatalf("branches = %+v", branches)
+	}
+	if backend.branchesFilter != "feat" || backend.branchesLimit != DefaultListLimit {
+		t.Fatalf("branch args = filter:%q limit:%d", backend.branchesFilter, backend.branchesLimit)
+	}
+
+	var diff Diff
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_get_commit_diff", map[string]any{"from": "feature", "to": "main"}), &diff)
+	if diff.Content.Text != "diff --git a/a b/a\n" || backend.commitDiffFrom != "feature" || backend.commitDiffTo != "main" {
+		t.Fatalf("diff = %+v from:%q to:%q", diff, backend.commitDiffFrom, backend.commitDiffTo)
+	}
+
+	var issues ListEnvelope[Issue]
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_list_issues", map[string]any{"state": "ON  Hold"}), &issues)
+	if issues.Count != 1 || issues.Items[0].Content != nil || backend.issuesState != "on hold" {
+		t.Fatalf("issues = %+v state:%q", issues, backend.issuesState)
+	}
+
+	var issue Issue
+	decodeStructuredContent(t, callContentTool(t, session, "bkt_get_issue", map[string]any{"id": 3}), &issue)
+	if issue.Content == nil || issue.Content.Text != "steps to reproduce" || backend.issueID != 3 {
+		t.Fatalf("issue = %+v", issue)
+	}
+}
+
+func TestRepositoryContentToolsReportCloudOnlyFeaturesOnDataCenter(t *testing.T) {
+	backend := &fakeC2BBackend{}
+	session := connectPair(t, newFullServer(
+		&Snapshot{Platform: "dc", HostLabel: "dc", DefaultScope: "PROJ", DefaultRepo: "api"},
+		"test",
+		backend,
+	))
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{tool: "bkt_list_pipelines", args: map[string]any{}},
+		{tool: "bkt_get_pipeline_step_log", args: map[string]any{"pipeline": "1"}},
+		{tool: "bkt_list_issues", args: map[string]any{}},
+		{tool: "bkt_get_issue", args: map[string]any{"id": 1}},
+	} {
+		got := decodeStructuredToolError(t, callContentTool(t, session, tc.tool, tc.args))
+		if got.Code != ErrorUnsupportedOnPlatform || got.Retryable {
+			t.Fatalf("%s error = %+v", tc.tool, got)
+		}
+	}
+	if backend.pipelinesCalls != 0 || backend.stepLogCalls != 0 || backend.issuesCalls != 0 || backend.issueCalls != 0 {
+		t.Fatalf("cloud-only tools reached the Data Center backend")
+	}
+}
+
+func TestRepositoryContentToolsRejectInvalidInputBeforeBackend(t *testing.T) {
+	backend := &fakeC2BBackend{}
+	session := connectPair(t, newFullServer(
+		&Snapshot{Platform: "cloud", HostLabel: "cloud", DefaultScope: "team", DefaultRepo: "api"},
+		"test",
+		backend,
+	))
+
+	tests := []struct {
+		name string
+		tool string
+		args map[string]any
+	}{
+		{name: "step log missing pipeline", tool: "bkt_get_pipeline_step_log", args: map[string]any{"pipeline": " "}},
+		{name: "diff missing to", tool: "bkt_get_commit_diff", args: map[string]any{"from": "feature"}},
+		{name: "diff dotted ref on cloud", tool: "bkt_get_commit_diff", args: map[string]any{"from": "a..b", "to": "main"}},
+		{name: "issues unknown state", tool: "bkt_list_issues", args: map[string]any{"state": "stale"}},
+		{name: "issue zero id", tool: "bkt_get_issue", args: map[string]any{"id": 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeStructuredToolError(t, callContentTool(t, session, tt.tool, tt.args))
+			if got.Code != ErrorInvalidInput || got.Retryable {
+				t.Fatalf("error = %+v", got)
+			}
+		})
+	}
+	if backend.stepLogCalls != 0 || backend.commitDiffCalls != 0 || backend.issuesCalls != 0 || backend.issueCalls != 0 {
+		t.Fatalf("invalid input reached the backend")
+	}
+}
@@ -0,0 +1,205 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	envTokenCommandTimeout = "BKT_TOKEN_COMMAND_TIMEOUT"
+	envTokenCommandTTL     = "BKT_TOKEN_COMMAND_TTL"
+	envHTTPDebug           = "BKT_HTTP_DEBUG"
+)
+
+const (
+	tokenCommandTimeoutDefault = 10 * time.Second
+	tokenCommandTTLDefault     = 5 * time.Minute
+
+	// tokenCommandStderrLimit caps how much command stderr is echoed back in
+	// errors so a chatty helper cannot flood the terminal.
+	tokenCommandStderrLimit = 512
+
+	// tokenCommandWaitDelay bounds how long Wait keeps draining output after
+	// the timeout kills the shell, so a background child that inherited
+	// stdout cannot keep the call blocked.
+	tokenCommandWaitDelay = 500 * time.Millisecond
+)
+
+// ErrTokenCommandTimeout indicates an external token command did not finish
+// within the configured timeout.
+var ErrTokenCommandTimeout = errors.New("token command timed out")
+
+type commandCacheEntry struct {
+	token     string
+	expiresAt time.Time
+}
+
+var (
+	commandCacheMu sync.Mutex
+	commandCache   = map[string]commandCacheEntry{}
+)
+
+// runShell executes a command line through the platform shell. It is a
+// package-level variable so tests can stub the process boundary.
+var runShell = func(ctx context.Context, command string) ([]byte, []byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+	cmd.WaitDelay = tokenCommandWaitDelay
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+// TokenFromCommand returns the token printed by an external command such as
+// `op read`, `vault kv get -field=token`, or `pass show`. Successful results
+// are cached in-process for BKT_TOKEN_COMMAND_TTL (default 5m) so that
+// long-running consumers do not shell out on every request.
+func TokenFromCommand(ctx context.Context, command string) (string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "", errors.New("token command is empty")
+	}
+
+	commandCacheMu.Lock()
+	entry, ok := commandCache[command]
+	commandCacheMu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		debugTokenCommand("%s (cached)", describeCommand(command))
+		return entry.token, nil
+	}
+
+	token, err := RunTokenCommand(ctx, command)
+	if err != nil {
+		return "", err
+	}
+
+	commandCacheMu.Lock()
+	commandCache[command] = commandCacheEntry{token: token, expiresAt: time.Now().Add(tokenCommandTTL())}
+	commandCacheMu.Unlock()
+
+	return token, nil
+}
+
+// RunTokenCommand executes command without consulting the cache. The first
+// non-empty line of stdout is the token; stderr is only surfaced (redacted)
+// when the command fails.
+func RunTokenCommand(ctx context.Context, command string) (string, error) {
+	command = strings.TrimSpace(command)
+	if command == "" {
+		return "", errors.New("token command is empty")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timeout := tokenCommandTimeout()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	debugTokenCommand("%s", describeCommand(command))
+
+	stdout, stderr, err := runShell(ctx, command)
+	token := firstLine(string(stdout))
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("%w after %s (raise %s if the helper needs an interactive unlock)", ErrTokenCommandTimeout, timeout, envTokenCommandTimeout)
+		}
+		detail := Redact(truncate(strings.TrimSpace(string(stderr)), tokenCommandStderrLimit), token)
+		if detail != "" {
+			return "", fmt.Errorf("token command failed: %w: %s", err, detail)
+		}
+		return "", fmt.Errorf("token command failed: %w", err)
+	}
+	if token == "" {
+		return "", errors.New("token command produced no output")
+	}
+
+	debugTokenCommand("%s ok in %s (token redacted)", describeCommand(command), time.Since(start).Round(time.Millisecond))
+	return token, nil
+}
+
+// ForgetTokenCommand drops any cached result for command, forcing the next
+// TokenFromCommand call to re-run it.
+func ForgetTokenCommand(command string) {
+	commandCacheMu.Lock()
+	delete(commandCache, strings.TrimSpace(command))
+	commandCacheMu.Unlock()
+}
+
+// Redact replaces every occurrence of each non-empty secret in s with a fixed
+// placeholder.
+func Redact(s string, secrets ...string) string {
+	for _, sec := range secrets {
+		if sec == "" {
+			continue
+		}
+		s = strings.ReplaceAll(s, sec, "[REDACTED]")
+	}
+	return s
+}
+
+// describeCommand returns a loggable label for command: the program name with
+// its arguments elided, since arguments frequently embed vault paths, item
+// identifiers, or even inline credentials.
+func describeCommand(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "token command"
+	}
+	if len(fields) == 1 {
+		return "token command: " + fields[0]
+	}
+	return "token command: " + fields[0] + " [args redacted]"
+}
+
+func debugTokenCommand(format string, args ...any) {
+	if os.Getenv(envHTTPDebug) == "" {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "--> "+format+"\n", args...)
+}
+
+func tokenCommandTimeout() time.Duration {
+	if d, ok := parseTimeoutEnv(strings.TrimSpace(os.Getenv(envTokenCommandTimeout))); ok {
+		return d
+	}
+	return tokenCommandTimeoutDefault
+}
+
+func tokenCommandTTL() time.Duration {
+	if d, ok := parseTimeoutEnv(strings.TrimSpace(os.Getenv(envTokenCommandTTL))); ok {
+		return d
+	}
+	return tokenCommandTTLDefault
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit] + "…"
+}
@@ -0,0 +1,186 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubRunShell(t *testing.T, fn func(ctx context.Context, command string) ([]byte, []byte, error)) *int {
+	t.Helper()
+	original := runShell
+	t.Cleanup(func() { runShell = original })
+
+	calls := 0
+	runShell = func(ctx context.Context, command string) ([]byte, []byte, error) {
+		calls++
+		return fn(ctx, command)
+	}
+	return &calls
+}
+
+func TestRunTokenCommandReturnsFirstLine(t *testing.T) {
+	stubRunShell(t, func(_ context.Context, command string) ([]byte, []byte, error) {
+		if command != "op read op://vault/bkt/token" {
+			t.Fatalf("unexpected command %q", command)
+		}
+		return []byte("\n  s3cr3t  \ntrailing\n"), nil, nil
+	})
+
+	got, err := RunTokenCommand(context.Background(), "  op read op://vault/bkt/token ")
+	if err != nil {
+		t.Fatalf("RunTokenCommand: %v", err)
+	}
+	if got != "s3cr3t" {
+		t.Fatalf("token = %q, want s3cr3t", got)
+	}
+}
+
+func TestRunTokenCommandEmptyOutput(t *testing.T) {
+	stubRunShell(t, func(context.Context, string) ([]byte, []byte, error) {
+		return []byte("  \n"), nil, nil
+	})
+
+	if _, err := RunTokenCommand(context.Background(), "true"); err == nil || !strings.Contains(err.Error(), "no output") {
+		t.Fatalf("expected no-output error, got %v", err)
+	}
+}
+
+func TestRunTokenCommandFailureRedactsToken(t *testing.T) {
+	stubRunShell(t, func(context.Context, string) ([]byte, []byte, error) {
+		return []byte("leaked-token\n"), []byte("vault: permission denied for leaked-token"), errors.New("exit status 2")
+	})
+
+	_, err := RunTokenCommand(context.Background(), "vault kv get -field=token secret/bkt")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if strings.Contains(err.Error(), "leaked-token") {
+		t.Fatalf("error leaks token: %v", err)
+	}
+	if !strings.Contains(err.Error(), "permission denied") {
+		t.Fatalf("error should include stderr detail, got %v", err)
+	}
+}
+
+func TestRunTokenCommandTimeout(t *testing.T) {
+	t.Setenv(envTokenCommandTimeout, "20ms")
+	stubRunShell(t, func(ctx context.Context, _ string) ([]byte, []byte, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	})
+
+	_, err := RunTokenCommand(context.Background(), "sleep 10")
+	if !errors.Is(err, ErrTokenCommandTimeout) {
+		t.Fatalf("expected ErrTokenCommandTimeout, got %v", err)
+	}
+}
+
+func TestRunTokenCommandTimeoutWithBackgroundChild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh job control")
+	}
+	t.Setenv(envTokenCommandTimeout, "100ms")
+
+	// The backgrounded sleep inherits stdout and outlives the killed shell.
+	start := time.Now()
+	_, err := RunTokenCommand(context.Background(), "sleep 30 & sleep 30")
+	if !errors.Is(err, ErrTokenCommandTimeout) {
+		t.Fatalf("expected ErrTokenCommandTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("RunTokenCommand blocked for %s after the timeout", elapsed)
+	}
+}
+
+func TestTokenFromCommandCachesResult(t *testing.T) {
+	command := "pass show bkt/cache-test"
+	t.Cleanup(func() { ForgetTokenCommand(command) })
+
+	calls := stubRunShell(t, func(context.Context, string) ([]byte, []byte, error) {
+		return []byte("cached-token\n"), nil, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		got, err := TokenFromCommand(context.Background(), command)
+		if err != nil {
+			t.Fatalf("TokenFromCommand: %v", err)
+		}
+		if got != "cached-token" {
+			t.Fatalf("token = %q", got)
+		}
+	}
+	if *calls != 1 {
+		t.Fatalf("command ran %d times, want 1", *calls)
+	}
+
+	ForgetTokenCommand(command)
+	if _, err := TokenFromCommand(context.Background(), command); err != nil {
+		t.Fatalf("TokenFromCommand after forget: %v", err)
+	}
+	if *calls != 2 {
+		t.Fatalf("command ran %d times after forget, want 2", *calls)
+	}
+}
+
+func TestTokenFromCommandExpiredCacheReruns(t *testing.T) {
+	command := "pass show bkt/ttl-test"
+	t.Cleanup(func() { ForgetTokenCommand(command) })
+
+	calls := stubRunShell(t, func(context.Context, string) ([]byte, []byte, error) {
+		return []byte("tok\n"), nil, nil
+	})
+
+	commandCacheMu.Lock()
+	commandCache[command] = commandCacheEntry{token: "stale", expiresAt: time.Now().Add(-time.Second)}
+	commandCacheMu.Unlock()
+
+	got, err := TokenFromCommand(context.Background(), command)
+	if err != nil {
+		t.Fatalf("TokenFromCommand: %v", err)
+	}
+	if got != "tok" || *calls != 1 {
+		t.Fatalf("got %q after %d calls, want fresh token after 1 call", got, *calls)
+	}
+}
+
+func TestDebugTokenCommandNeverLogsArguments(t *testing.T) {
+	t.Setenv(envHTTPDebug, "1")
+	stubRunShell(t, func(context.Context, string) ([]byte, []byte, error) {
+		return []byte("debug-token\n"), nil, nil
+	})
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	original := os.Stderr
+	os.Stderr = w
+	_, runErr := RunTokenCommand(context.Background(), "vault kv get -field=token secret/super-private")
+	os.Stderr = original
+	_ = w.Close()
+	if runErr != nil {
+		t.Fatalf("RunTokenCommand: %v", runErr)
+	}
+
+	buf := make([]byte, 4096)
+	n, _ := r.Read(buf)
+	logged := string(buf[:n])
+	if !strings.Contains(logged, "token command: vault [args redacted]") {
+		t.Fatalf("debug output missing command label: %q", logged)
+	}
+	if strings.Contains(logged, "super-private") || strings.Contains(logged, "debug-token") {
+		t.Fatalf("debug output leaks secrets: %q", logged)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got := Redact("token abc appears twice: abc", "abc", "")
+	if got != "token [REDACTED] appears twice: [REDACTED]" {
+		t.Fatalf("Redact = %q", got)
+	}
+}
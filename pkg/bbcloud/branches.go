@@ -14,8 +14,10 @@ type Branch struct {
 		Hash string `json:"hash"`
 		Type string `json:"type"`
 	} `json:"target"`
-	IsDefault bool `json:"default"`
-	Links     struct {
+	IsDefault            bool     `json:"default"`
+	MergeStrategies      []string `json:"merge_strategies,omitempty"`
+	DefaultMergeStrategy string   `json:"default_merge_strategy,omitempty"`
+	Links                struct {
 		HTML struct {
 			Href string `json:"href"`
 		} `json:"html"`
@@ -0,0 +1,145 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BranchRestriction models a Bitbucket Cloud branch restriction. Value is the
+// required count for kinds such as require_approvals_to_merge; Users and
+// Groups list who is exempt for kinds such as push.
+type BranchRestriction struct {
+	ID              int    `json:"id"`
+	Kind            string `json:"kind"`
+	BranchMatchKind string `json:"branch_match_kind"`
+	Pattern         string `json:"pattern,omitempty"`
+	BranchType      string `json:"branch_type,omitempty"`
+	Value           *int   `json:"value,omitempty"`
+	Users           []User `json:"users,omitempty"`
+	Groups          []struct {
+		Slug string `json:"slug"`
+		Name string `json:"name,omitempty"`
+	} `json:"groups,omitempty"`
+}
+
+// BranchRestrictionInput configures branch restriction creation. Users are
+// account UUIDs (wrapped in braces) or account IDs; Groups are group slugs.
+// A zero Value is omitted.
+type BranchRestrictionInput struct {
+	Kind    string
+	Pattern string
+	Value   int
+	Users   []string
+	Groups  []string
+}
+
+type branchRestrictionPage struct {
+	Values []BranchRestriction `json:"values"`
+	Next   string              `json:"next"`
+}
+
+// ListBranchRestrictions lists the branch restrictions of a repository.
+func (c *Client) ListBranchRestrictions(ctx context.Context, workspace, repoSlug string) ([]BranchRestriction, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+
+	path := branchRestrictionsPath(workspace, repoSlug) + "?pagelen=100"
+
+	var restrictions []BranchRestriction
+	for path != "" {
+		req, err := c.http.NewRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page branchRestrictionPage
+		if err := c.http.Do(req, &page); err != nil {
+			return nil, err
+		}
+		restrictions = append(restrictions, page.Values...)
+
+		if page.Next == "" {
+			break
+		}
+		nextURL, err := url.Parse(page.Next)
+		if err != nil {
+			return nil, err
+		}
+		path = nextURL.RequestURI()
+	}
+
+	return restrictions, nil
+}
+
+// CreateBranchRestriction adds a glob-matched branch restriction.
+func (c *Client) CreateBranchRestriction(ctx context.Context, workspace, repoSlug string, in BranchRestrictionInput) (*BranchRestriction, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+	if in.Kind == "" {
+		return nil, fmt.Errorf("restriction kind is required")
+	}
+	if in.Pattern == "" {
+		return nil, fmt.Errorf("branch pattern is required")
+	}
+
+	body := map[string]any{
+		"kind":              in.Kind,
+		"branch_match_kind": "glob",
+		"pattern":           in.Pattern,
+	}
+	if in.Value > 0 {
+		body["value"] = in.Value
+	}
+	if len(in.Users) > 0 {
+		users := make([]map[string]string, 0, len(in.Users))
+		for _, u := range in.Users {
+			if strings.HasPrefix(u, "{") {
+				users = append(users, map[string]string{"uuid": u})
+			} else {
+				users = append(users, map[string]string{"account_id": u})
+			}
+		}
+		body["users"] = users
+	}
+	if len(in.Groups) > 0 {
+		groups := make([]map[string]string, 0, len(in.Groups))
+		for _, g := range in.Groups {
+			groups = append(groups, map[string]string{"slug": g})
+		}
+		body["groups"] = groups
+	}
+
+	req, err := c.http.NewRequest(ctx, "POST", branchRestrictionsPath(workspace, repoSlug), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var restriction BranchRestriction
+	if err := c.http.Do(req, &restriction); err != nil {
+		return nil, err
+	}
+	return &restriction, nil
+}
+
+// DeleteBranchRestriction removes a branch restriction by id.
+func (c *Client) DeleteBranchRestriction(ctx context.Context, workspace, repoSlug string, id int) error {
+	if workspace == "" || repoSlug == "" {
+		return fmt.Errorf("workspace and repository slug are required")
+	}
+	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/%d", branchRestrictionsPath(workspace, repoSlug), id), nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+func branchRestrictionsPath(workspace, repoSlug string) string {
+	return fmt.Sprintf("/repositories/%s/%s/branch-restrictions",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+	)
+}
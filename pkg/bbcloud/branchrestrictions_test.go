@@ -0,0 +1,66 @@
+package bbcloud_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+)
+
+func TestCreateBranchRestrictionEncodesUsersAndGroups(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/workspace/repo/branch-restrictions" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 3, "kind": "push", "pattern": "main"})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := bbcloud.New(bbcloud.Options{BaseURL: server.URL, Username: "u", Token: "t"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	restriction, err := client.CreateBranchRestriction(context.Background(), "workspace", "repo", bbcloud.BranchRestrictionInput{
+		Kind:    "push",
+		Pattern: "main",
+		Users:   []string{"{11111111-1111-1111-1111-111111111111}", "557058:abc"},
+		Groups:  []string{"release-managers"},
+	})
+	if err != nil {
+		t.Fatalf("CreateBranchRestriction: %v", err)
+	}
+	if restriction.ID != 3 {
+		t.Fatalf("id = %d, want 3", restriction.ID)
+	}
+
+	if body["branch_match_kind"] != "glob" || body["pattern"] != "main" {
+		t.Fatalf("unexpected matcher: %+v", body)
+	}
+	if _, ok := body["value"]; ok {
+		t.Fatalf("zero value should be omitted: %+v", body)
+	}
+	users, _ := body["users"].([]any)
+	if len(users) != 2 {
+		t.Fatalf("users = %+v", body["users"])
+	}
+	if u := users[0].(map[string]any); u["uuid"] != "{11111111-1111-1111-1111-111111111111}" {
+		t.Fatalf("first user = %+v, want uuid", u)
+	}
+	if u := users[1].(map[string]any); u["account_id"] != "557058:abc" {
+		t.Fatalf("second user = %+v, want account_id", u)
+	}
+	groups, _ := body["groups"].([]any)
+	if len(groups) != 1 || groups[0].(map[string]any)["slug"] != "release-managers" {
+		t.Fatalf("groups = %+v", body["groups"])
+	}
+}
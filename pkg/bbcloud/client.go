@@ -146,6 +146,9 @@ type Pipeline struct {
 		Ref  struct {
 			Name string `json:"name"`
 		} `json:"ref"`
+		Commit struct {
+			Hash string `json:"hash"`
+		} `json:"commit"`
 	} `json:"target"`
 	CreatedOn   string `json:"created_on"`
 	CompletedOn string `json:"completed_on"`
@@ -271,33 +274,50 @@ type RepositoriesPage struct {
 
 // ListRepositories enumerates repositories for the workspace.
 func (c *Client) ListRepositories(ctx context.Context, workspace string, limit int) ([]Repository, error) {
+	var repos []Repository
+	err := c.EachRepository(ctx, workspace, limit, func(repo Repository) error {
+		repos = append(repos, repo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// EachRepository calls fn for each workspace repository as pages arrive,
+// stopping after limit repositories (0 for all) or at the first error.
+func (c *Client) EachRepository(ctx context.Context, workspace string, limit int, fn func(Repository) error) error {
 	pageLen := limit
 	if pageLen <= 0 || pageLen > 100 {
 		pageLen = 20
 	}
 
-	var repos []Repository
+	seen := 0
 	next := ""
 	for {
 		page, err := c.ListRepositoriesPage(ctx, workspace, pageLen, next)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		repos = append(repos, page.Values...)
-
-		if limit > 0 && len(repos) >= limit {
-			repos = repos[:limit]
-			break
+		for _, repo := range page.Values {
+			if limit > 0 && seen >= limit {
+				return nil
+			}
+			if err := fn(repo); err != nil {
+				return err
+			}
+			seen++
 		}
 
-		if page.Next == "" {
+		if (limit > 0 && seen >= limit) || page.Next == "" {
 			break
 		}
 		next = page.Next
 	}
 
-	return repos, nil
+	return nil
 }
 
 // ListRepositoriesPage fetches one repository page and preserves the opaque
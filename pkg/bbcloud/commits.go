@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
 )
 
 // CommitDiff streams the raw unified diff between two refs into w.
@@ -36,3 +39,37 @@ func (c *Client) CommitDiff(ctx context.Context, workspace, repoSlug, spec strin
 
 	return c.http.Do(req, w)
 }
+
+// FileContent streams the raw content of path at ref into w. ref may be a
+// commit SHA, branch, or tag; prefer a SHA for branch names containing "/",
+// which the Cloud src endpoint cannot always disambiguate.
+func (c *Client) FileContent(ctx context.Context, workspace, repoSlug, ref, path string, w io.Writer) error {
+	if workspace == "" || repoSlug == "" {
+		return fmt.Errorf("workspace and repository slug are required")
+	}
+	if ref == "" {
+		return fmt.Errorf("ref is required")
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return fmt.Errorf("file path is required")
+	}
+	if w == nil {
+		return fmt.Errorf("writer is required")
+	}
+
+	u := fmt.Sprintf("/repositories/%s/%s/src/%s/%s",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+		url.PathEscape(ref),
+		httpx.EscapePathSegments(path),
+	)
+
+	req, err := c.http.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	return c.http.Do(req, w)
+}
@@ -103,3 +103,25 @@ func TestCommitDiffValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestFileContentPath(t *testing.T) {
+	var gotEscapedPath string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEscapedPath = r.URL.EscapedPath()
+		_, _ = w.Write([]byte("package main\n"))
+	}))
+
+	var buf strings.Builder
+	if err := client.FileContent(context.Background(), "ws", "repo", "abc123", "cmd/app/main.go", &buf); err != nil {
+		t.Fatalf("FileContent: %v", err)
+	}
+	if gotEscapedPath != "/repositories/ws/repo/src/abc123/cmd/app/main.go" {
+		t.Errorf("escaped path = %q", gotEscapedPath)
+	}
+	if buf.String() != "package main\n" {
+		t.Errorf("content = %q", buf.String())
+	}
+	if err := client.FileContent(context.Background(), "ws", "repo", "", "main.go", &buf); err == nil {
+		t.Error("expected error for empty ref")
+	}
+}
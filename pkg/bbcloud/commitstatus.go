@@ -0,0 +1,156 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// webBaseURL is the Bitbucket Cloud web UI; pipeline payloads carry no HTML
+// links, so result pages are derived from it.
+const webBaseURL = "https://bitbucket.org"
+
+// commitPipelineDefaultLimit caps ListCommitPipelines when no limit is given,
+// and commitPipelineMaxPages bounds the walk on servers that ignore the
+// commit filter and page through every pipeline in the repository.
+const (
+	commitPipelineDefaultLimit = 50
+	commitPipelineMaxPages     = 5
+)
+
+// ListCommitPipelines returns up to limit pipelines that ran on commit,
+// newest first. A limit of 0 returns at most 50.
+func (c *Client) ListCommitPipelines(ctx context.Context, workspace, repoSlug, commit string, limit int) ([]Pipeline, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+	if commit == "" {
+		return nil, fmt.Errorf("commit SHA is required")
+	}
+
+	if limit <= 0 {
+		limit = commitPipelineDefaultLimit
+	}
+	pageLen := min(limit, 100)
+
+	query := url.Values{}
+	query.Set("target.commit.hash", commit)
+	query.Set("sort", "-created_on")
+	query.Set("pagelen", fmt.Sprint(pageLen))
+	path := fmt.Sprintf("/repositories/%s/%s/pipelines/?%s",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+		query.Encode(),
+	)
+
+	var pipelines []Pipeline
+	for pages := 0; path != "" && pages < commitPipelineMaxPages; pages++ {
+		req, err := c.http.NewRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page PipelinePage
+		if err := c.http.Do(req, &page); err != nil {
+			return nil, err
+		}
+
+		for _, p := range page.Values {
+			// Older API versions ignore the filter; never report another
+			// commit's pipeline.
+			if p.Target.Commit.Hash != "" && !sameCommit(p.Target.Commit.Hash, commit) {
+				continue
+			}
+			pipelines = append(pipelines, p)
+		}
+
+		if len(pipelines) >= limit {
+			pipelines = pipelines[:limit]
+			break
+		}
+		if page.Next == "" {
+			break
+		}
+		nextURL, err := url.Parse(page.Next)
+		if err != nil {
+			return nil, err
+		}
+		path = nextURL.RequestURI()
+	}
+
+	return pipelines, nil
+}
+
+// CommitBuildStatuses combines the statuses reported against commit with the
+// pipelines that ran on it, so Cloud results read like Data Center build
+// statuses. Pipelines already represented by a reported status are not
+// repeated.
+func (c *Client) CommitBuildStatuses(ctx context.Context, workspace, repoSlug, commit string) ([]CommitStatus, error) {
+	statuses, err := c.CommitStatuses(ctx, workspace, repoSlug, commit)
+	if err != nil {
+		return nil, err
+	}
+
+	pipelines, err := c.ListCommitPipelines(ctx, workspace, repoSlug, commit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list pipelines for commit %s: %w", commit, err)
+	}
+
+	reported := make(map[string]bool, len(statuses))
+	for _, s := range statuses {
+		reported[strings.TrimRight(s.URL, "/")] = true
+		reported[strings.Trim(s.Key, "{}")] = true
+	}
+	for _, p := range pipelines {
+		status := PipelineCommitStatus(p, workspace, repoSlug)
+		if reported[status.URL] || reported[strings.Trim(p.UUID, "{}")] {
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// PipelineCommitStatus describes a pipeline run as a commit status, mapping
+// its state and result onto SUCCESSFUL, FAILED, STOPPED, or INPROGRESS.
+func PipelineCommitStatus(p Pipeline, workspace, repoSlug string) CommitStatus {
+	name := fmt.Sprintf("Pipeline #%d", p.BuildNumber)
+	if ref := p.Target.Ref.Name; ref != "" {
+		name += " (" + ref + ")"
+	}
+
+	description := strings.ToUpper(p.State.Name)
+	if result := p.State.Result.Name; result != "" {
+		description = strings.ToUpper(result)
+	}
+
+	return CommitStatus{
+		State:       pipelineStatusState(p.State),
+		Key:         fmt.Sprintf("pipeline-%d", p.BuildNumber),
+		Name:        name,
+		URL:         fmt.Sprintf("%s/%s/%s/pipelines/results/%d", webBaseURL, url.PathEscape(workspace), url.PathEscape(repoSlug), p.BuildNumber),
+		Description: description,
+	}
+}
+
+func pipelineStatusState(state PipelineState) string {
+	if strings.ToUpper(state.Name) != "COMPLETED" {
+		return "INPROGRESS"
+	}
+	switch strings.ToUpper(state.Result.Name) {
+	case "SUCCESSFUL":
+		return "SUCCESSFUL"
+	case "STOPPED":
+		return "STOPPED"
+	default:
+		// FAILED, ERROR, and EXPIRED runs all leave the commit unbuilt.
+		return "FAILED"
+	}
+}
+
+// sameCommit compares full or abbreviated SHAs.
+func sameCommit(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
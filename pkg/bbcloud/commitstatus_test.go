@@ -0,0 +1,130 @@
+package bbcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCommitBuildStatusesMergesPipelines(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/ws/repo/commit/abc123/statuses":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values": []CommitStatus{
+					{State: "SUCCESSFUL", Key: "jenkins", Name: "Jenkins", URL: "https://ci.example.com/1"},
+					// Pipelines reports its own runs; this one must not repeat.
+					{State: "SUCCESSFUL", Key: "{11111111-2222-3333-4444-555555555555}", Name: "Pipeline #7"},
+				},
+			})
+		case "/repositories/ws/repo/pipelines/":
+			if got := r.URL.Query().Get("target.commit.hash"); got != "abc123" {
+				t.Errorf("target.commit.hash = %q, want abc123", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values": []map[string]any{
+					{
+						"uuid":         "{99999999-2222-3333-4444-555555555555}",
+						"build_number": 8,
+						"state":        map[string]any{"name": "COMPLETED", "result": map[string]any{"name": "FAILED"}},
+						"target":       map[string]any{"ref": map[string]any{"name": "main"}, "commit": map[string]any{"hash": "abc123"}},
+					},
+					{
+						"uuid":         "{11111111-2222-3333-4444-555555555555}",
+						"build_number": 7,
+						"state":        map[string]any{"name": "COMPLETED", "result": map[string]any{"name": "SUCCESSFUL"}},
+						"target":       map[string]any{"commit": map[string]any{"hash": "abc123"}},
+					},
+					{
+						"uuid":         "{00000000-2222-3333-4444-555555555555}",
+						"build_number": 6,
+						"state":        map[string]any{"name": "COMPLETED", "result": map[string]any{"name": "SUCCESSFUL"}},
+						"target":       map[string]any{"commit": map[string]any{"hash": "def456"}},
+					},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	statuses, err := client.CommitBuildStatuses(context.Background(), "ws", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("CommitBuildStatuses: %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("expected 3 statuses, got %d: %+v", len(statuses), statuses)
+	}
+
+	got := statuses[2]
+	want := CommitStatus{
+		State:       "FAILED",
+		Key:         "pipeline-8",
+		Name:        "Pipeline #8 (main)",
+		URL:         "https://bitbucket.org/ws/repo/pipelines/results/8",
+		Description: "FAILED",
+	}
+	if got != want {
+		t.Fatalf("pipeline status = %+v, want %+v", got, want)
+	}
+}
+
+func TestPipelineCommitStatusStates(t *testing.T) {
+	tests := []struct {
+		state, result, want string
+	}{
+		{state: "PENDING", want: "INPROGRESS"},
+		{state: "IN_PROGRESS", want: "INPROGRESS"},
+		{state: "COMPLETED", result: "SUCCESSFUL", want: "SUCCESSFUL"},
+		{state: "COMPLETED", result: "STOPPED", want: "STOPPED"},
+		{state: "COMPLETED", result: "ERROR", want: "FAILED"},
+		{state: "COMPLETED", result: "EXPIRED", want: "FAILED"},
+	}
+	for _, tt := range tests {
+		var p Pipeline
+		p.State.Name = tt.state
+		p.State.Result.Name = tt.result
+		if got := PipelineCommitStatus(p, "ws", "repo").State; got != tt.want {
+			t.Fatalf("state %s/%s mapped to %s, want %s", tt.state, tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestListCommitPipelinesBoundsUnfilteredWalk(t *testing.T) {
+	var requests int
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		// A server that ignores the commit filter returns other commits'
+		// pipelines on every page, forever.
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"values": []map[string]any{{"build_number": requests, "target": map[string]any{"commit": map[string]any{"hash": "def456"}}}},
+			"next":   server.URL + "/repositories/ws/repo/pipelines/?page=" + fmt.Sprint(requests+1),
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	pipelines, err := client.ListCommitPipelines(context.Background(), "ws", "repo", "abc123", 0)
+	if err != nil {
+		t.Fatalf("ListCommitPipelines: %v", err)
+	}
+	if len(pipelines) != 0 || requests != commitPipelineMaxPages {
+		t.Fatalf("got %d pipelines after %d requests, want none after %d", len(pipelines), requests, commitPipelineMaxPages)
+	}
+}
@@ -0,0 +1,94 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// ListDefaultReviewers returns the default reviewers configured on the
+// repository itself, excluding those inherited from the workspace.
+func (c *Client) ListDefaultReviewers(ctx context.Context, workspace, repoSlug string) ([]User, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+
+	path := defaultReviewersPath(workspace, repoSlug) + "?pagelen=100"
+
+	var users []User
+	for path != "" {
+		req, err := c.http.NewRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Values []User `json:"values"`
+			Next   string `json:"next"`
+		}
+		if err := c.http.Do(req, &page); err != nil {
+			return nil, err
+		}
+		users = append(users, page.Values...)
+
+		if page.Next == "" {
+			break
+		}
+		nextURL, err := url.Parse(page.Next)
+		if err != nil {
+			return nil, err
+		}
+		path = nextURL.RequestURI()
+	}
+
+	return users, nil
+}
+
+// AddDefaultReviewer adds a user, given by username or UUID, to the
+// repository's default reviewers.
+func (c *Client) AddDefaultReviewer(ctx context.Context, workspace, repoSlug, user string) (*User, error) {
+	path, err := defaultReviewerPath(workspace, repoSlug, user)
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.http.NewRequest(ctx, "PUT", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var added User
+	if err := c.http.Do(req, &added); err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
+
+// RemoveDefaultReviewer removes a user, given by username or UUID, from the
+// repository's default reviewers.
+func (c *Client) RemoveDefaultReviewer(ctx context.Context, workspace, repoSlug, user string) error {
+	path, err := defaultReviewerPath(workspace, repoSlug, user)
+	if err != nil {
+		return err
+	}
+	req, err := c.http.NewRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+func defaultReviewersPath(workspace, repoSlug string) string {
+	return fmt.Sprintf("/repositories/%s/%s/default-reviewers",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+	)
+}
+
+func defaultReviewerPath(workspace, repoSlug, user string) (string, error) {
+	if workspace == "" || repoSlug == "" || user == "" {
+		return "", fmt.Errorf("workspace, repository slug, and reviewer are required")
+	}
+	if LooksLikeUUID(user) {
+		user = NormalizeUUID(user)
+	}
+	return defaultReviewersPath(workspace, repoSlug) + "/" + url.PathEscape(user), nil
+}
@@ -0,0 +1,203 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Group identifies a Bitbucket Cloud workspace group.
+type Group struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// UserPermission is an explicit permission granted to a user on a
+// repository or project.
+type UserPermission struct {
+	Permission string `json:"permission"`
+	User       User   `json:"user"`
+}
+
+// GroupPermission is an explicit permission granted to a group on a
+// repository or project.
+type GroupPermission struct {
+	Permission string `json:"permission"`
+	Group      Group  `json:"group"`
+}
+
+// WorkspaceMembership describes a user's membership of a workspace; the
+// permission is owner, collaborator, or member.
+type WorkspaceMembership struct {
+	Permission string `json:"permission"`
+	User       User   `json:"user"`
+}
+
+// ListRepositoryUserPermissions returns the users granted explicit access to
+// a repository.
+func (c *Client) ListRepositoryUserPermissions(ctx context.Context, workspace, repoSlug string) ([]UserPermission, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+	return listPages[UserPermission](ctx, c, repoPermissionsConfigPath(workspace, repoSlug, "users")+"?pagelen=100")
+}
+
+// ListRepositoryGroupPermissions returns the groups granted explicit access
+// to a repository.
+func (c *Client) ListRepositoryGroupPermissions(ctx context.Context, workspace, repoSlug string) ([]GroupPermission, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+	return listPages[GroupPermission](ctx, c, repoPermissionsConfigPath(workspace, repoSlug, "groups")+"?pagelen=100")
+}
+
+// SetRepositoryUserPermission grants read, write, or admin on a repository
+// to a user, given by account ID or UUID, replacing any existing grant.
+func (c *Client) SetRepositoryUserPermission(ctx context.Context, workspace, repoSlug, user, permission string) error {
+	path, err := repoUserPermissionPath(workspace, repoSlug, user)
+	if err != nil {
+		return err
+	}
+	return c.putRepositoryPermission(ctx, path, permission)
+}
+
+// SetRepositoryGroupPermission grants read, write, or admin on a repository
+// to a group, given by slug, replacing any existing grant.
+func (c *Client) SetRepositoryGroupPermission(ctx context.Context, workspace, repoSlug, group, permission string) error {
+	path, err := repoGroupPermissionPath(workspace, repoSlug, group)
+	if err != nil {
+		return err
+	}
+	return c.putRepositoryPermission(ctx, path, permission)
+}
+
+// DeleteRepositoryUserPermission removes a user's explicit repository grant.
+func (c *Client) DeleteRepositoryUserPermission(ctx context.Context, workspace, repoSlug, user string) error {
+	path, err := repoUserPermissionPath(workspace, repoSlug, user)
+	if err != nil {
+		return err
+	}
+	return c.deleteRepositoryPermission(ctx, path)
+}
+
+// DeleteRepositoryGroupPermission removes a group's explicit repository grant.
+func (c *Client) DeleteRepositoryGroupPermission(ctx context.Context, workspace, repoSlug, group string) error {
+	path, err := repoGroupPermissionPath(workspace, repoSlug, group)
+	if err != nil {
+		return err
+	}
+	return c.deleteRepositoryPermission(ctx, path)
+}
+
+// ListProjectUserPermissions returns the users granted explicit access to a
+// project. Project permissions are inherited by the project's repositories.
+func (c *Client) ListProjectUserPermissions(ctx context.Context, workspace, projectKey string) ([]UserPermission, error) {
+	if workspace == "" || projectKey == "" {
+		return nil, fmt.Errorf("workspace and project key are required")
+	}
+	return listPages[UserPermission](ctx, c, projectPermissionsConfigPath(workspace, projectKey, "users")+"?pagelen=100")
+}
+
+// ListProjectGroupPermissions returns the groups granted explicit access to a
+// project.
+func (c *Client) ListProjectGroupPermissions(ctx context.Context, workspace, projectKey string) ([]GroupPermission, error) {
+	if workspace == "" || projectKey == "" {
+		return nil, fmt.Errorf("workspace and project key are required")
+	}
+	return listPages[GroupPermission](ctx, c, projectPermissionsConfigPath(workspace, projectKey, "groups")+"?pagelen=100")
+}
+
+// ListWorkspaceMembers returns every member of a workspace with their
+// workspace permission.
+func (c *Client) ListWorkspaceMembers(ctx context.Context, workspace string) ([]WorkspaceMembership, error) {
+	if workspace == "" {
+		return nil, fmt.Errorf("workspace is required")
+	}
+	return listPages[WorkspaceMembership](ctx, c, fmt.Sprintf("/workspaces/%s/permissions?pagelen=100", url.PathEscape(workspace)))
+}
+
+func (c *Client) putRepositoryPermission(ctx context.Context, path, permission string) error {
+	permission = strings.ToLower(strings.TrimSpace(permission))
+	switch permission {
+	case "read", "write", "admin":
+	default:
+		return fmt.Errorf("invalid permission %q (expected read, write, or admin)", permission)
+	}
+	req, err := c.http.NewRequest(ctx, "PUT", path, map[string]string{"permission": permission})
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+func (c *Client) deleteRepositoryPermission(ctx context.Context, path string) error {
+	req, err := c.http.NewRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+// listPages follows "next" links from path and collects every value.
+func listPages[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var out []T
+	for path != "" {
+		req, err := c.http.NewRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Values []T    `json:"values"`
+			Next   string `json:"next"`
+		}
+		if err := c.http.Do(req, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+
+		if page.Next == "" {
+			break
+		}
+		nextURL, err := url.Parse(page.Next)
+		if err != nil {
+			return nil, err
+		}
+		path = nextURL.RequestURI()
+	}
+	return out, nil
+}
+
+func repoPermissionsConfigPath(workspace, repoSlug, kind string) string {
+	return fmt.Sprintf("/repositories/%s/%s/permissions-config/%s",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+		kind,
+	)
+}
+
+func projectPermissionsConfigPath(workspace, projectKey, kind string) string {
+	return fmt.Sprintf("/workspaces/%s/projects/%s/permissions-config/%s",
+		url.PathEscape(workspace),
+		url.PathEscape(projectKey),
+		kind,
+	)
+}
+
+func repoUserPermissionPath(workspace, repoSlug, user string) (string, error) {
+	if workspace == "" || repoSlug == "" || user == "" {
+		return "", fmt.Errorf("workspace, repository slug, and user are required")
+	}
+	if LooksLikeUUID(user) {
+		user = NormalizeUUID(user)
+	}
+	return repoPermissionsConfigPath(workspace, repoSlug, "users") + "/" + url.PathEscape(user), nil
+}
+
+func repoGroupPermissionPath(workspace, repoSlug, group string) (string, error) {
+	if workspace == "" || repoSlug == "" || group == "" {
+		return "", fmt.Errorf("workspace, repository slug, and group are required")
+	}
+	return repoPermissionsConfigPath(workspace, repoSlug, "groups") + "/" + url.PathEscape(group), nil
+}
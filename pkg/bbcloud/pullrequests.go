@@ -234,27 +234,45 @@ func (c *Client) fetchPullRequestsPage(ctx context.Context, path string) (*PullR
 // to opts.Limit.
 func (c *Client) ListPullRequests(ctx context.Context, workspace, repoSlug string, opts PullRequestListOptions) ([]PullRequest, error) {
 	var prs []PullRequest
+	err := c.EachPullRequest(ctx, workspace, repoSlug, opts, func(pr PullRequest) error {
+		prs = append(prs, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// EachPullRequest is the streaming form of ListPullRequests: fn receives
+// each pull request as its page arrives, and paging stops at opts.Limit or
+// the first error fn returns.
+func (c *Client) EachPullRequest(ctx context.Context, workspace, repoSlug string, opts PullRequestListOptions, fn func(PullRequest) error) error {
+	seen := 0
 	next := ""
 	for {
 		page, err := c.ListRepoPullRequestsPage(ctx, workspace, repoSlug, opts, next)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		prs = append(prs, page.Values...)
-
-		if opts.Limit > 0 && len(prs) >= opts.Limit {
-			prs = prs[:opts.Limit]
-			break
+		for _, pr := range page.Values {
+			if opts.Limit > 0 && seen >= opts.Limit {
+				return nil
+			}
+			if err := fn(pr); err != nil {
+				return err
+			}
+			seen++
 		}
 
-		if page.Next == "" {
+		if (opts.Limit > 0 && seen >= opts.Limit) || page.Next == "" {
 			break
 		}
 		next = page.Next
 	}
 
-	return prs, nil
+	return nil
 }
 
 func authorFilterField(identity string) string {
@@ -127,6 +127,54 @@ func TestListPullRequestsPaginates(t *testing.T) {
 	}
 }
 
+func TestEachPullRequestDeliversPagesIncrementally(t *testing.T) {
+	var hits int32
+	var serverURL string
+	var seenBeforeSecondPage int32
+	var delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count := atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		switch count {
+		case 1:
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values": []map[string]any{{"id": 1}, {"id": 2}},
+				"next":   serverURL + "/repositories/ws/repo/pullrequests?pagelen=20&page=2",
+			})
+		case 2:
+			atomic.StoreInt32(&seenBeforeSecondPage, atomic.LoadInt32(&delivered))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values": []map[string]any{{"id": 3}, {"id": 4}},
+			})
+		default:
+			t.Fatalf("unexpected request %d", count)
+		}
+	}))
+	serverURL = server.URL
+	t.Cleanup(server.Close)
+
+	client, err := bbcloud.New(bbcloud.Options{BaseURL: server.URL, Username: "u", Token: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int
+	err = client.EachPullRequest(context.Background(), "ws", "repo", bbcloud.PullRequestListOptions{Limit: 3}, func(pr bbcloud.PullRequest) error {
+		atomic.AddInt32(&delivered, 1)
+		ids = append(ids, pr.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("EachPullRequest: %v", err)
+	}
+	if len(ids) != 3 || ids[2] != 3 {
+		t.Fatalf("ids = %v, want [1 2 3]", ids)
+	}
+	if seenBeforeSecondPage != 2 {
+		t.Fatalf("expected first page delivered before second request, got %d", seenBeforeSecondPage)
+	}
+}
+
 func TestListPullRequestsRespectsLimit(t *testing.T) {
 	var hits int32
 	var serverURL string
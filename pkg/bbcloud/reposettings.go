@@ -0,0 +1,97 @@
+package bbcloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// BranchingModelSettings is a repository's branching model configuration.
+type BranchingModelSettings struct {
+	Development BranchingModelBranch `json:"development"`
+	Production  BranchingModelBranch `json:"production"`
+	BranchTypes []BranchingModelType `json:"branch_types"`
+}
+
+// BranchingModelBranch selects the development or production branch.
+// UseMainbranch follows the repository's main branch instead of Name.
+// Enabled only applies to the production branch.
+type BranchingModelBranch struct {
+	Name          string `json:"name,omitempty"`
+	UseMainbranch bool   `json:"use_mainbranch"`
+	Enabled       *bool  `json:"enabled,omitempty"`
+	IsValid       *bool  `json:"is_valid,omitempty"`
+}
+
+// BranchingModelType is a branch kind (bugfix, feature, hotfix, release) and
+// the prefix that identifies it.
+type BranchingModelType struct {
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+	Prefix  string `json:"prefix,omitempty"`
+}
+
+// GetBranchingModelSettings returns the repository's branching model.
+func (c *Client) GetBranchingModelSettings(ctx context.Context, workspace, repoSlug string) (*BranchingModelSettings, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+	req, err := c.http.NewRequest(ctx, "GET", branchingModelSettingsPath(workspace, repoSlug), nil)
+	if err != nil {
+		return nil, err
+	}
+	var settings BranchingModelSettings
+	if err := c.http.Do(req, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdateBranchingModelSettings replaces the repository's branching model.
+func (c *Client) UpdateBranchingModelSettings(ctx context.Context, workspace, repoSlug string, settings BranchingModelSettings) (*BranchingModelSettings, error) {
+	if workspace == "" || repoSlug == "" {
+		return nil, fmt.Errorf("workspace and repository slug are required")
+	}
+	// is_valid is computed by Bitbucket and rejected on update.
+	settings.Development.IsValid = nil
+	settings.Production.IsValid = nil
+
+	req, err := c.http.NewRequest(ctx, "PUT", branchingModelSettingsPath(workspace, repoSlug), settings)
+	if err != nil {
+		return nil, err
+	}
+	var updated BranchingModelSettings
+	if err := c.http.Do(req, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// GetBranch fetches a single branch, including the merge strategies allowed
+// for pull requests targeting it.
+func (c *Client) GetBranch(ctx context.Context, workspace, repoSlug, name string) (*Branch, error) {
+	if workspace == "" || repoSlug == "" || name == "" {
+		return nil, fmt.Errorf("workspace, repository slug, and branch name are required")
+	}
+	path := fmt.Sprintf("/repositories/%s/%s/refs/branches/%s",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+		url.PathEscape(name),
+	)
+	req, err := c.http.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var branch Branch
+	if err := c.http.Do(req, &branch); err != nil {
+		return nil, err
+	}
+	return &branch, nil
+}
+
+func branchingModelSettingsPath(workspace, repoSlug string) string {
+	return fmt.Sprintf("/repositories/%s/%s/branching-model/settings",
+		url.PathEscape(workspace),
+		url.PathEscape(repoSlug),
+	)
+}
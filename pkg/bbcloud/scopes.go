@@ -0,0 +1,34 @@
+package bbcloud
+
+import (
+	"context"
+	"strings"
+)
+
+// GrantedScopes returns the OAuth scopes Bitbucket Cloud reports for the
+// client's credential via the X-OAuth-Scopes response header. The boolean is
+// false when the header is absent, which happens for credential types that do
+// not advertise their scopes.
+func (c *Client) GrantedScopes(ctx context.Context) ([]string, bool, error) {
+	req, err := c.http.NewRequest(ctx, "GET", "/user", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	header, err := c.http.DoWithHeader(req, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	values := header.Values("X-OAuth-Scopes")
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	var scopes []string
+	for _, value := range values {
+		for _, scope := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes, true, nil
+}
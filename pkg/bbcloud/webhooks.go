@@ -7,29 +7,96 @@ import (
 	"strings"
 )
 
-// Webhook models a Bitbucket Cloud repository webhook.
+// Webhook models a Bitbucket Cloud repository or workspace webhook.
 type Webhook struct {
 	UUID        string   `json:"uuid"`
 	Description string   `json:"description"`
 	URL         string   `json:"url"`
 	Events      []string `json:"events"`
 	Active      bool     `json:"active"`
+	SecretSet   bool     `json:"secret_set,omitempty"`
 }
 
-// WebhookInput configures webhook creation.
+// WebhookInput configures webhook creation and updates. An empty Secret
+// leaves the stored secret unchanged.
 type WebhookInput struct {
 	Description string
 	URL         string
 	Events      []string
 	Active      bool
+	Secret      string
 }
 
 // ListWebhooks enumerates repository webhooks.
 func (c *Client) ListWebhooks(ctx context.Context, workspace, repoSlug string) ([]Webhook, error) {
-	path := fmt.Sprintf("/repositories/%s/%s/hooks",
+	return c.listWebhooks(ctx, repoHooksPath(workspace, repoSlug))
+}
+
+// CreateWebhook creates a new repository webhook.
+func (c *Client) CreateWebhook(ctx context.Context, workspace, repoSlug string, input WebhookInput) (*Webhook, error) {
+	return c.saveWebhook(ctx, "POST", repoHooksPath(workspace, repoSlug), input)
+}
+
+// DeleteWebhook removes a webhook by uuid.
+func (c *Client) DeleteWebhook(ctx context.Context, workspace, repoSlug, uuid string) error {
+	return c.deleteWebhook(ctx, hookPath(repoHooksPath(workspace, repoSlug), uuid))
+}
+
+// GetWebhook fetches a webhook by uuid.
+func (c *Client) GetWebhook(ctx context.Context, workspace, repoSlug, uuid string) (*Webhook, error) {
+	return c.getWebhook(ctx, hookPath(repoHooksPath(workspace, repoSlug), uuid))
+}
+
+// UpdateWebhook replaces a webhook's definition, keeping its uuid.
+func (c *Client) UpdateWebhook(ctx context.Context, workspace, repoSlug, uuid string, input WebhookInput) (*Webhook, error) {
+	return c.saveWebhook(ctx, "PUT", hookPath(repoHooksPath(workspace, repoSlug), uuid), input)
+}
+
+// ListWorkspaceWebhooks enumerates workspace webhooks. They fire for events
+// in every repository of the workspace.
+func (c *Client) ListWorkspaceWebhooks(ctx context.Context, workspace string) ([]Webhook, error) {
+	return c.listWebhooks(ctx, workspaceHooksPath(workspace))
+}
+
+// CreateWorkspaceWebhook creates a new workspace webhook.
+func (c *Client) CreateWorkspaceWebhook(ctx context.Context, workspace string, input WebhookInput) (*Webhook, error) {
+	return c.saveWebhook(ctx, "POST", workspaceHooksPath(workspace), input)
+}
+
+// DeleteWorkspaceWebhook removes a workspace webhook by uuid.
+func (c *Client) DeleteWorkspaceWebhook(ctx context.Context, workspace, uuid string) error {
+	return c.deleteWebhook(ctx, hookPath(workspaceHooksPath(workspace), uuid))
+}
+
+// GetWorkspaceWebhook fetches a workspace webhook by uuid.
+func (c *Client) GetWorkspaceWebhook(ctx context.Context, workspace, uuid string) (*Webhook, error) {
+	return c.getWebhook(ctx, hookPath(workspaceHooksPath(workspace), uuid))
+}
+
+// UpdateWorkspaceWebhook replaces a workspace webhook's definition, keeping
+// its uuid.
+func (c *Client) UpdateWorkspaceWebhook(ctx context.Context, workspace, uuid string, input WebhookInput) (*Webhook, error) {
+	return c.saveWebhook(ctx, "PUT", hookPath(workspaceHooksPath(workspace), uuid), input)
+}
+
+func repoHooksPath(workspace, repoSlug string) string {
+	return fmt.Sprintf("/repositories/%s/%s/hooks",
 		url.PathEscape(workspace),
 		url.PathEscape(repoSlug),
 	)
+}
+
+func workspaceHooksPath(workspace string) string {
+	return fmt.Sprintf("/workspaces/%s/hooks", url.PathEscape(workspace))
+}
+
+// hookPath addresses a single hook; Cloud returns uuids wrapped in braces
+// but expects them bare in paths.
+func hookPath(hooksPath, uuid string) string {
+	return hooksPath + "/" + url.PathEscape(strings.Trim(uuid, "{}"))
+}
+
+func (c *Client) listWebhooks(ctx context.Context, path string) ([]Webhook, error) {
 	req, err := c.http.NewRequest(ctx, "GET", path, nil)
 	if err != nil {
 		return nil, err
@@ -44,8 +111,7 @@ func (c *Client) ListWebhooks(ctx context.Context, workspace, repoSlug string) (
 	return resp.Values, nil
 }
 
-// CreateWebhook creates a new repository webhook.
-func (c *Client) CreateWebhook(ctx context.Context, workspace, repoSlug string, input WebhookInput) (*Webhook, error) {
+func (c *Client) saveWebhook(ctx context.Context, method, path string, input WebhookInput) (*Webhook, error) {
 	if input.URL == "" {
 		return nil, fmt.Errorf("webhook url is required")
 	}
@@ -59,12 +125,11 @@ func (c *Client) CreateWebhook(ctx context.Context, workspace, repoSlug string,
 		"events":      input.Events,
 		"active":      input.Active,
 	}
+	if input.Secret != "" {
+		body["secret"] = input.Secret
+	}
 
-	path := fmt.Sprintf("/repositories/%s/%s/hooks",
-		url.PathEscape(workspace),
-		url.PathEscape(repoSlug),
-	)
-	req, err := c.http.NewRequest(ctx, "POST", path, body)
+	req, err := c.http.NewRequest(ctx, method, path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -73,20 +138,26 @@ func (c *Client) CreateWebhook(ctx context.Context, workspace, repoSlug string,
 	if err := c.http.Do(req, &hook); err != nil {
 		return nil, err
 	}
-
 	return &hook, nil
 }
 
-// DeleteWebhook removes a webhook by uuid.
-func (c *Client) DeleteWebhook(ctx context.Context, workspace, repoSlug, uuid string) error {
-	path := fmt.Sprintf("/repositories/%s/%s/hooks/%s",
-		url.PathEscape(workspace),
-		url.PathEscape(repoSlug),
-		url.PathEscape(strings.Trim(uuid, "{}")),
-	)
+func (c *Client) deleteWebhook(ctx context.Context, path string) error {
 	req, err := c.http.NewRequest(ctx, "DELETE", path, nil)
 	if err != nil {
 		return err
 	}
 	return c.http.Do(req, nil)
 }
+
+func (c *Client) getWebhook(ctx context.Context, path string) (*Webhook, error) {
+	req, err := c.http.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var hook Webhook
+	if err := c.http.Do(req, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
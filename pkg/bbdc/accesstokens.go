@@ -0,0 +1,186 @@
+package bbdc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AccessToken describes a Bitbucket Data Center HTTP access token. The Token
+// field is only populated in the response to a create call.
+type AccessToken struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	CreatedDate       int64    `json:"createdDate"`
+	LastAuthenticated int64    `json:"lastAuthenticated,omitempty"`
+	ExpiryDays        int      `json:"expiryDays,omitempty"`
+	ExpiryDate        int64    `json:"expiryDate,omitempty"`
+	Permissions       []string `json:"permissions"`
+	User              *User    `json:"user,omitempty"`
+	Token             string   `json:"token,omitempty"`
+}
+
+// AccessTokenInput describes a token to create. Permissions use Bitbucket's
+// names such as PROJECT_READ or REPO_WRITE; ExpiryDays of zero leaves the
+// expiry to the server's policy.
+type AccessTokenInput struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	ExpiryDays  int      `json:"expiryDays,omitempty"`
+}
+
+// AuthenticatedUsername returns the username Bitbucket associates with the
+// client's credentials, as reported by the X-AUSERNAME response header. It
+// works for bearer tokens where no username was configured.
+func (c *Client) AuthenticatedUsername(ctx context.Context) (string, error) {
+	req, err := c.http.NewRequest(ctx, "GET", "/rest/api/1.0/users?limit=1", nil)
+	if err != nil {
+		return "", err
+	}
+	header, err := c.http.DoWithHeader(req, nil)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(header.Get("X-AUSERNAME"))
+	if name == "" {
+		return "", fmt.Errorf("server did not report the authenticated user")
+	}
+	return name, nil
+}
+
+// ListUserAccessTokens enumerates the personal access tokens owned by a user.
+func (c *Client) ListUserAccessTokens(ctx context.Context, userSlug string, limit int) ([]AccessToken, error) {
+	if userSlug == "" {
+		return nil, fmt.Errorf("user slug is required")
+	}
+	return c.listAccessTokens(ctx, fmt.Sprintf("/rest/access-tokens/1.0/users/%s", url.PathEscape(userSlug)), limit)
+}
+
+// CreateUserAccessToken mints a personal access token for a user. The returned
+// token carries the secret, which Bitbucket never reveals again.
+func (c *Client) CreateUserAccessToken(ctx context.Context, userSlug string, in AccessTokenInput) (*AccessToken, error) {
+	if userSlug == "" {
+		return nil, fmt.Errorf("user slug is required")
+	}
+	return c.createAccessToken(ctx, fmt.Sprintf("/rest/access-tokens/1.0/users/%s", url.PathEscape(userSlug)), in)
+}
+
+// RevokeUserAccessToken deletes one of a user's personal access tokens.
+func (c *Client) RevokeUserAccessToken(ctx context.Context, userSlug, tokenID string) error {
+	if userSlug == "" {
+		return fmt.Errorf("user slug is required")
+	}
+	return c.revokeAccessToken(ctx, fmt.Sprintf("/rest/access-tokens/1.0/users/%s", url.PathEscape(userSlug)), tokenID)
+}
+
+// ListProjectAccessTokens enumerates the HTTP access tokens owned by a project.
+func (c *Client) ListProjectAccessTokens(ctx context.Context, projectKey string, limit int) ([]AccessToken, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.listAccessTokens(ctx, projectAccessTokensPath(projectKey), limit)
+}
+
+// CreateProjectAccessToken mints an HTTP access token scoped to a project.
+func (c *Client) CreateProjectAccessToken(ctx context.Context, projectKey string, in AccessTokenInput) (*AccessToken, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.createAccessToken(ctx, projectAccessTokensPath(projectKey), in)
+}
+
+// RevokeProjectAccessToken deletes a project HTTP access token.
+func (c *Client) RevokeProjectAccessToken(ctx context.Context, projectKey, tokenID string) error {
+	if projectKey == "" {
+		return fmt.Errorf("project key is required")
+	}
+	return c.revokeAccessToken(ctx, projectAccessTokensPath(projectKey), tokenID)
+}
+
+// ListRepositoryAccessTokens enumerates the HTTP access tokens owned by a
+// repository.
+func (c *Client) ListRepositoryAccessTokens(ctx context.Context, projectKey, repoSlug string, limit int) ([]AccessToken, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.listAccessTokens(ctx, repoAccessTokensPath(projectKey, repoSlug), limit)
+}
+
+// CreateRepositoryAccessToken mints an HTTP access token scoped to a
+// repository. Only REPO_* permissions are accepted by the server.
+func (c *Client) CreateRepositoryAccessToken(ctx context.Context, projectKey, repoSlug string, in AccessTokenInput) (*AccessToken, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.createAccessToken(ctx, repoAccessTokensPath(projectKey, repoSlug), in)
+}
+
+// RevokeRepositoryAccessToken deletes a repository HTTP access token.
+func (c *Client) RevokeRepositoryAccessToken(ctx context.Context, projectKey, repoSlug, tokenID string) error {
+	if projectKey == "" || repoSlug == "" {
+		return fmt.Errorf("project key and repository slug are required")
+	}
+	return c.revokeAccessToken(ctx, repoAccessTokensPath(projectKey, repoSlug), tokenID)
+}
+
+func projectAccessTokensPath(projectKey string) string {
+	return fmt.Sprintf("/rest/access-tokens/1.0/projects/%s", url.PathEscape(projectKey))
+}
+
+func repoAccessTokensPath(projectKey, repoSlug string) string {
+	return fmt.Sprintf("/rest/access-tokens/1.0/projects/%s/repos/%s", url.PathEscape(projectKey), url.PathEscape(repoSlug))
+}
+
+func (c *Client) createAccessToken(ctx context.Context, path string, in AccessTokenInput) (*AccessToken, error) {
+	if strings.TrimSpace(in.Name) == "" || len(in.Permissions) == 0 {
+		return nil, fmt.Errorf("token name and at least one permission are required")
+	}
+	req, err := c.http.NewRequest(ctx, "PUT", path, in)
+	if err != nil {
+		return nil, err
+	}
+	var token AccessToken
+	if err := c.http.Do(req, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (c *Client) revokeAccessToken(ctx context.Context, path, tokenID string) error {
+	if tokenID == "" {
+		return fmt.Errorf("token id is required")
+	}
+	req, err := c.http.NewRequest(ctx, "DELETE", path+"/"+url.PathEscape(tokenID), nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+func (c *Client) listAccessTokens(ctx context.Context, path string, limit int) ([]AccessToken, error) {
+	pageLimit := valueOrPositive(limit, 100)
+	start := 0
+	var out []AccessToken
+
+	for {
+		u := fmt.Sprintf("%s?limit=%d&start=%d", path, pageLimit, start)
+		req, err := c.http.NewRequest(ctx, "GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		var resp paged[AccessToken]
+		if err := c.http.Do(req, &resp); err != nil {
+			return nil, err
+		}
+		out = append(out, resp.Values...)
+		if resp.IsLastPage || len(resp.Values) == 0 || (limit > 0 && len(out) >= limit) {
+			if limit > 0 && len(out) > limit {
+				out = out[:limit]
+			}
+			break
+		}
+		start = resp.NextPageStart
+	}
+	return out, nil
+}
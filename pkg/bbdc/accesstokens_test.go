@@ -0,0 +1,67 @@
+package bbdc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestAuthenticatedUsernameReadsHeader(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-AUSERNAME", "alice")
+		_, _ = w.Write([]byte(`{"values":[]}`))
+	}))
+
+	got, err := client.AuthenticatedUsername(context.Background())
+	if err != nil {
+		t.Fatalf("AuthenticatedUsername: %v", err)
+	}
+	if got != "alice" {
+		t.Fatalf("username = %q, want alice", got)
+	}
+}
+
+func TestCreateUserAccessTokenSendsPermissionsAndExpiry(t *testing.T) {
+	var body map[string]any
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/access-tokens/1.0/users/alice" {
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"id":"1","name":"ci","permissions":["REPO_READ"],"token":"s3cret"}`))
+	}))
+
+	tok, err := client.CreateUserAccessToken(context.Background(), "alice", AccessTokenInput{
+		Name:        "ci",
+		Permissions: []string{"REPO_READ"},
+		ExpiryDays:  30,
+	})
+	if err != nil {
+		t.Fatalf("CreateUserAccessToken: %v", err)
+	}
+	if tok.Token != "s3cret" {
+		t.Fatalf("token = %q, want s3cret", tok.Token)
+	}
+	if body["expiryDays"] != float64(30) || body["name"] != "ci" {
+		t.Fatalf("unexpected request body: %v", body)
+	}
+}
+
+func TestListUserAccessTokensPaginates(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "0" {
+			_, _ = w.Write([]byte(`{"values":[{"id":"1"}],"isLastPage":false,"nextPageStart":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values":[{"id":"2"}],"isLastPage":true}`))
+	}))
+
+	tokens, err := client.ListUserAccessTokens(context.Background(), "alice", 0)
+	if err != nil {
+		t.Fatalf("ListUserAccessTokens: %v", err)
+	}
+	if len(tokens) != 2 || tokens[1].ID != "2" {
+		t.Fatalf("tokens = %+v, want two pages", tokens)
+	}
+}
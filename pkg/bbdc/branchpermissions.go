@@ -31,24 +31,23 @@ type BranchRestrictionInput struct {
 	Groups      []string
 }
 
-// ListBranchRestrictions lists restriction rules for the repository.
+// ListBranchRestrictions lists every restriction rule for the repository.
 func (c *Client) ListBranchRestrictions(ctx context.Context, projectKey, repoSlug string) ([]BranchRestriction, error) {
-	req, err := c.http.NewRequest(ctx, "GET", fmt.Sprintf("/rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions",
+	return listPaged[BranchRestriction](ctx, c, fmt.Sprintf("/rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions",
 		url.PathEscape(projectKey),
 		url.PathEscape(repoSlug),
-	), nil)
-	if err != nil {
-		return nil, err
-	}
+	), 0)
+}
 
-	var resp struct {
-		Values []BranchRestriction `json:"values"`
-	}
-	if err := c.http.Do(req, &resp); err != nil {
-		return nil, err
+// NormalizeRestrictionType returns the branch-permissions API id of a
+// restriction type (no-deletes, read-only, pull-request-only, ...), also
+// accepting the upper-case constant spelling (NO_DELETES, PULL_REQUEST).
+func NormalizeRestrictionType(t string) string {
+	id := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(t)), "_", "-")
+	if id == "pull-request" {
+		return "pull-request-only"
 	}
-
-	return resp.Values, nil
+	return id
 }
 
 // CreateBranchRestriction creates a new restriction.
@@ -64,7 +63,7 @@ func (c *Client) CreateBranchRestriction(ctx context.Context, projectKey, repoSl
 	}
 
 	body := map[string]any{
-		"type": map[string]any{"id": strings.ToUpper(in.Type)},
+		"type": NormalizeRestrictionType(in.Type),
 		"matcher": map[string]any{
 			"id":        in.MatcherID,
 			"displayId": in.MatcherID,
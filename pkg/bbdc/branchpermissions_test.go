@@ -0,0 +1,39 @@
+package bbdc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestListBranchRestrictionsPaginates(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("start") == "0" {
+			_, _ = w.Write([]byte(`{"values":[{"id":1,"type":"no-deletes"}],"isLastPage":false,"nextPageStart":1}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"values":[{"id":2,"type":"read-only"}],"isLastPage":true}`))
+	}))
+
+	restrictions, err := client.ListBranchRestrictions(context.Background(), "PROJ", "api")
+	if err != nil {
+		t.Fatalf("ListBranchRestrictions: %v", err)
+	}
+	if len(restrictions) != 2 || restrictions[1].ID != 2 {
+		t.Fatalf("restrictions = %+v, want two pages", restrictions)
+	}
+}
+
+func TestNormalizeRestrictionType(t *testing.T) {
+	for in, want := range map[string]string{
+		"no-deletes":        "no-deletes",
+		"NO_DELETES":        "no-deletes",
+		"FAST_FORWARD_ONLY": "fast-forward-only",
+		"PULL_REQUEST":      "pull-request-only",
+		"pull-request-only": "pull-request-only",
+	} {
+		if got := NormalizeRestrictionType(in); got != want {
+			t.Errorf("NormalizeRestrictionType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
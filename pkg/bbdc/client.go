@@ -181,17 +181,31 @@ func (c *Client) CurrentUser(ctx context.Context, userSlug string) (*User, error
 
 // ListRepositories enumerates repositories for a project, handling pagination.
 func (c *Client) ListRepositories(ctx context.Context, projectKey string, limit int) ([]Repository, error) {
+	var found []Repository
+	err := c.EachRepository(ctx, projectKey, limit, func(repo Repository) error {
+		found = append(found, repo)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// EachRepository calls fn for each repository in a project as pages arrive,
+// stopping after limit repositories (0 for all) or at the first error.
+func (c *Client) EachRepository(ctx context.Context, projectKey string, limit int, fn func(Repository) error) error {
 	const defaultPageSize = 25
 
 	var (
 		start = 0
-		found []Repository
+		seen  = 0
 	)
 
 	for {
 		pageSize := defaultPageSize
 		if limit > 0 {
-			remaining := limit - len(found)
+			remaining := limit - seen
 			if remaining <= 0 {
 				break
 			}
@@ -202,14 +216,17 @@ func (c *Client) ListRepositories(ctx context.Context, projectKey string, limit
 
 		page, err := c.ListRepositoriesPage(ctx, projectKey, pageSize, start)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		found = append(found, page.Values...)
-
-		if limit > 0 && len(found) >= limit {
-			found = found[:limit]
-			break
+		for _, repo := range page.Values {
+			if limit > 0 && seen >= limit {
+				return nil
+			}
+			if err := fn(repo); err != nil {
+				return err
+			}
+			seen++
 		}
 
 		if page.IsLast || len(page.Values) == 0 {
@@ -218,7 +235,7 @@ func (c *Client) ListRepositories(ctx context.Context, projectKey string, limit
 		start = page.NextStart
 	}
 
-	return found, nil
+	return nil
 }
 
 // ListRepositoriesPage fetches one repository page and preserves upstream
@@ -377,15 +394,30 @@ func (c *Client) ListPullRequests(ctx context.Context, projectKey, repoSlug, sta
 // and terminates on the last or an empty page. opts.Start is the initial page
 // offset.
 func (c *Client) ListPullRequestsWithOptions(ctx context.Context, projectKey, repoSlug string, opts RepoPullRequestsOptions) ([]PullRequest, error) {
+	var all []PullRequest
+	err := c.EachPullRequest(ctx, projectKey, repoSlug, opts, func(pr PullRequest) error {
+		all = append(all, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// EachPullRequest is the streaming form of ListPullRequestsWithOptions: fn
+// receives each pull request as its page arrives, and paging stops at
+// opts.Limit or the first error fn returns.
+func (c *Client) EachPullRequest(ctx context.Context, projectKey, repoSlug string, opts RepoPullRequestsOptions, fn func(PullRequest) error) error {
 	const defaultPageSize = 25
 
-	var all []PullRequest
+	seen := 0
 	start := opts.Start
 
 	for {
 		pageSize := defaultPageSize
 		if opts.Limit > 0 {
-			remaining := opts.Limit - len(all)
+			remaining := opts.Limit - seen
 			if remaining <= 0 {
 				break
 			}
@@ -402,10 +434,18 @@ func (c *Client) ListPullRequestsWithOptions(ctx context.Context, projectKey, re
 			Start:    start,
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		all = append(all, page.Values...)
+		for _, pr := range page.Values {
+			if opts.Limit > 0 && seen >= opts.Limit {
+				return nil
+			}
+			if err := fn(pr); err != nil {
+				return err
+			}
+			seen++
+		}
 
 		if page.IsLast || len(page.Values) == 0 {
 			break
@@ -413,11 +453,7 @@ func (c *Client) ListPullRequestsWithOptions(ctx context.Context, projectKey, re
 		start = page.NextStart
 	}
 
-	if opts.Limit > 0 && len(all) > opts.Limit {
-		all = all[:opts.Limit]
-	}
-
-	return all, nil
+	return nil
 }
 
 // CommitStatuses returns build statuses for a commit.
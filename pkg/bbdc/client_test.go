@@ -3,6 +3,7 @@ package bbdc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -101,6 +102,36 @@ func TestListRepositoriesPaginates(t *testing.T) {
 	}
 }
 
+func TestEachRepositoryStopsOnCallbackError(t *testing.T) {
+	var hits int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(paged[Repository]{
+			Values:        []Repository{{Slug: "repo1"}, {Slug: "repo2"}},
+			IsLastPage:    false,
+			NextPageStart: 2,
+		})
+	})
+
+	client := newTestClient(t, handler)
+	stop := errors.New("stop")
+	var seen []string
+	err := client.EachRepository(context.Background(), "PROJ", 0, func(repo Repository) error {
+		seen = append(seen, repo.Slug)
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("EachRepository error = %v, want stop", err)
+	}
+	if len(seen) != 1 || seen[0] != "repo1" {
+		t.Fatalf("callback saw %v, want [repo1]", seen)
+	}
+	if hits != 1 {
+		t.Fatalf("expected 1 request, got %d", hits)
+	}
+}
+
 func TestListRepositoriesPageReturnsContinuation(t *testing.T) {
 	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/rest/api/1.0/projects/PROJ/repos" {
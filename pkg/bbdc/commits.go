@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
 )
 
 // CommitDiff streams the raw unified diff between two refs into w.
@@ -43,3 +46,35 @@ func (c *Client) CommitDiff(ctx context.Context, projectKey, repoSlug, from, to
 
 	return c.http.Do(req, w)
 }
+
+// FileContent streams the raw content of path at ref into w. An empty ref
+// reads the repository's default branch.
+func (c *Client) FileContent(ctx context.Context, projectKey, repoSlug, path, ref string, w io.Writer) error {
+	if projectKey == "" || repoSlug == "" {
+		return fmt.Errorf("project key and repository slug are required")
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return fmt.Errorf("file path is required")
+	}
+	if w == nil {
+		return fmt.Errorf("writer is required")
+	}
+
+	u := fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/raw/%s",
+		url.PathEscape(projectKey),
+		url.PathEscape(repoSlug),
+		httpx.EscapePathSegments(path),
+	)
+	if ref != "" {
+		u += "?at=" + url.QueryEscape(ref)
+	}
+
+	req, err := c.http.NewRequest(ctx, "GET", u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/plain")
+
+	return c.http.Do(req, w)
+}
@@ -149,3 +149,27 @@ func TestCommitDiffSetsAcceptHeader(t *testing.T) {
 		t.Errorf("Accept header = %q, want text/plain", gotAccept)
 	}
 }
+
+func TestFileContentEscapesPathAndPassesRef(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.EscapedPath(); got != "/rest/api/1.0/projects/PROJ/repos/my-repo/raw/docs/read%20me.md" {
+			t.Errorf("escaped path = %q", got)
+		}
+		if got := r.URL.Query().Get("at"); got != "refs/heads/feature/x" {
+			t.Errorf("at = %q", got)
+		}
+		_, _ = w.Write([]byte("# Hello\n"))
+	})
+
+	client := newTestClient(t, handler)
+	var buf bytes.Buffer
+	if err := client.FileContent(context.Background(), "PROJ", "my-repo", "/docs/read me.md", "refs/heads/feature/x", &buf); err != nil {
+		t.Fatalf("FileContent: %v", err)
+	}
+	if buf.String() != "# Hello\n" {
+		t.Errorf("content = %q", buf.String())
+	}
+	if err := client.FileContent(context.Background(), "PROJ", "my-repo", "/", "", &buf); err == nil {
+		t.Error("expected error for empty path")
+	}
+}
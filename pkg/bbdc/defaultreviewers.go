@@ -0,0 +1,180 @@
+package bbdc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// RefMatcher selects the refs a default reviewer condition applies to. Type.ID
+// is ANY_REF, BRANCH, PATTERN, MODEL_BRANCH, or MODEL_CATEGORY.
+type RefMatcher struct {
+	ID        string `json:"id"`
+	DisplayID string `json:"displayId,omitempty"`
+	Type      struct {
+		ID   string `json:"id"`
+		Name string `json:"name,omitempty"`
+	} `json:"type"`
+}
+
+// DefaultReviewerCondition adds Reviewers to pull requests whose source and
+// target refs match, requiring RequiredApprovals of them to approve. Scope.Type
+// is PROJECT for conditions inherited from the project.
+type DefaultReviewerCondition struct {
+	ID                int                `json:"id"`
+	Scope             ReviewerGroupScope `json:"scope"`
+	SourceRefMatcher  RefMatcher         `json:"sourceRefMatcher"`
+	TargetRefMatcher  RefMatcher         `json:"targetRefMatcher"`
+	Reviewers         []User             `json:"reviewers"`
+	RequiredApprovals int                `json:"requiredApprovals"`
+}
+
+// DefaultReviewerConditionInput describes a condition to create or replace.
+// Matchers are given as a type ID and matcher ID; reviewers by user ID.
+type DefaultReviewerConditionInput struct {
+	SourceType        string
+	SourceID          string
+	TargetType        string
+	TargetID          string
+	ReviewerIDs       []int
+	RequiredApprovals int
+}
+
+// ListDefaultReviewerConditions returns the default reviewer conditions that
+// apply to a repository, including those inherited from its project.
+func (c *Client) ListDefaultReviewerConditions(ctx context.Context, projectKey, repoSlug string) ([]DefaultReviewerCondition, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.listDefaultReviewerConditions(ctx, repoDefaultReviewersPath(projectKey, repoSlug))
+}
+
+// CreateDefaultReviewerCondition adds a repository default reviewer condition.
+func (c *Client) CreateDefaultReviewerCondition(ctx context.Context, projectKey, repoSlug string, in DefaultReviewerConditionInput) (*DefaultReviewerCondition, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.saveDefaultReviewerCondition(ctx, "POST", repoDefaultReviewersPath(projectKey, repoSlug)+"/condition", in)
+}
+
+// UpdateDefaultReviewerCondition replaces a repository default reviewer
+// condition, keeping its ID.
+func (c *Client) UpdateDefaultReviewerCondition(ctx context.Context, projectKey, repoSlug string, id int, in DefaultReviewerConditionInput) (*DefaultReviewerCondition, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.saveDefaultReviewerCondition(ctx, "PUT", fmt.Sprintf("%s/condition/%d", repoDefaultReviewersPath(projectKey, repoSlug), id), in)
+}
+
+// DeleteDefaultReviewerCondition removes a repository default reviewer
+// condition by ID.
+func (c *Client) DeleteDefaultReviewerCondition(ctx context.Context, projectKey, repoSlug string, id int) error {
+	if projectKey == "" || repoSlug == "" {
+		return fmt.Errorf("project key and repository slug are required")
+	}
+	return c.deleteDefaultReviewerCondition(ctx, repoDefaultReviewersPath(projectKey, repoSlug), id)
+}
+
+// ListProjectDefaultReviewerConditions returns a project's default reviewer
+// conditions. They apply to every repository in the project.
+func (c *Client) ListProjectDefaultReviewerConditions(ctx context.Context, projectKey string) ([]DefaultReviewerCondition, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.listDefaultReviewerConditions(ctx, projectDefaultReviewersPath(projectKey))
+}
+
+// CreateProjectDefaultReviewerCondition adds a project default reviewer
+// condition.
+func (c *Client) CreateProjectDefaultReviewerCondition(ctx context.Context, projectKey string, in DefaultReviewerConditionInput) (*DefaultReviewerCondition, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.saveDefaultReviewerCondition(ctx, "POST", projectDefaultReviewersPath(projectKey)+"/condition", in)
+}
+
+// UpdateProjectDefaultReviewerCondition replaces a project default reviewer
+// condition, keeping its ID.
+func (c *Client) UpdateProjectDefaultReviewerCondition(ctx context.Context, projectKey string, id int, in DefaultReviewerConditionInput) (*DefaultReviewerCondition, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.saveDefaultReviewerCondition(ctx, "PUT", fmt.Sprintf("%s/condition/%d", projectDefaultReviewersPath(projectKey), id), in)
+}
+
+// DeleteProjectDefaultReviewerCondition removes a project default reviewer
+// condition by ID.
+func (c *Client) DeleteProjectDefaultReviewerCondition(ctx context.Context, projectKey string, id int) error {
+	if projectKey == "" {
+		return fmt.Errorf("project key is required")
+	}
+	return c.deleteDefaultReviewerCondition(ctx, projectDefaultReviewersPath(projectKey), id)
+}
+
+func (c *Client) listDefaultReviewerConditions(ctx context.Context, base string) ([]DefaultReviewerCondition, error) {
+	req, err := c.http.NewRequest(ctx, "GET", base+"/conditions", nil)
+	if err != nil {
+		return nil, err
+	}
+	var conditions []DefaultReviewerCondition
+	if err := c.http.Do(req, &conditions); err != nil {
+		return nil, err
+	}
+	return conditions, nil
+}
+
+func (c *Client) saveDefaultReviewerCondition(ctx context.Context, method, path string, in DefaultReviewerConditionInput) (*DefaultReviewerCondition, error) {
+	if in.SourceType == "" || in.TargetType == "" {
+		return nil, fmt.Errorf("source and target matchers are required")
+	}
+	if len(in.ReviewerIDs) == 0 {
+		return nil, fmt.Errorf("at least one reviewer is required")
+	}
+	if in.RequiredApprovals < 0 || in.RequiredApprovals > len(in.ReviewerIDs) {
+		return nil, fmt.Errorf("required approvals must be between 0 and the number of reviewers (%d)", len(in.ReviewerIDs))
+	}
+
+	reviewers := make([]map[string]int, 0, len(in.ReviewerIDs))
+	for _, id := range in.ReviewerIDs {
+		reviewers = append(reviewers, map[string]int{"id": id})
+	}
+	body := map[string]any{
+		"sourceMatcher": map[string]any{
+			"id":   in.SourceID,
+			"type": map[string]string{"id": in.SourceType},
+		},
+		"targetMatcher": map[string]any{
+			"id":   in.TargetID,
+			"type": map[string]string{"id": in.TargetType},
+		},
+		"reviewers":         reviewers,
+		"requiredApprovals": in.RequiredApprovals,
+	}
+
+	req, err := c.http.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var condition DefaultReviewerCondition
+	if err := c.http.Do(req, &condition); err != nil {
+		return nil, err
+	}
+	return &condition, nil
+}
+
+func (c *Client) deleteDefaultReviewerCondition(ctx context.Context, base string, id int) error {
+	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/condition/%d", base, id), nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+func repoDefaultReviewersPath(projectKey, repoSlug string) string {
+	return fmt.Sprintf("/rest/default-reviewers/1.0/projects/%s/repos/%s",
+		url.PathEscape(projectKey), url.PathEscape(repoSlug))
+}
+
+func projectDefaultReviewersPath(projectKey string) string {
+	return fmt.Sprintf("/rest/default-reviewers/1.0/projects/%s", url.PathEscape(projectKey))
+}
@@ -13,13 +13,23 @@ type Permission struct {
 	Permission string `json:"permission"`
 }
 
+// Group identifies a Bitbucket user group.
+type Group struct {
+	Name string `json:"name"`
+}
+
+// GroupPermission represents a permission granted to a group.
+type GroupPermission struct {
+	Group      Group  `json:"group"`
+	Permission string `json:"permission"`
+}
+
 // ListRepoPermissions returns repository user permissions.
 func (c *Client) ListRepoPermissions(ctx context.Context, projectKey, repoSlug string, limit int) ([]Permission, error) {
 	if projectKey == "" || repoSlug == "" {
 		return nil, fmt.Errorf("project key and repository slug are required")
 	}
-	return c.listPermissions(ctx, fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/permissions/users",
-		url.PathEscape(projectKey), url.PathEscape(repoSlug)), limit)
+	return listPaged[Permission](ctx, c, repoPermissionsPath(projectKey, repoSlug, "users"), limit)
 }
 
 // ListProjectPermissions returns project user permissions.
@@ -27,21 +37,51 @@ func (c *Client) ListProjectPermissions(ctx context.Context, projectKey string,
 	if projectKey == "" {
 		return nil, fmt.Errorf("project key is required")
 	}
-	return c.listPermissions(ctx, fmt.Sprintf("/rest/api/1.0/projects/%s/permissions/users", url.PathEscape(projectKey)), limit)
+	return listPaged[Permission](ctx, c, projectPermissionsPath(projectKey, "users"), limit)
+}
+
+// ListRepoGroupPermissions returns repository group permissions.
+func (c *Client) ListRepoGroupPermissions(ctx context.Context, projectKey, repoSlug string, limit int) ([]GroupPermission, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return listPaged[GroupPermission](ctx, c, repoPermissionsPath(projectKey, repoSlug, "groups"), limit)
+}
+
+// ListProjectGroupPermissions returns project group permissions.
+func (c *Client) ListProjectGroupPermissions(ctx context.Context, projectKey string, limit int) ([]GroupPermission, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return listPaged[GroupPermission](ctx, c, projectPermissionsPath(projectKey, "groups"), limit)
+}
+
+// ListGroupMembers returns the users in a group. Data Center only exposes
+// group membership to users with at least LICENSED_USER permission, and some
+// instances restrict it to administrators.
+func (c *Client) ListGroupMembers(ctx context.Context, group string, limit int) ([]User, error) {
+	if group == "" {
+		return nil, fmt.Errorf("group name is required")
+	}
+	return listPaged[User](ctx, c, "/rest/api/1.0/admin/groups/more-members?context="+url.QueryEscape(group), limit)
 }
 
-func (c *Client) listPermissions(ctx context.Context, path string, limit int) ([]Permission, error) {
+func listPaged[T any](ctx context.Context, c *Client, path string, limit int) ([]T, error) {
 	pageLimit := valueOrPositive(limit, 100)
 	start := 0
-	var out []Permission
+	var out []T
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
 
 	for {
-		u := fmt.Sprintf("%s?limit=%d&start=%d", path, pageLimit, start)
+		u := fmt.Sprintf("%s%slimit=%d&start=%d", path, sep, pageLimit, start)
 		req, err := c.http.NewRequest(ctx, "GET", u, nil)
 		if err != nil {
 			return nil, err
 		}
-		var resp paged[Permission]
+		var resp paged[T]
 		if err := c.http.Do(req, &resp); err != nil {
 			return nil, err
 		}
@@ -62,17 +102,7 @@ func (c *Client) GrantRepoPermission(ctx context.Context, projectKey, repoSlug,
 	if projectKey == "" || repoSlug == "" || username == "" || permission == "" {
 		return fmt.Errorf("project, repo, username, and permission are required")
 	}
-
-	req, err := c.http.NewRequest(ctx, "PUT", fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/permissions/users?name=%s&permission=%s",
-		url.PathEscape(projectKey),
-		url.PathEscape(repoSlug),
-		url.QueryEscape(username),
-		url.QueryEscape(strings.ToUpper(permission)),
-	), nil)
-	if err != nil {
-		return err
-	}
-	return c.http.Do(req, nil)
+	return c.putPermission(ctx, repoPermissionsPath(projectKey, repoSlug, "users"), username, permission)
 }
 
 // GrantProjectPermission assigns a permission to a user for a project.
@@ -80,16 +110,7 @@ func (c *Client) GrantProjectPermission(ctx context.Context, projectKey, usernam
 	if projectKey == "" || username == "" || permission == "" {
 		return fmt.Errorf("project key, username, and permission are required")
 	}
-
-	req, err := c.http.NewRequest(ctx, "PUT", fmt.Sprintf("/rest/api/1.0/projects/%s/permissions/users?name=%s&permission=%s",
-		url.PathEscape(projectKey),
-		url.QueryEscape(username),
-		url.QueryEscape(strings.ToUpper(permission)),
-	), nil)
-	if err != nil {
-		return err
-	}
-	return c.http.Do(req, nil)
+	return c.putPermission(ctx, projectPermissionsPath(projectKey, "users"), username, permission)
 }
 
 // RevokeRepoPermission removes a repository permission for a user.
@@ -97,16 +118,7 @@ func (c *Client) RevokeRepoPermission(ctx context.Context, projectKey, repoSlug,
 	if projectKey == "" || repoSlug == "" || username == "" {
 		return fmt.Errorf("project, repo, and username are required")
 	}
-
-	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/permissions/users?name=%s",
-		url.PathEscape(projectKey),
-		url.PathEscape(repoSlug),
-		url.QueryEscape(username),
-	), nil)
-	if err != nil {
-		return err
-	}
-	return c.http.Do(req, nil)
+	return c.deletePermission(ctx, repoPermissionsPath(projectKey, repoSlug, "users"), username)
 }
 
 // RevokeProjectPermission removes a project permission for a user.
@@ -114,13 +126,66 @@ func (c *Client) RevokeProjectPermission(ctx context.Context, projectKey, userna
 	if projectKey == "" || username == "" {
 		return fmt.Errorf("project key and username are required")
 	}
+	return c.deletePermission(ctx, projectPermissionsPath(projectKey, "users"), username)
+}
+
+// GrantRepoGroupPermission assigns a permission to a group for a repository.
+func (c *Client) GrantRepoGroupPermission(ctx context.Context, projectKey, repoSlug, group, permission string) error {
+	if projectKey == "" || repoSlug == "" || group == "" || permission == "" {
+		return fmt.Errorf("project, repo, group, and permission are required")
+	}
+	return c.putPermission(ctx, repoPermissionsPath(projectKey, repoSlug, "groups"), group, permission)
+}
+
+// GrantProjectGroupPermission assigns a permission to a group for a project.
+func (c *Client) GrantProjectGroupPermission(ctx context.Context, projectKey, group, permission string) error {
+	if projectKey == "" || group == "" || permission == "" {
+		return fmt.Errorf("project key, group, and permission are required")
+	}
+	return c.putPermission(ctx, projectPermissionsPath(projectKey, "groups"), group, permission)
+}
 
-	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("/rest/api/1.0/projects/%s/permissions/users?name=%s",
-		url.PathEscape(projectKey),
-		url.QueryEscape(username),
+// RevokeRepoGroupPermission removes a repository permission for a group.
+func (c *Client) RevokeRepoGroupPermission(ctx context.Context, projectKey, repoSlug, group string) error {
+	if projectKey == "" || repoSlug == "" || group == "" {
+		return fmt.Errorf("project, repo, and group are required")
+	}
+	return c.deletePermission(ctx, repoPermissionsPath(projectKey, repoSlug, "groups"), group)
+}
+
+// RevokeProjectGroupPermission removes a project permission for a group.
+func (c *Client) RevokeProjectGroupPermission(ctx context.Context, projectKey, group string) error {
+	if projectKey == "" || group == "" {
+		return fmt.Errorf("project key and group are required")
+	}
+	return c.deletePermission(ctx, projectPermissionsPath(projectKey, "groups"), group)
+}
+
+func (c *Client) putPermission(ctx context.Context, path, name, permission string) error {
+	req, err := c.http.NewRequest(ctx, "PUT", fmt.Sprintf("%s?name=%s&permission=%s",
+		path,
+		url.QueryEscape(name),
+		url.QueryEscape(strings.ToUpper(permission)),
 	), nil)
 	if err != nil {
 		return err
 	}
 	return c.http.Do(req, nil)
 }
+
+func (c *Client) deletePermission(ctx context.Context, path, name string) error {
+	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("%s?name=%s", path, url.QueryEscape(name)), nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
+func projectPermissionsPath(projectKey, kind string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/permissions/%s", url.PathEscape(projectKey), kind)
+}
+
+func repoPermissionsPath(projectKey, repoSlug, kind string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/permissions/%s",
+		url.PathEscape(projectKey), url.PathEscape(repoSlug), kind)
+}
@@ -0,0 +1,183 @@
+package bbdc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// BranchModelConfig is a repository's branching model configuration. A nil
+// Production means no production branch is configured.
+type BranchModelConfig struct {
+	Development *BranchModelBranch `json:"development,omitempty"`
+	Production  *BranchModelBranch `json:"production,omitempty"`
+	Types       []BranchModelType  `json:"types,omitempty"`
+}
+
+// BranchModelBranch selects a development or production branch. UseDefault
+// follows the repository's default branch instead of RefID.
+type BranchModelBranch struct {
+	RefID      string `json:"refId,omitempty"`
+	UseDefault bool   `json:"useDefault"`
+}
+
+// BranchModelType is a branch type (BUGFIX, FEATURE, HOTFIX, RELEASE) and the
+// prefix that identifies it.
+type BranchModelType struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	Prefix      string `json:"prefix,omitempty"`
+}
+
+// MergeStrategy is a pull request merge strategy such as no-ff or squash.
+type MergeStrategy struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// MergeConfig lists the merge strategies available to a repository.
+type MergeConfig struct {
+	DefaultStrategy *MergeStrategy  `json:"defaultStrategy,omitempty"`
+	Strategies      []MergeStrategy `json:"strategies"`
+	Type            string          `json:"type,omitempty"`
+}
+
+// PullRequestSettings holds a repository's merge checks and merge strategies.
+type PullRequestSettings struct {
+	MergeConfig              MergeConfig `json:"mergeConfig"`
+	RequiredApprovers        int         `json:"requiredApprovers"`
+	RequiredAllApprovers     bool        `json:"requiredAllApprovers"`
+	RequiredAllTasksComplete bool        `json:"requiredAllTasksComplete"`
+	RequiredSuccessfulBuilds int         `json:"requiredSuccessfulBuilds"`
+}
+
+// PullRequestSettingsInput updates pull request settings; nil fields are
+// left unchanged. Strategies, when set, replaces the enabled strategy IDs and
+// requires DefaultStrategy.
+type PullRequestSettingsInput struct {
+	RequiredApprovers        *int
+	RequiredAllTasksComplete *bool
+	RequiredSuccessfulBuilds *int
+	Strategies               []string
+	DefaultStrategy          string
+}
+
+// GetBranchModelConfig returns the repository's branching model.
+func (c *Client) GetBranchModelConfig(ctx context.Context, projectKey, repoSlug string) (*BranchModelConfig, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	req, err := c.http.NewRequest(ctx, "GET", branchModelConfigPath(projectKey, repoSlug), nil)
+	if err != nil {
+		return nil, err
+	}
+	var cfg BranchModelConfig
+	if err := c.http.Do(req, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// UpdateBranchModelConfig replaces the repository's branching model.
+func (c *Client) UpdateBranchModelConfig(ctx context.Context, projectKey, repoSlug string, cfg BranchModelConfig) (*BranchModelConfig, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	if cfg.Development == nil {
+		return nil, fmt.Errorf("a development branch is required")
+	}
+	req, err := c.http.NewRequest(ctx, "PUT", branchModelConfigPath(projectKey, repoSlug), cfg)
+	if err != nil {
+		return nil, err
+	}
+	var updated BranchModelConfig
+	if err := c.http.Do(req, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// GetPullRequestSettings returns the repository's merge checks and merge
+// strategies.
+func (c *Client) GetPullRequestSettings(ctx context.Context, projectKey, repoSlug string) (*PullRequestSettings, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	req, err := c.http.NewRequest(ctx, "GET", pullRequestSettingsPath(projectKey, repoSlug), nil)
+	if err != nil {
+		return nil, err
+	}
+	var settings PullRequestSettings
+	if err := c.http.Do(req, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// UpdatePullRequestSettings changes the repository's merge checks and merge
+// strategies.
+func (c *Client) UpdatePullRequestSettings(ctx context.Context, projectKey, repoSlug string, in PullRequestSettingsInput) (*PullRequestSettings, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+
+	body := map[string]any{}
+	if in.RequiredApprovers != nil {
+		body["requiredApprovers"] = *in.RequiredApprovers
+	}
+	if in.RequiredAllTasksComplete != nil {
+		body["requiredAllTasksComplete"] = *in.RequiredAllTasksComplete
+	}
+	if in.RequiredSuccessfulBuilds != nil {
+		body["requiredSuccessfulBuilds"] = *in.RequiredSuccessfulBuilds
+	}
+	if in.Strategies != nil {
+		if in.DefaultStrategy == "" {
+			return nil, fmt.Errorf("a default merge strategy is required")
+		}
+		strategies := make([]map[string]string, 0, len(in.Strategies))
+		for _, id := range in.Strategies {
+			strategies = append(strategies, map[string]string{"id": id})
+		}
+		body["mergeConfig"] = map[string]any{
+			"defaultStrategy": map[string]string{"id": in.DefaultStrategy},
+			"strategies":      strategies,
+		}
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("no pull request settings to update")
+	}
+
+	req, err := c.http.NewRequest(ctx, "POST", pullRequestSettingsPath(projectKey, repoSlug), body)
+	if err != nil {
+		return nil, err
+	}
+	var settings PullRequestSettings
+	if err := c.http.Do(req, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// EnabledStrategies returns the IDs of the enabled merge strategies.
+func (m MergeConfig) EnabledStrategies() []string {
+	var ids []string
+	for _, s := range m.Strategies {
+		if s.Enabled {
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids
+}
+
+func branchModelConfigPath(projectKey, repoSlug string) string {
+	return fmt.Sprintf("/rest/branch-utils/1.0/projects/%s/repos/%s/branchmodel/configuration",
+		url.PathEscape(projectKey), url.PathEscape(repoSlug))
+}
+
+func pullRequestSettingsPath(projectKey, repoSlug string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/settings/pull-requests",
+		url.PathEscape(projectKey), url.PathEscape(repoSlug))
+}
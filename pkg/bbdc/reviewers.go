@@ -28,13 +28,85 @@ type ProjectReviewerGroup struct {
 	Users       []User             `json:"users"`
 }
 
+// ReviewerGroupInput is the complete reviewer group definition sent on create
+// and update; Data Center replaces the stored group with it. Users are
+// usernames.
+type ReviewerGroupInput struct {
+	Name        string
+	Description string
+	Users       []string
+}
+
 // ListProjectReviewerGroups returns the reviewer groups defined in a project's
 // settings. A limit of 0 returns all groups.
 func (c *Client) ListProjectReviewerGroups(ctx context.Context, projectKey string, limit int) ([]ProjectReviewerGroup, error) {
 	if projectKey == "" {
 		return nil, fmt.Errorf("project key is required")
 	}
+	return c.listReviewerGroups(ctx, projectReviewerGroupsPath(projectKey), limit)
+}
+
+// CreateProjectReviewerGroup defines a reviewer group in a project's settings.
+func (c *Client) CreateProjectReviewerGroup(ctx context.Context, projectKey string, in ReviewerGroupInput) (*ProjectReviewerGroup, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.saveReviewerGroup(ctx, "POST", projectReviewerGroupsPath(projectKey), in)
+}
+
+// UpdateProjectReviewerGroup replaces a project reviewer group, keeping its ID.
+func (c *Client) UpdateProjectReviewerGroup(ctx context.Context, projectKey string, id int, in ReviewerGroupInput) (*ProjectReviewerGroup, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.saveReviewerGroup(ctx, "PUT", fmt.Sprintf("%s/%d", projectReviewerGroupsPath(projectKey), id), in)
+}
+
+// DeleteProjectReviewerGroup removes a project reviewer group by ID.
+func (c *Client) DeleteProjectReviewerGroup(ctx context.Context, projectKey string, id int) error {
+	if projectKey == "" {
+		return fmt.Errorf("project key is required")
+	}
+	return c.deleteReviewerGroup(ctx, projectReviewerGroupsPath(projectKey), id)
+}
+
+// ListRepositoryReviewerGroups returns the reviewer groups defined in a
+// repository's settings, excluding those inherited from its project. A limit
+// of 0 returns all groups.
+func (c *Client) ListRepositoryReviewerGroups(ctx context.Context, projectKey, repoSlug string, limit int) ([]ProjectReviewerGroup, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.listReviewerGroups(ctx, repoReviewerGroupsPath(projectKey, repoSlug), limit)
+}
 
+// CreateRepositoryReviewerGroup defines a reviewer group in a repository's
+// settings.
+func (c *Client) CreateRepositoryReviewerGroup(ctx context.Context, projectKey, repoSlug string, in ReviewerGroupInput) (*ProjectReviewerGroup, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.saveReviewerGroup(ctx, "POST", repoReviewerGroupsPath(projectKey, repoSlug), in)
+}
+
+// UpdateRepositoryReviewerGroup replaces a repository reviewer group, keeping
+// its ID.
+func (c *Client) UpdateRepositoryReviewerGroup(ctx context.Context, projectKey, repoSlug string, id int, in ReviewerGroupInput) (*ProjectReviewerGroup, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.saveReviewerGroup(ctx, "PUT", fmt.Sprintf("%s/%d", repoReviewerGroupsPath(projectKey, repoSlug), id), in)
+}
+
+// DeleteRepositoryReviewerGroup removes a repository reviewer group by ID.
+func (c *Client) DeleteRepositoryReviewerGroup(ctx context.Context, projectKey, repoSlug string, id int) error {
+	if projectKey == "" || repoSlug == "" {
+		return fmt.Errorf("project key and repository slug are required")
+	}
+	return c.deleteReviewerGroup(ctx, repoReviewerGroupsPath(projectKey, repoSlug), id)
+}
+
+func (c *Client) listReviewerGroups(ctx context.Context, base string, limit int) ([]ProjectReviewerGroup, error) {
 	const defaultPageSize = 25
 
 	var (
@@ -54,8 +126,7 @@ func (c *Client) ListProjectReviewerGroups(ctx context.Context, projectKey strin
 			}
 		}
 
-		path := fmt.Sprintf("/rest/api/1.0/projects/%s/settings/reviewer-groups?limit=%d&start=%d",
-			url.PathEscape(projectKey), pageSize, start)
+		path := fmt.Sprintf("%s?limit=%d&start=%d", base, pageSize, start)
 		req, err := c.http.NewRequest(ctx, "GET", path, nil)
 		if err != nil {
 			return nil, err
@@ -86,6 +157,43 @@ func (c *Client) ListProjectReviewerGroups(ctx context.Context, projectKey strin
 	return found, nil
 }
 
+func (c *Client) saveReviewerGroup(ctx context.Context, method, path string, in ReviewerGroupInput) (*ProjectReviewerGroup, error) {
+	if strings.TrimSpace(in.Name) == "" {
+		return nil, fmt.Errorf("reviewer group name is required")
+	}
+	if len(in.Users) == 0 {
+		return nil, fmt.Errorf("a reviewer group needs at least one member")
+	}
+
+	users := make([]map[string]string, 0, len(in.Users))
+	for _, u := range in.Users {
+		users = append(users, map[string]string{"name": u})
+	}
+	body := map[string]any{
+		"name":        in.Name,
+		"description": in.Description,
+		"users":       users,
+	}
+
+	req, err := c.http.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	var group ProjectReviewerGroup
+	if err := c.http.Do(req, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (c *Client) deleteReviewerGroup(ctx context.Context, base string, id int) error {
+	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/%d", base, id), nil)
+	if err != nil {
+		return err
+	}
+	return c.http.Do(req, nil)
+}
+
 // ListReviewerGroups returns reviewer groups associated with a repository's default reviewers.
 func (c *Client) ListReviewerGroups(ctx context.Context, projectKey, repoSlug string) ([]ReviewerGroup, error) {
 	if projectKey == "" || repoSlug == "" {
@@ -219,3 +327,12 @@ func defaultReviewerRefID(ref string) string {
 	ref = strings.TrimPrefix(ref, "refs/tags/")
 	return ref
 }
+
+func projectReviewerGroupsPath(projectKey string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/settings/reviewer-groups", url.PathEscape(projectKey))
+}
+
+func repoReviewerGroupsPath(projectKey, repoSlug string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/settings/reviewer-groups",
+		url.PathEscape(projectKey), url.PathEscape(repoSlug))
+}
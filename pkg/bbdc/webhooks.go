@@ -21,18 +21,7 @@ func (c *Client) ListWebhooks(ctx context.Context, projectKey, repoSlug string)
 	if projectKey == "" || repoSlug == "" {
 		return nil, fmt.Errorf("project key and repository slug are required")
 	}
-	req, err := c.http.NewRequest(ctx, "GET", fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/webhooks",
-		url.PathEscape(projectKey), url.PathEscape(repoSlug)), nil)
-	if err != nil {
-		return nil, err
-	}
-	var resp struct {
-		Values []Webhook `json:"values"`
-	}
-	if err := c.http.Do(req, &resp); err != nil {
-		return nil, err
-	}
-	return resp.Values, nil
+	return c.listWebhooks(ctx, repoWebhooksPath(projectKey, repoSlug))
 }
 
 // CreateWebhookInput describes webhook creation request.
@@ -48,6 +37,195 @@ func (c *Client) CreateWebhook(ctx context.Context, projectKey, repoSlug string,
 	if projectKey == "" || repoSlug == "" {
 		return nil, fmt.Errorf("project key and repository slug are required")
 	}
+	return c.createWebhook(ctx, repoWebhooksPath(projectKey, repoSlug), in)
+}
+
+// DeleteWebhook removes a webhook by ID.
+func (c *Client) DeleteWebhook(ctx context.Context, projectKey, repoSlug string, id int) error {
+	if projectKey == "" || repoSlug == "" {
+		return fmt.Errorf("project key and repository slug are required")
+	}
+	return c.deleteWebhook(ctx, repoWebhooksPath(projectKey, repoSlug), id)
+}
+
+// TestWebhook triggers a test delivery for the webhook.
+func (c *Client) TestWebhook(ctx context.Context, projectKey, repoSlug string, id int) error {
+	if projectKey == "" || repoSlug == "" {
+		return fmt.Errorf("project key and repository slug are required")
+	}
+	return c.testWebhook(ctx, repoWebhooksPath(projectKey, repoSlug), id)
+}
+
+// GetWebhook fetches a single webhook by ID.
+func (c *Client) GetWebhook(ctx context.Context, projectKey, repoSlug string, id int) (*Webhook, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.getWebhook(ctx, repoWebhooksPath(projectKey, repoSlug), id)
+}
+
+// UpdateWebhookInput is the complete webhook definition sent on update;
+// Data Center replaces the stored webhook with it.
+type UpdateWebhookInput struct {
+	Name          string
+	URL           string
+	Events        []string
+	Active        bool
+	Configuration map[string]any
+}
+
+// UpdateWebhook replaces a webhook's definition, keeping its ID.
+func (c *Client) UpdateWebhook(ctx context.Context, projectKey, repoSlug string, id int, in UpdateWebhookInput) (*Webhook, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.updateWebhook(ctx, repoWebhooksPath(projectKey, repoSlug), id, in)
+}
+
+// WebhookInvocation is one recorded webhook delivery. Times are epoch
+// milliseconds; Duration is in milliseconds.
+type WebhookInvocation struct {
+	ID       int64  `json:"id"`
+	Event    string `json:"event"`
+	Start    int64  `json:"start"`
+	Finish   int64  `json:"finish"`
+	Duration int64  `json:"duration"`
+	Request  struct {
+		Method  string `json:"method"`
+		URL     string `json:"url"`
+		Headers any    `json:"headers,omitempty"`
+		Body    string `json:"body,omitempty"`
+	} `json:"request"`
+	Result struct {
+		Outcome     string `json:"outcome"`
+		Description string `json:"description"`
+		StatusCode  int    `json:"statusCode,omitempty"`
+		Headers     any    `json:"headers,omitempty"`
+		Body        string `json:"body,omitempty"`
+	} `json:"result"`
+}
+
+// WebhookStatistics summarises a webhook's recent deliveries.
+type WebhookStatistics struct {
+	LastSuccess *WebhookInvocation `json:"lastSuccess,omitempty"`
+	LastFailure *WebhookInvocation `json:"lastFailure,omitempty"`
+	LastError   *WebhookInvocation `json:"lastError,omitempty"`
+	Counts      struct {
+		Successes int   `json:"successes"`
+		Failures  int   `json:"failures"`
+		Errors    int   `json:"errors"`
+		Window    int64 `json:"window"`
+	} `json:"counts"`
+}
+
+// LatestWebhookInvocation returns the most recent delivery, optionally
+// filtered by event key and outcome (SUCCESS, FAILURE, or ERROR). It returns
+// nil when the webhook has not been invoked.
+func (c *Client) LatestWebhookInvocation(ctx context.Context, projectKey, repoSlug string, id int, event, outcome string) (*WebhookInvocation, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.latestWebhookInvocation(ctx, repoWebhooksPath(projectKey, repoSlug), id, event, outcome)
+}
+
+// WebhookStatistics returns delivery counts and the last success, failure,
+// and error for a webhook, optionally for one event key.
+func (c *Client) WebhookStatistics(ctx context.Context, projectKey, repoSlug string, id int, event string) (*WebhookStatistics, error) {
+	if projectKey == "" || repoSlug == "" {
+		return nil, fmt.Errorf("project key and repository slug are required")
+	}
+	return c.webhookStatistics(ctx, repoWebhooksPath(projectKey, repoSlug), id, event)
+}
+
+// ListProjectWebhooks retrieves project webhooks. They fire for events in
+// every repository of the project.
+func (c *Client) ListProjectWebhooks(ctx context.Context, projectKey string) ([]Webhook, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.listWebhooks(ctx, projectWebhooksPath(projectKey))
+}
+
+// CreateProjectWebhook registers a webhook for the project.
+func (c *Client) CreateProjectWebhook(ctx context.Context, projectKey string, in CreateWebhookInput) (*Webhook, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.createWebhook(ctx, projectWebhooksPath(projectKey), in)
+}
+
+// DeleteProjectWebhook removes a project webhook by ID.
+func (c *Client) DeleteProjectWebhook(ctx context.Context, projectKey string, id int) error {
+	if projectKey == "" {
+		return fmt.Errorf("project key is required")
+	}
+	return c.deleteWebhook(ctx, projectWebhooksPath(projectKey), id)
+}
+
+// TestProjectWebhook triggers a test delivery for a project webhook.
+func (c *Client) TestProjectWebhook(ctx context.Context, projectKey string, id int) error {
+	if projectKey == "" {
+		return fmt.Errorf("project key is required")
+	}
+	return c.testWebhook(ctx, projectWebhooksPath(projectKey), id)
+}
+
+// GetProjectWebhook fetches a single project webhook by ID.
+func (c *Client) GetProjectWebhook(ctx context.Context, projectKey string, id int) (*Webhook, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.getWebhook(ctx, projectWebhooksPath(projectKey), id)
+}
+
+// UpdateProjectWebhook replaces a project webhook's definition, keeping its ID.
+func (c *Client) UpdateProjectWebhook(ctx context.Context, projectKey string, id int, in UpdateWebhookInput) (*Webhook, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.updateWebhook(ctx, projectWebhooksPath(projectKey), id, in)
+}
+
+// LatestProjectWebhookInvocation is LatestWebhookInvocation for a project
+// webhook.
+func (c *Client) LatestProjectWebhookInvocation(ctx context.Context, projectKey string, id int, event, outcome string) (*WebhookInvocation, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.latestWebhookInvocation(ctx, projectWebhooksPath(projectKey), id, event, outcome)
+}
+
+// ProjectWebhookStatistics is WebhookStatistics for a project webhook.
+func (c *Client) ProjectWebhookStatistics(ctx context.Context, projectKey string, id int, event string) (*WebhookStatistics, error) {
+	if projectKey == "" {
+		return nil, fmt.Errorf("project key is required")
+	}
+	return c.webhookStatistics(ctx, projectWebhooksPath(projectKey), id, event)
+}
+
+func projectWebhooksPath(projectKey string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/webhooks", url.PathEscape(projectKey))
+}
+
+func repoWebhooksPath(projectKey, repoSlug string) string {
+	return fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/webhooks", url.PathEscape(projectKey), url.PathEscape(repoSlug))
+}
+
+func (c *Client) listWebhooks(ctx context.Context, path string) ([]Webhook, error) {
+	req, err := c.http.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Values []Webhook `json:"values"`
+	}
+	if err := c.http.Do(req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Values, nil
+}
+
+func (c *Client) createWebhook(ctx context.Context, path string, in CreateWebhookInput) (*Webhook, error) {
 	if in.Name == "" || in.URL == "" || len(in.Events) == 0 {
 		return nil, fmt.Errorf("name, url, and at least one event are required")
 	}
@@ -59,8 +237,7 @@ func (c *Client) CreateWebhook(ctx context.Context, projectKey, repoSlug string,
 		"active": in.Active,
 	}
 
-	req, err := c.http.NewRequest(ctx, "POST", fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/webhooks",
-		url.PathEscape(projectKey), url.PathEscape(repoSlug)), body)
+	req, err := c.http.NewRequest(ctx, "POST", path, body)
 	if err != nil {
 		return nil, err
 	}
@@ -72,28 +249,101 @@ func (c *Client) CreateWebhook(ctx context.Context, projectKey, repoSlug string,
 	return &hook, nil
 }
 
-// DeleteWebhook removes a webhook by ID.
-func (c *Client) DeleteWebhook(ctx context.Context, projectKey, repoSlug string, id int) error {
-	if projectKey == "" || repoSlug == "" {
-		return fmt.Errorf("project key and repository slug are required")
-	}
-	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/webhooks/%d",
-		url.PathEscape(projectKey), url.PathEscape(repoSlug), id), nil)
+func (c *Client) deleteWebhook(ctx context.Context, path string, id int) error {
+	req, err := c.http.NewRequest(ctx, "DELETE", fmt.Sprintf("%s/%d", path, id), nil)
 	if err != nil {
 		return err
 	}
 	return c.http.Do(req, nil)
 }
 
-// TestWebhook triggers a test delivery for the webhook.
-func (c *Client) TestWebhook(ctx context.Context, projectKey, repoSlug string, id int) error {
-	if projectKey == "" || repoSlug == "" {
-		return fmt.Errorf("project key and repository slug are required")
-	}
-	req, err := c.http.NewRequest(ctx, "POST", fmt.Sprintf("/rest/api/1.0/projects/%s/repos/%s/webhooks/%d/test",
-		url.PathEscape(projectKey), url.PathEscape(repoSlug), id), nil)
+func (c *Client) testWebhook(ctx context.Context, path string, id int) error {
+	req, err := c.http.NewRequest(ctx, "POST", fmt.Sprintf("%s/%d/test", path, id), nil)
 	if err != nil {
 		return err
 	}
 	return c.http.Do(req, nil)
 }
+
+func (c *Client) getWebhook(ctx context.Context, path string, id int) (*Webhook, error) {
+	req, err := c.http.NewRequest(ctx, "GET", fmt.Sprintf("%s/%d", path, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var hook Webhook
+	if err := c.http.Do(req, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (c *Client) updateWebhook(ctx context.Context, path string, id int, in UpdateWebhookInput) (*Webhook, error) {
+	if in.Name == "" || in.URL == "" || len(in.Events) == 0 {
+		return nil, fmt.Errorf("name, url, and at least one event are required")
+	}
+
+	body := map[string]any{
+		"name":   in.Name,
+		"url":    in.URL,
+		"events": in.Events,
+		"active": in.Active,
+	}
+	if len(in.Configuration) > 0 {
+		body["configuration"] = in.Configuration
+	}
+
+	req, err := c.http.NewRequest(ctx, "PUT", fmt.Sprintf("%s/%d", path, id), body)
+	if err != nil {
+		return nil, err
+	}
+	var hook Webhook
+	if err := c.http.Do(req, &hook); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (c *Client) latestWebhookInvocation(ctx context.Context, path string, id int, event, outcome string) (*WebhookInvocation, error) {
+	query := url.Values{}
+	if event != "" {
+		query.Set("event", event)
+	}
+	if outcome != "" {
+		query.Set("outcome", outcome)
+	}
+	path = fmt.Sprintf("%s/%d/latest", path, id)
+	if encoded := query.Encode(); encoded != "" {
+		path += "?" + encoded
+	}
+
+	req, err := c.http.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var invocation WebhookInvocation
+	if err := c.http.Do(req, &invocation); err != nil {
+		return nil, err
+	}
+	// 204 No Content leaves the invocation empty.
+	if invocation.ID == 0 && invocation.Event == "" {
+		return nil, nil
+	}
+	return &invocation, nil
+}
+
+func (c *Client) webhookStatistics(ctx context.Context, path string, id int, event string) (*WebhookStatistics, error) {
+	path = fmt.Sprintf("%s/%d/statistics", path, id)
+	if event != "" {
+		path += "?event=" + url.QueryEscape(event)
+	}
+
+	req, err := c.http.NewRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var stats WebhookStatistics
+	if err := c.http.Do(req, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -0,0 +1,504 @@
+// Package accesstoken implements the token subcommands shared by
+// "bkt repo token" and "bkt project token".
+package accesstoken
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// Scope selects which resource owns the tokens.
+type Scope string
+
+const (
+	// ScopeRepository manages repository access tokens.
+	ScopeRepository Scope = "repository"
+	// ScopeProject manages project access tokens (and, on Cloud without a
+	// project key, workspace access tokens).
+	ScopeProject Scope = "project"
+)
+
+const cloudWebURL = "https://bitbucket.org"
+
+// NewCmdToken returns the token command for the given scope.
+func NewCmdToken(f *cmdutil.Factory, scope Scope) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: fmt.Sprintf("Manage %s access tokens for bots and CI", scope),
+		Long: fmt.Sprintf(`Create, list, and revoke %[1]s access tokens. Unlike personal access
+tokens, these belong to the %[1]s rather than a user, which makes them the
+right credential for CI bots.
+
+The tokens are managed through the Data Center access-tokens REST API. The
+secret of a new token is printed once, or with --store-as-variable written
+straight into a Bitbucket Cloud pipeline variable without being displayed.
+
+Bitbucket Cloud does not expose access-token management through its REST
+API, so on a Cloud context these commands fail with a link to the page
+where the tokens are managed.`, scope),
+		Example: tokenExample(scope),
+	}
+
+	cmd.AddCommand(newListCmd(f, scope))
+	cmd.AddCommand(newCreateCmd(f, scope))
+	cmd.AddCommand(newRevokeCmd(f, scope))
+
+	return cmd
+}
+
+func tokenExample(scope Scope) string {
+	if scope == ScopeProject {
+		return `  # List project access tokens
+  bkt project token list --project PLAT
+
+  # Create a token that can read the project and push to its repositories
+  bkt project token create --project PLAT --name ci --repo-permission write --expiry-days 90
+
+  # Revoke a project access token
+  bkt project token revoke 123456789012 --project PLAT --yes`
+	}
+	return `  # List access tokens for the current repository
+  bkt repo token list
+
+  # Create a read-only token and store it in a Cloud pipeline variable
+  bkt repo token create --name deploy --permission read \
+    --store-as-variable DC_TOKEN --variable-context cloud --variable-repo team/app
+
+  # Revoke a repository access token
+  bkt repo token revoke 123456789012 --yes`
+}
+
+// targetOptions holds the flags that select the token owner.
+type targetOptions struct {
+	Project   string
+	Workspace string
+	Repo      string
+}
+
+func (o *targetOptions) addFlags(cmd *cobra.Command, scope Scope) {
+	cmd.Flags().StringVar(&o.Project, "project", "", "Bitbucket project key override")
+	cmd.Flags().StringVar(&o.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
+	if scope == ScopeRepository {
+		cmd.Flags().StringVar(&o.Repo, "repo", "", "Repository slug override")
+	}
+}
+
+// target is a resolved token owner.
+type target struct {
+	scope     Scope
+	host      *config.Host
+	project   string
+	workspace string
+	repo      string
+}
+
+func (t target) String() string {
+	switch {
+	case t.host.Kind == "cloud" && t.scope == ScopeRepository:
+		return t.workspace + "/" + t.repo
+	case t.host.Kind == "cloud" && t.project == "":
+		return "workspace " + t.workspace
+	case t.host.Kind == "cloud":
+		return t.workspace + " project " + t.project
+	case t.scope == ScopeRepository:
+		return t.project + "/" + t.repo
+	default:
+		return "project " + t.project
+	}
+}
+
+// cloudSettingsURL is the Bitbucket Cloud page where the target's access
+// tokens are managed.
+func (t target) cloudSettingsURL() string {
+	ws := url.PathEscape(t.workspace)
+	switch {
+	case t.scope == ScopeRepository:
+		return fmt.Sprintf("%s/%s/%s/admin/access-tokens", cloudWebURL, ws, url.PathEscape(t.repo))
+	case t.project != "":
+		return fmt.Sprintf("%s/%s/workspace/projects/%s/settings/access-tokens", cloudWebURL, ws, url.PathEscape(t.project))
+	default:
+		return fmt.Sprintf("%s/%s/workspace/settings/access-tokens", cloudWebURL, ws)
+	}
+}
+
+func (t target) cloudUnsupported() error {
+	return fmt.Errorf("bkt %s token is only supported for Bitbucket Data Center; the Bitbucket Cloud API cannot manage access tokens, manage tokens for %s at %s", t.command(), t, t.cloudSettingsURL())
+}
+
+func (t target) command() string {
+	if t.scope == ScopeRepository {
+		return "repo"
+	}
+	return "project"
+}
+
+func resolveTarget(cmd *cobra.Command, f *cmdutil.Factory, scope Scope, opts *targetOptions) (target, error) {
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, cmdutil.FlagValue(cmd, "context"))
+	if err != nil {
+		return target{}, err
+	}
+
+	t := target{scope: scope, host: host}
+	switch host.Kind {
+	case "dc":
+		t.project = cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		if scope == ScopeRepository {
+			t.repo = cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+			if t.project == "" || t.repo == "" {
+				return target{}, fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+			}
+		} else if t.project == "" {
+			return target{}, fmt.Errorf("context must supply a project; use --project if needed")
+		}
+	case "cloud":
+		t.workspace = cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		if t.workspace == "" {
+			return target{}, fmt.Errorf("context must supply a workspace; use --workspace if needed")
+		}
+		if scope == ScopeRepository {
+			t.repo = cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+			if t.repo == "" {
+				return target{}, fmt.Errorf("context must supply a repo; use --repo if needed")
+			}
+		} else {
+			// Without a project key, Cloud project tokens fall back to the
+			// workspace's own access tokens.
+			t.project = opts.Project
+		}
+		return target{}, t.cloudUnsupported()
+	default:
+		return target{}, fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+	return t, nil
+}
+
+type listOptions struct {
+	targetOptions
+	Limit int
+}
+
+func newListCmd(f *cmdutil.Factory, scope Scope) *cobra.Command {
+	opts := &listOptions{}
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   fmt.Sprintf("List %s access tokens", scope),
+		Long: fmt.Sprintf(`List the access tokens owned by a %s with their permissions, creation
+date, last use, and expiry. Token secrets are never shown. Data Center only.`, scope),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(cmd, f, scope, opts)
+		},
+	}
+	opts.addFlags(cmd, scope)
+	cmd.Flags().IntVar(&opts.Limit, "limit", 0, "Maximum tokens to display (0 for all)")
+	return cmd
+}
+
+func runList(cmd *cobra.Command, f *cmdutil.Factory, scope Scope, opts *listOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	t, err := resolveTarget(cmd, f, scope, &opts.targetOptions)
+	if err != nil {
+		return err
+	}
+	client, err := f.DCClient(t.host)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	var tokens []bbdc.AccessToken
+	if scope == ScopeRepository {
+		tokens, err = client.ListRepositoryAccessTokens(ctx, t.project, t.repo, opts.Limit)
+	} else {
+		tokens, err = client.ListProjectAccessTokens(ctx, t.project, opts.Limit)
+	}
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"project": t.project,
+		"tokens":  tokens,
+	}
+	if t.repo != "" {
+		payload["repo"] = t.repo
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		if len(tokens) == 0 {
+			_, err := fmt.Fprintf(ios.Out, "No access tokens for %s.\n", t)
+			return err
+		}
+		return WriteList(ios.Out, tokens)
+	})
+}
+
+type createOptions struct {
+	targetOptions
+	Name              string
+	Permission        string
+	ProjectPermission string
+	RepoPermission    string
+	ExpiryDays        int
+	StoreAsVariable   string
+	VariableContext   string
+	VariableRepo      string
+}
+
+func newCreateCmd(f *cmdutil.Factory, scope Scope) *cobra.Command {
+	opts := &createOptions{
+		Permission:        "read",
+		ProjectPermission: "read",
+		RepoPermission:    "none",
+	}
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: fmt.Sprintf("Create a %s access token", scope),
+		Long: fmt.Sprintf(`Create a %s access token.
+
+Choose permissions with %s and an optional
+--expiry-days. The secret is printed once; with --json it appears in the
+"token" field.
+
+With --store-as-variable KEY the secret is never displayed. It is written to
+a secured pipeline variable on a Bitbucket Cloud repository, taken from
+--variable-repo (workspace/slug) or the Cloud context named by
+--variable-context. If storing fails, the new token is revoked again.
+Data Center only.`, scope, permissionFlagsHelp(scope)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreate(cmd, f, scope, opts)
+		},
+	}
+	opts.addFlags(cmd, scope)
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Token name (required)")
+	if scope == ScopeRepository {
+		cmd.Flags().StringVar(&opts.Permission, "permission", opts.Permission, "Repository permission: read, write, or admin")
+	} else {
+		cmd.Flags().StringVar(&opts.ProjectPermission, "project-permission", opts.ProjectPermission, "Project permission: read, write, admin, or none")
+		cmd.Flags().StringVar(&opts.RepoPermission, "repo-permission", opts.RepoPermission, "Permission on the project's repositories: read, write, admin, or none")
+	}
+	cmd.Flags().IntVar(&opts.ExpiryDays, "expiry-days", 0, "Days until the token expires (0 uses the server default)")
+	cmd.Flags().StringVar(&opts.StoreAsVariable, "store-as-variable", "", "Write the token into this secured Cloud pipeline variable instead of printing it")
+	cmd.Flags().StringVar(&opts.VariableContext, "variable-context", "", "Cloud context that owns the pipeline variable (defaults to the current context)")
+	cmd.Flags().StringVar(&opts.VariableRepo, "variable-repo", "", "Cloud repository for the pipeline variable as workspace/slug")
+	_ = cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func permissionFlagsHelp(scope Scope) string {
+	if scope == ScopeRepository {
+		return "--permission"
+	}
+	return "--project-permission and --repo-permission"
+}
+
+func runCreate(cmd *cobra.Command, f *cmdutil.Factory, scope Scope, opts *createOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+	if opts.ExpiryDays < 0 {
+		return fmt.Errorf("--expiry-days must not be negative")
+	}
+
+	t, err := resolveTarget(cmd, f, scope, &opts.targetOptions)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	permissions, err := dcPermissions(scope, opts)
+	if err != nil {
+		return err
+	}
+
+	client, err := f.DCClient(t.host)
+	if err != nil {
+		return err
+	}
+
+	input := bbdc.AccessTokenInput{
+		Name:        opts.Name,
+		Permissions: permissions,
+		ExpiryDays:  opts.ExpiryDays,
+	}
+	var token *bbdc.AccessToken
+	if scope == ScopeRepository {
+		token, err = client.CreateRepositoryAccessToken(ctx, t.project, t.repo, input)
+	} else {
+		token, err = client.CreateProjectAccessToken(ctx, t.project, input)
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.StoreAsVariable != "" {
+		location, err := storeAsVariable(ctx, cmd, f, opts, token.Token)
+		if err != nil {
+			if rerr := revokeDC(ctx, client, t, token.ID); rerr != nil {
+				return fmt.Errorf("%w (revoking the new token %s also failed: %v)", err, token.ID, rerr)
+			}
+			return fmt.Errorf("%w; the new token was revoked", err)
+		}
+		token.Token = ""
+		return cmdutil.WriteOutput(cmd, ios, token, func() error {
+			_, err := fmt.Fprintf(ios.Out, "✓ Created token %s (%s) for %s and stored it in secured variable %s on %s\n", token.ID, token.Name, t, opts.StoreAsVariable, location)
+			return err
+		})
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, token, func() error {
+		return WriteCreated(ios.Out, ios.ErrOut, token, t.String())
+	})
+}
+
+// storeAsVariable writes the token into a secured repository pipeline variable
+// on Bitbucket Cloud, updating the variable when it already exists. It
+// returns the workspace/slug it wrote to.
+func storeAsVariable(ctx context.Context, cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions, value string) (string, error) {
+	contextName := cmdutil.FirstNonEmpty(opts.VariableContext, cmdutil.FlagValue(cmd, "context"))
+	_, ctxCfg, host, err := cmdutil.ResolveContextStatic(f, contextName)
+	if err != nil {
+		return "", fmt.Errorf("resolve variable context: %w", err)
+	}
+	if host.Kind != "cloud" {
+		return "", fmt.Errorf("--store-as-variable writes a Bitbucket Cloud pipeline variable; pass --variable-context naming a Cloud context")
+	}
+
+	workspace, repoSlug := ctxCfg.Workspace, ctxCfg.DefaultRepo
+	if opts.VariableRepo != "" {
+		var ok bool
+		workspace, repoSlug, ok = strings.Cut(opts.VariableRepo, "/")
+		if !ok || workspace == "" || repoSlug == "" {
+			return "", fmt.Errorf("--variable-repo must be workspace/slug, got %q", opts.VariableRepo)
+		}
+	}
+	if workspace == "" || repoSlug == "" {
+		return "", fmt.Errorf("variable context must supply workspace and repo; use --variable-repo workspace/slug")
+	}
+
+	client, err := f.CloudClient(host)
+	if err != nil {
+		return "", err
+	}
+
+	variables, err := client.ListRepositoryVariables(ctx, workspace, repoSlug, bbcloud.VariableListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, v := range variables {
+		if v.Key == opts.StoreAsVariable {
+			_, err = client.UpdateRepositoryVariable(ctx, workspace, repoSlug, v.UUID, bbcloud.UpdateRepositoryVariableInput{
+				Key:     opts.StoreAsVariable,
+				Value:   value,
+				Secured: true,
+			})
+			return workspace + "/" + repoSlug, err
+		}
+	}
+	_, err = client.CreateRepositoryVariable(ctx, workspace, repoSlug, bbcloud.CreateRepositoryVariableInput{
+		Key:     opts.StoreAsVariable,
+		Value:   value,
+		Secured: true,
+	})
+	return workspace + "/" + repoSlug, err
+}
+
+type revokeOptions struct {
+	targetOptions
+	ID  string
+	Yes bool
+}
+
+func newRevokeCmd(f *cmdutil.Factory, scope Scope) *cobra.Command {
+	opts := &revokeOptions{}
+	cmd := &cobra.Command{
+		Use:     "revoke <id>",
+		Aliases: []string{"delete", "rm"},
+		Short:   fmt.Sprintf("Revoke a %s access token", scope),
+		Long: fmt.Sprintf(`Revoke a %s access token by ID (shown by "list"). Clients using the
+token lose access immediately. A confirmation prompt is shown unless --yes is
+passed. Data Center only.`, scope),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ID = args[0]
+			return runRevoke(cmd, f, scope, opts)
+		},
+	}
+	opts.addFlags(cmd, scope)
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Skip confirmation prompt")
+	return cmd
+}
+
+func runRevoke(cmd *cobra.Command, f *cmdutil.Factory, scope Scope, opts *revokeOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	t, err := resolveTarget(cmd, f, scope, &opts.targetOptions)
+	if err != nil {
+		return err
+	}
+	if !opts.Yes {
+		confirmed, err := f.Prompt().Confirm(fmt.Sprintf("Revoke access token %s on %s?", opts.ID, t), false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			_, _ = fmt.Fprintln(ios.Out, "Aborted.")
+			return nil
+		}
+	}
+
+	client, err := f.DCClient(t.host)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	if err := revokeDC(ctx, client, t, opts.ID); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(ios.Out, "✓ Revoked access token %s on %s\n", opts.ID, t)
+	return err
+}
+
+func revokeDC(ctx context.Context, client *bbdc.Client, t target, id string) error {
+	if t.scope == ScopeRepository {
+		return client.RevokeRepositoryAccessToken(ctx, t.project, t.repo, id)
+	}
+	return client.RevokeProjectAccessToken(ctx, t.project, id)
+}
+
+// dcPermissions maps the permission flags of the scope to Bitbucket's token
+// permission names.
+func dcPermissions(scope Scope, opts *createOptions) ([]string, error) {
+	if scope == ScopeProject {
+		return Permissions(
+			PermissionLevel{Resource: "PROJECT", Flag: "--project-permission", Level: opts.ProjectPermission},
+			PermissionLevel{Resource: "REPO", Flag: "--repo-permission", Level: opts.RepoPermission},
+		)
+	}
+	return Permissions(PermissionLevel{Resource: "REPO", Flag: "--permission", Level: opts.Permission})
+}
@@ -0,0 +1,239 @@
+package accesstoken_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/accesstoken"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/root"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
+)
+
+func TestRepoTokenCreatePrintsSecret(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/rest/access-tokens/1.0/projects/PROJ/repos/my-repo" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"id":"7","name":"ci","permissions":["REPO_WRITE"],"token":"s3cret"}`))
+	}))
+	defer srv.Close()
+
+	stdout, stderr, err := runCLI(t, dcConfig(srv.URL), "repo", "token", "create", "--name", "ci", "--permission", "write", "--expiry-days", "30")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !strings.Contains(stdout, "Token:   s3cret") {
+		t.Fatalf("expected secret in stdout:\n%s", stdout)
+	}
+	if !strings.Contains(stderr, "will not show it again") {
+		t.Fatalf("expected one-time warning, got %q", stderr)
+	}
+	perms, _ := body["permissions"].([]any)
+	if len(perms) != 1 || perms[0] != "REPO_WRITE" || body["expiryDays"] != float64(30) {
+		t.Fatalf("unexpected request body: %v", body)
+	}
+}
+
+func TestRepoTokenCreateStoresVariableWithoutPrinting(t *testing.T) {
+	dc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"7","name":"deploy","permissions":["REPO_READ"],"token":"s3cret"}`))
+	}))
+	defer dc.Close()
+
+	var variable map[string]any
+	cloud := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repositories/team/app/pipelines_config/variables":
+			_, _ = w.Write([]byte(`{"values":[]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repositories/team/app/pipelines_config/variables":
+			_ = json.NewDecoder(r.Body).Decode(&variable)
+			_, _ = w.Write([]byte(`{"uuid":"{v}","key":"DC_TOKEN","secured":true}`))
+		default:
+			t.Errorf("unexpected cloud request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer cloud.Close()
+
+	cfg := withCloudContext(dcConfig(dc.URL), cloud.URL)
+	stdout, _, err := runCLI(t, cfg, "repo", "token", "create", "--name", "deploy",
+		"--store-as-variable", "DC_TOKEN", "--variable-context", "cloud", "--variable-repo", "team/app")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if strings.Contains(stdout, "s3cret") {
+		t.Fatalf("secret must not be printed:\n%s", stdout)
+	}
+	if !strings.Contains(stdout, "stored it in secured variable DC_TOKEN on team/app") {
+		t.Fatalf("unexpected output:\n%s", stdout)
+	}
+	if variable["value"] != "s3cret" || variable["secured"] != true || variable["key"] != "DC_TOKEN" {
+		t.Fatalf("unexpected variable payload: %v", variable)
+	}
+}
+
+func TestRepoTokenCreateRevokesWhenVariableFails(t *testing.T) {
+	var mu sync.Mutex
+	var revoked string
+	dc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			revoked = r.URL.Path
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"7","name":"deploy","permissions":["REPO_READ"],"token":"s3cret"}`))
+	}))
+	defer dc.Close()
+
+	cloud := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer cloud.Close()
+
+	cfg := withCloudContext(dcConfig(dc.URL), cloud.URL)
+	_, _, err := runCLI(t, cfg, "repo", "token", "create", "--name", "deploy",
+		"--store-as-variable", "DC_TOKEN", "--variable-context", "cloud", "--variable-repo", "team/app")
+	if err == nil || !strings.Contains(err.Error(), "the new token was revoked") {
+		t.Fatalf("expected rollback error, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if revoked != "/rest/access-tokens/1.0/projects/PROJ/repos/my-repo/7" {
+		t.Fatalf("revoked path = %q", revoked)
+	}
+}
+
+func TestProjectTokenListDataCenter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/access-tokens/1.0/projects/PROJ" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"isLastPage":true,"values":[{"id":"9","name":"bot","permissions":["PROJECT_READ"],"createdDate":1700000000000}]}`))
+	}))
+	defer srv.Close()
+
+	stdout, _, err := runCLI(t, dcConfig(srv.URL), "project", "token", "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if !strings.Contains(stdout, "9\tbot\tPROJECT_READ") || !strings.Contains(stdout, "last used never") {
+		t.Fatalf("unexpected output:\n%s", stdout)
+	}
+}
+
+func TestTokenCommandsRejectCloud(t *testing.T) {
+	_, _, err := runCLI(t, cloudConfig("http://localhost"), "repo", "token", "list")
+	if err == nil || !strings.Contains(err.Error(), "https://bitbucket.org/myworkspace/my-repo/admin/access-tokens") {
+		t.Fatalf("expected repository settings URL, got %v", err)
+	}
+
+	_, _, err = runCLI(t, cloudConfig("http://localhost"), "project", "token", "revoke", "1", "--yes")
+	if err == nil || !strings.Contains(err.Error(), "https://bitbucket.org/myworkspace/workspace/settings/access-tokens") {
+		t.Fatalf("expected workspace settings URL, got %v", err)
+	}
+
+	_, _, err = runCLI(t, cloudConfig("http://localhost"), "project", "token", "create", "--name", "ci", "--project", "PLAT")
+	if err == nil || !strings.Contains(err.Error(), "only supported for Bitbucket Data Center") {
+		t.Fatalf("expected Data Center only error, got %v", err)
+	}
+}
+
+func TestTokenCreateRejectsUnknownPermission(t *testing.T) {
+	_, _, err := runCLI(t, dcConfig("http://localhost"), "project", "token", "create", "--name", "x", "--repo-permission", "owner")
+	if err == nil || !strings.Contains(err.Error(), `invalid --repo-permission "owner"`) {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+}
+
+func TestPermissionsRejectsUnknownLevel(t *testing.T) {
+	_, err := accesstoken.Permissions(
+		accesstoken.PermissionLevel{Resource: "PROJECT", Flag: "--project-permission", Level: "read"},
+		accesstoken.PermissionLevel{Resource: "REPO", Flag: "--repo-permission", Level: "owner"},
+	)
+	if err == nil || !strings.Contains(err.Error(), `invalid --repo-permission "owner"`) {
+		t.Fatalf("expected invalid --repo-permission error, got %v", err)
+	}
+	if _, err := accesstoken.Permissions(accesstoken.PermissionLevel{Resource: "REPO", Flag: "--permission", Level: "none"}); err == nil {
+		t.Fatal("expected error when no permission is granted")
+	}
+}
+
+func withCloudContext(cfg *config.Config, baseURL string) *config.Config {
+	cfg.Contexts["cloud"] = &config.Context{Host: "cloudmock", Workspace: "team"}
+	cfg.Hosts["cloudmock"] = &config.Host{Kind: "cloud", BaseURL: baseURL, Username: "admin", Token: "token"}
+	return cfg
+}
+
+func cloudConfig(baseURL string) *config.Config {
+	return &config.Config{
+		ActiveContext: "test",
+		Contexts: map[string]*config.Context{
+			"test": {Host: "mock", Workspace: "myworkspace", DefaultRepo: "my-repo"},
+		},
+		Hosts: map[string]*config.Host{
+			"mock": {Kind: "cloud", BaseURL: baseURL, Username: "admin", Token: "token"},
+		},
+	}
+}
+
+func dcConfig(baseURL string) *config.Config {
+	return &config.Config{
+		ActiveContext: "test",
+		Contexts: map[string]*config.Context{
+			"test": {Host: "mock", ProjectKey: "PROJ", DefaultRepo: "my-repo"},
+		},
+		Hosts: map[string]*config.Host{
+			"mock": {Kind: "dc", BaseURL: baseURL, Username: "admin", Token: "token"},
+		},
+	}
+}
+
+func runCLI(t *testing.T, cfg *config.Config, args ...string) (string, string, error) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	factory := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams: &iostreams.IOStreams{
+			In:     io.NopCloser(bytes.NewReader(nil)),
+			Out:    stdout,
+			ErrOut: stderr,
+		},
+		Config: func() (*config.Config, error) {
+			return cfg, nil
+		},
+	}
+
+	rootCmd, err := root.NewCmdRoot(factory)
+	if err != nil {
+		t.Fatalf("NewCmdRoot: %v", err)
+	}
+	rootCmd.SetArgs(args)
+	rootCmd.SetOut(stdout)
+	rootCmd.SetErr(stderr)
+	rootCmd.SilenceUsage = true
+
+	t.Setenv("BKT_NO_UPDATE_CHECK", "1")
+	t.Setenv("NO_COLOR", "1")
+
+	err = rootCmd.ExecuteContext(context.Background())
+	return stdout.String(), stderr.String(), err
+}
@@ -0,0 +1,92 @@
+package accesstoken
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+)
+
+// TimeLayout formats token creation, last-use, and expiry dates.
+const TimeLayout = "2006-01-02 15:04"
+
+// PermissionLevel is the read, write, admin, or none level chosen by Flag
+// for a token resource (PROJECT or REPO).
+type PermissionLevel struct {
+	Resource string
+	Flag     string
+	Level    string
+}
+
+// Permissions maps read/write/admin levels to Bitbucket's token permission
+// names, skipping resources set to none. At least one level must grant
+// access.
+func Permissions(levels ...PermissionLevel) ([]string, error) {
+	var out []string
+	for _, l := range levels {
+		level := strings.ToUpper(strings.TrimSpace(l.Level))
+		switch level {
+		case "", "NONE":
+			continue
+		case "READ", "WRITE", "ADMIN":
+			out = append(out, l.Resource+"_"+level)
+		default:
+			return nil, fmt.Errorf("invalid %s %q; use read, write, admin, or none", l.Flag, l.Level)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("the token must grant at least one permission")
+	}
+	return out, nil
+}
+
+// FormatTime renders a Bitbucket millisecond timestamp in local time, or
+// zero when the timestamp is unset.
+func FormatTime(unixMilli int64, zero string) string {
+	if unixMilli == 0 {
+		return zero
+	}
+	return time.UnixMilli(unixMilli).Local().Format(TimeLayout)
+}
+
+// WriteList prints one line per token with its permissions and dates.
+func WriteList(w io.Writer, tokens []bbdc.AccessToken) error {
+	for _, tok := range tokens {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\tcreated %s\tlast used %s\texpires %s\n",
+			tok.ID,
+			tok.Name,
+			strings.Join(tok.Permissions, ","),
+			FormatTime(tok.CreatedDate, "-"),
+			FormatTime(tok.LastAuthenticated, "never"),
+			FormatTime(tok.ExpiryDate, "never"),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCreated prints a newly created token including its secret, followed
+// by a one-time warning on errOut. owner, when set, names the project or
+// repository the token belongs to.
+func WriteCreated(out, errOut io.Writer, token *bbdc.AccessToken, owner string) error {
+	forOwner := ""
+	if owner != "" {
+		forOwner = " for " + owner
+	}
+	if _, err := fmt.Fprintf(out, "✓ Created token %s (%s)%s with %s\n", token.ID, token.Name, forOwner, strings.Join(token.Permissions, ", ")); err != nil {
+		return err
+	}
+	if token.ExpiryDate > 0 {
+		if _, err := fmt.Fprintf(out, "  Expires: %s\n", FormatTime(token.ExpiryDate, "")); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(out, "  Token:   %s\n", token.Token); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(errOut, "Copy the token now; Bitbucket will not show it again.")
+	return err
+}
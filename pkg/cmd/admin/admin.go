@@ -219,7 +219,7 @@ func runLoggingGet(cmd *cobra.Command, f *cmdutil.Factory) error {
 		return err
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, cfg, func() error {
+	return cmdutil.WriteOutput(cmd, ios, cfg, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Level: %s\nAsync: %t\n", cfg.Level, cfg.Async)
 		return err
 	})
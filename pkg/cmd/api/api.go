@@ -153,7 +153,7 @@ func runAPI(cmd *cobra.Command, f *cmdutil.Factory, opts *apiOptions, path strin
 		}
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, data, func() error {
+	return cmdutil.WriteOutput(cmd, ios, data, func() error {
 		if buf.Len() == 0 {
 			return nil
 		}
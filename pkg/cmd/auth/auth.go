@@ -47,13 +47,15 @@ uses Personal Access Tokens (PATs). For Bitbucket Cloud, bkt uses Atlassian
 API tokens with scopes.
 
 Use "bkt auth login" to add a host, "bkt auth status" to inspect stored
-credentials, and "bkt auth logout" to remove them.`,
+credentials, and "bkt auth logout" to remove them. On Data Center, "bkt auth
+token" manages personal access tokens.`,
 	}
 
 	cmd.AddCommand(newLoginCmd(f))
 	cmd.AddCommand(newStatusCmd(f))
 	cmd.AddCommand(newLogoutCmd(f))
 	cmd.AddCommand(newDoctorCmd(f))
+	cmd.AddCommand(newTokenCmd(f))
 
 	return cmd
 }
@@ -68,6 +70,9 @@ type loginOptions struct {
 	AllowHTTP          bool
 	Web                bool
 	WebToken           bool
+	TokenCommand       string
+	TokenID            string
+	Rotate             bool
 }
 
 func newLoginCmd(f *cmdutil.Factory) *cobra.Command {
@@ -95,7 +100,23 @@ access token that is automatically refreshed.
 Credentials are verified against the remote host before being stored. If no
 OS keychain is available, pass --allow-insecure-store to use encrypted file
 fallback. In non-interactive environments, provide --username and --token
-on the command line or via stdin.`,
+on the command line or via stdin.
+
+To keep the token in an external secret manager (1Password, HashiCorp Vault,
+pass, ...), use --token-command. The command runs through the system shell
+whenever bkt needs the token and must print it on stdout; nothing is written
+to the keychain. Results are cached in-process for BKT_TOKEN_COMMAND_TTL
+(default 5m) and the command is killed after BKT_TOKEN_COMMAND_TIMEOUT
+(default 10s).
+
+On Data Center, pass --token-id with the ID of the personal access token
+being stored (see "bkt auth token list") so that bkt knows which token it
+authenticates with. --rotate then replaces that token for an existing login:
+bkt mints a new token with the same name, permissions, and expiry period,
+verifies and stores it, then revokes the old one. If any step fails the
+previous token stays in place and any new token is revoked. Rotation is
+refused when the token ID is unknown or the stored credential is not a
+personal access token.`,
 		Example: `  # Login to Bitbucket Cloud via OAuth
   bkt auth login https://bitbucket.org --kind cloud --web
 
@@ -109,7 +130,17 @@ on the command line or via stdin.`,
   bkt auth login https://bitbucket.example.com --web-token
 
   # Non-interactive login with flags (CI pipelines)
-  bkt auth login https://bitbucket.example.com --username admin --token "$PAT"`,
+  bkt auth login https://bitbucket.example.com --username admin --token "$PAT"
+
+  # Record which personal access token is stored so it can be rotated later
+  bkt auth login https://bitbucket.example.com --username admin --token "$PAT" --token-id 123456789012
+
+  # Fetch the token from 1Password on every use instead of the keychain
+  bkt auth login https://bitbucket.example.com --username admin \
+    --token-command 'op read op://Engineering/bitbucket/token'
+
+  # Replace the stored Data Center token with a fresh one and revoke the old
+  bkt auth login bitbucket.example.com --rotate`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -127,6 +158,9 @@ on the command line or via stdin.`,
 	cmd.Flags().BoolVar(&opts.AllowHTTP, "allow-http", false, "Allow http:// URLs for login even though credentials will be sent in plaintext")
 	cmd.Flags().BoolVarP(&opts.Web, "web", "w", false, "Authenticate via OAuth in the browser (Cloud only)")
 	cmd.Flags().BoolVar(&opts.WebToken, "web-token", false, "Open browser to create an API token, then prompt for credentials")
+	cmd.Flags().StringVar(&opts.TokenID, "token-id", "", "ID of the Data Center personal access token being stored or rotated (see bkt auth token list)")
+	cmd.Flags().BoolVar(&opts.Rotate, "rotate", false, "Replace the stored Data Center token with a newly minted one and revoke the old token")
+	cmd.Flags().StringVar(&opts.TokenCommand, "token-command", "", "Shell command that prints the token on stdout (e.g. 'op read ...'); the token is fetched on demand instead of stored")
 
 	return cmd
 }
@@ -135,6 +169,9 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 	if secret.TokenFromEnv() != "" {
 		return fmt.Errorf("%s environment variable is set; token is externally managed. Unset %s to use auth login", secret.EnvToken, secret.EnvToken)
 	}
+	if opts.Rotate {
+		return runRotate(cmd, f, opts)
+	}
 
 	ios, err := f.Streams()
 	if err != nil {
@@ -189,6 +226,10 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 	if kind == "cloud" && authMethod != "basic" && !opts.Web {
 		return fmt.Errorf("--auth-method is only supported for Data Center hosts")
 	}
+	opts.TokenID = strings.TrimSpace(opts.TokenID)
+	if kind == "cloud" && opts.TokenID != "" {
+		return fmt.Errorf("--token-id is only supported for Data Center personal access tokens")
+	}
 
 	if opts.Web && opts.WebToken {
 		return fmt.Errorf("--web and --web-token are mutually exclusive")
@@ -197,6 +238,17 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 		return fmt.Errorf("--web OAuth login is only supported for Bitbucket Cloud; use --web-token to open the PAT page")
 	}
 
+	opts.TokenCommand = strings.TrimSpace(opts.TokenCommand)
+	if opts.TokenCommand != "" {
+		if opts.Token != "" || opts.Web || opts.WebToken {
+			return fmt.Errorf("--token-command cannot be combined with --token, --web, or --web-token")
+		}
+		opts.Token, err = secret.RunTokenCommand(cmd.Context(), opts.TokenCommand)
+		if err != nil {
+			return err
+		}
+	}
+
 	cfg, err := f.ResolveConfig()
 	if err != nil {
 		return err
@@ -290,7 +342,13 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 			displayName = cmdutil.FirstNonEmpty(user.FullName, user.Name, opts.Username)
 		}
 
-		if err := storeHostToken(hostKey, opts.Token, opts.AllowInsecureStore); err != nil {
+		if opts.TokenID != "" {
+			if err := verifyLoginTokenID(ctx, client, opts); err != nil {
+				return err
+			}
+		}
+
+		if err := storeLoginToken(hostKey, opts); err != nil {
 			return fmt.Errorf("store token: %w", err)
 		}
 
@@ -300,6 +358,8 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 			Username:           opts.Username,
 			AuthMethod:         authMethod,
 			AllowInsecureStore: opts.AllowInsecureStore,
+			TokenCommand:       opts.TokenCommand,
+			TokenID:            opts.TokenID,
 		})
 
 		if err := cfg.Save(); err != nil {
@@ -457,7 +517,7 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 				return fmt.Errorf("verify credentials: %w", err)
 			}
 
-			if err := storeHostToken(hostKey, opts.Token, opts.AllowInsecureStore); err != nil {
+			if err := storeLoginToken(hostKey, opts); err != nil {
 				return fmt.Errorf("store token: %w", err)
 			}
 
@@ -467,6 +527,7 @@ func runLogin(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error
 				Username:           opts.Username,
 				AuthMethod:         "basic",
 				AllowInsecureStore: opts.AllowInsecureStore,
+				TokenCommand:       opts.TokenCommand,
 			})
 
 			if err := cfg.Save(); err != nil {
@@ -492,8 +553,8 @@ func newStatusCmd(f *cmdutil.Factory) *cobra.Command {
 contexts.
 
 For each host, the output includes the base URL, deployment kind (dc or
-cloud), the stored username, and the token source (OS keychain or the
-BKT_TOKEN environment variable). Configured contexts are listed with their
+cloud), the stored username, and the token source (OS keychain, an external
+token_command, or the BKT_TOKEN environment variable). Configured contexts are listed with their
 associated host, project/workspace, and default repository.
 
 Use --output json to get machine-readable output suitable for scripting.`,
@@ -558,9 +619,9 @@ func runStatus(cmd *cobra.Command, f *cmdutil.Factory) error {
 			BaseURL:     h.BaseURL,
 			Username:    h.Username,
 			AuthMethod:  am,
-			TokenSource: tokenSource,
+			TokenSource: hostTokenSource(h, tokenSource),
 		}
-		if am == "oauth" && tokenSource != secret.EnvToken {
+		if am == "oauth" && hs.TokenSource == "keyring" {
 			hs.Expires = oauthExpiryLabel(key, h)
 			hs.Refresh = oauthRefreshStatus(hs.Expires)
 		}
@@ -596,7 +657,7 @@ func runStatus(cmd *cobra.Command, f *cmdutil.Factory) error {
 		Contexts:      contexts,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(hosts) == 0 {
 			if _, err := fmt.Fprintln(ios.Out, "No hosts configured. Run `bkt auth login` to add one."); err != nil {
 				return err
@@ -747,7 +808,11 @@ func runLogout(cmd *cobra.Command, f *cmdutil.Factory, opts *logoutOptions) erro
 	}
 
 	host := cfg.Hosts[key]
-	if err := deleteHostToken(key, host); err != nil {
+	if host != nil && host.TokenCommand != "" {
+		// The token lives in an external secret manager; there is no
+		// keychain item to delete.
+		host.Token = ""
+	} else if err := deleteHostToken(key, host); err != nil {
 		return fmt.Errorf("delete credentials: %w", err)
 	}
 
@@ -769,6 +834,15 @@ func runLogout(cmd *cobra.Command, f *cmdutil.Factory, opts *logoutOptions) erro
 	return nil
 }
 
+// storeLoginToken persists the verified login token unless it comes from an
+// external command, in which case it is re-fetched on demand.
+func storeLoginToken(hostKey string, opts *loginOptions) error {
+	if opts.TokenCommand != "" {
+		return nil
+	}
+	return storeHostToken(hostKey, opts.Token, opts.AllowInsecureStore)
+}
+
 func storeHostToken(hostKey, token string, allowInsecure bool) error {
 	opts := []secret.Option{}
 	if allowInsecure {
@@ -874,6 +948,19 @@ func resolvedTokenSource() string {
 	return "keyring"
 }
 
+// hostTokenSource refines the global token source for a single host: hosts
+// configured with token_command fetch their token from an external command
+// unless BKT_TOKEN overrides it.
+func hostTokenSource(host *config.Host, global string) string {
+	if global == secret.EnvToken {
+		return global
+	}
+	if host != nil && strings.TrimSpace(host.TokenCommand) != "" {
+		return "command"
+	}
+	return global
+}
+
 func detectedAuthMethod(hostKey string, host *config.Host, tokenSource string) string {
 	if tokenSource == secret.EnvToken {
 		if m := strings.TrimSpace(os.Getenv(secret.EnvAuthMethod)); m != "" {
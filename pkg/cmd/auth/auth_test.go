@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -1053,6 +1054,99 @@ func TestRunLogoutFullFlow(t *testing.T) {
 	}
 }
 
+func TestRunLoginTokenCommandSkipsKeyring(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, pass, ok := r.BasicAuth(); !ok || pass != "vault-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if strings.Contains(r.URL.Path, "/rest/api/1.0/users/admin") {
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "admin", "displayName": "Admin User"})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	// A keyring that cannot open proves the token never touches it.
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("BKT_ALLOW_INSECURE_STORE", "")
+	t.Setenv(secret.EnvToken, "")
+	t.Setenv("BKT_CONFIG_DIR", t.TempDir())
+
+	cfg := &config.Config{
+		Hosts:    make(map[string]*config.Host),
+		Contexts: make(map[string]*config.Context),
+	}
+	f, stdout, _ := newAuthTestFactory(cfg)
+
+	err := runLogin(newTestCmd(), f, &loginOptions{
+		Host:         srv.URL,
+		Kind:         "dc",
+		Username:     "admin",
+		AllowHTTP:    true,
+		TokenCommand: "printf 'vault-token\\n'",
+	})
+	if err != nil {
+		t.Fatalf("runLogin: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Admin User") {
+		t.Errorf("expected display name in output, got:\n%s", stdout.String())
+	}
+
+	hostKey, err := cmdutil.HostKeyFromURL(srv.URL)
+	if err != nil {
+		t.Fatalf("HostKeyFromURL: %v", err)
+	}
+	host := cfg.Hosts[hostKey]
+	if host == nil || host.TokenCommand != "printf 'vault-token\\n'" {
+		t.Fatalf("expected token_command to be persisted, got %#v", host)
+	}
+}
+
+func TestRunLoginTokenCommandRejectsTokenFlag(t *testing.T) {
+	cfg := &config.Config{Hosts: map[string]*config.Host{}, Contexts: map[string]*config.Context{}}
+	f, _, _ := newAuthTestFactory(cfg)
+	t.Setenv(secret.EnvToken, "")
+
+	err := runLogin(newTestCmd(), f, &loginOptions{
+		Host:         "https://bitbucket.example.com",
+		Kind:         "dc",
+		Token:        "inline",
+		TokenCommand: "op read op://vault/bkt",
+	})
+	if err == nil || !strings.Contains(err.Error(), "--token-command cannot be combined") {
+		t.Fatalf("expected mutual exclusion error, got %v", err)
+	}
+}
+
+func TestRunStatusShowsCommandTokenSource(t *testing.T) {
+	t.Setenv(secret.EnvToken, "")
+	cfg := &config.Config{
+		Hosts: map[string]*config.Host{
+			"bitbucket.example.com": {
+				Kind:         "dc",
+				BaseURL:      "https://bitbucket.example.com",
+				Username:     "admin",
+				AuthMethod:   "basic",
+				TokenCommand: "pass show bitbucket",
+			},
+		},
+	}
+	f, stdout, _ := newAuthTestFactory(cfg)
+
+	if err := runStatus(newTestCmd(), f); err != nil {
+		t.Fatalf("runStatus: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "token source: command") {
+		t.Errorf("expected command token source, got:\n%s", stdout.String())
+	}
+}
+
 func newTestCmd() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.SetContext(context.Background())
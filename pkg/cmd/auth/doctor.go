@@ -28,11 +28,13 @@ const (
 )
 
 type doctorOptions struct {
-	Host string
+	Host       string
+	Scopes     bool
+	ExpiryDays int
 }
 
 func newDoctorCmd(f *cmdutil.Factory) *cobra.Command {
-	opts := &doctorOptions{}
+	opts := &doctorOptions{ExpiryDays: 14}
 
 	cmd := &cobra.Command{
 		Use:   "doctor [host]",
@@ -48,12 +50,29 @@ keep prompting for the Keychain password on every read. The fix in that case
 is to re-run ` + "`bkt auth login`" + ` so the item is recreated with the
 current binary's DR.
 
-The command never reads the stored secret itself.`,
+Hosts configured with a token_command have that command executed once
+(bypassing the in-process cache) to verify it prints a token within the
+timeout. The token itself is never displayed.
+
+With --scopes, doctor also resolves each host's credential and audits what
+it is allowed to do: personal access token permissions on Data Center and
+the X-OAuth-Scopes response header on Cloud. Each command group (repo, pr,
+webhook, variable, ...) is compared against the least privilege it needs, and
+tokens expiring within --expiry-days are flagged. On Data Center the host's
+own token is only identified when it was recorded with "bkt auth login
+--token-id"; otherwise doctor says its expiry was not checked and flags every
+token of the user that expires within the window. This mode reads the stored
+token and talks to the server.
+
+Without --scopes the command never reads the stored keychain secret itself.`,
 		Example: `  # Inspect the default host
   bkt auth doctor
 
   # Inspect a specific host
-  bkt auth doctor bitbucket.example.com`,
+  bkt auth doctor bitbucket.example.com
+
+  # Audit token permissions and warn about tokens expiring within 30 days
+  bkt auth doctor --scopes --expiry-days 30`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -63,6 +82,9 @@ The command never reads the stored secret itself.`,
 		},
 	}
 
+	cmd.Flags().BoolVar(&opts.Scopes, "scopes", false, "Audit token permissions/scopes and expiry against what each command group needs (contacts the server)")
+	cmd.Flags().IntVar(&opts.ExpiryDays, "expiry-days", opts.ExpiryDays, "With --scopes, warn about tokens expiring within this many days")
+
 	return cmd
 }
 
@@ -84,11 +106,14 @@ type doctorReport struct {
 }
 
 type hostProbe struct {
-	Key        string `json:"key"`
-	BaseURL    string `json:"base_url,omitempty"`
-	AuthMethod string `json:"auth_method,omitempty"`
-	ItemStored bool   `json:"item_stored"`
-	ProbeError string `json:"probe_error,omitempty"`
+	Key          string      `json:"key"`
+	BaseURL      string      `json:"base_url,omitempty"`
+	AuthMethod   string      `json:"auth_method,omitempty"`
+	ItemStored   bool        `json:"item_stored"`
+	ProbeError   string      `json:"probe_error,omitempty"`
+	TokenCommand string      `json:"token_command,omitempty"` // "ok" or "failed"
+	CommandError string      `json:"token_command_error,omitempty"`
+	Audit        *tokenAudit `json:"audit,omitempty"`
 }
 
 func runDoctor(cmd *cobra.Command, f *cmdutil.Factory, opts *doctorOptions) error {
@@ -131,13 +156,25 @@ func runDoctor(cmd *cobra.Command, f *cmdutil.Factory, opts *doctorOptions) erro
 
 	for _, key := range hostKeys {
 		probe := hostProbe{Key: key}
-		if host, ok := cfg.Hosts[key]; ok {
+		host, ok := cfg.Hosts[key]
+		if ok {
 			probe.BaseURL = host.BaseURL
 			probe.AuthMethod = host.AuthMethod
 			if probe.AuthMethod == "" {
 				probe.AuthMethod = "basic"
 			}
 		}
+		if ok && strings.TrimSpace(host.TokenCommand) != "" {
+			if _, cmdErr := runTokenCommand(cmd.Context(), host.TokenCommand); cmdErr != nil {
+				probe.TokenCommand = "failed"
+				probe.CommandError = cmdErr.Error()
+				report.ProbeErrors = append(report.ProbeErrors, fmt.Sprintf("token_command for %s: %v", key, cmdErr))
+			} else {
+				probe.TokenCommand = "ok"
+			}
+			report.Hosts = append(report.Hosts, probe)
+			continue
+		}
 		if runtime.GOOS == "darwin" {
 			present, probeErr := keychainItemPresent(key)
 			probe.ItemStored = present
@@ -149,10 +186,21 @@ func runDoctor(cmd *cobra.Command, f *cmdutil.Factory, opts *doctorOptions) erro
 		report.Hosts = append(report.Hosts, probe)
 	}
 
+	if opts.Scopes {
+		window := time.Duration(opts.ExpiryDays) * 24 * time.Hour
+		for i := range report.Hosts {
+			if report.Hosts[i].TokenCommand == "failed" {
+				continue
+			}
+			report.Hosts[i].Audit = auditHostToken(cmd.Context(), f, report.Hosts[i].Key, window)
+		}
+	}
+
 	report.Diagnosis, report.NextSteps = diagnose(report, cfg)
+	report.NextSteps = append(report.NextSteps, auditNextSteps(report.Hosts, opts.ExpiryDays)...)
 	report.Elapsed = time.Since(start).Round(time.Millisecond)
 
-	return cmdutil.WriteOutput(cmd, ios.Out, report, func() error {
+	return cmdutil.WriteOutput(cmd, ios, report, func() error {
 		return writeDoctorText(ios.Out, report, f)
 	})
 }
@@ -279,6 +327,10 @@ func keychainItemPresent(hostKey string) (bool, error) {
 	return false, fmt.Errorf("security find-generic-password: %w (%s)", err, detail)
 }
 
+// runTokenCommand is a package-level function pointer so tests can stub
+// external token helpers.
+var runTokenCommand = secret.RunTokenCommand
+
 // runCmd is a package-level function pointer so tests can stub the shell-out
 // surface without running real codesign/security binaries.
 var runCmd = func(ctx context.Context, name string, args ...string) (string, error) {
@@ -288,6 +340,21 @@ var runCmd = func(ctx context.Context, name string, args ...string) (string, err
 }
 
 func diagnose(r doctorReport, cfg *config.Config) (string, []string) {
+	var failedCommands []string
+	for _, h := range r.Hosts {
+		if h.TokenCommand == "failed" {
+			failedCommands = append(failedCommands, h.Key)
+		}
+	}
+	if len(failedCommands) > 0 {
+		return fmt.Sprintf("token_command failed for %s; every command against these hosts will fail until it prints a token.", strings.Join(failedCommands, ", ")),
+			[]string{
+				"Run the token_command from config.yml in your shell and confirm it prints only the token.",
+				"Sign in to the secret manager (e.g. `op signin`, `vault login`) if the session has expired.",
+				"Raise BKT_TOKEN_COMMAND_TIMEOUT if the helper needs time for an interactive unlock.",
+			}
+	}
+
 	if runtime.GOOS != "darwin" {
 		if len(cfg.Hosts) == 0 {
 			return "No hosts configured.", []string{"Run `bkt auth login <host>` to add one."}
@@ -425,7 +492,9 @@ func writeDoctorText(out io.Writer, r doctorReport, f *cmdutil.Factory) error {
 			if h.AuthMethod != "" {
 				line += fmt.Sprintf(", auth=%s", h.AuthMethod)
 			}
-			if r.Platform == "darwin" {
+			if h.TokenCommand != "" {
+				line += fmt.Sprintf(", token_command=%s", h.TokenCommand)
+			} else if r.Platform == "darwin" {
 				switch {
 				case h.ProbeError != "":
 					line += ", keychain=unknown"
@@ -438,6 +507,9 @@ func writeDoctorText(out io.Writer, r doctorReport, f *cmdutil.Factory) error {
 			if _, err := fmt.Fprintln(w, line); err != nil {
 				return err
 			}
+			if err := writeAuditText(w, h.Audit); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -468,6 +540,106 @@ func writeDoctorText(out io.Writer, r doctorReport, f *cmdutil.Factory) error {
 	return nil
 }
 
+func writeAuditText(w io.Writer, a *tokenAudit) error {
+	if a == nil {
+		return nil
+	}
+	if a.Error != "" {
+		_, err := fmt.Fprintf(w, "      permissions: unknown (%s)\n", a.Error)
+		return err
+	}
+	if len(a.Granted) > 0 {
+		line := "      granted:  " + strings.Join(a.Granted, ", ")
+		if a.TokenName != "" {
+			line += fmt.Sprintf(" (token %q)", a.TokenName)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if a.ExpiresAt != nil {
+		line := "      expires:  " + a.ExpiresAt.Format("2006-01-02")
+		if a.ExpiresSoon {
+			line += " (soon)"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if len(a.Expiring) > 0 {
+		var names []string
+		for _, tok := range a.Expiring {
+			names = append(names, fmt.Sprintf("%s %s", tok.Name, tok.ExpiresAt.Format("2006-01-02")))
+		}
+		if _, err := fmt.Fprintf(w, "      expiring: %s\n", strings.Join(names, ", ")); err != nil {
+			return err
+		}
+	}
+	if len(a.Groups) > 0 {
+		var ok, missing []string
+		for _, g := range a.Groups {
+			if g.Granted {
+				ok = append(ok, g.Group)
+			} else {
+				missing = append(missing, fmt.Sprintf("%s (needs %s)", g.Group, g.Requires))
+			}
+		}
+		if len(ok) > 0 {
+			if _, err := fmt.Fprintf(w, "      usable:   %s\n", strings.Join(ok, ", ")); err != nil {
+				return err
+			}
+		}
+		if len(missing) > 0 {
+			if _, err := fmt.Fprintf(w, "      missing:  %s\n", strings.Join(missing, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+	if a.Note != "" {
+		if _, err := fmt.Fprintf(w, "      note:     %s\n", a.Note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditNextSteps turns token audit findings into actionable next steps.
+func auditNextSteps(hosts []hostProbe, expiryDays int) []string {
+	var steps []string
+	for _, h := range hosts {
+		a := h.Audit
+		if a == nil || a.Error != "" {
+			continue
+		}
+		if missing := a.Missing(); len(missing) > 0 {
+			var needs []string
+			for _, g := range missing {
+				needs = append(needs, fmt.Sprintf("%s for `%s`", g.Requires, g.Group))
+			}
+			steps = append(steps, fmt.Sprintf("%s: grant %s, or expect those commands to fail with 401/403.", h.Key, strings.Join(needs, ", ")))
+		}
+		if a.ExpiresSoon && a.ExpiresAt != nil {
+			verb := "expires"
+			if a.ExpiresAt.Before(time.Now()) {
+				verb = "expired"
+			}
+			steps = append(steps, fmt.Sprintf("%s: token %s %s (within %d days); create a replacement and re-run `bkt auth login`.", h.Key, verb, a.ExpiresAt.Format("2006-01-02"), expiryDays))
+		}
+		if a.ExpiryUnchecked {
+			if len(a.Expiring) > 0 {
+				var names []string
+				for _, tok := range a.Expiring {
+					names = append(names, tok.Name)
+				}
+				steps = append(steps, fmt.Sprintf("%s: personal access tokens %s expire within %d days; if this host uses one, create a replacement and re-run `bkt auth login --token-id <id>`.", h.Key, strings.Join(names, ", "), expiryDays))
+			} else {
+				steps = append(steps, fmt.Sprintf("%s: token expiry was not checked; record the token with `bkt auth login --token-id <id>`.", h.Key))
+			}
+		}
+	}
+	return steps
+}
+
 func printKV(w io.Writer, label, value string) {
 	if value == "" {
 		value = "(unknown)"
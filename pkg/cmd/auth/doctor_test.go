@@ -455,3 +455,45 @@ func TestWriteDoctorText_NonDarwinMinimal(t *testing.T) {
 		t.Errorf("host line missing:\n%s", out)
 	}
 }
+
+func TestRunDoctorReportsFailingTokenCommand(t *testing.T) {
+	original := runTokenCommand
+	t.Cleanup(func() { runTokenCommand = original })
+
+	var ran []string
+	runTokenCommand = func(_ context.Context, command string) (string, error) {
+		ran = append(ran, command)
+		if strings.Contains(command, "broken") {
+			return "", errors.New("token command failed: exit status 1: not signed in")
+		}
+		return "secret-value", nil
+	}
+
+	cfg := &config.Config{Hosts: map[string]*config.Host{
+		"good.example.com":   {Kind: "dc", BaseURL: "https://good.example.com", TokenCommand: "op read op://vault/good"},
+		"broken.example.com": {Kind: "dc", BaseURL: "https://broken.example.com", TokenCommand: "op read op://vault/broken"},
+	}}
+	f, stdout, _ := newAuthTestFactory(cfg)
+
+	if err := runDoctor(newTestCmd(), f, &doctorOptions{}); err != nil {
+		t.Fatalf("runDoctor: %v", err)
+	}
+	if len(ran) != 2 {
+		t.Fatalf("expected both token commands to run, got %v", ran)
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"token_command failed for broken.example.com",
+		"broken.example.com (https://broken.example.com), auth=basic, token_command=failed",
+		"good.example.com (https://good.example.com), auth=basic, token_command=ok",
+		"not signed in",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n---\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "secret-value") {
+		t.Errorf("doctor output must never include the token:\n%s", out)
+	}
+}
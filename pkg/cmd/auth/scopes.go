@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// commandRequirement records the least privilege each command group needs on
+// each platform. An empty value means the group is not available there.
+type commandRequirement struct {
+	Group string
+	DC    string
+	Cloud string
+}
+
+// commandRequirements is ordered roughly from least to most privileged so the
+// doctor output reads top-down.
+var commandRequirements = []commandRequirement{
+	{Group: "repo", DC: "REPO_READ", Cloud: "repository"},
+	{Group: "project", DC: "PROJECT_READ", Cloud: "project"},
+	{Group: "commit", DC: "REPO_READ", Cloud: "repository"},
+	{Group: "branch", DC: "REPO_WRITE", Cloud: "repository:write"},
+	{Group: "pr", DC: "REPO_WRITE", Cloud: "pullrequest:write"},
+	{Group: "issue", Cloud: "issue:write"},
+	{Group: "pipeline", Cloud: "pipeline:write"},
+	{Group: "variable", Cloud: "pipeline:write"},
+	{Group: "webhook", DC: "REPO_ADMIN", Cloud: "webhook"},
+	{Group: "perms", DC: "REPO_ADMIN", Cloud: "repository:admin"},
+}
+
+// groupCheck is the outcome of comparing one command group's requirement with
+// the token's granted permissions.
+type groupCheck struct {
+	Group    string `json:"group"`
+	Requires string `json:"requires"`
+	Granted  bool   `json:"granted"`
+}
+
+// tokenAudit summarises what a host's credential is allowed to do.
+type tokenAudit struct {
+	Source      string       `json:"source"`
+	TokenName   string       `json:"token_name,omitempty"`
+	Granted     []string     `json:"granted,omitempty"`
+	Groups      []groupCheck `json:"groups,omitempty"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty"`
+	ExpiresSoon bool         `json:"expires_soon,omitempty"`
+	// ExpiryUnchecked is set when bkt cannot tell which personal access token
+	// the host uses; Expiring then lists every token of the user that expires
+	// within the window instead.
+	ExpiryUnchecked bool            `json:"expiry_unchecked,omitempty"`
+	Expiring        []expiringToken `json:"expiring,omitempty"`
+	Note            string          `json:"note,omitempty"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// expiringToken is a personal access token close to its expiry date.
+type expiringToken struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Missing returns the command groups the credential cannot use.
+func (a *tokenAudit) Missing() []groupCheck {
+	if a == nil {
+		return nil
+	}
+	var out []groupCheck
+	for _, g := range a.Groups {
+		if !g.Granted {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// auditHostToken resolves the host's credential and discovers its effective
+// permissions: PAT permissions on Data Center and X-OAuth-Scopes on Cloud.
+func auditHostToken(ctx context.Context, f *cmdutil.Factory, hostKey string, expiryWindow time.Duration) *tokenAudit {
+	_, host, err := cmdutil.ResolveHost(f, "", hostKey)
+	if err != nil {
+		return &tokenAudit{Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	switch host.Kind {
+	case "dc":
+		return auditDCToken(ctx, f, host, expiryWindow)
+	case "cloud":
+		return auditCloudToken(ctx, f, host)
+	default:
+		return &tokenAudit{Error: fmt.Sprintf("unsupported host kind %q", host.Kind)}
+	}
+}
+
+func auditDCToken(ctx context.Context, f *cmdutil.Factory, host *config.Host, expiryWindow time.Duration) *tokenAudit {
+	audit := &tokenAudit{Source: "access-tokens"}
+
+	client, err := f.DCClient(host)
+	if err != nil {
+		audit.Error = err.Error()
+		return audit
+	}
+
+	if host.TokenID == "" {
+		audit.ExpiryUnchecked = true
+		audit.Note = "expiry not checked: bkt does not know which personal access token this host uses; record it with `bkt auth login --token-id <id>` (see `bkt auth token list`)"
+	}
+
+	user, err := tokenOwner(ctx, client, host, "")
+	if err != nil {
+		if audit.ExpiryUnchecked {
+			return audit
+		}
+		audit.Error = err.Error()
+		return audit
+	}
+
+	tokens, err := client.ListUserAccessTokens(ctx, user, 0)
+	if err != nil {
+		if audit.ExpiryUnchecked {
+			return audit
+		}
+		audit.Error = fmt.Sprintf("list access tokens for %s: %v", user, err)
+		return audit
+	}
+	if audit.ExpiryUnchecked {
+		// Without the token id, flag every token of the user that is about
+		// to expire, since the host may be using any of them.
+		for _, tok := range tokens {
+			if tok.ExpiryDate <= 0 {
+				continue
+			}
+			expires := time.UnixMilli(tok.ExpiryDate).UTC()
+			if time.Until(expires) <= expiryWindow {
+				audit.Expiring = append(audit.Expiring, expiringToken{ID: tok.ID, Name: tok.Name, ExpiresAt: expires})
+			}
+		}
+		return audit
+	}
+	current, ok := findAccessToken(tokens, host.TokenID)
+	if !ok {
+		audit.Note = fmt.Sprintf("personal access token %s not found for %s; it may have been revoked", host.TokenID, user)
+		return audit
+	}
+
+	audit.TokenName = current.Name
+	audit.Granted = append([]string(nil), current.Permissions...)
+	sort.Strings(audit.Granted)
+	for _, req := range commandRequirements {
+		if req.DC == "" {
+			continue
+		}
+		audit.Groups = append(audit.Groups, groupCheck{
+			Group:    req.Group,
+			Requires: req.DC,
+			Granted:  dcPermissionSatisfies(current.Permissions, req.DC),
+		})
+	}
+
+	if current.ExpiryDate > 0 {
+		expires := time.UnixMilli(current.ExpiryDate).UTC()
+		audit.ExpiresAt = &expires
+		audit.ExpiresSoon = time.Until(expires) <= expiryWindow
+	}
+	return audit
+}
+
+func auditCloudToken(ctx context.Context, f *cmdutil.Factory, host *config.Host) *tokenAudit {
+	audit := &tokenAudit{Source: "x-oauth-scopes"}
+
+	client, err := f.CloudClient(host)
+	if err != nil {
+		audit.Error = err.Error()
+		return audit
+	}
+
+	scopes, advertised, err := client.GrantedScopes(ctx)
+	if err != nil {
+		audit.Error = err.Error()
+		return audit
+	}
+	if !advertised {
+		audit.Note = "Bitbucket Cloud did not advertise scopes for this credential type"
+		return audit
+	}
+
+	audit.Granted = scopes
+	for _, req := range commandRequirements {
+		if req.Cloud == "" {
+			continue
+		}
+		audit.Groups = append(audit.Groups, groupCheck{
+			Group:    req.Group,
+			Requires: req.Cloud,
+			Granted:  cloudScopeSatisfies(scopes, req.Cloud),
+		})
+	}
+	return audit
+}
+
+var dcPermissionRank = map[string]int{"READ": 1, "WRITE": 2, "ADMIN": 3}
+
+// dcPermissionSatisfies reports whether granted PAT permissions cover the
+// required one. Permissions rank READ < WRITE < ADMIN within a resource, and a
+// project permission implies the same level on the project's repositories.
+func dcPermissionSatisfies(granted []string, required string) bool {
+	reqResource, reqLevel, ok := splitDCPermission(required)
+	if !ok {
+		return false
+	}
+	for _, g := range granted {
+		resource, level, ok := splitDCPermission(g)
+		if !ok || dcPermissionRank[level] < dcPermissionRank[reqLevel] {
+			continue
+		}
+		if resource == reqResource || (resource == "PROJECT" && reqResource == "REPO") {
+			return true
+		}
+	}
+	return false
+}
+
+func splitDCPermission(p string) (string, string, bool) {
+	resource, level, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(p)), "_")
+	if !ok || dcPermissionRank[level] == 0 {
+		return "", "", false
+	}
+	return resource, level, true
+}
+
+// cloudScopeImplies lists scopes granted implicitly by a broader scope, per
+// Bitbucket Cloud's documented scope hierarchy.
+var cloudScopeImplies = map[string][]string{
+	"repository:admin":  {"repository:write"},
+	"repository:delete": {"repository:admin"},
+	"repository:write":  {"repository"},
+	"pullrequest:write": {"pullrequest", "repository:write"},
+	"pullrequest":       {"repository"},
+	"issue:write":       {"issue"},
+	"issue":             {"repository"},
+	"pipeline:admin":    {"pipeline:variable"},
+	"pipeline:variable": {"pipeline:write"},
+	"pipeline:write":    {"pipeline"},
+	"project:admin":     {"project"},
+	"account:write":     {"account"},
+	"team:write":        {"team"},
+	"snippet:write":     {"snippet"},
+	"webhook:write":     {"webhook"},
+	"wiki":              {"repository"},
+}
+
+// cloudScopeSatisfies reports whether any granted scope equals or implies the
+// required scope. Atlassian API-token scopes such as
+// "write:repository:bitbucket" are normalised to their OAuth equivalents.
+func cloudScopeSatisfies(granted []string, required string) bool {
+	seen := map[string]bool{}
+	queue := make([]string, 0, len(granted))
+	for _, g := range granted {
+		queue = append(queue, normalizeCloudScope(g))
+	}
+	for len(queue) > 0 {
+		scope := queue[0]
+		queue = queue[1:]
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		if scope == required {
+			return true
+		}
+		queue = append(queue, cloudScopeImplies[scope]...)
+	}
+	return false
+}
+
+func normalizeCloudScope(scope string) string {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	parts := strings.Split(scope, ":")
+	if len(parts) != 3 || parts[2] != "bitbucket" {
+		return scope
+	}
+	switch parts[0] {
+	case "read":
+		return parts[1]
+	case "write", "admin", "delete":
+		return parts[1] + ":" + parts[0]
+	default:
+		return scope
+	}
+}
@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+)
+
+func TestDCPermissionSatisfies(t *testing.T) {
+	tests := []struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		{[]string{"REPO_READ"}, "REPO_READ", true},
+		{[]string{"REPO_READ"}, "REPO_WRITE", false},
+		{[]string{"REPO_ADMIN"}, "REPO_WRITE", true},
+		{[]string{"PROJECT_WRITE"}, "REPO_WRITE", true},
+		{[]string{"PROJECT_READ", "REPO_WRITE"}, "REPO_ADMIN", false},
+		{[]string{"REPO_ADMIN"}, "PROJECT_READ", false},
+		{[]string{"project_admin"}, "PROJECT_READ", true},
+		{nil, "REPO_READ", false},
+	}
+	for _, tt := range tests {
+		if got := dcPermissionSatisfies(tt.granted, tt.required); got != tt.want {
+			t.Errorf("dcPermissionSatisfies(%v, %s) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestCloudScopeSatisfies(t *testing.T) {
+	tests := []struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		{[]string{"repository"}, "repository", true},
+		{[]string{"repository:admin"}, "repository", true},
+		{[]string{"pullrequest:write"}, "repository:write", true},
+		{[]string{"pullrequest"}, "pullrequest:write", false},
+		{[]string{"pipeline:variable"}, "pipeline:write", true},
+		{[]string{"pipeline"}, "pipeline:write", false},
+		{[]string{"write:repository:bitbucket"}, "repository:write", true},
+		{[]string{"read:pullrequest:bitbucket"}, "repository", true},
+		{[]string{"write:webhook:bitbucket"}, "webhook", true},
+	}
+	for _, tt := range tests {
+		if got := cloudScopeSatisfies(tt.granted, tt.required); got != tt.want {
+			t.Errorf("cloudScopeSatisfies(%v, %s) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestAuditHostTokenDataCenter(t *testing.T) {
+	expiry := time.Now().Add(5 * 24 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/access-tokens/1.0/users/alice":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"isLastPage": true,
+				"values": []map[string]any{
+					{"id": "1", "name": "old", "permissions": []string{"REPO_ADMIN"}, "lastAuthenticated": 300},
+					{"id": "2", "name": "ci", "permissions": []string{"PROJECT_READ", "REPO_WRITE"}, "lastAuthenticated": 200, "expiryDate": expiry.UnixMilli()},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Hosts: map[string]*config.Host{
+		"dc": {Kind: "dc", BaseURL: server.URL, Username: "alice", Token: "secret", TokenID: "2"},
+	}}
+	f, _, _ := newAuthTestFactory(cfg)
+
+	audit := auditHostToken(context.Background(), f, "dc", 14*24*time.Hour)
+	if audit.Error != "" {
+		t.Fatalf("unexpected audit error: %s", audit.Error)
+	}
+	if audit.TokenName != "ci" {
+		t.Fatalf("TokenName = %q, want ci (the recorded token ID)", audit.TokenName)
+	}
+	if !audit.ExpiresSoon || audit.ExpiresAt == nil {
+		t.Fatalf("expected token to be flagged as expiring soon, got %+v", audit)
+	}
+
+	var missing []string
+	for _, g := range audit.Missing() {
+		missing = append(missing, g.Group)
+	}
+	if len(missing) != 2 || missing[0] != "webhook" || missing[1] != "perms" {
+		t.Fatalf("missing groups = %v, want [webhook perms]", missing)
+	}
+
+	steps := auditNextSteps([]hostProbe{{Key: "dc", Audit: audit}}, 14)
+	if len(steps) != 2 {
+		t.Fatalf("next steps = %v, want missing-permission and expiry steps", steps)
+	}
+}
+
+func TestAuditHostTokenDataCenterUnknownTokenID(t *testing.T) {
+	cfg := &config.Config{Hosts: map[string]*config.Host{
+		"dc": {Kind: "dc", BaseURL: "http://127.0.0.1:1", Username: "alice", Token: "secret"},
+	}}
+	f, _, _ := newAuthTestFactory(cfg)
+
+	audit := auditHostToken(context.Background(), f, "dc", 14*24*time.Hour)
+	if audit.TokenName != "" || !audit.ExpiryUnchecked || !strings.Contains(audit.Note, "--token-id") {
+		t.Fatalf("expected a note asking for --token-id, got %+v", audit)
+	}
+	steps := auditNextSteps([]hostProbe{{Key: "dc", Audit: audit}}, 14)
+	if len(steps) != 1 || !strings.Contains(steps[0], "token expiry was not checked") {
+		t.Fatalf("next steps = %v, want an unchecked-expiry step", steps)
+	}
+}
+
+func TestAuditHostTokenDataCenterWithoutTokenIDFlagsExpiringTokens(t *testing.T) {
+	soon := time.Now().Add(3 * 24 * time.Hour)
+	later := time.Now().Add(90 * 24 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/access-tokens/1.0/users/alice" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"isLastPage": true,
+			"values": []map[string]any{
+				{"id": "1", "name": "ci", "expiryDate": soon.UnixMilli()},
+				{"id": "2", "name": "laptop", "expiryDate": later.UnixMilli()},
+				{"id": "3", "name": "forever"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Hosts: map[string]*config.Host{
+		"dc": {Kind: "dc", BaseURL: server.URL, Username: "alice", Token: "secret"},
+	}}
+	f, _, _ := newAuthTestFactory(cfg)
+
+	audit := auditHostToken(context.Background(), f, "dc", 14*24*time.Hour)
+	if audit.Error != "" || !audit.ExpiryUnchecked || !strings.Contains(audit.Note, "expiry not checked") {
+		t.Fatalf("expected an unchecked-expiry note, got %+v", audit)
+	}
+	if len(audit.Expiring) != 1 || audit.Expiring[0].Name != "ci" {
+		t.Fatalf("expiring = %+v, want only ci", audit.Expiring)
+	}
+
+	steps := auditNextSteps([]hostProbe{{Key: "dc", Audit: audit}}, 14)
+	if len(steps) != 1 || !strings.Contains(steps[0], "ci expire within 14 days") || !strings.Contains(steps[0], "--token-id") {
+		t.Fatalf("next steps = %v, want an expiring-token step", steps)
+	}
+
+	var out strings.Builder
+	if err := writeAuditText(&out, audit); err != nil {
+		t.Fatalf("writeAuditText: %v", err)
+	}
+	if !strings.Contains(out.String(), "expiring: ci "+soon.UTC().Format("2006-01-02")) {
+		t.Fatalf("unexpected audit text:\n%s", out.String())
+	}
+}
+
+func TestAuditHostTokenCloud(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-OAuth-Scopes", "repository:write, pullrequest:write, pipeline")
+		_, _ = w.Write([]byte(`{"username":"alice"}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{Hosts: map[string]*config.Host{
+		"cloud": {Kind: "cloud", BaseURL: server.URL, Username: "alice", Token: "secret"},
+	}}
+	f, _, _ := newAuthTestFactory(cfg)
+
+	audit := auditHostToken(context.Background(), f, "cloud", 0)
+	if audit.Error != "" {
+		t.Fatalf("unexpected audit error: %s", audit.Error)
+	}
+	got := map[string]bool{}
+	for _, g := range audit.Groups {
+		got[g.Group] = g.Granted
+	}
+	if !got["pr"] || !got["branch"] || got["pipeline"] || got["variable"] || got["webhook"] {
+		t.Fatalf("unexpected group grants: %v", got)
+	}
+}
@@ -0,0 +1,455 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/accesstoken"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+func newTokenCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage Data Center personal access tokens (DC only)",
+		Long: `Create, list, and revoke personal access tokens on a Bitbucket Data Center
+host using the credentials bkt is already logged in with.
+
+Tokens belong to the authenticated user unless --user names another account
+(which requires admin rights). The secret of a newly created token is shown
+exactly once. To replace the token bkt itself uses, run
+"bkt auth login --rotate" instead.`,
+		Example: `  # List your tokens with their last-used and expiry dates
+  bkt auth token list
+
+  # Create a read-only token that expires in 90 days
+  bkt auth token create --name ci-readonly --repo-permission read --expiry-days 90
+
+  # Revoke a token by ID
+  bkt auth token revoke 123456789012`,
+	}
+
+	cmd.AddCommand(newTokenListCmd(f))
+	cmd.AddCommand(newTokenCreateCmd(f))
+	cmd.AddCommand(newTokenRevokeCmd(f))
+
+	return cmd
+}
+
+type tokenListOptions struct {
+	Host  string
+	User  string
+	Limit int
+}
+
+func newTokenListCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenListOptions{}
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List personal access tokens",
+		Long: `List the personal access tokens owned by a user, including their
+permissions, creation date, last use, and expiry. Token secrets are never
+shown.`,
+		Example: `  # List your tokens
+  bkt auth token list
+
+  # List tokens on a specific host as JSON
+  bkt auth token list --host bitbucket.example.com --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTokenList(cmd, f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Host, "host", "", "Host key or base URL override")
+	cmd.Flags().StringVar(&opts.User, "user", "", "Token owner (defaults to the authenticated user)")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 0, "Maximum tokens to display (0 for all)")
+
+	return cmd
+}
+
+func runTokenList(cmd *cobra.Command, f *cmdutil.Factory, opts *tokenListOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	hostKey, client, user, err := resolveTokenOwner(ctx, cmd, f, opts.Host, opts.User)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := client.ListUserAccessTokens(ctx, user, opts.Limit)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"host":   hostKey,
+		"user":   user,
+		"tokens": tokens,
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		if len(tokens) == 0 {
+			_, err := fmt.Fprintf(ios.Out, "No personal access tokens for %s.\n", user)
+			return err
+		}
+		return accesstoken.WriteList(ios.Out, tokens)
+	})
+}
+
+type tokenCreateOptions struct {
+	Host              string
+	User              string
+	Name              string
+	ProjectPermission string
+	RepoPermission    string
+	ExpiryDays        int
+}
+
+func newTokenCreateCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenCreateOptions{
+		ProjectPermission: "read",
+		RepoPermission:    "write",
+	}
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a personal access token",
+		Long: `Create a personal access token with the given project and repository
+permissions. Permission levels are read, write, admin, or none; a project
+permission also applies to every repository in that project.
+
+The token secret is printed once and cannot be retrieved again. With --json
+it is included in the "token" field.`,
+		Example: `  # Create a token with the defaults bkt needs (project read, repository write)
+  bkt auth token create --name laptop
+
+  # Create a read-only token that expires in 30 days
+  bkt auth token create --name audit --project-permission read --repo-permission read --expiry-days 30`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTokenCreate(cmd, f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Host, "host", "", "Host key or base URL override")
+	cmd.Flags().StringVar(&opts.User, "user", "", "Token owner (defaults to the authenticated user)")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Token name (required)")
+	cmd.Flags().StringVar(&opts.ProjectPermission, "project-permission", opts.ProjectPermission, "Project permission: read, write, admin, or none")
+	cmd.Flags().StringVar(&opts.RepoPermission, "repo-permission", opts.RepoPermission, "Repository permission: read, write, admin, or none")
+	cmd.Flags().IntVar(&opts.ExpiryDays, "expiry-days", 0, "Days until the token expires (0 uses the server default)")
+
+	_ = cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func runTokenCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *tokenCreateOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	permissions, err := accesstoken.Permissions(
+		accesstoken.PermissionLevel{Resource: "PROJECT", Flag: "--project-permission", Level: opts.ProjectPermission},
+		accesstoken.PermissionLevel{Resource: "REPO", Flag: "--repo-permission", Level: opts.RepoPermission},
+	)
+	if err != nil {
+		return err
+	}
+	if opts.ExpiryDays < 0 {
+		return fmt.Errorf("--expiry-days must not be negative")
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	_, client, user, err := resolveTokenOwner(ctx, cmd, f, opts.Host, opts.User)
+	if err != nil {
+		return err
+	}
+
+	token, err := client.CreateUserAccessToken(ctx, user, bbdc.AccessTokenInput{
+		Name:        opts.Name,
+		Permissions: permissions,
+		ExpiryDays:  opts.ExpiryDays,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, token, func() error {
+		return accesstoken.WriteCreated(ios.Out, ios.ErrOut, token, "")
+	})
+}
+
+type tokenRevokeOptions struct {
+	Host string
+	User string
+	ID   string
+	Yes  bool
+}
+
+func newTokenRevokeCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &tokenRevokeOptions{}
+	cmd := &cobra.Command{
+		Use:     "revoke <id>",
+		Aliases: []string{"delete", "rm"},
+		Short:   "Revoke a personal access token",
+		Long: `Revoke a personal access token by ID (shown by "bkt auth token list").
+Clients using the token lose access immediately. A confirmation prompt is
+shown unless --yes is passed.`,
+		Example: `  # Revoke a token (will prompt for confirmation)
+  bkt auth token revoke 123456789012
+
+  # Revoke without confirmation
+  bkt auth token revoke 123456789012 --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.ID = args[0]
+			return runTokenRevoke(cmd, f, opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Host, "host", "", "Host key or base URL override")
+	cmd.Flags().StringVar(&opts.User, "user", "", "Token owner (defaults to the authenticated user)")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func runTokenRevoke(cmd *cobra.Command, f *cmdutil.Factory, opts *tokenRevokeOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	_, client, user, err := resolveTokenOwner(ctx, cmd, f, opts.Host, opts.User)
+	if err != nil {
+		return err
+	}
+
+	if !opts.Yes {
+		confirmed, err := f.Prompt().Confirm(fmt.Sprintf("Revoke token %s owned by %s?", opts.ID, user), false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			_, _ = fmt.Fprintln(ios.Out, "Aborted.")
+			return nil
+		}
+	}
+
+	if err := client.RevokeUserAccessToken(ctx, user, opts.ID); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(ios.Out, "✓ Revoked token %s\n", opts.ID)
+	return err
+}
+
+// resolveTokenOwner returns a Data Center client for the selected host and the
+// user whose tokens are managed, defaulting to the authenticated user.
+func resolveTokenOwner(ctx context.Context, cmd *cobra.Command, f *cmdutil.Factory, hostOverride, userOverride string) (string, *bbdc.Client, string, error) {
+	hostKey, host, err := cmdutil.ResolveHost(f, cmdutil.FlagValue(cmd, "context"), hostOverride)
+	if err != nil {
+		return "", nil, "", err
+	}
+	if host.Kind != "dc" {
+		return "", nil, "", fmt.Errorf("personal access token management is only supported for Bitbucket Data Center hosts")
+	}
+
+	client, err := f.DCClient(host)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	user, err := tokenOwner(ctx, client, host, userOverride)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("%w; pass --user", err)
+	}
+	return hostKey, client, user, nil
+}
+
+func tokenOwner(ctx context.Context, client *bbdc.Client, host *config.Host, override string) (string, error) {
+	if user := strings.TrimSpace(override); user != "" {
+		return user, nil
+	}
+	if host.Username != "" && host.AuthMethod != "bearer" {
+		return host.Username, nil
+	}
+	user, err := client.AuthenticatedUsername(ctx)
+	if err != nil {
+		return "", fmt.Errorf("identify token owner: %w", err)
+	}
+	return user, nil
+}
+
+// personalAccessTokenPattern matches Data Center personal access token
+// secrets: "BBDC-" prefixed tokens from 8.x onwards and the 44-character
+// base64 tokens issued before that.
+var personalAccessTokenPattern = regexp.MustCompile(`^(?:BBDC-[A-Za-z0-9+/_=-]+|[A-Za-z0-9+/]{44})$`)
+
+// looksLikePersonalAccessToken reports whether a stored credential has the
+// shape of a Data Center personal access token rather than a password.
+func looksLikePersonalAccessToken(token string) bool {
+	return personalAccessTokenPattern.MatchString(strings.TrimSpace(token))
+}
+
+func findAccessToken(tokens []bbdc.AccessToken, id string) (bbdc.AccessToken, bool) {
+	for _, tok := range tokens {
+		if tok.ID == id {
+			return tok, true
+		}
+	}
+	return bbdc.AccessToken{}, false
+}
+
+// verifyLoginTokenID checks that --token-id names a personal access token of
+// the user logging in and that the credential being stored is such a token.
+func verifyLoginTokenID(ctx context.Context, client *bbdc.Client, opts *loginOptions) error {
+	if !looksLikePersonalAccessToken(opts.Token) {
+		return fmt.Errorf("--token-id was given but the credential is not a personal access token")
+	}
+	user := opts.Username
+	if user == "" {
+		var err error
+		if user, err = client.AuthenticatedUsername(ctx); err != nil {
+			return fmt.Errorf("identify token owner: %w", err)
+		}
+	}
+	tokens, err := client.ListUserAccessTokens(ctx, user, 0)
+	if err != nil {
+		return fmt.Errorf("list access tokens: %w", err)
+	}
+	if _, ok := findAccessToken(tokens, opts.TokenID); !ok {
+		return fmt.Errorf("personal access token %s not found for %s; check the ID with `bkt auth token list`", opts.TokenID, user)
+	}
+	return nil
+}
+
+// runRotate replaces the stored Data Center token for an existing login. The
+// sequence is mint → verify → store → revoke old; a failure at any step leaves
+// the previous token stored and revokes the new one, so the host is never
+// left without a working credential.
+func runRotate(cmd *cobra.Command, f *cmdutil.Factory, opts *loginOptions) error {
+	if opts.Token != "" || opts.Web || opts.WebToken || strings.TrimSpace(opts.TokenCommand) != "" {
+		return fmt.Errorf("--rotate cannot be combined with --token, --web, --web-token, or --token-command")
+	}
+
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	hostKey, host, err := cmdutil.ResolveHost(f, cmdutil.FlagValue(cmd, "context"), opts.Host)
+	if err != nil {
+		return err
+	}
+	if host.Kind != "dc" {
+		return fmt.Errorf("--rotate is only supported for Bitbucket Data Center personal access tokens")
+	}
+	if host.TokenCommand != "" {
+		return fmt.Errorf("host %q reads its token from token_command; rotate it in the external secret manager", hostKey)
+	}
+
+	if !looksLikePersonalAccessToken(host.Token) {
+		return fmt.Errorf("the credential stored for %q is not a personal access token; log in with a token and --token-id before rotating", hostKey)
+	}
+	tokenID := cmdutil.FirstNonEmpty(strings.TrimSpace(opts.TokenID), host.TokenID)
+	if tokenID == "" {
+		return fmt.Errorf("bkt does not know which personal access token %q authenticates with; pass --token-id with the ID shown by `bkt auth token list`", hostKey)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	oldClient, err := f.DCClient(host)
+	if err != nil {
+		return err
+	}
+	user, err := tokenOwner(ctx, oldClient, host, "")
+	if err != nil {
+		return err
+	}
+
+	tokens, err := oldClient.ListUserAccessTokens(ctx, user, 0)
+	if err != nil {
+		return fmt.Errorf("list access tokens: %w", err)
+	}
+	current, ok := findAccessToken(tokens, tokenID)
+	if !ok {
+		return fmt.Errorf("personal access token %s not found for %s; pass the ID of the stored token with --token-id", tokenID, user)
+	}
+
+	created, err := oldClient.CreateUserAccessToken(ctx, user, bbdc.AccessTokenInput{
+		Name:        current.Name,
+		Permissions: current.Permissions,
+		ExpiryDays:  current.ExpiryDays,
+	})
+	if err != nil {
+		return fmt.Errorf("create replacement token: %w", err)
+	}
+
+	rollback := func(cause error) error {
+		if err := oldClient.RevokeUserAccessToken(ctx, user, created.ID); err != nil {
+			return fmt.Errorf("%w (revoking the new token %s also failed: %v; revoke it with `bkt auth token revoke %s`)", cause, created.ID, err, created.ID)
+		}
+		return cause
+	}
+
+	rotated := *host
+	rotated.Token = created.Token
+	newClient, err := f.DCClient(&rotated)
+	if err != nil {
+		return rollback(err)
+	}
+	if _, err := newClient.AuthenticatedUsername(ctx); err != nil {
+		return rollback(fmt.Errorf("verify replacement token: %w", err))
+	}
+
+	if err := storeHostToken(hostKey, created.Token, host.AllowInsecureStore); err != nil {
+		return rollback(fmt.Errorf("store token: %w", err))
+	}
+
+	if err := newClient.RevokeUserAccessToken(ctx, user, current.ID); err != nil {
+		cause := fmt.Errorf("revoke previous token %s: %w", current.ID, err)
+		if serr := storeHostToken(hostKey, host.Token, host.AllowInsecureStore); serr != nil {
+			return fmt.Errorf("%w; restoring the previous token also failed: %v; the new token %s remains stored", cause, serr, created.ID)
+		}
+		return rollback(cause)
+	}
+	host.Token = created.Token
+	host.TokenID = created.ID
+
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("token rotated to %s but saving its ID failed: %w; pass --token-id %s on the next rotation", created.ID, err, created.ID)
+	}
+
+	if _, err := fmt.Fprintf(ios.Out, "✓ Rotated token %q for %s: revoked %s, now using %s\n", created.Name, hostKey, current.ID, created.ID); err != nil {
+		return err
+	}
+	if created.ExpiryDate > 0 {
+		if _, err := fmt.Fprintf(ios.Out, "  Expires: %s\n", accesstoken.FormatTime(created.ExpiryDate, "")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
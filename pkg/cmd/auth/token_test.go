@@ -0,0 +1,323 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/internal/secret"
+)
+
+// fakeTokenServer emulates the Data Center access-tokens REST resource for a
+// single user, authenticating requests by bearer/basic token.
+type fakeTokenServer struct {
+	mu         sync.Mutex
+	tokens     map[string]map[string]any // id -> token JSON
+	secrets    map[string]string         // secret -> id
+	failRevoke string
+	next       int
+}
+
+func newFakeTokenServer(t *testing.T) (*fakeTokenServer, *httptest.Server) {
+	t.Helper()
+	fs := &fakeTokenServer{
+		tokens: map[string]map[string]any{
+			"100": {"id": "100", "name": "laptop", "permissions": []string{"PROJECT_READ", "REPO_WRITE"}, "expiryDays": 90, "lastAuthenticated": 1},
+			"200": {"id": "200", "name": "ci", "permissions": []string{"REPO_READ"}, "lastAuthenticated": 2},
+		},
+		secrets: map[string]string{"BBDC-oldsecret": "100", "BBDC-cisecret": "200"},
+		next:    300,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(fs.serve))
+	t.Cleanup(srv.Close)
+	return fs, srv
+}
+
+func (fs *fakeTokenServer) serve(w http.ResponseWriter, r *http.Request) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	_, pass, _ := r.BasicAuth()
+	if _, ok := fs.secrets[pass]; !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const base = "/rest/access-tokens/1.0/users/alice"
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-AUSERNAME", "alice")
+	switch {
+	case r.URL.Path == "/rest/api/1.0/users":
+		_, _ = w.Write([]byte(`{"values":[]}`))
+	case r.URL.Path == "/rest/api/1.0/users/alice":
+		_, _ = w.Write([]byte(`{"name":"alice","slug":"alice","displayName":"Alice"}`))
+	case r.URL.Path == base && r.Method == http.MethodGet:
+		var values []map[string]any
+		for _, id := range []string{"100", "200", "300", "301"} {
+			if tok, ok := fs.tokens[id]; ok {
+				values = append(values, tok)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"isLastPage": true, "values": values})
+	case r.URL.Path == base && r.Method == http.MethodPut:
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		id := strconv.Itoa(fs.next)
+		fs.next++
+		secretValue := "BBDC-newsecret" + id
+		tok := map[string]any{"id": id, "name": body["name"], "permissions": body["permissions"], "expiryDays": body["expiryDays"]}
+		fs.tokens[id] = tok
+		fs.secrets[secretValue] = id
+		out := map[string]any{"token": secretValue}
+		for k, v := range tok {
+			out[k] = v
+		}
+		_ = json.NewEncoder(w).Encode(out)
+	case strings.HasPrefix(r.URL.Path, base+"/") && r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, base+"/")
+		if id == fs.failRevoke {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		delete(fs.tokens, id)
+		for s, tid := range fs.secrets {
+			if tid == id {
+				delete(fs.secrets, s)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func tokenTestConfig(baseURL, token string) *config.Config {
+	return &config.Config{
+		Hosts: map[string]*config.Host{
+			"dc": {Kind: "dc", BaseURL: baseURL, Username: "alice", Token: token, TokenID: "100", AllowInsecureStore: true},
+		},
+		Contexts: map[string]*config.Context{},
+	}
+}
+
+func TestRunTokenListShowsLastUsed(t *testing.T) {
+	_, srv := newFakeTokenServer(t)
+	f, stdout, _ := newAuthTestFactory(tokenTestConfig(srv.URL, "BBDC-oldsecret"))
+
+	if err := runTokenList(newTestCmd(), f, &tokenListOptions{Host: "dc"}); err != nil {
+		t.Fatalf("runTokenList: %v", err)
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "100\tlaptop\tPROJECT_READ,REPO_WRITE") {
+		t.Fatalf("missing laptop token row:\n%s", out)
+	}
+	if !strings.Contains(out, "expires never") {
+		t.Fatalf("expected non-expiring tokens to say so:\n%s", out)
+	}
+}
+
+func TestRunTokenCreatePrintsSecretOnce(t *testing.T) {
+	fs, srv := newFakeTokenServer(t)
+	f, stdout, stderr := newAuthTestFactory(tokenTestConfig(srv.URL, "BBDC-oldsecret"))
+
+	err := runTokenCreate(newTestCmd(), f, &tokenCreateOptions{
+		Host:              "dc",
+		Name:              "audit",
+		ProjectPermission: "none",
+		RepoPermission:    "read",
+		ExpiryDays:        30,
+	})
+	if err != nil {
+		t.Fatalf("runTokenCreate: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Token:   BBDC-newsecret300") {
+		t.Fatalf("expected secret in output:\n%s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "will not show it again") {
+		t.Fatalf("expected one-time warning, got %q", stderr.String())
+	}
+	perms, _ := fs.tokens["300"]["permissions"].([]any)
+	if len(perms) != 1 || perms[0] != "REPO_READ" {
+		t.Fatalf("created permissions = %v, want [REPO_READ]", perms)
+	}
+}
+
+func TestRunTokenRevokeWithYes(t *testing.T) {
+	fs, srv := newFakeTokenServer(t)
+	f, stdout, _ := newAuthTestFactory(tokenTestConfig(srv.URL, "BBDC-oldsecret"))
+
+	if err := runTokenRevoke(newTestCmd(), f, &tokenRevokeOptions{Host: "dc", ID: "200", Yes: true}); err != nil {
+		t.Fatalf("runTokenRevoke: %v", err)
+	}
+	if _, ok := fs.tokens["200"]; ok {
+		t.Fatal("token 200 should have been revoked")
+	}
+	if !strings.Contains(stdout.String(), "Revoked token 200") {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestRunLoginRotateReplacesStoredToken(t *testing.T) {
+	setupFileKeyring(t)
+	t.Setenv(secret.EnvToken, "")
+	t.Setenv("BKT_CONFIG_DIR", t.TempDir())
+	fs, srv := newFakeTokenServer(t)
+	cfg := tokenTestConfig(srv.URL, "BBDC-oldsecret")
+	f, stdout, _ := newAuthTestFactory(cfg)
+
+	if err := runLogin(newTestCmd(), f, &loginOptions{Host: "dc", Rotate: true}); err != nil {
+		t.Fatalf("runLogin --rotate: %v", err)
+	}
+
+	if _, ok := fs.tokens["100"]; ok {
+		t.Fatal("old token should have been revoked")
+	}
+	rotated, ok := fs.tokens["300"]
+	if !ok || rotated["name"] != "laptop" {
+		t.Fatalf("replacement token = %v, want name laptop", rotated)
+	}
+	if rotated["expiryDays"] != float64(90) {
+		t.Fatalf("replacement expiryDays = %v, want 90", rotated["expiryDays"])
+	}
+
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	if err != nil {
+		t.Fatalf("secret.Open: %v", err)
+	}
+	got, err := store.Get(secret.TokenKey("dc"))
+	if err != nil {
+		t.Fatalf("store.Get: %v", err)
+	}
+	if got != "BBDC-newsecret300" {
+		t.Fatalf("stored token = %q, want BBDC-newsecret300", got)
+	}
+	if !strings.Contains(stdout.String(), "revoked 100, now using 300") {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+	if _, ok := fs.tokens["200"]; !ok {
+		t.Fatal("the more recently used CI token must not be touched")
+	}
+	if cfg.Hosts["dc"].TokenID != "300" {
+		t.Fatalf("stored token ID = %q, want 300", cfg.Hosts["dc"].TokenID)
+	}
+}
+
+func TestRunLoginRotateRequiresKnownTokenID(t *testing.T) {
+	setupFileKeyring(t)
+	t.Setenv(secret.EnvToken, "")
+	fs, srv := newFakeTokenServer(t)
+	cfg := tokenTestConfig(srv.URL, "BBDC-oldsecret")
+	cfg.Hosts["dc"].TokenID = ""
+	f, _, _ := newAuthTestFactory(cfg)
+
+	err := runLogin(newTestCmd(), f, &loginOptions{Host: "dc", Rotate: true})
+	if err == nil || !strings.Contains(err.Error(), "pass --token-id") {
+		t.Fatalf("expected unknown token ID error, got %v", err)
+	}
+
+	err = runLogin(newTestCmd(), f, &loginOptions{Host: "dc", Rotate: true, TokenID: "999"})
+	if err == nil || !strings.Contains(err.Error(), "personal access token 999 not found") {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if len(fs.tokens) != 2 {
+		t.Fatalf("no token may be created or revoked, got %v", fs.tokens)
+	}
+}
+
+func TestRunLoginRotateRefusesPasswordLogin(t *testing.T) {
+	setupFileKeyring(t)
+	t.Setenv(secret.EnvToken, "")
+	fs, srv := newFakeTokenServer(t)
+	fs.secrets["hunter2"] = ""
+	f, _, _ := newAuthTestFactory(tokenTestConfig(srv.URL, "hunter2"))
+
+	err := runLogin(newTestCmd(), f, &loginOptions{Host: "dc", Rotate: true, TokenID: "100"})
+	if err == nil || !strings.Contains(err.Error(), "not a personal access token") {
+		t.Fatalf("expected password login to be refused, got %v", err)
+	}
+	if len(fs.tokens) != 2 {
+		t.Fatalf("no token may be created or revoked, got %v", fs.tokens)
+	}
+}
+
+func TestRunLoginRotateRollsBackWhenRevokeFails(t *testing.T) {
+	setupFileKeyring(t)
+	t.Setenv(secret.EnvToken, "")
+	fs, srv := newFakeTokenServer(t)
+	fs.failRevoke = "100"
+	f, _, _ := newAuthTestFactory(tokenTestConfig(srv.URL, "BBDC-oldsecret"))
+
+	err := runLogin(newTestCmd(), f, &loginOptions{Host: "dc", Rotate: true})
+	if err == nil || !strings.Contains(err.Error(), "revoke previous token 100") {
+		t.Fatalf("expected revoke failure, got %v", err)
+	}
+
+	if _, ok := fs.tokens["100"]; !ok {
+		t.Fatal("old token must survive a failed rotation")
+	}
+	if _, ok := fs.tokens["300"]; ok {
+		t.Fatal("replacement token should have been revoked during rollback")
+	}
+
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	if err != nil {
+		t.Fatalf("secret.Open: %v", err)
+	}
+	got, err := store.Get(secret.TokenKey("dc"))
+	if err != nil {
+		t.Fatalf("store.Get: %v", err)
+	}
+	if got != "BBDC-oldsecret" {
+		t.Fatalf("stored token = %q, want BBDC-oldsecret restored", got)
+	}
+}
+
+func TestRunLoginRotateRejectsCloud(t *testing.T) {
+	t.Setenv(secret.EnvToken, "")
+	cfg := &config.Config{Hosts: map[string]*config.Host{
+		"api.bitbucket.org": {Kind: "cloud", BaseURL: "https://api.bitbucket.org/2.0", Token: "x"},
+	}}
+	f, _, _ := newAuthTestFactory(cfg)
+
+	err := runLogin(newTestCmd(), f, &loginOptions{Host: "api.bitbucket.org", Rotate: true})
+	if err == nil || !strings.Contains(err.Error(), "only supported for Bitbucket Data Center") {
+		t.Fatalf("expected DC-only error, got %v", err)
+	}
+}
+
+func TestRunLoginRecordsTokenID(t *testing.T) {
+	setupFileKeyring(t)
+	t.Setenv(secret.EnvToken, "")
+	t.Setenv("BKT_CONFIG_DIR", t.TempDir())
+	fs, srv := newFakeTokenServer(t)
+	fs.secrets["hunter2"] = ""
+
+	cfg := &config.Config{Hosts: map[string]*config.Host{}, Contexts: map[string]*config.Context{}}
+	f, _, _ := newAuthTestFactory(cfg)
+	login := func(token, id string) error {
+		return runLogin(newTestCmd(), f, &loginOptions{
+			Host: srv.URL, Kind: "dc", Username: "alice", Token: token, TokenID: id,
+			AllowHTTP: true, AllowInsecureStore: true,
+		})
+	}
+
+	if err := login("hunter2", "100"); err == nil || !strings.Contains(err.Error(), "not a personal access token") {
+		t.Fatalf("expected password with --token-id to be rejected, got %v", err)
+	}
+	if err := login("BBDC-oldsecret", "999"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected unknown token ID to be rejected, got %v", err)
+	}
+	if err := login("BBDC-oldsecret", "100"); err != nil {
+		t.Fatalf("runLogin: %v", err)
+	}
+	for _, h := range cfg.Hosts {
+		if h.TokenID != "100" {
+			t.Fatalf("stored token ID = %q, want 100", h.TokenID)
+		}
+	}
+}
@@ -22,8 +22,8 @@ func NewCmdBranch(f *cmdutil.Factory) *cobra.Command {
 Supports listing, creating, deleting, rebasing, and setting the default branch.
 Branch protection rules are available through the "protect" subcommand.
 
-Listing works on both Bitbucket Data Center and Cloud. Create, delete,
-set-default, and protect subcommands currently support Data Center only.`,
+Listing and protection rules work on both Bitbucket Data Center and Cloud.
+Create, delete, and set-default subcommands currently support Data Center only.`,
 		Example: `  # List branches in the current context
   bkt branch list
 
@@ -127,7 +127,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			"branches": branches,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(branches) == 0 {
 				_, err := fmt.Fprintln(ios.Out, "No branches found.")
 				return err
@@ -171,7 +171,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			"branches":  branches,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(branches) == 0 {
 				_, err := fmt.Fprintln(ios.Out, "No branches found.")
 				return err
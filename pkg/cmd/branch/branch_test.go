@@ -4,10 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/avivsinai/bitbucket-cli/internal/config"
@@ -48,6 +52,7 @@ func runBranchCmd(t *testing.T, f *cmdutil.Factory, args ...string) error {
 	cmd := NewCmdBranch(f)
 	cmd.PersistentFlags().String("context", "", "Named context to use")
 	cmd.PersistentFlags().String("output", "text", "Output format")
+	cmd.PersistentFlags().Bool("json", false, "Output JSON")
 	cmd.SilenceUsage = true
 	cmd.SilenceErrors = true
 
@@ -413,7 +418,7 @@ func TestBranchProtectList(t *testing.T) {
 			"values": []map[string]any{
 				{
 					"id":   42,
-					"type": "NO_DELETES",
+					"type": "no-deletes",
 					"matcher": map[string]any{
 						"id":        "refs/heads/main",
 						"displayId": "main",
@@ -421,6 +426,7 @@ func TestBranchProtectList(t *testing.T) {
 					},
 				},
 			},
+			"isLastPage": true,
 		})
 	}))
 	t.Cleanup(srv.Close)
@@ -430,7 +436,7 @@ func TestBranchProtectList(t *testing.T) {
 		t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr.String())
 	}
 	out := stdout.String()
-	if !strings.Contains(out, "42") || !strings.Contains(out, "NO_DELETES") || !strings.Contains(out, "main") {
+	if !strings.Contains(out, "42") || !strings.Contains(out, "no-deletes") || !strings.Contains(out, "main") {
 		t.Errorf("expected restriction row in output, got: %s", out)
 	}
 }
@@ -462,9 +468,8 @@ func TestBranchProtectAdd(t *testing.T) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		typeObj, _ := body["type"].(map[string]any)
-		if typeObj["id"] != "FAST_FORWARD_ONLY" {
-			t.Errorf("expected FAST_FORWARD_ONLY type, got %v", typeObj["id"])
+		if body["type"] != "fast-forward-only" {
+			t.Errorf("expected fast-forward-only type, got %v", body["type"])
 		}
 		matcher, _ := body["matcher"].(map[string]any)
 		if matcher["id"] != "refs/heads/main" {
@@ -473,7 +478,7 @@ func TestBranchProtectAdd(t *testing.T) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(map[string]any{
 			"id":   99,
-			"type": "FAST_FORWARD_ONLY",
+			"type": "fast-forward-only",
 			"matcher": map[string]any{
 				"id":        "refs/heads/main",
 				"displayId": "main",
@@ -533,21 +538,297 @@ func TestBranchProtectRemoveRejectsBadID(t *testing.T) {
 	}
 }
 
-func TestBranchProtectRejectsCloud(t *testing.T) {
-	for _, sub := range [][]string{
-		{"protect", "list"},
-		{"protect", "add", "main", "--type", "no-creates"},
-		{"protect", "remove", "1"},
+func TestBranchProtectListCloud(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repositories/myworkspace/my-repo/branch-restrictions" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"values": []map[string]any{
+				{"id": 7, "kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "main", "value": 2},
+				{"id": 8, "kind": "delete", "branch_match_kind": "branching_model", "branch_type": "release"},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	f, stdout, stderr := newTestFactory(cloudConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "list"); err != nil {
+		t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"7\trequire_approvals_to_merge (2)\tmain", "8\tdelete\ttype:release"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestBranchProtectAddCloud(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/repositories/myworkspace/my-repo/branch-restrictions" {
+			http.NotFound(w, r)
+			return
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if body["kind"] != "require_approvals_to_merge" || body["pattern"] != "release/*" || body["branch_match_kind"] != "glob" {
+			t.Errorf("unexpected body: %+v", body)
+		}
+		if body["value"] != float64(2) {
+			t.Errorf("value = %v, want 2", body["value"])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id": 11, "kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "release/*", "value": 2,
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	f, stdout, stderr := newTestFactory(cloudConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "add", "release/*", "--type", "require-approvals", "--count", "2"); err != nil {
+		t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Added restriction 11 (require_approvals_to_merge (2)) on release/*") {
+		t.Errorf("expected success message, got: %s", stdout.String())
+	}
+}
+
+func TestBranchProtectRemoveCloud(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/repositories/myworkspace/my-repo/branch-restrictions/7" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	f, stdout, _ := newTestFactory(cloudConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "remove", "7"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Removed restriction 7") {
+		t.Errorf("expected success message, got: %s", stdout.String())
+	}
+}
+
+func TestBranchProtectAddPlatformSpecificTypes(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  *config.Config
+		args []string
+		want string
+	}{
+		{"missing type", cloudConfig("http://localhost"), nil, `required flag(s) "type" not set`},
+		{"no-creates on cloud", cloudConfig("http://localhost"), []string{"--type", "no-creates"}, "Data Center contexts only"},
+		{"passing builds on data center", dcConfig("http://localhost"), []string{"--type", "require-passing-builds"}, "Cloud contexts only"},
+		{"count on data center", dcConfig("http://localhost"), []string{"--type", "require-approvals", "--count", "2"}, "required count is supported for Cloud contexts only"},
+		{"users on cloud delete", cloudConfig("http://localhost"), []string{"--type", "no-deletes", "--user", "alice"}, "users and groups are not supported"},
+		{"count on cloud force", cloudConfig("http://localhost"), []string{"--type", "fast-forward-only", "--count", "2"}, "required count is not supported"},
+		{"username on cloud", cloudConfig("http://localhost"), []string{"--type", "read-only", "--user", "release-bot"}, `user "release-bot" is not an account ID or {UUID}`},
+		{"bare uuid on cloud", cloudConfig("http://localhost"), []string{"--type", "read-only", "--user", "12345678-1234-1234-1234-123456789abc"}, "not an account ID or {UUID}"},
 	} {
-		t.Run(strings.Join(sub, " "), func(t *testing.T) {
-			f, _, _ := newTestFactory(cloudConfig("http://localhost"))
-			err := runBranchCmd(t, f, sub...)
-			if err == nil {
-				t.Fatal("expected error on Cloud")
+		t.Run(tc.name, func(t *testing.T) {
+			f, _, _ := newTestFactory(tc.cfg)
+			err := runBranchCmd(t, f, append([]string{"protect", "add", "main"}, tc.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
 			}
-			if !strings.Contains(err.Error(), "Data Center") {
-				t.Errorf("unexpected error: %v", err)
+		})
+	}
+}
+
+func writePolicy(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write policy: %v", err)
+	}
+	return path
+}
+
+func TestBranchProtectApplyDC(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		restrictions = map[string][]map[string]any{
+			"api": {
+				// The branch-permissions API reports lower-case type ids.
+				{"id": 1, "type": "no-deletes", "matcher": map[string]any{"id": "refs/heads/main", "type": map[string]any{"id": "BRANCH"}}},
+				{"id": 2, "type": "fast-forward-only", "matcher": map[string]any{"id": "refs/heads/main", "type": map[string]any{"id": "BRANCH"}}},
+				{"id": 3, "type": "no-creates", "matcher": map[string]any{"id": "refs/heads/develop", "type": map[string]any{"id": "BRANCH"}}},
+			},
+			"web": {},
+		}
+		nextID = 100
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/rest/api/1.0/projects/PROJ/repos" {
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values":     []map[string]any{{"slug": "api"}, {"slug": "web"}, {"slug": "docs"}},
+				"isLastPage": true,
+			})
+			return
+		}
+
+		rest, ok := strings.CutPrefix(r.URL.Path, "/rest/branch-permissions/2.0/projects/PROJ/repos/")
+		if !ok {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		repo, idPart, _ := strings.Cut(strings.TrimSuffix(rest, "/restrictions"), "/restrictions/")
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"values": restrictions[repo], "isLastPage": true})
+		case http.MethodPost:
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			nextID++
+			created := map[string]any{"id": nextID, "type": body["type"], "matcher": body["matcher"]}
+			restrictions[repo] = append(restrictions[repo], created)
+			_ = json.NewEncoder(w).Encode(created)
+		case http.MethodDelete:
+			kept := restrictions[repo][:0]
+			for _, res := range restrictions[repo] {
+				if fmt.Sprint(res["id"]) != idPart {
+					kept = append(kept, res)
+				}
 			}
+			restrictions[repo] = kept
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	policy := writePolicy(t, `
+restrictions:
+  - branches: [main, release/*]
+    type: no-deletes
+`)
+
+	f, stdout, stderr := newTestFactory(dcConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "apply", "-f", policy, "--repos", "PROJ/[aw]*", "--dry-run"); err != nil {
+		t.Fatalf("dry run: %v (stderr=%s)", err, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"PROJ/api\t+ no-deletes on PATTERN:release/*",
+		"PROJ/api\t- #2 fast-forward-only on BRANCH:refs/heads/main",
+		"PROJ/api: 1 to create, 1 to delete",
+		"PROJ/web: 2 to create, 0 to delete",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("dry-run output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "docs") || strings.Contains(out, "#3") {
+		t.Fatalf("dry run touched unselected repo or unmanaged branch:\n%s", out)
+	}
+	if len(restrictions["api"]) != 3 || len(restrictions["web"]) != 0 {
+		t.Fatalf("dry run changed restrictions: %+v", restrictions)
+	}
+
+	f, stdout, stderr = newTestFactory(dcConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "apply", "-f", policy, "--repos", "PROJ/[aw]*"); err != nil {
+		t.Fatalf("apply: %v (stderr=%s)", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "PROJ/api: 1 created, 1 deleted") {
+		t.Fatalf("unexpected apply output:\n%s", stdout.String())
+	}
+
+	f, stdout, stderr = newTestFactory(dcConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "apply", "-f", policy, "--repos", "PROJ/[aw]*"); err != nil {
+		t.Fatalf("rerun: %v (stderr=%s)", err, stderr.String())
+	}
+	if got := stdout.String(); got != "PROJ/api: up to date\nPROJ/web: up to date\n" {
+		t.Fatalf("rerun should be a no-op, got:\n%s", got)
+	}
+}
+
+func TestBranchProtectApplyCloudDryRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/repositories/myworkspace/my-repo/branch-restrictions" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"values": []map[string]any{
+				{"id": 5, "kind": "require_approvals_to_merge", "branch_match_kind": "glob", "pattern": "main", "value": 1},
+			},
 		})
+	}))
+	t.Cleanup(srv.Close)
+
+	policy := writePolicy(t, `
+restrictions:
+  - branches: [main]
+    type: require-approvals
+    count: 2
+`)
+
+	f, stdout, stderr := newTestFactory(cloudConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "apply", "-f", policy, "--dry-run", "--json"); err != nil {
+		t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr.String())
+	}
+
+	var payload struct {
+		DryRun bool `json:"dry_run"`
+		Repos  []struct {
+			Repo   string `json:"repo"`
+			Create []struct {
+				Type  string `json:"type"`
+				Value int    `json:"value"`
+			} `json:"create"`
+			Delete []struct {
+				ID int `json:"id"`
+			} `json:"delete"`
+		} `json:"repos"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("decode json: %v\n%s", err, stdout.String())
+	}
+	if !payload.DryRun || len(payload.Repos) != 1 || payload.Repos[0].Repo != "myworkspace/my-repo" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+	plan := payload.Repos[0]
+	if len(plan.Create) != 1 || plan.Create[0].Value != 2 || len(plan.Delete) != 1 || plan.Delete[0].ID != 5 {
+		t.Fatalf("unexpected plan: %+v", plan)
+	}
+}
+
+func TestBranchProtectApplyRejectsPlatformMismatch(t *testing.T) {
+	policy := writePolicy(t, `
+restrictions:
+  - branches: [main]
+    type: require-passing-builds
+`)
+	f, _, _ := newTestFactory(dcConfig("http://localhost"))
+	err := runBranchCmd(t, f, "protect", "apply", "-f", policy)
+	if err == nil || !strings.Contains(err.Error(), "policy rule 1") || !strings.Contains(err.Error(), "Cloud contexts only") {
+		t.Fatalf("expected policy rule error, got %v", err)
+	}
+}
+
+func TestBranchProtectApplyCloudRejectsUsernames(t *testing.T) {
+	policy := writePolicy(t, `
+restrictions:
+  - branches: [release/*]
+    type: read-only
+    users: ["557058:12345678-1234-1234-1234-123456789abc", release-bot]
+`)
+	// The server must never be reached: usernames are rejected up front.
+	f, _, _ := newTestFactory(cloudConfig("http://localhost"))
+	err := runBranchCmd(t, f, "protect", "apply", "-f", policy)
+	if err == nil || !strings.Contains(err.Error(), "policy rule 1") || !strings.Contains(err.Error(), `user "release-bot"`) {
+		t.Fatalf("expected username error, got %v", err)
 	}
 }
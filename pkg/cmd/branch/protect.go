@@ -9,45 +9,74 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
 	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 )
 
 type protectOptions struct {
-	Project string
-	Repo    string
-	Branch  string
-	Type    string
-	Users   []string
-	Groups  []string
-	ID      int
+	Project   string
+	Workspace string
+	Repo      string
+	Branch    string
+	Type      string
+	Users     []string
+	Groups    []string
+	Count     int
+	ID        int
+}
+
+// protectKind maps a --type value onto each platform's restriction model. An
+// empty dc or cloud field means that platform has no equivalent.
+type protectKind struct {
+	dc    string
+	cloud string
+	// cloudExempt reports whether the Cloud kind accepts --user/--group.
+	cloudExempt bool
+	// cloudCount reports whether the Cloud kind accepts --count.
+	cloudCount bool
+}
+
+var protectKinds = map[string]protectKind{
+	"no-creates":              {dc: "no-creates"},
+	"no-deletes":              {dc: "no-deletes", cloud: "delete"},
+	"fast-forward-only":       {dc: "fast-forward-only", cloud: "force"},
+	"read-only":               {dc: "read-only", cloud: "push", cloudExempt: true},
+	"require-approvals":       {dc: "pull-request-only", cloud: "require_approvals_to_merge", cloudCount: true},
+	"require-passing-builds":  {cloud: "require_passing_builds_to_merge", cloudCount: true},
+	"require-tasks-completed": {cloud: "require_tasks_to_be_completed"},
 }
 
 func newProtectCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "protect",
-		Short: "Manage branch protection rules (DC only)",
-		Long: `Manage branch protection rules (restrictions) for a Bitbucket Data Center
-repository. Supports listing existing restrictions, adding new ones, and
-removing them by ID.
-
-Restriction types include no-creates, no-deletes, fast-forward-only, and
-require-approvals. Restrictions can target specific users or groups.
-
-This command group currently supports Data Center contexts only.`,
+		Short: "Manage branch protection rules",
+		Long: `Manage branch protection rules (restrictions) for a Bitbucket repository.
+Supports listing existing restrictions, adding new ones, and removing them by
+ID.
+
+Restriction types include no-deletes, fast-forward-only, read-only, and
+require-approvals on both Data Center and Cloud, plus platform-specific types
+such as no-creates (Data Center) and require-passing-builds (Cloud).
+Restrictions can exempt specific users or groups. Use "protect apply" to sync
+restrictions across many repositories from a policy file.`,
 		Example: `  # List all branch restrictions
   bkt branch protect list
 
-  # Prevent direct pushes to main
+  # Prevent force pushes to main
   bkt branch protect add main --type fast-forward-only
 
   # Remove a restriction by ID
-  bkt branch protect remove 42`,
+  bkt branch protect remove 42
+
+  # Preview syncing a policy across a project
+  bkt branch protect apply -f policy.yaml --repos 'PROJ/*' --dry-run`,
 	}
 
 	cmd.AddCommand(newProtectListCmd(f))
 	cmd.AddCommand(newProtectAddCmd(f))
 	cmd.AddCommand(newProtectRemoveCmd(f))
+	cmd.AddCommand(newProtectApplyCmd(f))
 
 	return cmd
 }
@@ -56,25 +85,27 @@ func newProtectListCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &protectOptions{}
 	cmd := &cobra.Command{
 		Use:   "list",
-		Short: "List branch restrictions (DC only)",
-		Long: `List all branch restrictions configured for a Bitbucket Data Center repository.
-
-Each restriction is shown with its ID, type, and the branch matcher it applies
-to. Use the restriction ID with "protect remove" to delete a rule.
+		Short: "List branch restrictions",
+		Long: `List all branch restrictions configured for a Bitbucket repository.
 
-This command currently supports Data Center contexts only.`,
+Each restriction is shown with its ID, type, and the branch it applies to. On
+Data Center the type is the restriction type (e.g. no-deletes); on Cloud it is
+the restriction kind (e.g. require_approvals_to_merge) followed by its required
+count, if any. Use the restriction ID with "protect remove" to delete a rule.`,
 		Example: `  # List all restrictions in the current context
   bkt branch protect list
 
   # List restrictions for a specific project and repo
-  bkt branch protect list --project MYPROJ --repo backend`,
+  bkt branch protect list --project MYPROJ --repo backend
+
+  # List restrictions for a Cloud repository
+  bkt branch protect list --workspace myteam --repo backend`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runProtectList(cmd, f, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+	addProtectRepoFlags(cmd, opts)
 
 	return cmd
 }
@@ -83,25 +114,33 @@ func newProtectAddCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &protectOptions{}
 	cmd := &cobra.Command{
 		Use:   "add <branch>",
-		Short: "Add a branch restriction (DC only)",
-		Long: `Add a branch restriction to a Bitbucket Data Center repository.
-
-Available restriction types:
-  no-creates          Prevent creating branches matching the pattern
-  no-deletes          Prevent deleting branches matching the pattern
-  fast-forward-only   Only allow fast-forward merges (no force pushes)
-  require-approvals   Require pull request approval before merging
-
-Restrictions can be scoped to specific users or groups with the --user and
---group flags, which are repeatable.
-
-This command currently supports Data Center contexts only.`,
+		Short: "Add a branch restriction",
+		Long: `Add a branch restriction to a Bitbucket repository. The branch may be a
+name or, on Cloud, a glob pattern such as release/*.
+
+--type is required; the available restriction types are:
+  no-creates               Prevent creating matching branches (Data Center)
+  no-deletes               Prevent deleting matching branches
+  fast-forward-only        Prevent history rewrites (Cloud: force pushes)
+  read-only                Prevent pushes, except by --user/--group
+  require-approvals        Data Center: prevent changes without a pull request;
+                           Cloud: require --count approvals to merge
+  require-passing-builds   Require --count passing builds to merge (Cloud)
+  require-tasks-completed  Require all pull request tasks resolved (Cloud)
+
+On Data Center, --user and --group exempt users and groups from any
+restriction. On Cloud they apply to read-only only and take account IDs or
+{UUID}s, and group slugs; usernames are rejected.`,
 		Example: `  # Prevent force pushes to main
   bkt branch protect add main --type fast-forward-only
 
   # Require PR approvals on release branches
   bkt branch protect add release/v2 --type require-approvals
 
+  # Require two approvals and a passing build on Cloud release branches
+  bkt branch protect add 'release/*' --type require-approvals --count 2
+  bkt branch protect add 'release/*' --type require-passing-builds
+
   # Block branch deletion for specific users
   bkt branch protect add main --type no-deletes --user alice --user bob
 
@@ -114,11 +153,12 @@ This command currently supports Data Center contexts only.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
-	cmd.Flags().StringVar(&opts.Type, "type", "no-creates", "Restriction type (no-creates, no-deletes, fast-forward-only, require-approvals)")
-	cmd.Flags().StringSliceVar(&opts.Users, "user", nil, "Usernames to apply the restriction to (repeatable)")
-	cmd.Flags().StringSliceVar(&opts.Groups, "group", nil, "Group names to apply the restriction to (repeatable)")
+	addProtectRepoFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.Type, "type", "", "Restriction type (no-creates, no-deletes, fast-forward-only, read-only, require-approvals, require-passing-builds, require-tasks-completed; required)")
+	cmd.Flags().StringSliceVar(&opts.Users, "user", nil, "Users exempt from the restriction (repeatable)")
+	cmd.Flags().StringSliceVar(&opts.Groups, "group", nil, "Groups exempt from the restriction (repeatable)")
+	cmd.Flags().IntVar(&opts.Count, "count", 0, "Required approvals or passing builds (Cloud; default 1)")
+	_ = cmd.MarkFlagRequired("type")
 
 	return cmd
 }
@@ -127,11 +167,9 @@ func newProtectRemoveCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &protectOptions{}
 	cmd := &cobra.Command{
 		Use:   "remove <restriction-id>",
-		Short: "Remove a branch restriction (DC only)",
-		Long: `Remove a branch restriction from a Bitbucket Data Center repository by its
-numeric ID. Use "bkt branch protect list" to find restriction IDs.
-
-This command currently supports Data Center contexts only.`,
+		Short: "Remove a branch restriction",
+		Long: `Remove a branch restriction from a Bitbucket repository by its numeric ID.
+Use "bkt branch protect list" to find restriction IDs.`,
 		Example: `  # Remove a restriction by ID
   bkt branch protect remove 42
 
@@ -148,12 +186,17 @@ This command currently supports Data Center contexts only.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+	addProtectRepoFlags(cmd, opts)
 
 	return cmd
 }
 
+func addProtectRepoFlags(cmd *cobra.Command, opts *protectOptions) {
+	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override (Data Center)")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+}
+
 func runProtectList(cmd *cobra.Command, f *cmdutil.Factory, opts *protectOptions) error {
 	ios, err := f.Streams()
 	if err != nil {
@@ -164,47 +207,87 @@ func runProtectList(cmd *cobra.Command, f *cmdutil.Factory, opts *protectOptions
 	if err != nil {
 		return err
 	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("branch protect list currently supports Data Center contexts only")
-	}
 
-	projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-	repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-	if projectKey == "" || repoSlug == "" {
-		return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
-	}
+	switch host.Kind {
+	case "dc":
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if projectKey == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+		}
 
-	client, err := cmdutil.NewDCClient(host)
-	if err != nil {
-		return err
-	}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
 
-	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
-	defer cancel()
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
 
-	restrictions, err := client.ListBranchRestrictions(ctx, projectKey, repoSlug)
-	if err != nil {
-		return err
-	}
+		restrictions, err := client.ListBranchRestrictions(ctx, projectKey, repoSlug)
+		if err != nil {
+			return err
+		}
 
-	payload := map[string]any{
-		"project":      projectKey,
-		"repo":         repoSlug,
-		"restrictions": restrictions,
-	}
+		payload := map[string]any{
+			"project":      projectKey,
+			"repo":         repoSlug,
+			"restrictions": restrictions,
+		}
+
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+			if len(restrictions) == 0 {
+				_, err := fmt.Fprintln(ios.Out, "No branch restrictions configured.")
+				return err
+			}
+			for _, res := range restrictions {
+				if _, err := fmt.Fprintf(ios.Out, "%d\t%s\t%s\n", res.ID, res.Type, res.Matcher.DisplayID); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if workspace == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
-		if len(restrictions) == 0 {
-			_, err := fmt.Fprintln(ios.Out, "No branch restrictions configured.")
+		restrictions, err := client.ListBranchRestrictions(ctx, workspace, repoSlug)
+		if err != nil {
 			return err
 		}
-		for _, res := range restrictions {
-			if _, err := fmt.Fprintf(ios.Out, "%d\t%s\t%s\n", res.ID, res.Type, res.Matcher.DisplayID); err != nil {
+
+		payload := map[string]any{
+			"workspace":    workspace,
+			"repo":         repoSlug,
+			"restrictions": restrictions,
+		}
+
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+			if len(restrictions) == 0 {
+				_, err := fmt.Fprintln(ios.Out, "No branch restrictions configured.")
 				return err
 			}
-		}
-		return nil
-	})
+			for _, res := range restrictions {
+				if _, err := fmt.Fprintf(ios.Out, "%d\t%s\t%s\n", res.ID, cloudRestrictionKind(res), cloudRestrictionTarget(res)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
 }
 
 func runProtectAdd(cmd *cobra.Command, f *cmdutil.Factory, opts *protectOptions) error {
@@ -213,48 +296,92 @@ func runProtectAdd(cmd *cobra.Command, f *cmdutil.Factory, opts *protectOptions)
 		return err
 	}
 
+	if _, ok := protectKinds[strings.ToLower(opts.Type)]; !ok {
+		return fmt.Errorf("unsupported restriction type %q", opts.Type)
+	}
+
 	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, cmdutil.FlagValue(cmd, "context"))
 	if err != nil {
 		return err
 	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("branch protect add currently supports Data Center contexts only")
-	}
 
-	projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-	repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-	if projectKey == "" || repoSlug == "" {
-		return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
-	}
+	switch host.Kind {
+	case "dc":
+		typeID, err := dcRestrictionType(opts.Type, cmd.Flags().Changed("count"))
+		if err != nil {
+			return err
+		}
 
-	typeID := mapProtectType(opts.Type)
-	if typeID == "" {
-		return fmt.Errorf("unsupported restriction type %q", opts.Type)
-	}
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if projectKey == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+		}
 
-	client, err := cmdutil.NewDCClient(host)
-	if err != nil {
-		return err
-	}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
 
-	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
-	defer cancel()
+		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+		defer cancel()
+
+		restriction, err := client.CreateBranchRestriction(ctx, projectKey, repoSlug, bbdc.BranchRestrictionInput{
+			Type:        typeID,
+			MatcherID:   ensureBranchRef(opts.Branch),
+			MatcherType: "BRANCH",
+			Users:       opts.Users,
+			Groups:      opts.Groups,
+		})
+		if err != nil {
+			return err
+		}
 
-	restriction, err := client.CreateBranchRestriction(ctx, projectKey, repoSlug, bbdc.BranchRestrictionInput{
-		Type:        typeID,
-		MatcherID:   ensureBranchRef(opts.Branch),
-		MatcherType: "BRANCH",
-		Users:       opts.Users,
-		Groups:      opts.Groups,
-	})
-	if err != nil {
-		return err
-	}
+		if _, err := fmt.Fprintf(ios.Out, "✓ Added restriction %d (%s) on %s\n", restriction.ID, restriction.Type, restriction.Matcher.DisplayID); err != nil {
+			return err
+		}
+		return nil
+	case "cloud":
+		kind, count, err := cloudRestrictionType(opts.Type, len(opts.Users) > 0 || len(opts.Groups) > 0, opts.Count, cmd.Flags().Changed("count"))
+		if err != nil {
+			return err
+		}
+		if err := checkCloudRestrictionUsers(opts.Users); err != nil {
+			return err
+		}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Added restriction %d (%s) on %s\n", restriction.ID, restriction.Type, restriction.Matcher.DisplayID); err != nil {
-		return err
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if workspace == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+		defer cancel()
+
+		restriction, err := client.CreateBranchRestriction(ctx, workspace, repoSlug, bbcloud.BranchRestrictionInput{
+			Kind:    kind,
+			Pattern: strings.TrimPrefix(opts.Branch, "refs/heads/"),
+			Value:   count,
+			Users:   opts.Users,
+			Groups:  opts.Groups,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(ios.Out, "✓ Added restriction %d (%s) on %s\n", restriction.ID, cloudRestrictionKind(*restriction), cloudRestrictionTarget(*restriction)); err != nil {
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
-	return nil
 }
 
 func runProtectRemove(cmd *cobra.Command, f *cmdutil.Factory, opts *protectOptions) error {
@@ -267,47 +394,108 @@ func runProtectRemove(cmd *cobra.Command, f *cmdutil.Factory, opts *protectOptio
 	if err != nil {
 		return err
 	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("branch protect remove currently supports Data Center contexts only")
-	}
 
-	projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-	repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-	if projectKey == "" || repoSlug == "" {
-		return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
+
+	switch host.Kind {
+	case "dc":
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if projectKey == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+		}
+
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		if err := client.DeleteBranchRestriction(ctx, projectKey, repoSlug, opts.ID); err != nil {
+			return err
+		}
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if workspace == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		if err := client.DeleteBranchRestriction(ctx, workspace, repoSlug, opts.ID); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
 
-	client, err := cmdutil.NewDCClient(host)
-	if err != nil {
+	if _, err := fmt.Fprintf(ios.Out, "✓ Removed restriction %d\n", opts.ID); err != nil {
 		return err
 	}
+	return nil
+}
 
-	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
-	defer cancel()
+// dcRestrictionType resolves a restriction type to its Data Center type ID.
+func dcRestrictionType(typeName string, hasCount bool) (string, error) {
+	kind, ok := protectKinds[strings.ToLower(typeName)]
+	if !ok {
+		return "", fmt.Errorf("unsupported restriction type %q", typeName)
+	}
+	if kind.dc == "" {
+		return "", fmt.Errorf("restriction type %q is supported for Cloud contexts only", typeName)
+	}
+	if hasCount {
+		return "", fmt.Errorf("a required count is supported for Cloud contexts only")
+	}
+	return kind.dc, nil
+}
 
-	if err := client.DeleteBranchRestriction(ctx, projectKey, repoSlug, opts.ID); err != nil {
-		return err
+// cloudRestrictionType resolves a restriction type to its Cloud kind and
+// required count, rejecting exemptions and counts the kind does not take.
+func cloudRestrictionType(typeName string, hasExempt bool, count int, hasCount bool) (string, int, error) {
+	kind, ok := protectKinds[strings.ToLower(typeName)]
+	if !ok {
+		return "", 0, fmt.Errorf("unsupported restriction type %q", typeName)
+	}
+	if kind.cloud == "" {
+		return "", 0, fmt.Errorf("restriction type %q is supported for Data Center contexts only", typeName)
 	}
+	if hasExempt && !kind.cloudExempt {
+		return "", 0, fmt.Errorf("users and groups are not supported for restriction type %q on Cloud", typeName)
+	}
+	if !kind.cloudCount {
+		if hasCount {
+			return "", 0, fmt.Errorf("a required count is not supported for restriction type %q", typeName)
+		}
+		return kind.cloud, 0, nil
+	}
+	if !hasCount {
+		return kind.cloud, 1, nil
+	}
+	if count < 1 {
+		return "", 0, fmt.Errorf("required count must be at least 1")
+	}
+	return kind.cloud, count, nil
+}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Removed restriction %d\n", opts.ID); err != nil {
-		return err
+// checkCloudRestrictionUsers rejects exempt users Cloud would misread: the
+// API takes account IDs or {UUID}s and treats anything else as an account ID.
+func checkCloudRestrictionUsers(users []string) error {
+	for _, u := range users {
+		if bbcloud.LooksLikeAccountID(u) || (strings.HasPrefix(u, "{") && bbcloud.LooksLikeUUID(u)) {
+			continue
+		}
+		return fmt.Errorf("user %q is not an account ID or {UUID}; Cloud branch restrictions do not accept usernames", u)
 	}
 	return nil
 }
 
+// mapProtectType returns the Data Center restriction type for a --type value,
+// or "" when Data Center has no equivalent.
 func mapProtectType(t string) string {
-	switch strings.ToLower(t) {
-	case "no-creates":
-		return "NO_CREATES"
-	case "no-deletes":
-		return "NO_DELETES"
-	case "fast-forward-only":
-		return "FAST_FORWARD_ONLY"
-	case "require-approvals":
-		return "PULL_REQUEST"
-	default:
-		return ""
-	}
+	return protectKinds[strings.ToLower(t)].dc
 }
 
 func ensureBranchRef(branch string) string {
@@ -319,3 +507,20 @@ func ensureBranchRef(branch string) string {
 	}
 	return "refs/heads/" + branch
 }
+
+// cloudRestrictionKind renders a Cloud restriction kind with its required
+// count, e.g. "require_approvals_to_merge (2)".
+func cloudRestrictionKind(res bbcloud.BranchRestriction) string {
+	if res.Value != nil {
+		return fmt.Sprintf("%s (%d)", res.Kind, *res.Value)
+	}
+	return res.Kind
+}
+
+// cloudRestrictionTarget renders the branches a Cloud restriction applies to.
+func cloudRestrictionTarget(res bbcloud.BranchRestriction) string {
+	if res.BranchMatchKind == "branching_model" {
+		return "type:" + res.BranchType
+	}
+	return res.Pattern
+}
@@ -0,0 +1,529 @@
+package branch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// protectPolicy is the policy file read by "protect apply".
+type protectPolicy struct {
+	Restrictions []protectPolicyRule `yaml:"restrictions"`
+}
+
+// protectPolicyRule declares one restriction type on one or more branches.
+type protectPolicyRule struct {
+	Branches []string `yaml:"branches"`
+	Type     string   `yaml:"type"`
+	Count    int      `yaml:"count,omitempty"`
+	Users    []string `yaml:"users,omitempty"`
+	Groups   []string `yaml:"groups,omitempty"`
+}
+
+// protectRule is a restriction in platform-neutral form. Branch is the Data
+// Center matcher ("BRANCH:refs/heads/main", "PATTERN:release/*") or the
+// Cloud glob pattern.
+type protectRule struct {
+	ID     int      `json:"id,omitempty"`
+	Type   string   `json:"type"`
+	Branch string   `json:"branch"`
+	Value  int      `json:"value,omitempty"`
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+
+	// userAliases holds every identifier of the current rule's users, so a
+	// policy may name a user by any of them.
+	userAliases map[string]bool
+}
+
+func (r protectRule) String() string {
+	s := r.Type + " on " + r.Branch
+	if r.Value > 0 {
+		s += fmt.Sprintf(" (%d)", r.Value)
+	}
+	if exempt := append(append([]string{}, r.Users...), r.Groups...); len(exempt) > 0 {
+		s += " except " + strings.Join(exempt, ", ")
+	}
+	return s
+}
+
+// satisfies reports whether the current rule r implements the desired rule.
+func (r protectRule) satisfies(want protectRule) bool {
+	if r.Type != want.Type || r.Branch != want.Branch || r.Value != want.Value {
+		return false
+	}
+	if len(r.Users) != len(want.Users) || !sameSet(r.Groups, want.Groups) {
+		return false
+	}
+	for _, u := range want.Users {
+		if !r.userAliases[u] {
+			return false
+		}
+	}
+	return true
+}
+
+// protectPlan is the change set for one repository.
+type protectPlan struct {
+	Repo   string        `json:"repo"`
+	Create []protectRule `json:"create"`
+	Delete []protectRule `json:"delete"`
+	Error  string        `json:"error,omitempty"`
+}
+
+type protectApplyOptions struct {
+	File   string
+	Repos  []string
+	DryRun bool
+}
+
+func newProtectApplyCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &protectApplyOptions{}
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Sync branch restrictions with a policy file",
+		Long: `Make the branch restrictions of one or more repositories match a policy
+file. For every repository the current restrictions are compared with the
+policy, missing rules are created, and rules that differ are deleted.
+
+Only branches named in the policy are managed; restrictions on other branches
+are left alone. Rerunning apply against an unchanged policy makes no changes.
+
+The policy lists restriction rules using the types of "protect add":
+
+  restrictions:
+    - branches: [main, release/*]
+      type: no-deletes
+    - branches: [main]
+      type: require-approvals
+      count: 2            # Cloud only
+    - branches: [release/*]
+      type: read-only
+      users: [release-bot]
+      groups: [release-managers]
+
+On Data Center users are usernames. On Cloud they must be account IDs or
+{UUID}s (e.g. users: ["557058:1b2c..."]); usernames are rejected before any
+change is made.
+
+--repos selects repositories as PROJECT/GLOB on Data Center or
+WORKSPACE/GLOB on Cloud and is repeatable; without it the context repository
+is used. Use --dry-run to print the plan without changing anything.`,
+		Example: `  # Preview the changes across every repository in a project
+  bkt branch protect apply -f policy.yaml --repos 'PROJ/*' --dry-run
+
+  # Apply the policy to the services of a Cloud workspace
+  bkt branch protect apply -f policy.yaml --repos 'myteam/svc-*'
+
+  # Apply the policy to the current repository
+  bkt branch protect apply -f policy.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProtectApply(cmd, f, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.File, "file", "f", "", "Policy file (YAML; - for stdin)")
+	cmd.Flags().StringSliceVar(&opts.Repos, "repos", nil, "Repositories as PROJECT/GLOB or WORKSPACE/GLOB (repeatable)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the plan without changing restrictions")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func runProtectApply(cmd *cobra.Command, f *cmdutil.Factory, opts *protectApplyOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	policy, err := readProtectPolicy(opts.File, ios.In)
+	if err != nil {
+		return err
+	}
+
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, cmdutil.FlagValue(cmd, "context"))
+	if err != nil {
+		return err
+	}
+
+	var (
+		applier   protectApplier
+		listRepos cmdutil.RepoLister
+	)
+	switch host.Kind {
+	case "dc":
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		applier = &dcProtectApplier{client: client}
+		listRepos = cmdutil.DCRepoLister(client)
+	case "cloud":
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		applier = &cloudProtectApplier{client: client}
+		listRepos = cmdutil.CloudRepoLister(client)
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+
+	desired, err := applier.desired(policy)
+	if err != nil {
+		return err
+	}
+
+	repos, err := resolveApplyRepos(cmd.Context(), listRepos, host, ctxCfg, opts.Repos)
+	if err != nil {
+		return err
+	}
+
+	var plans []protectPlan
+	failed := 0
+	for _, repo := range repos {
+		plan, err := applyProtectPolicy(cmd.Context(), applier, repo, desired, opts.DryRun)
+		if err != nil {
+			plan.Error = err.Error()
+			failed++
+		}
+		plans = append(plans, plan)
+	}
+
+	payload := map[string]any{
+		"dry_run": opts.DryRun,
+		"repos":   plans,
+	}
+	if err := cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		return writeProtectPlans(ios.Out, plans, opts.DryRun)
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("policy apply failed for %d of %d repositories", failed, len(plans))
+	}
+	return nil
+}
+
+func readProtectPolicy(file string, stdin io.Reader) (*protectPolicy, error) {
+	data, err := cmdutil.ReadPolicyFile(file, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy protectPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if len(policy.Restrictions) == 0 {
+		return nil, fmt.Errorf("policy defines no restrictions")
+	}
+	for i, rule := range policy.Restrictions {
+		if len(rule.Branches) == 0 {
+			return nil, fmt.Errorf("policy rule %d: branches is required", i+1)
+		}
+		if rule.Type == "" {
+			return nil, fmt.Errorf("policy rule %d: type is required", i+1)
+		}
+	}
+	return &policy, nil
+}
+
+// resolveApplyRepos expands --repos patterns into OWNER/SLUG names, falling
+// back to the context repository.
+func resolveApplyRepos(ctx context.Context, listRepos cmdutil.RepoLister, host *config.Host, ctxCfg *config.Context, patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		owner := ctxCfg.ProjectKey
+		if host.Kind == "cloud" {
+			owner = ctxCfg.Workspace
+		}
+		if owner == "" || ctxCfg.DefaultRepo == "" {
+			return nil, fmt.Errorf("context must supply a repository; use --repos to select repositories")
+		}
+		return []string{owner + "/" + ctxCfg.DefaultRepo}, nil
+	}
+
+	return cmdutil.ExpandRepoPatterns(ctx, patterns, listRepos)
+}
+
+// applyProtectPolicy plans, and unless dryRun applies, the changes for one
+// repository. New rules are created before stale ones are deleted so the
+// branches are never left unprotected.
+func applyProtectPolicy(ctx context.Context, applier protectApplier, repo string, desired []protectRule, dryRun bool) (protectPlan, error) {
+	plan := protectPlan{Repo: repo, Create: []protectRule{}, Delete: []protectRule{}}
+	owner, slug, _ := strings.Cut(repo, "/")
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	current, err := applier.list(ctx, owner, slug)
+	if err != nil {
+		return plan, err
+	}
+	plan.Create, plan.Delete = diffProtectRules(current, desired)
+	if dryRun {
+		return plan, nil
+	}
+
+	for _, rule := range plan.Create {
+		if err := applier.create(ctx, owner, slug, rule); err != nil {
+			return plan, fmt.Errorf("create %s: %w", rule, err)
+		}
+	}
+	for _, rule := range plan.Delete {
+		if err := applier.remove(ctx, owner, slug, rule.ID); err != nil {
+			return plan, fmt.Errorf("delete restriction %d: %w", rule.ID, err)
+		}
+	}
+	return plan, nil
+}
+
+// diffProtectRules returns the desired rules missing from current and the
+// current rules on policy-managed branches that no desired rule accounts for.
+// A rule the policy repeats is only wanted once.
+func diffProtectRules(current, desired []protectRule) (create, remove []protectRule) {
+	managed := map[string]bool{}
+	for _, want := range desired {
+		managed[want.Branch] = true
+	}
+
+	used := make([]bool, len(current))
+	wanted := map[string]bool{}
+	create = []protectRule{}
+	for _, want := range desired {
+		if wanted[want.String()] {
+			continue
+		}
+		wanted[want.String()] = true
+		found := false
+		for i, have := range current {
+			if !used[i] && have.satisfies(want) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			create = append(create, want)
+		}
+	}
+
+	remove = []protectRule{}
+	for i, have := range current {
+		if !used[i] && managed[have.Branch] {
+			remove = append(remove, have)
+		}
+	}
+	return create, remove
+}
+
+func writeProtectPlans(w io.Writer, plans []protectPlan, dryRun bool) error {
+	for _, plan := range plans {
+		create := make([]string, 0, len(plan.Create))
+		for _, rule := range plan.Create {
+			create = append(create, rule.String())
+		}
+		remove := make([]string, 0, len(plan.Delete))
+		for _, rule := range plan.Delete {
+			remove = append(remove, fmt.Sprintf("#%d %s", rule.ID, rule))
+		}
+		if err := cmdutil.WritePlan(w, plan.Repo, create, remove, plan.Error, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// protectApplier adapts one platform's branch restriction API.
+type protectApplier interface {
+	desired(policy *protectPolicy) ([]protectRule, error)
+	list(ctx context.Context, owner, slug string) ([]protectRule, error)
+	create(ctx context.Context, owner, slug string, rule protectRule) error
+	remove(ctx context.Context, owner, slug string, id int) error
+}
+
+type dcProtectApplier struct {
+	client *bbdc.Client
+}
+
+func (a *dcProtectApplier) desired(policy *protectPolicy) ([]protectRule, error) {
+	var rules []protectRule
+	for i, p := range policy.Restrictions {
+		typeID, err := dcRestrictionType(p.Type, p.Count != 0)
+		if err != nil {
+			return nil, fmt.Errorf("policy rule %d: %w", i+1, err)
+		}
+		for _, branch := range p.Branches {
+			matcherType, matcherID := dcBranchMatcher(branch)
+			rules = append(rules, protectRule{
+				Type:   typeID,
+				Branch: matcherType + ":" + matcherID,
+				Users:  sortedCopy(p.Users),
+				Groups: sortedCopy(p.Groups),
+			})
+		}
+	}
+	return rules, nil
+}
+
+func (a *dcProtectApplier) list(ctx context.Context, projectKey, repoSlug string) ([]protectRule, error) {
+	restrictions, err := a.client.ListBranchRestrictions(ctx, projectKey, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]protectRule, 0, len(restrictions))
+	for _, res := range restrictions {
+		rule := protectRule{
+			ID:          res.ID,
+			Type:        bbdc.NormalizeRestrictionType(res.Type),
+			Branch:      res.Matcher.Type.ID + ":" + res.Matcher.ID,
+			Groups:      sortedCopy(res.Groups),
+			userAliases: map[string]bool{},
+		}
+		for _, u := range res.Users {
+			rule.Users = append(rule.Users, u.Name)
+			rule.userAliases[u.Name] = true
+			rule.userAliases[u.Slug] = true
+		}
+		sort.Strings(rule.Users)
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (a *dcProtectApplier) create(ctx context.Context, projectKey, repoSlug string, rule protectRule) error {
+	matcherType, matcherID, _ := strings.Cut(rule.Branch, ":")
+	_, err := a.client.CreateBranchRestriction(ctx, projectKey, repoSlug, bbdc.BranchRestrictionInput{
+		Type:        rule.Type,
+		MatcherID:   matcherID,
+		MatcherType: matcherType,
+		Users:       rule.Users,
+		Groups:      rule.Groups,
+	})
+	return err
+}
+
+func (a *dcProtectApplier) remove(ctx context.Context, projectKey, repoSlug string, id int) error {
+	return a.client.DeleteBranchRestriction(ctx, projectKey, repoSlug, id)
+}
+
+type cloudProtectApplier struct {
+	client *bbcloud.Client
+}
+
+func (a *cloudProtectApplier) desired(policy *protectPolicy) ([]protectRule, error) {
+	var rules []protectRule
+	for i, p := range policy.Restrictions {
+		kind, value, err := cloudRestrictionType(p.Type, len(p.Users) > 0 || len(p.Groups) > 0, p.Count, p.Count != 0)
+		if err != nil {
+			return nil, fmt.Errorf("policy rule %d: %w", i+1, err)
+		}
+		if err := checkCloudRestrictionUsers(p.Users); err != nil {
+			return nil, fmt.Errorf("policy rule %d: %w", i+1, err)
+		}
+		for _, branch := range p.Branches {
+			rules = append(rules, protectRule{
+				Type:   kind,
+				Branch: strings.TrimPrefix(branch, "refs/heads/"),
+				Value:  value,
+				Users:  sortedCopy(p.Users),
+				Groups: sortedCopy(p.Groups),
+			})
+		}
+	}
+	return rules, nil
+}
+
+func (a *cloudProtectApplier) list(ctx context.Context, workspace, repoSlug string) ([]protectRule, error) {
+	restrictions, err := a.client.ListBranchRestrictions(ctx, workspace, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]protectRule, 0, len(restrictions))
+	for _, res := range restrictions {
+		// Branching-model restrictions have no pattern and are never managed.
+		if res.BranchMatchKind == "branching_model" {
+			continue
+		}
+		rule := protectRule{
+			ID:          res.ID,
+			Type:        res.Kind,
+			Branch:      res.Pattern,
+			userAliases: map[string]bool{},
+		}
+		if res.Value != nil {
+			rule.Value = *res.Value
+		}
+		for _, u := range res.Users {
+			rule.Users = append(rule.Users, cmdutil.FirstNonEmpty(u.AccountID, u.UUID))
+			rule.userAliases[u.UUID] = true
+			rule.userAliases[u.AccountID] = true
+		}
+		for _, g := range res.Groups {
+			rule.Groups = append(rule.Groups, g.Slug)
+		}
+		sort.Strings(rule.Users)
+		sort.Strings(rule.Groups)
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (a *cloudProtectApplier) create(ctx context.Context, workspace, repoSlug string, rule protectRule) error {
+	_, err := a.client.CreateBranchRestriction(ctx, workspace, repoSlug, bbcloud.BranchRestrictionInput{
+		Kind:    rule.Type,
+		Pattern: rule.Branch,
+		Value:   rule.Value,
+		Users:   rule.Users,
+		Groups:  rule.Groups,
+	})
+	return err
+}
+
+func (a *cloudProtectApplier) remove(ctx context.Context, workspace, repoSlug string, id int) error {
+	return a.client.DeleteBranchRestriction(ctx, workspace, repoSlug, id)
+}
+
+// dcBranchMatcher returns the Data Center matcher for a policy branch: glob
+// patterns become PATTERN matchers, anything else a BRANCH ref.
+func dcBranchMatcher(branch string) (matcherType, matcherID string) {
+	if strings.ContainsAny(branch, "*?") {
+		return "PATTERN", strings.TrimPrefix(branch, "refs/heads/")
+	}
+	return "BRANCH", ensureBranchRef(branch)
+}
+
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	out := append([]string(nil), values...)
+	sort.Strings(out)
+	return out
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = sortedCopy(a), sortedCopy(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+package branch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDiffProtectRules(t *testing.T) {
+	mainNoDeletes := protectRule{Type: "no-deletes", Branch: "BRANCH:refs/heads/main"}
+	releaseReadOnly := protectRule{Type: "read-only", Branch: "PATTERN:release/*", Users: []string{"alice"}}
+
+	tests := []struct {
+		name       string
+		current    []protectRule
+		desired    []protectRule
+		wantCreate []string
+		wantRemove []int
+	}{
+		{
+			name: "rerun is a no-op",
+			current: []protectRule{
+				{ID: 1, Type: "no-deletes", Branch: "BRANCH:refs/heads/main"},
+				{ID: 2, Type: "read-only", Branch: "PATTERN:release/*", Users: []string{"alice"}, userAliases: map[string]bool{"alice": true}},
+			},
+			desired: []protectRule{mainNoDeletes, releaseReadOnly},
+		},
+		{
+			name:       "changed rule on a managed pattern is created and the old one deleted",
+			current:    []protectRule{{ID: 1, Type: "fast-forward-only", Branch: "PATTERN:release/*"}},
+			desired:    []protectRule{{Type: "no-deletes", Branch: "PATTERN:release/*"}},
+			wantCreate: []string{"no-deletes on PATTERN:release/*"},
+			wantRemove: []int{1},
+		},
+		{
+			name:       "changed exemptions replace the rule",
+			current:    []protectRule{{ID: 2, Type: "read-only", Branch: "PATTERN:release/*", Users: []string{"bob"}, userAliases: map[string]bool{"bob": true}}},
+			desired:    []protectRule{releaseReadOnly},
+			wantCreate: []string{"read-only on PATTERN:release/* except alice"},
+			wantRemove: []int{2},
+		},
+		{
+			name: "duplicate type and pattern on the server is deleted",
+			current: []protectRule{
+				{ID: 1, Type: "no-deletes", Branch: "BRANCH:refs/heads/main"},
+				{ID: 3, Type: "no-deletes", Branch: "BRANCH:refs/heads/main"},
+			},
+			desired:    []protectRule{mainNoDeletes},
+			wantRemove: []int{3},
+		},
+		{
+			name:       "duplicate type and pattern in the policy is created once",
+			desired:    []protectRule{mainNoDeletes, mainNoDeletes},
+			wantCreate: []string{"no-deletes on BRANCH:refs/heads/main"},
+		},
+		{
+			name:       "unmanaged branches are left alone",
+			current:    []protectRule{{ID: 4, Type: "no-creates", Branch: "BRANCH:refs/heads/develop"}},
+			desired:    []protectRule{mainNoDeletes},
+			wantCreate: []string{"no-deletes on BRANCH:refs/heads/main"},
+		},
+		{
+			name:    "user aliases satisfy the policy",
+			current: []protectRule{{ID: 9, Type: "push", Branch: "main", Users: []string{"557058:abc"}, userAliases: map[string]bool{"{uuid-1}": true, "557058:abc": true}}},
+			desired: []protectRule{{Type: "push", Branch: "main", Users: []string{"{uuid-1}"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			create, remove := diffProtectRules(tt.current, tt.desired)
+			var gotCreate []string
+			for _, r := range create {
+				gotCreate = append(gotCreate, r.String())
+			}
+			var gotRemove []int
+			for _, r := range remove {
+				gotRemove = append(gotRemove, r.ID)
+			}
+			if strings.Join(gotCreate, "|") != strings.Join(tt.wantCreate, "|") {
+				t.Errorf("create = %q, want %q", gotCreate, tt.wantCreate)
+			}
+			if len(gotRemove) != len(tt.wantRemove) {
+				t.Fatalf("remove = %v, want %v", gotRemove, tt.wantRemove)
+			}
+			for i := range gotRemove {
+				if gotRemove[i] != tt.wantRemove[i] {
+					t.Fatalf("remove = %v, want %v", gotRemove, tt.wantRemove)
+				}
+			}
+		})
+	}
+}
+
+// protectApplyServer serves a Data Center project with the repositories api,
+// web, and broken, whose restrictions cannot be read. It counts every request
+// that is not a GET.
+func protectApplyServer(t *testing.T, writes *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet {
+			writes.Add(1)
+		}
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values":     []map[string]any{{"slug": "api"}, {"slug": "broken"}, {"slug": "web"}},
+				"isLastPage": true,
+			})
+		case "/rest/branch-permissions/2.0/projects/PROJ/repos/broken/restrictions":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"You are not permitted to access this resource"}]}`))
+		case "/rest/branch-permissions/2.0/projects/PROJ/repos/api/restrictions",
+			"/rest/branch-permissions/2.0/projects/PROJ/repos/web/restrictions":
+			if r.Method == http.MethodPost {
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": 100, "type": "no-deletes"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"values": []map[string]any{}, "isLastPage": true})
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBranchProtectApplyDryRunMakesNoWrites(t *testing.T) {
+	var writes atomic.Int32
+	srv := protectApplyServer(t, &writes)
+	policy := writePolicy(t, `
+restrictions:
+  - branches: [main]
+    type: no-deletes
+`)
+
+	f, stdout, stderr := newTestFactory(dcConfig(srv.URL))
+	if err := runBranchCmd(t, f, "protect", "apply", "-f", policy, "--repos", "PROJ/[aw]*", "--dry-run"); err != nil {
+		t.Fatalf("dry run: %v (stderr=%s)", err, stderr.String())
+	}
+	if n := writes.Load(); n != 0 {
+		t.Fatalf("dry run made %d write requests", n)
+	}
+	if !strings.Contains(stdout.String(), "PROJ/web: 1 to create, 0 to delete") {
+		t.Fatalf("unexpected dry-run output:\n%s", stdout.String())
+	}
+}
+
+func TestBranchProtectApplyReportsEveryRepoWhenOneFails(t *testing.T) {
+	var writes atomic.Int32
+	srv := protectApplyServer(t, &writes)
+	policy := writePolicy(t, `
+restrictions:
+  - branches: [main]
+    type: no-deletes
+`)
+
+	f, stdout, stderr := newTestFactory(dcConfig(srv.URL))
+	err := runBranchCmd(t, f, "protect", "apply", "-f", policy, "--repos", "PROJ/*")
+	if err == nil || !strings.Contains(err.Error(), "policy apply failed for 1 of 3 repositories") {
+		t.Fatalf("expected failure summary error, got %v (stderr=%s)", err, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"PROJ/api: 1 created, 0 deleted",
+		"PROJ/broken: error: ",
+		"PROJ/web: 1 created, 0 deleted",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+	if n := writes.Load(); n != 2 {
+		t.Fatalf("expected a create in each readable repo, got %d writes", n)
+	}
+}
+
+func TestDCBranchMatcher(t *testing.T) {
+	cases := []struct {
+		in, wantType, wantID string
+	}{
+		{"main", "BRANCH", "refs/heads/main"},
+		{"refs/heads/main", "BRANCH", "refs/heads/main"},
+		{"release/*", "PATTERN", "release/*"},
+		{"refs/heads/hotfix-?", "PATTERN", "hotfix-?"},
+	}
+	for _, tc := range cases {
+		gotType, gotID := dcBranchMatcher(tc.in)
+		if gotType != tc.wantType || gotID != tc.wantID {
+			t.Errorf("dcBranchMatcher(%q) = %s %s, want %s %s", tc.in, gotType, gotID, tc.wantType, tc.wantID)
+		}
+	}
+}
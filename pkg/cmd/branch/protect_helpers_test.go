@@ -8,12 +8,12 @@ func TestMapProtectType(t *testing.T) {
 		in   string
 		want string
 	}{
-		{"no-creates lowercase", "no-creates", "NO_CREATES"},
-		{"no-creates uppercase", "NO-CREATES", "NO_CREATES"},
-		{"no-creates mixed case", "No-Creates", "NO_CREATES"},
-		{"no-deletes", "no-deletes", "NO_DELETES"},
-		{"fast-forward-only", "fast-forward-only", "FAST_FORWARD_ONLY"},
-		{"require-approvals maps to PULL_REQUEST", "require-approvals", "PULL_REQUEST"},
+		{"no-creates lowercase", "no-creates", "no-creates"},
+		{"no-creates uppercase", "NO-CREATES", "no-creates"},
+		{"no-creates mixed case", "No-Creates", "no-creates"},
+		{"no-deletes", "no-deletes", "no-deletes"},
+		{"fast-forward-only", "fast-forward-only", "fast-forward-only"},
+		{"require-approvals maps to pull-request-only", "require-approvals", "pull-request-only"},
 		{"unknown type returns empty", "bogus", ""},
 		{"empty string returns empty", "", ""},
 	}
@@ -268,7 +268,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory) error {
 		Contexts: contexts,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(contexts) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No contexts configured. Use `%s context create` to add one.\n", f.ExecutableName)
 			return err
@@ -0,0 +1,348 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// tokenScope and writeTokenScope are the manifest scopes that ask bkt to
+// hand the extension a short-lived credential for the resolved context:
+// read-only, or able to push to repositories.
+const (
+	tokenScope      = "token"
+	writeTokenScope = "token:write"
+)
+
+// tokenMinValidity is how long a forwarded OAuth access token must remain
+// valid; tokens closer to expiry are refreshed before the extension starts.
+const tokenMinValidity = 5 * time.Minute
+
+// extensionTokenExpiryDays is the lifetime of the Data Center access token
+// minted for an extension run. One day is the shortest expiry the
+// access-tokens API accepts; the token is revoked when the extension exits.
+const extensionTokenExpiryDays = 1
+
+// extensionTokenRevokeTimeout bounds revoking the minted token after the
+// extension exits, including when bkt itself was interrupted.
+const extensionTokenRevokeTimeout = 15 * time.Second
+
+// injectedEnv lists the variables bkt sets for extensions. Inherited values
+// are dropped so an extension never sees a stale context.
+var injectedEnv = []string{
+	"BKT_EXTENSION_DIR",
+	"BKT_EXTENSION_NAME",
+	"BKT_CONTEXT",
+	"BKT_HOST_KIND",
+	"BKT_BASE_URL",
+	"BKT_PROJECT",
+	"BKT_WORKSPACE",
+	"BKT_REPO",
+	"BKT_USERNAME",
+	"BKT_AUTH_METHOD",
+	"BKT_TOKEN_EXPIRES_AT",
+}
+
+// RegisterInstalled adds each installed extension to root as a top-level
+// command, so "bkt lint" runs the lint extension. Extensions whose names
+// collide with built-in commands stay reachable through "bkt extension exec".
+// Discovery problems never prevent the CLI from starting.
+func RegisterInstalled(root *cobra.Command, f *cmdutil.Factory) {
+	dir, err := extensionRoot(f)
+	if err != nil {
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || validateExtensionName(name) != nil {
+			continue
+		}
+		if isReservedCommand(root, name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		root.AddCommand(newExtensionCommand(f, name))
+	}
+}
+
+func isReservedCommand(root *cobra.Command, name string) bool {
+	// Cobra adds help and completion lazily at execution time.
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newExtensionCommand(f *cmdutil.Factory, name string) *cobra.Command {
+	return &cobra.Command{
+		Use:   name + " [args...]",
+		Short: fmt.Sprintf("Run the %s extension", name),
+		Long: fmt.Sprintf(`Run the installed %s extension, forwarding all arguments to it.
+
+A leading --context/-c selects the bkt context the extension receives; put
+"--" first to pass such a flag to the extension itself. See
+"bkt extension exec --help" for the environment extensions receive.`, name),
+		// Everything after the command name belongs to the extension.
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			contextName, rest := splitContextArgs(args)
+			return runExtension(cmd, f, name, contextName, rest)
+		},
+	}
+}
+
+// splitContextArgs peels leading --context/-c flags, which cobra leaves in
+// place for commands that disable flag parsing, and a "--" terminator.
+func splitContextArgs(args []string) (string, []string) {
+	var contextName string
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == "--":
+			return contextName, args[1:]
+		case (arg == "--context" || arg == "-c") && len(args) > 1:
+			contextName, args = args[1], args[2:]
+		case strings.HasPrefix(arg, "--context="):
+			contextName, args = strings.TrimPrefix(arg, "--context="), args[1:]
+		case strings.HasPrefix(arg, "-c="):
+			contextName, args = strings.TrimPrefix(arg, "-c="), args[1:]
+		default:
+			return contextName, args
+		}
+	}
+	return contextName, args
+}
+
+// runExtension starts an installed extension with the resolved context
+// injected into its environment.
+func runExtension(cmd *cobra.Command, f *cmdutil.Factory, name, contextName string, args []string) error {
+	if err := validateExtensionName(name); err != nil {
+		return err
+	}
+
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	root, err := extensionRoot(f)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(root, name)
+	if _, err := os.Stat(dir); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("extension %q is not installed", name)
+		}
+		return err
+	}
+
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return fmt.Errorf("extension %s: %w", name, err)
+	}
+	if err := manifest.checkCompatible(f.AppVersion); err != nil {
+		return err
+	}
+
+	execPath, err := resolveExtensionExecutable(dir, name, manifest)
+	if err != nil {
+		return err
+	}
+
+	env, revoke, err := extensionEnv(cmd, f, name, dir, contextName, manifest)
+	if err != nil {
+		return err
+	}
+	defer revoke()
+
+	cmdExec := exec.CommandContext(cmd.Context(), execPath, args...)
+	cmdExec.Stdout = ios.Out
+	cmdExec.Stderr = ios.ErrOut
+	cmdExec.Stdin = ios.In
+	cmdExec.Dir = dir
+	cmdExec.Env = env
+
+	if err := cmdExec.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			// The extension reported its own failure; mirror its exit code.
+			return &cmdutil.ExitError{Code: exitErr.ExitCode()}
+		}
+		return err
+	}
+	return nil
+}
+
+// extensionEnv builds the extension environment: the caller's environment
+// minus bkt secrets, plus the resolved context. A short-lived token is added
+// only when the manifest declares a token scope; the returned func revokes
+// it once the extension has exited. Without an explicit context or a token
+// request, an unresolvable context is not an error; the extension simply
+// runs without context variables.
+func extensionEnv(cmd *cobra.Command, f *cmdutil.Factory, name, dir, contextName string, manifest *Manifest) ([]string, func(), error) {
+	noop := func() {}
+	env := withoutEnv(filterSensitiveEnv(), injectedEnv)
+	env = append(env,
+		"BKT_EXTENSION_DIR="+dir,
+		"BKT_EXTENSION_NAME="+name,
+	)
+
+	wantsWrite := manifest.requests(writeTokenScope)
+	wantsToken := wantsWrite || manifest.requests(tokenScope)
+	resolved, ctx, host, err := cmdutil.ResolveContext(f, cmd, contextName)
+	if err != nil {
+		if contextName != "" || wantsToken {
+			return nil, noop, err
+		}
+		return env, noop, nil
+	}
+
+	baseURL := host.BaseURL
+	if baseURL == "" && host.Kind == "cloud" {
+		baseURL = "https://api.bitbucket.org/2.0"
+	}
+	env = append(env,
+		"BKT_CONTEXT="+resolved,
+		"BKT_HOST_KIND="+host.Kind,
+		"BKT_BASE_URL="+baseURL,
+	)
+	env = appendContextEnv(env, host, ctx)
+
+	if !wantsToken {
+		return env, noop, nil
+	}
+
+	switch {
+	case host.Kind == "dc":
+		return mintExtensionToken(cmd, f, name, host, wantsWrite, env)
+	case host.Kind == "cloud" && host.AuthMethod == "oauth":
+		token, err := cmdutil.FreshAccessToken(cmd.Context(), host, tokenMinValidity)
+		if err != nil {
+			return nil, noop, err
+		}
+		if token == "" {
+			return nil, noop, fmt.Errorf("extension %s requests a token, but no credential is available for host %s", name, host.BaseURL)
+		}
+		env = append(env, "BKT_TOKEN="+token, "BKT_AUTH_METHOD=bearer")
+		if !host.OAuthExpiresAt.IsZero() {
+			env = append(env, "BKT_TOKEN_EXPIRES_AT="+host.OAuthExpiresAt.UTC().Format(time.RFC3339))
+		}
+		return env, noop, nil
+	default:
+		return nil, noop, fmt.Errorf("extension %s requests a token, but bkt only hands out expiring tokens: log in to %s with OAuth (bkt auth login --web) to use it on Bitbucket Cloud", name, baseURL)
+	}
+}
+
+// mintExtensionToken creates a Data Center personal access token for one
+// extension run, limited to reading projects and reading (or, for the
+// token:write scope, writing) repositories, and expiring after a day. The
+// returned func revokes it.
+func mintExtensionToken(cmd *cobra.Command, f *cmdutil.Factory, name string, host *config.Host, write bool, env []string) ([]string, func(), error) {
+	noop := func() {}
+	client, err := f.DCClient(host)
+	if err != nil {
+		return nil, noop, err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	user := host.Username
+	if user == "" || host.AuthMethod == "bearer" {
+		if user, err = client.AuthenticatedUsername(ctx); err != nil {
+			return nil, noop, fmt.Errorf("extension %s requests a token: identify token owner: %w", name, err)
+		}
+	}
+
+	permissions := []string{"PROJECT_READ", "REPO_READ"}
+	if write {
+		permissions = []string{"PROJECT_READ", "REPO_WRITE"}
+	}
+	token, err := client.CreateUserAccessToken(ctx, user, bbdc.AccessTokenInput{
+		Name:        "bkt extension " + name,
+		Permissions: permissions,
+		ExpiryDays:  extensionTokenExpiryDays,
+	})
+	if err != nil {
+		return nil, noop, fmt.Errorf("extension %s requests a token, but creating a short-lived access token failed: %w", name, err)
+	}
+
+	revoke := func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(cmd.Context()), extensionTokenRevokeTimeout)
+		defer cancel()
+		if err := client.RevokeUserAccessToken(ctx, user, token.ID); err != nil {
+			if ios, ierr := f.Streams(); ierr == nil {
+				_, _ = fmt.Fprintf(ios.ErrOut, "warning: could not revoke extension token %s: %v\n", token.ID, err)
+			}
+		}
+	}
+
+	env = append(env, "BKT_TOKEN="+token.Token, "BKT_AUTH_METHOD=bearer")
+	if token.ExpiryDate > 0 {
+		env = append(env, "BKT_TOKEN_EXPIRES_AT="+time.UnixMilli(token.ExpiryDate).UTC().Format(time.RFC3339))
+	}
+	return env, revoke, nil
+}
+
+func appendContextEnv(env []string, host *config.Host, ctx *config.Context) []string {
+	if ctx == nil {
+		return env
+	}
+	if host.Kind == "dc" && ctx.ProjectKey != "" {
+		env = append(env, "BKT_PROJECT="+ctx.ProjectKey)
+	}
+	if host.Kind == "cloud" && ctx.Workspace != "" {
+		env = append(env, "BKT_WORKSPACE="+ctx.Workspace)
+	}
+	if ctx.DefaultRepo != "" {
+		env = append(env, "BKT_REPO="+ctx.DefaultRepo)
+	}
+	return env
+}
+
+func withoutEnv(env, keys []string) []string {
+	filtered := env[:0:0]
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		drop := false
+		for _, k := range keys {
+			if key == k {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
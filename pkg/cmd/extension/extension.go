@@ -1,17 +1,21 @@
 package extension
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/repo"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 )
 
@@ -53,23 +57,47 @@ func NewCmdExtension(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "extension",
 		Short: "Manage bkt CLI extensions",
-		Long: `Install, list, remove, and execute external bkt CLI extensions.
+		Long: `Install, upgrade, list, remove, and execute external bkt CLI extensions.
 
 Extensions are Git repositories that contain an executable following the
-bkt-<name> naming convention. Once installed, an extension can be invoked
-directly through "bkt extension exec" or discovered with "bkt extension list".
-Extensions work identically for both Bitbucket Cloud and Data Center contexts.`,
+bkt-<name> naming convention, or an extension.yaml manifest that names the
+entrypoint explicitly. Once installed, an extension runs as a top-level
+command ("bkt lint ...") or through "bkt extension exec", and is discovered
+with "bkt extension list". Extensions whose names collide with a built-in
+command are reachable only through "bkt extension exec". Extensions work
+identically for both Bitbucket Cloud and Data Center contexts.
+
+The optional extension.yaml manifest at the repository root looks like:
+
+  name: lint
+  version: 1.4.0
+  min_bkt_version: 0.32.0
+  entrypoint: bin/bkt-lint
+  scopes: [token]
+
+Installs and upgrades track the repository's highest version tag (v1.2.3 or
+1.2.3); repositories without version tags track their default branch.`,
 		Example: `  # Install an extension from a Git repository
   bkt extension install https://bitbucket.org/myteam/bkt-lint
 
-  # List all installed extensions
+  # Install from a configured Bitbucket host and pin a release
+  bkt extension install PLAT/bkt-lint --host bitbucket.example.com --pin v1.2.0
+
+  # List installed extensions with their latest available tags
   bkt extension list
 
+  # Upgrade every unpinned extension
+  bkt extension upgrade --all
+
   # Run an installed extension with arguments
-  bkt extension exec lint --fix`,
+  bkt lint --fix
+
+  # Run it against another context
+  bkt --context prod lint --fix`,
 	}
 
 	cmd.AddCommand(newInstallCmd(f))
+	cmd.AddCommand(newUpgradeCmd(f))
 	cmd.AddCommand(newListCmd(f))
 	cmd.AddCommand(newRemoveCmd(f))
 	cmd.AddCommand(newExecCmd(f))
@@ -77,14 +105,32 @@ Extensions work identically for both Bitbucket Cloud and Data Center contexts.`,
 	return cmd
 }
 
+type installOptions struct {
+	Repository string
+	Pin        string
+	Host       string
+}
+
 func newInstallCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &installOptions{}
 	cmd := &cobra.Command{
 		Use:   "install <repository>",
 		Short: "Install an extension from a repository",
 		Long: `Clone a Git repository into the bkt extensions directory and register it as
-a CLI extension. The repository must contain an executable named bkt-<name>
-at the top level or inside a bin/ subdirectory. The extension name is inferred
-from the repository URL by stripping the optional "bkt-" prefix.
+a CLI extension. The repository is either a Git URL or an OWNER/REPO
+reference (a Data Center project key or Cloud workspace, then the repository
+slug) that is resolved through a configured Bitbucket host, so private
+extensions clone with the same credentials as "bkt repo clone". Use --host to
+pick a host other than the active context's.
+
+The extension name comes from extension.yaml when present, otherwise it is
+inferred from the repository name by stripping the optional "bkt-" prefix.
+Without a manifest the repository must contain an executable named
+bkt-<name> at the top level or inside a bin/ subdirectory.
+
+The highest version tag is checked out unless --pin selects a specific tag;
+pinned extensions are skipped by "bkt extension upgrade". Installation fails
+when the manifest requires a newer bkt than the one running.
 
 If the extension is already installed, the command returns an error. Remove it
 first with "bkt extension remove" before reinstalling.`,
@@ -94,13 +140,67 @@ first with "bkt extension remove" before reinstalling.`,
   # Install from an SSH URL
   bkt extension install git@bitbucket.org:myteam/bkt-deploy.git
 
-  # Install from a GitHub repository
-  bkt extension install https://github.com/user/bkt-formatter`,
+  # Install from a project on a configured Data Center host
+  bkt extension install PLAT/bkt-release --host bitbucket.example.com
+
+  # Install a specific release and keep it there
+  bkt extension install myteam/bkt-lint --pin v1.2.0`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runExtensionInstall(cmd, f, args[0])
+			opts.Repository = args[0]
+			return runExtensionInstall(cmd, f, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Pin, "pin", "", "Check out and pin this tag instead of the latest version")
+	cmd.Flags().StringVar(&opts.Host, "host", "", "Configured host that serves an OWNER/REPO reference")
+	return cmd
+}
+
+type upgradeOptions struct {
+	Name string
+	All  bool
+	Pin  string
+}
+
+func newUpgradeCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &upgradeOptions{}
+	cmd := &cobra.Command{
+		Use:   "upgrade [<name>]",
+		Short: "Upgrade installed extensions",
+		Long: `Fetch new tags for an installed extension and check out the highest version
+tag, or fast-forward the default branch when the repository has no version
+tags. Pass --all to upgrade every installed extension.
+
+Pinned extensions are left in place; pass --pin <tag> to move an extension to
+a different tag and record that as its new pin. If the upgraded manifest is
+invalid or requires a newer bkt, the extension is rolled back to the version
+that was installed before.`,
+		Example: `  # Upgrade one extension
+  bkt extension upgrade lint
+
+  # Upgrade every unpinned extension
+  bkt extension upgrade --all
+
+  # Move a pinned extension to a new release
+  bkt extension upgrade lint --pin v1.3.0`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.Name = args[0]
+			}
+			switch {
+			case opts.All && opts.Name != "":
+				return fmt.Errorf("specify an extension name or --all, not both")
+			case !opts.All && opts.Name == "":
+				return fmt.Errorf("specify an extension name or --all")
+			case opts.All && opts.Pin != "":
+				return fmt.Errorf("--pin requires a single extension name")
+			}
+			return runExtensionUpgrade(cmd, f, opts)
 		},
 	}
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Upgrade all installed extensions")
+	cmd.Flags().StringVar(&opts.Pin, "pin", "", "Check out and pin this tag")
 	return cmd
 }
 
@@ -110,9 +210,10 @@ func newListCmd(f *cmdutil.Factory) *cobra.Command {
 		Aliases: []string{"ls"},
 		Short:   "List installed extensions",
 		Long: `Display all extensions currently installed in the bkt extensions directory.
-Each entry shows the extension name and, when available, the relative path
-to its executable. If no extensions are installed, a hint is printed
-suggesting the install command.`,
+Each entry shows the extension name, the installed tag (or commit), the
+latest version tag published by the extension's repository, and the relative
+path to its executable. Pinned extensions are marked. If no extensions are
+installed, a hint is printed suggesting the install command.`,
 		Example: `  # List all installed extensions
   bkt extension list
 
@@ -154,10 +255,36 @@ func newExecCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:   "exec <name> [args...]",
 		Short: "Execute an installed extension",
 		Long: `Run an installed extension by name, forwarding any additional arguments to the
-extension executable. The extension runs in its own directory with
-BKT_EXTENSION_DIR and BKT_EXTENSION_NAME environment variables set.
+extension executable. Installed extensions are also available as top-level
+commands ("bkt <name>"), which behave identically.
+
+The extension runs in its own directory with BKT_EXTENSION_DIR and
+BKT_EXTENSION_NAME set. When a context resolves (--context, the active
+context, or .bkt.yaml and git remote defaults), it also receives:
+
+  BKT_CONTEXT      context name (empty for BKT_HOST environment hosts)
+  BKT_HOST_KIND    dc or cloud
+  BKT_BASE_URL     API base URL of the host
+  BKT_PROJECT      Data Center project key, when known
+  BKT_WORKSPACE    Cloud workspace, when known
+  BKT_REPO         repository slug, when known
+
 Sensitive bkt configuration variables (tokens, keyring passphrase) are
-stripped from the environment before the extension process starts.`,
+stripped from the environment before the extension process starts, and the
+stored credential itself is never handed over. An extension whose
+extension.yaml declares the "token" scope (or "token:write") instead receives
+a short-lived bearer token in BKT_TOKEN, with BKT_AUTH_METHOD=bearer and
+BKT_TOKEN_EXPIRES_AT:
+
+  Data Center   a personal access token minted for the run with
+                PROJECT_READ and REPO_READ (REPO_WRITE for "token:write"),
+                expiring after a day and revoked when the extension exits
+  Cloud OAuth   the OAuth access token, refreshed first when it expires
+                within five minutes
+
+Other Cloud logins (API tokens, app passwords) cannot mint expiring tokens,
+so extensions requesting a token fail to start there. A non-zero extension
+exit code becomes bkt's exit code.`,
 		Example: `  # Run an extension with no arguments
   bkt extension exec lint
 
@@ -174,20 +301,27 @@ stripped from the environment before the extension process starts.`,
 	return cmd
 }
 
-func runExtensionInstall(cmd *cobra.Command, f *cmdutil.Factory, repo string) error {
+func runExtensionInstall(cmd *cobra.Command, f *cmdutil.Factory, opts *installOptions) error {
 	ios, err := f.Streams()
 	if err != nil {
 		return err
 	}
 
+	if err := validatePin(opts.Pin); err != nil {
+		return err
+	}
+
 	root, err := ensureExtensionRoot(f)
 	if err != nil {
 		return err
 	}
 
-	name := inferExtensionName(repo)
+	cloneURL, name, err := resolveExtensionSource(cmd, f, opts)
+	if err != nil {
+		return err
+	}
 	if name == "" {
-		return fmt.Errorf("unable to infer extension name from %q", repo)
+		return fmt.Errorf("unable to infer extension name from %q", opts.Repository)
 	}
 
 	destination := filepath.Join(root, name)
@@ -195,7 +329,7 @@ func runExtensionInstall(cmd *cobra.Command, f *cmdutil.Factory, repo string) er
 		return fmt.Errorf("extension %q is already installed", name)
 	}
 
-	args := []string{"clone", "--", repo, destination}
+	args := []string{"clone", "--", cloneURL, destination}
 	gitCmd := exec.CommandContext(cmd.Context(), "git", args...)
 	gitCmd.Stdout = ios.Out
 	gitCmd.Stderr = ios.ErrOut
@@ -204,14 +338,38 @@ func runExtensionInstall(cmd *cobra.Command, f *cmdutil.Factory, repo string) er
 	if err := gitCmd.Run(); err != nil {
 		return fmt.Errorf("git clone failed: %w", err)
 	}
-	execPath, err := findExtensionExecutable(destination, name)
+
+	manifest, err := prepareInstalledExtension(cmd, f, destination, opts.Pin)
+	if err != nil {
+		_ = os.RemoveAll(destination)
+		return err
+	}
+
+	if manifest != nil && manifest.Name != name {
+		renamed := filepath.Join(root, manifest.Name)
+		if _, err := os.Stat(renamed); err == nil {
+			_ = os.RemoveAll(destination)
+			return fmt.Errorf("extension %q is already installed", manifest.Name)
+		}
+		if err := os.Rename(destination, renamed); err != nil {
+			_ = os.RemoveAll(destination)
+			return fmt.Errorf("rename extension: %w", err)
+		}
+		name, destination = manifest.Name, renamed
+	}
+
+	execPath, err := resolveExtensionExecutable(destination, name, manifest)
 	if err != nil {
 		if _, warnErr := fmt.Fprintf(ios.ErrOut, "warning: %v\n", err); warnErr != nil {
 			return warnErr
 		}
 	}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Installed extension %s\n", name); err != nil {
+	installed := name
+	if ref := installedRef(cmd.Context(), destination, manifest); ref != "" {
+		installed = fmt.Sprintf("%s %s", name, ref)
+	}
+	if _, err := fmt.Fprintf(ios.Out, "✓ Installed extension %s\n", installed); err != nil {
 		return err
 	}
 	if execPath != "" {
@@ -220,9 +378,311 @@ func runExtensionInstall(cmd *cobra.Command, f *cmdutil.Factory, repo string) er
 			return err
 		}
 	}
+	if opts.Pin != "" {
+		if _, err := fmt.Fprintf(ios.Out, "  pinned: %s\n", opts.Pin); err != nil {
+			return err
+		}
+	}
+	if manifest != nil && len(manifest.Scopes) > 0 {
+		if _, err := fmt.Fprintf(ios.Out, "  scopes: %s\n", strings.Join(manifest.Scopes, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareInstalledExtension checks out the pinned or latest version tag of a
+// fresh clone and validates its manifest against the running bkt.
+func prepareInstalledExtension(cmd *cobra.Command, f *cmdutil.Factory, dir, pin string) (*Manifest, error) {
+	ios, err := f.Streams()
+	if err != nil {
+		return nil, err
+	}
+	ctx := cmd.Context()
+
+	ref := pin
+	if ref == "" {
+		tags, err := localTags(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		ref = latestVersionTag(tags)
+	}
+	if ref != "" {
+		if err := gitCheckout(ctx, ios.ErrOut, dir, ref); err != nil {
+			return nil, err
+		}
+	}
+	if pin != "" {
+		if err := gitRun(ctx, ios.ErrOut, dir, "config", "--local", pinConfigKey, pin); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.checkCompatible(f.AppVersion); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// resolveExtensionSource turns the install argument into a clone URL and an
+// extension name. OWNER/REPO references are looked up on a configured host.
+func resolveExtensionSource(cmd *cobra.Command, f *cmdutil.Factory, opts *installOptions) (string, string, error) {
+	source := strings.TrimSpace(opts.Repository)
+	owner, slug, ok := splitRepoReference(source)
+	if !ok {
+		if strings.TrimSpace(opts.Host) != "" {
+			return "", "", fmt.Errorf("--host applies only to OWNER/REPO references, not %q", source)
+		}
+		return source, inferExtensionName(source), nil
+	}
+
+	_, host, err := cmdutil.ResolveHost(f, cmdutil.FlagValue(cmd, "context"), opts.Host)
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	var cloneURL string
+	switch host.Kind {
+	case "dc":
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return "", "", err
+		}
+		r, err := client.GetRepository(ctx, owner, slug)
+		if err != nil {
+			return "", "", err
+		}
+		cloneURL, err = repo.SelectCloneURLDC(*r, false)
+		if err != nil {
+			return "", "", fmt.Errorf("%s/%s: %w", owner, slug, err)
+		}
+	case "cloud":
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return "", "", err
+		}
+		r, err := client.GetRepository(ctx, owner, slug)
+		if err != nil {
+			return "", "", err
+		}
+		cloneURL, err = repo.SelectCloneURLCloud(*r, false)
+		if err != nil {
+			return "", "", fmt.Errorf("%s/%s: %w", owner, slug, err)
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+	return cloneURL, inferExtensionName(slug), nil
+}
+
+// splitRepoReference recognises OWNER/REPO arguments. URLs, scp-style
+// remotes, and paths that exist locally are left to git.
+func splitRepoReference(repo string) (string, string, bool) {
+	if strings.Contains(repo, "://") || strings.ContainsAny(repo, ":@\\") {
+		return "", "", false
+	}
+	if strings.HasPrefix(repo, "-") || strings.HasPrefix(repo, ".") || strings.HasPrefix(repo, "/") {
+		return "", "", false
+	}
+	owner, slug, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || slug == "" || strings.Contains(slug, "/") {
+		return "", "", false
+	}
+	if _, err := os.Stat(repo); err == nil {
+		return "", "", false
+	}
+	return owner, strings.TrimSuffix(slug, ".git"), true
+}
+
+func validatePin(pin string) error {
+	if strings.HasPrefix(pin, "-") || strings.ContainsAny(pin, " \t\n") {
+		return fmt.Errorf("invalid --pin %q", pin)
+	}
 	return nil
 }
 
+func runExtensionUpgrade(cmd *cobra.Command, f *cmdutil.Factory, opts *upgradeOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	if err := validatePin(opts.Pin); err != nil {
+		return err
+	}
+
+	root, err := extensionRoot(f)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	if opts.All {
+		entries, err := os.ReadDir(root)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			_, err := fmt.Fprintln(ios.Out, "No extensions installed.")
+			return err
+		}
+	} else {
+		if err := validateExtensionName(opts.Name); err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(root, opts.Name)); errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("extension %q is not installed", opts.Name)
+		} else if err != nil {
+			return err
+		}
+		names = []string{opts.Name}
+	}
+
+	failed := 0
+	for _, name := range names {
+		if err := upgradeExtension(cmd, f, filepath.Join(root, name), name, opts.Pin, opts.All); err != nil {
+			if !opts.All {
+				return err
+			}
+			failed++
+			if _, werr := fmt.Fprintf(ios.ErrOut, "✗ %s: %v\n", name, err); werr != nil {
+				return werr
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d extensions failed to upgrade", failed, len(names))
+	}
+	return nil
+}
+
+// upgradeExtension moves one extension to its pinned or latest version and
+// rolls back to the previous commit if the result is not usable.
+func upgradeExtension(cmd *cobra.Command, f *cmdutil.Factory, dir, name, pin string, all bool) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+	ctx := cmd.Context()
+
+	if !isGitCheckout(dir) {
+		if all {
+			_, err := fmt.Fprintf(ios.Out, "- %s was not installed from git; skipping\n", name)
+			return err
+		}
+		return fmt.Errorf("extension %q was not installed from git", name)
+	}
+
+	if pin == "" {
+		if current := pinnedRef(ctx, dir); current != "" {
+			_, err := fmt.Fprintf(ios.Out, "- %s is pinned to %s; pass --pin to change it\n", name, current)
+			return err
+		}
+	}
+
+	prevManifest, _ := loadManifest(dir)
+	before := installedRef(ctx, dir, prevManifest)
+	prevCommit, err := gitOutput(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+	prevBranch, _ := gitOutput(ctx, dir, "symbolic-ref", "--quiet", "--short", "HEAD")
+
+	if err := gitRun(ctx, ios.ErrOut, dir, "fetch", "--quiet", "--tags", "--force", "origin"); err != nil {
+		return err
+	}
+
+	ref := pin
+	if ref == "" {
+		tags, err := localTags(ctx, dir)
+		if err != nil {
+			return err
+		}
+		ref = latestVersionTag(tags)
+	}
+	if ref != "" {
+		err = gitCheckout(ctx, ios.ErrOut, dir, ref)
+	} else {
+		err = gitRun(ctx, ios.ErrOut, dir, "pull", "--quiet", "--ff-only")
+	}
+	if err != nil {
+		return rollbackExtension(ctx, ios.ErrOut, dir, prevBranch, prevCommit, err)
+	}
+
+	manifest, err := loadManifest(dir)
+	if err == nil {
+		err = manifest.checkCompatible(f.AppVersion)
+	}
+	if err == nil && manifest != nil && manifest.Name != name {
+		err = fmt.Errorf("manifest renames the extension to %q; reinstall it instead", manifest.Name)
+	}
+	if err != nil {
+		return rollbackExtension(ctx, ios.ErrOut, dir, prevBranch, prevCommit, err)
+	}
+
+	if pin != "" {
+		if err := gitRun(ctx, ios.ErrOut, dir, "config", "--local", pinConfigKey, pin); err != nil {
+			return err
+		}
+	}
+
+	after := installedRef(ctx, dir, manifest)
+	if head, err := gitOutput(ctx, dir, "rev-parse", "HEAD"); err == nil && head == prevCommit {
+		_, err := fmt.Fprintf(ios.Out, "✓ %s is already up to date (%s)\n", name, after)
+		return err
+	}
+	_, err = fmt.Fprintf(ios.Out, "✓ Upgraded %s %s → %s\n", name, before, after)
+	return err
+}
+
+func rollbackExtension(ctx context.Context, errOut io.Writer, dir, branch, commit string, cause error) error {
+	ref := commit
+	if branch != "" {
+		ref = branch
+	}
+	if err := gitCheckout(ctx, errOut, dir, ref); err != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+	}
+	if branch != "" {
+		if err := gitRun(ctx, errOut, dir, "reset", "--quiet", "--hard", commit); err != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+		}
+	}
+	return fmt.Errorf("%w; kept the previously installed version", cause)
+}
+
+// installedRef names the installed version: the checked-out tag, the
+// manifest version, or the short commit hash, in that order.
+func installedRef(ctx context.Context, dir string, manifest *Manifest) string {
+	if !isGitCheckout(dir) {
+		if manifest != nil {
+			return manifest.Version
+		}
+		return ""
+	}
+	if tag := exactTag(ctx, dir); tag != "" {
+		return tag
+	}
+	if manifest != nil && manifest.Version != "" {
+		return manifest.Version
+	}
+	return shortCommit(ctx, dir)
+}
+
 func runExtensionList(cmd *cobra.Command, f *cmdutil.Factory) error {
 	ios, err := f.Streams()
 	if err != nil {
@@ -242,9 +702,15 @@ func runExtensionList(cmd *cobra.Command, f *cmdutil.Factory) error {
 	}
 
 	type extensionSummary struct {
-		Name       string `json:"name"`
-		Path       string `json:"path"`
-		Executable string `json:"executable,omitempty"`
+		Name       string   `json:"name"`
+		Path       string   `json:"path"`
+		Executable string   `json:"executable,omitempty"`
+		Version    string   `json:"version,omitempty"`
+		Installed  string   `json:"installed,omitempty"`
+		Latest     string   `json:"latest,omitempty"`
+		Pinned     string   `json:"pinned,omitempty"`
+		Scopes     []string `json:"scopes,omitempty"`
+		Error      string   `json:"error,omitempty"`
 	}
 
 	var summaries []extensionSummary
@@ -254,16 +720,29 @@ func runExtensionList(cmd *cobra.Command, f *cmdutil.Factory) error {
 		}
 		name := entry.Name()
 		dir := filepath.Join(root, name)
-		execPath, _ := findExtensionExecutable(dir, name)
-		rel := ""
-		if execPath != "" {
-			rel, _ = filepath.Rel(root, execPath)
+		summary := extensionSummary{Name: name, Path: dir}
+
+		manifest, err := loadManifest(dir)
+		if err != nil {
+			summary.Error = err.Error()
 		}
-		summaries = append(summaries, extensionSummary{
-			Name:       name,
-			Path:       dir,
-			Executable: rel,
-		})
+		if manifest != nil {
+			summary.Version = manifest.Version
+			summary.Scopes = manifest.Scopes
+		}
+		if execPath, _ := resolveExtensionExecutable(dir, name, manifest); execPath != "" {
+			summary.Executable, _ = filepath.Rel(root, execPath)
+		}
+		summary.Installed = installedRef(cmd.Context(), dir, manifest)
+		if isGitCheckout(dir) {
+			summary.Pinned = pinnedRef(cmd.Context(), dir)
+			ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+			if tags, err := remoteTags(ctx, dir); err == nil {
+				summary.Latest = latestVersionTag(tags)
+			}
+			cancel()
+		}
+		summaries = append(summaries, summary)
 	}
 
 	sort.Slice(summaries, func(i, j int) bool {
@@ -274,16 +753,25 @@ func runExtensionList(cmd *cobra.Command, f *cmdutil.Factory) error {
 		Extensions []extensionSummary `json:"extensions"`
 	}{Extensions: summaries}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, data, func() error {
+	return cmdutil.WriteOutput(cmd, ios, data, func() error {
 		if len(summaries) == 0 {
 			_, err := fmt.Fprintln(ios.Out, "No extensions installed. Use `bkt extension install <repository>` to add one.")
 			return err
 		}
 		for _, ext := range summaries {
-			line := ext.Name
-			if ext.Executable != "" {
-				line = fmt.Sprintf("%s\t%s", ext.Name, ext.Executable)
+			installed := valueOrDash(ext.Installed)
+			if ext.Pinned != "" {
+				installed += " (pinned)"
+			}
+			latest := valueOrDash(ext.Latest)
+			if ext.Latest != "" && ext.Latest != ext.Installed {
+				latest += " available"
+			}
+			executable := ext.Executable
+			if ext.Error != "" {
+				executable = "error: " + ext.Error
 			}
+			line := fmt.Sprintf("%s\t%s\t%s\t%s", ext.Name, installed, latest, valueOrDash(executable))
 			if _, err := fmt.Fprintln(ios.Out, line); err != nil {
 				return err
 			}
@@ -292,6 +780,13 @@ func runExtensionList(cmd *cobra.Command, f *cmdutil.Factory) error {
 	})
 }
 
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 func runExtensionRemove(cmd *cobra.Command, f *cmdutil.Factory, name string) error {
 	if err := validateExtensionName(name); err != nil {
 		return err
@@ -325,44 +820,16 @@ func runExtensionRemove(cmd *cobra.Command, f *cmdutil.Factory, name string) err
 }
 
 func runExtensionExec(cmd *cobra.Command, f *cmdutil.Factory, name string, args []string) error {
-	if err := validateExtensionName(name); err != nil {
-		return err
-	}
-
-	ios, err := f.Streams()
-	if err != nil {
-		return err
-	}
-
-	root, err := extensionRoot(f)
-	if err != nil {
-		return err
-	}
-
-	dir := filepath.Join(root, name)
-	if _, err := os.Stat(dir); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("extension %q is not installed", name)
-		}
-		return err
-	}
+	return runExtension(cmd, f, name, cmdutil.FlagValue(cmd, "context"), args)
+}
 
-	execPath, err := findExtensionExecutable(dir, name)
-	if err != nil {
-		return err
+// resolveExtensionExecutable prefers the manifest entrypoint and falls back
+// to the bkt-<name> naming convention for extensions without a manifest.
+func resolveExtensionExecutable(dir, name string, manifest *Manifest) (string, error) {
+	if manifest != nil {
+		return manifest.entrypointPath(dir), nil
 	}
-
-	cmdExec := exec.CommandContext(cmd.Context(), execPath, args...)
-	cmdExec.Stdout = ios.Out
-	cmdExec.Stderr = ios.ErrOut
-	cmdExec.Stdin = ios.In
-	cmdExec.Dir = dir
-	cmdExec.Env = append(filterSensitiveEnv(),
-		fmt.Sprintf("BKT_EXTENSION_DIR=%s", dir),
-		fmt.Sprintf("BKT_EXTENSION_NAME=%s", name),
-	)
-
-	return cmdExec.Run()
+	return findExtensionExecutable(dir, name)
 }
 
 func extensionRoot(f *cmdutil.Factory) (string, error) {
@@ -447,6 +914,10 @@ func isExecutable(entry os.DirEntry) bool {
 	if err != nil {
 		return false
 	}
+	return isExecutableInfo(info)
+}
+
+func isExecutableInfo(info os.FileInfo) bool {
 	if info.IsDir() {
 		return false
 	}
@@ -1,11 +1,17 @@
 package extension
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -82,7 +88,7 @@ func TestRunExtensionInstallUsesDoubleDash(t *testing.T) {
 	cmd := &cobra.Command{}
 	cmd.SetContext(t.Context())
 
-	if err := runExtensionInstall(cmd, f, "--upload-pack=evil"); err != nil {
+	if err := runExtensionInstall(cmd, f, &installOptions{Repository: "--upload-pack=evil"}); err != nil {
 		t.Fatalf("runExtensionInstall returned error: %v", err)
 	}
 
@@ -91,7 +97,9 @@ func TestRunExtensionInstallUsesDoubleDash(t *testing.T) {
 		t.Fatalf("ReadFile(%s): %v", argsFile, err)
 	}
 
-	got := strings.Split(strings.TrimSpace(string(data)), "\n")
+	// Each git invocation is recorded as its own block; the clone comes first.
+	first, _, _ := strings.Cut(string(data), "\n\n")
+	got := strings.Split(strings.TrimSpace(first), "\n")
 	want := []string{"clone", "--", "--upload-pack=evil", filepath.Join(extensionParentRootForTest(f), "--upload-pack=evil")}
 	if strings.Join(got, "\n") != strings.Join(want, "\n") {
 		t.Fatalf("git args = %#v, want %#v", got, want)
@@ -234,7 +242,7 @@ func writeGitHelperScript(t *testing.T, target string) {
 		return
 	}
 
-	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > \"$EXTENSION_GIT_ARGS_FILE\"\n"
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" >> \"$EXTENSION_GIT_ARGS_FILE\"\necho >> \"$EXTENSION_GIT_ARGS_FILE\"\n"
 	if err := os.WriteFile(target, []byte(script), 0o755); err != nil {
 		t.Fatalf("WriteFile(%s): %v", target, err)
 	}
@@ -253,7 +261,14 @@ func TestExtensionGitHelperProcess(t *testing.T) {
 	}
 
 	args := extensionTestArgsAfterDoubleDash(os.Args)
-	if err := os.WriteFile(os.Getenv("EXTENSION_GIT_ARGS_FILE"), []byte(strings.Join(args, "\n")), 0o644); err != nil {
+	file, err := os.OpenFile(os.Getenv("EXTENSION_GIT_ARGS_FILE"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := file.WriteString(strings.Join(args, "\n") + "\n\n"); err != nil {
+		panic(err)
+	}
+	if err := file.Close(); err != nil {
 		panic(err)
 	}
 	os.Exit(0)
@@ -267,3 +282,481 @@ func extensionTestArgsAfterDoubleDash(args []string) []string {
 	}
 	return nil
 }
+
+func TestSplitRepoReference(t *testing.T) {
+	tests := []struct {
+		input     string
+		owner     string
+		slug      string
+		reference bool
+	}{
+		{input: "PLAT/bkt-lint", owner: "PLAT", slug: "bkt-lint", reference: true},
+		{input: "myteam/bkt-lint.git", owner: "myteam", slug: "bkt-lint", reference: true},
+		{input: "https://bitbucket.org/myteam/bkt-lint"},
+		{input: "git@bitbucket.org:myteam/bkt-lint.git"},
+		{input: "./local/bkt-lint"},
+		{input: "/srv/git/bkt-lint"},
+		{input: "a/b/c"},
+		{input: "--upload-pack=evil"},
+		{input: "bkt-lint"},
+	}
+	for _, tt := range tests {
+		owner, slug, ok := splitRepoReference(tt.input)
+		if ok != tt.reference || owner != tt.owner || slug != tt.slug {
+			t.Fatalf("splitRepoReference(%q) = %q, %q, %v", tt.input, owner, slug, ok)
+		}
+	}
+}
+
+func TestRunExtensionInstallChecksOutLatestTagAndUsesManifest(t *testing.T) {
+	src := newExtensionSourceRepo(t)
+	releaseExtension(t, src, "1.0.0", "")
+	releaseExtension(t, src, "1.1.0", "")
+	commitExtensionChange(t, src, "unreleased work")
+
+	f, stdout, _ := newExtensionTestFactory(t)
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+
+	if err := runExtensionInstall(cmd, f, &installOptions{Repository: src}); err != nil {
+		t.Fatalf("runExtensionInstall: %v", err)
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"✓ Installed extension demo v1.1.0\n",
+		"  binary: " + filepath.Join("demo", "bin", "bkt-demo") + "\n",
+		"  scopes: repo:read\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("install output missing %q:\n%s", want, out)
+		}
+	}
+
+	// The clone directory is named after the manifest, not the source path.
+	if _, err := os.Stat(filepath.Join(extensionParentRootForTest(f), filepath.Base(src))); !os.IsNotExist(err) {
+		t.Fatalf("expected clone to be renamed to the manifest name, stat err = %v", err)
+	}
+
+	stdout.Reset()
+	if err := runExtensionExec(cmd, f, "demo", []string{"hello"}); err != nil {
+		t.Fatalf("runExtensionExec: %v", err)
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "demo 1.1.0 hello" {
+		t.Fatalf("exec output = %q, want %q", got, "demo 1.1.0 hello")
+	}
+}
+
+func TestRunExtensionUpgradeHonoursPins(t *testing.T) {
+	src := newExtensionSourceRepo(t)
+	releaseExtension(t, src, "1.0.0", "")
+
+	f, stdout, _ := newExtensionTestFactory(t)
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+
+	if err := runExtensionInstall(cmd, f, &installOptions{Repository: src, Pin: "v1.0.0"}); err != nil {
+		t.Fatalf("runExtensionInstall: %v", err)
+	}
+	releaseExtension(t, src, "1.1.0", "")
+
+	stdout.Reset()
+	if err := runExtensionList(cmd, f); err != nil {
+		t.Fatalf("runExtensionList: %v", err)
+	}
+	wantList := "demo\tv1.0.0 (pinned)\tv1.1.0 available\t" + filepath.Join("demo", "bin", "bkt-demo") + "\n"
+	if stdout.String() != wantList {
+		t.Fatalf("list output = %q, want %q", stdout.String(), wantList)
+	}
+
+	stdout.Reset()
+	if err := runExtensionUpgrade(cmd, f, &upgradeOptions{All: true}); err != nil {
+		t.Fatalf("runExtensionUpgrade --all: %v", err)
+	}
+	if got := stdout.String(); got != "- demo is pinned to v1.0.0; pass --pin to change it\n" {
+		t.Fatalf("upgrade --all output = %q", got)
+	}
+
+	stdout.Reset()
+	if err := runExtensionUpgrade(cmd, f, &upgradeOptions{Name: "demo", Pin: "v1.1.0"}); err != nil {
+		t.Fatalf("runExtensionUpgrade --pin: %v", err)
+	}
+	if got := stdout.String(); got != "✓ Upgraded demo v1.0.0 → v1.1.0\n" {
+		t.Fatalf("upgrade --pin output = %q", got)
+	}
+	dir := filepath.Join(extensionParentRootForTest(f), "demo")
+	if pin := pinnedRef(t.Context(), dir); pin != "v1.1.0" {
+		t.Fatalf("pin = %q, want v1.1.0", pin)
+	}
+
+	stdout.Reset()
+	if err := runExtensionUpgrade(cmd, f, &upgradeOptions{Name: "demo", Pin: "v1.1.0"}); err != nil {
+		t.Fatalf("runExtensionUpgrade repeat: %v", err)
+	}
+	if got := stdout.String(); got != "✓ demo is already up to date (v1.1.0)\n" {
+		t.Fatalf("repeat upgrade output = %q", got)
+	}
+}
+
+func TestRunExtensionUpgradeRollsBackIncompatibleRelease(t *testing.T) {
+	src := newExtensionSourceRepo(t)
+	releaseExtension(t, src, "1.0.0", "")
+
+	f, stdout, _ := newExtensionTestFactory(t)
+	f.AppVersion = "1.0.0"
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+
+	if err := runExtensionInstall(cmd, f, &installOptions{Repository: src}); err != nil {
+		t.Fatalf("runExtensionInstall: %v", err)
+	}
+	releaseExtension(t, src, "2.0.0", "9.0.0")
+
+	stdout.Reset()
+	err := runExtensionUpgrade(cmd, f, &upgradeOptions{Name: "demo"})
+	if err == nil {
+		t.Fatal("expected upgrade to fail")
+	}
+	want := "extension demo requires bkt 9.0.0 or newer (running 1.0.0); kept the previously installed version"
+	if err.Error() != want {
+		t.Fatalf("error = %q, want %q", err.Error(), want)
+	}
+	dir := filepath.Join(extensionParentRootForTest(f), "demo")
+	if tag := exactTag(t.Context(), dir); tag != "v1.0.0" {
+		t.Fatalf("HEAD tag after rollback = %q, want v1.0.0", tag)
+	}
+}
+
+func TestRunExtensionInstallResolvesRepoReferenceThroughHost(t *testing.T) {
+	src := newExtensionSourceRepo(t)
+	releaseExtension(t, src, "1.0.0", "")
+
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"slug": "bkt-demo",
+			"links": map[string]any{
+				"clone": []map[string]string{
+					{"name": "ssh", "href": "ssh://git@example.invalid/plat/bkt-demo.git"},
+					{"name": "http", "href": "file://" + filepath.ToSlash(src)},
+				},
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	f, stdout, _ := newExtensionTestFactory(t)
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	cfg.SetHost("dc.example", &config.Host{Kind: "dc", BaseURL: server.URL, Token: "token"})
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+	if err := runExtensionInstall(cmd, f, &installOptions{Repository: "PLAT/bkt-demo", Host: "dc.example"}); err != nil {
+		t.Fatalf("runExtensionInstall: %v", err)
+	}
+
+	if requested != "/rest/api/1.0/projects/PLAT/repos/bkt-demo" {
+		t.Fatalf("requested %q", requested)
+	}
+	if !strings.Contains(stdout.String(), "✓ Installed extension demo v1.0.0") {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestRunExtensionInstallRejectsHostForURLs(t *testing.T) {
+	f, _, _ := newExtensionTestFactory(t)
+	cmd := &cobra.Command{}
+	cmd.SetContext(t.Context())
+
+	err := runExtensionInstall(cmd, f, &installOptions{Repository: "https://bitbucket.org/team/bkt-lint", Host: "dc.example"})
+	if err == nil || err.Error() != `--host applies only to OWNER/REPO references, not "https://bitbucket.org/team/bkt-lint"` {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// newExtensionSourceRepo creates a Git repository holding a manifest-based
+// "demo" extension whose entrypoint echoes its version and arguments.
+func newExtensionSourceRepo(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("extension source fixtures use POSIX shell entrypoints")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := filepath.Join(t.TempDir(), "bkt-demo-src")
+	runTestGit(t, "", "init", "--quiet", dir)
+	return dir
+}
+
+// releaseExtension commits a manifest for version and tags it v<version>.
+func releaseExtension(t *testing.T, dir, version, minBkt string) {
+	t.Helper()
+
+	manifest := "name: demo\nversion: " + version + "\nentrypoint: bin/bkt-demo\nscopes: [repo:read]\n"
+	if minBkt != "" {
+		manifest += "min_bkt_version: " + minBkt + "\n"
+	}
+	writeTestFile(t, filepath.Join(dir, manifestFile), manifest, 0o644)
+	writeTestFile(t, filepath.Join(dir, "bin", "bkt-demo"), "#!/bin/sh\necho demo "+version+" \"$@\"\n", 0o755)
+	commitExtensionChange(t, dir, "release "+version)
+	runTestGit(t, dir, "tag", "v"+version)
+}
+
+func commitExtensionChange(t *testing.T, dir, message string) {
+	t.Helper()
+	writeTestFile(t, filepath.Join(dir, "CHANGES"), message+"\n", 0o644)
+	runTestGit(t, dir, "add", "-A")
+	runTestGit(t, dir, "commit", "--quiet", "-m", message)
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_AUTHOR_NAME=bkt", "GIT_AUTHOR_EMAIL=bkt@example.com",
+		"GIT_COMMITTER_NAME=bkt", "GIT_COMMITTER_EMAIL=bkt@example.com",
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+func TestSplitContextArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		context string
+		rest    []string
+	}{
+		{args: []string{"--context", "prod", "--fix"}, context: "prod", rest: []string{"--fix"}},
+		{args: []string{"-c", "prod", "run"}, context: "prod", rest: []string{"run"}},
+		{args: []string{"--context=prod"}, context: "prod", rest: []string{}},
+		{args: []string{"-c=prod", "--", "-c", "x"}, context: "prod", rest: []string{"-c", "x"}},
+		{args: []string{"--", "--context", "mine"}, rest: []string{"--context", "mine"}},
+		{args: []string{"run", "-c", "prod"}, rest: []string{"run", "-c", "prod"}},
+		{args: []string{"-check"}, rest: []string{"-check"}},
+	}
+	for _, tt := range tests {
+		gotContext, gotRest := splitContextArgs(tt.args)
+		if gotContext != tt.context || strings.Join(gotRest, " ") != strings.Join(tt.rest, " ") {
+			t.Fatalf("splitContextArgs(%q) = %q, %q; want %q, %q", tt.args, gotContext, gotRest, tt.context, tt.rest)
+		}
+	}
+}
+
+func TestRegisterInstalledAddsTopLevelCommands(t *testing.T) {
+	f, _, _ := newExtensionTestFactory(t)
+	root := extensionParentRootForTest(f)
+	for _, name := range []string{"demo", "repo", "rp", "help", ".cache"} {
+		if err := os.MkdirAll(filepath.Join(root, name), 0o755); err != nil {
+			t.Fatalf("MkdirAll: %v", err)
+		}
+	}
+
+	rootCmd := &cobra.Command{Use: "bkt"}
+	rootCmd.AddCommand(&cobra.Command{Use: "repo", Aliases: []string{"rp"}})
+	RegisterInstalled(rootCmd, f)
+
+	var names []string
+	for _, cmd := range rootCmd.Commands() {
+		names = append(names, cmd.Name())
+	}
+	if got := strings.Join(names, ","); got != "demo,repo" {
+		t.Fatalf("commands = %s, want demo,repo", got)
+	}
+}
+
+func TestExtensionCommandInjectsResolvedContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifest  string
+		wantToken string
+		wantPerms string
+	}{
+		{
+			name:      "legacy extension gets no token",
+			wantToken: "||",
+		},
+		{
+			name:      "token scope gets a short-lived read token",
+			manifest:  "name: demo\nentrypoint: " + helperExecutableName("demo") + "\nscopes: [token]\n",
+			wantToken: "BBDC-minted|bearer|",
+			wantPerms: "PROJECT_READ,REPO_READ",
+		},
+		{
+			name:      "token:write scope may push",
+			manifest:  "name: demo\nentrypoint: " + helperExecutableName("demo") + "\nscopes: [token:write]\n",
+			wantToken: "BBDC-minted|bearer|",
+			wantPerms: "PROJECT_READ,REPO_WRITE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			var created bbdcTokenInput
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, r.Method+" "+r.URL.Path)
+				switch {
+				case r.Method == http.MethodPut && r.URL.Path == "/rest/access-tokens/1.0/users/alice":
+					if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+						t.Errorf("decode: %v", err)
+					}
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = io.WriteString(w, `{"id":"77","name":"bkt extension demo","token":"BBDC-minted"}`)
+				case r.Method == http.MethodDelete && r.URL.Path == "/rest/access-tokens/1.0/users/alice/77":
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			f, stdout, _ := newExtensionContextFactory(t, server.URL)
+			dir := filepath.Join(extensionParentRootForTest(f), "demo")
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatalf("MkdirAll: %v", err)
+			}
+			copyTestBinary(t, filepath.Join(dir, helperExecutableName("demo")))
+			if tt.manifest != "" {
+				writeTestFile(t, filepath.Join(dir, manifestFile), tt.manifest, 0o644)
+			}
+
+			t.Setenv("GO_WANT_EXTENSION_CONTEXT_HELPER", "1")
+			t.Setenv("BKT_REPO", "stale")
+
+			rootCmd := newExtensionTestRoot(f)
+			rootCmd.SetArgs([]string{"--context", "work", "demo", "-test.run=TestExtensionContextHelperProcess"})
+			if err := rootCmd.ExecuteContext(t.Context()); err != nil {
+				t.Fatalf("execute: %v", err)
+			}
+
+			want := "work|dc|" + server.URL + "|PLAT|api|" + tt.wantToken
+			if got := strings.TrimSpace(stdout.String()); got != want {
+				t.Fatalf("extension env = %q, want %q", got, want)
+			}
+			if tt.wantPerms == "" {
+				if len(calls) != 0 {
+					t.Fatalf("unexpected requests %v", calls)
+				}
+				return
+			}
+			if got := strings.Join(created.Permissions, ","); got != tt.wantPerms || created.ExpiryDays != extensionTokenExpiryDays {
+				t.Fatalf("created token = %+v, want %s expiring in %d day", created, tt.wantPerms, extensionTokenExpiryDays)
+			}
+			if len(calls) != 2 || calls[1] != "DELETE /rest/access-tokens/1.0/users/alice/77" {
+				t.Fatalf("requests = %v, want the minted token revoked", calls)
+			}
+		})
+	}
+}
+
+// bbdcTokenInput mirrors the access-token creation body.
+type bbdcTokenInput struct {
+	Permissions []string `json:"permissions"`
+	ExpiryDays  int      `json:"expiryDays"`
+}
+
+func TestExtensionTokenRefusedForCloudWithoutOAuth(t *testing.T) {
+	f, _, _ := newExtensionContextFactory(t, "http://127.0.0.1:1")
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	cfg.SetHost("dc.example", &config.Host{Kind: "cloud", BaseURL: "http://127.0.0.1:1", Username: "alice", Token: "app-password"})
+
+	dir := filepath.Join(extensionParentRootForTest(f), "demo")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	copyTestBinary(t, filepath.Join(dir, helperExecutableName("demo")))
+	writeTestFile(t, filepath.Join(dir, manifestFile), "name: demo\nentrypoint: "+helperExecutableName("demo")+"\nscopes: [token]\n", 0o644)
+
+	t.Setenv("GO_WANT_EXTENSION_CONTEXT_HELPER", "1")
+	rootCmd := newExtensionTestRoot(f)
+	rootCmd.SetArgs([]string{"demo", "-test.run=TestExtensionContextHelperProcess"})
+	err = rootCmd.ExecuteContext(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "only hands out expiring tokens") {
+		t.Fatalf("expected refusal, got %v", err)
+	}
+}
+
+func TestExtensionCommandPropagatesExitCode(t *testing.T) {
+	f, _, _ := newExtensionContextFactory(t, "https://dc.example")
+	dir := filepath.Join(extensionParentRootForTest(f), "demo")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	copyTestBinary(t, filepath.Join(dir, helperExecutableName("demo")))
+
+	t.Setenv("GO_WANT_EXTENSION_CONTEXT_HELPER", "1")
+	t.Setenv("EXTENSION_HELPER_EXIT", "3")
+
+	rootCmd := newExtensionTestRoot(f)
+	rootCmd.SetArgs([]string{"demo", "-test.run=TestExtensionContextHelperProcess"})
+	err := rootCmd.ExecuteContext(t.Context())
+
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("error = %v, want exit code 3", err)
+	}
+}
+
+func TestExtensionContextHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_EXTENSION_CONTEXT_HELPER") != "1" {
+		return
+	}
+
+	fmt.Println(strings.Join([]string{
+		os.Getenv("BKT_CONTEXT"),
+		os.Getenv("BKT_HOST_KIND"),
+		os.Getenv("BKT_BASE_URL"),
+		os.Getenv("BKT_PROJECT"),
+		os.Getenv("BKT_REPO"),
+		os.Getenv("BKT_TOKEN"),
+		os.Getenv("BKT_AUTH_METHOD"),
+		os.Getenv("BKT_USERNAME"),
+	}, "|"))
+	if code := os.Getenv("EXTENSION_HELPER_EXIT"); code != "" {
+		n, _ := strconv.Atoi(code)
+		os.Exit(n)
+	}
+	os.Exit(0)
+}
+
+// newExtensionContextFactory returns a test factory whose config holds a
+// "work" Data Center context (the active one) at baseURL with a stored token.
+func newExtensionContextFactory(t *testing.T, baseURL string) (*cmdutil.Factory, *strings.Builder, *strings.Builder) {
+	t.Helper()
+
+	f, stdout, stderr := newExtensionTestFactory(t)
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		t.Fatalf("ResolveConfig: %v", err)
+	}
+	cfg.SetHost("dc.example", &config.Host{Kind: "dc", BaseURL: baseURL, Username: "alice", Token: "pat"})
+	cfg.SetContext("work", &config.Context{Host: "dc.example", ProjectKey: "PLAT", DefaultRepo: "api"})
+	if err := cfg.SetActiveContext("work"); err != nil {
+		t.Fatalf("SetActiveContext: %v", err)
+	}
+	return f, stdout, stderr
+}
+
+// newExtensionTestRoot mirrors the root command's persistent --context flag
+// and registers the installed extensions on it.
+func newExtensionTestRoot(f *cmdutil.Factory) *cobra.Command {
+	rootCmd := &cobra.Command{Use: "bkt", SilenceUsage: true, SilenceErrors: true}
+	rootCmd.PersistentFlags().StringP("context", "c", "", "")
+	RegisterInstalled(rootCmd, f)
+	return rootCmd
+}
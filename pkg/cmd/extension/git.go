@@ -0,0 +1,108 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// pinConfigKey stores the pinned tag in the extension clone's git config.
+const pinConfigKey = "bkt.pin"
+
+// gitRun runs git inside dir, streaming its output to errOut so progress and
+// failures stay visible without polluting stdout.
+func gitRun(ctx context.Context, errOut io.Writer, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = errOut
+	cmd.Stderr = errOut
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return nil
+}
+
+// gitOutput runs git inside dir and returns its trimmed stdout. Credential
+// prompts are disabled so read-only queries never block on a terminal.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// isGitCheckout reports whether dir was installed from a Git repository.
+func isGitCheckout(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// gitCheckout detaches the working tree at ref.
+func gitCheckout(ctx context.Context, errOut io.Writer, dir, ref string) error {
+	return gitRun(ctx, errOut, dir, "checkout", "--quiet", ref, "--")
+}
+
+// localTags lists the tags known to the local clone.
+func localTags(ctx context.Context, dir string) ([]string, error) {
+	out, err := gitOutput(ctx, dir, "tag", "--list")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
+
+// remoteTags lists the tags published on origin without fetching them.
+func remoteTags(ctx context.Context, dir string) ([]string, error) {
+	out, err := gitOutput(ctx, dir, "ls-remote", "--tags", "--refs", "origin")
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, line := range strings.Split(out, "\n") {
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		tags = append(tags, strings.TrimPrefix(ref, "refs/tags/"))
+	}
+	return tags, nil
+}
+
+// exactTag returns the tag HEAD sits on, or "" when HEAD is untagged.
+func exactTag(ctx context.Context, dir string) string {
+	tag, err := gitOutput(ctx, dir, "describe", "--tags", "--exact-match", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return tag
+}
+
+// shortCommit returns the abbreviated HEAD commit hash.
+func shortCommit(ctx context.Context, dir string) string {
+	sha, err := gitOutput(ctx, dir, "rev-parse", "--short", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return sha
+}
+
+// pinnedRef returns the tag recorded by `install --pin` or `upgrade --pin`.
+func pinnedRef(ctx context.Context, dir string) string {
+	pin, err := gitOutput(ctx, dir, "config", "--local", "--get", pinConfigKey)
+	if err != nil {
+		return ""
+	}
+	return pin
+}
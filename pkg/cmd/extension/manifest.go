@@ -0,0 +1,199 @@
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFile is the optional manifest at the root of an extension repository.
+const manifestFile = "extension.yaml"
+
+var scopePattern = regexp.MustCompile(`^[a-z][a-z0-9_:.-]*$`)
+
+// Manifest describes an extension. Extensions without a manifest keep the
+// legacy behaviour of running the first bkt-<name> executable found.
+type Manifest struct {
+	Name          string   `yaml:"name" json:"name"`
+	Version       string   `yaml:"version,omitempty" json:"version,omitempty"`
+	MinBktVersion string   `yaml:"min_bkt_version,omitempty" json:"min_bkt_version,omitempty"`
+	Entrypoint    string   `yaml:"entrypoint" json:"entrypoint"`
+	Scopes        []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+}
+
+// loadManifest reads and validates dir/extension.yaml. It returns nil without
+// error when the extension ships no manifest.
+func loadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", manifestFile, err)
+	}
+	if err := m.validate(dir); err != nil {
+		return nil, fmt.Errorf("%s: %w", manifestFile, err)
+	}
+	return &m, nil
+}
+
+func (m *Manifest) validate(dir string) error {
+	m.Name = strings.TrimSpace(m.Name)
+	if err := validateExtensionName(m.Name); err != nil {
+		return err
+	}
+	if m.Version != "" {
+		if _, ok := parseVersion(m.Version); !ok {
+			return fmt.Errorf("version %q is not a semantic version", m.Version)
+		}
+	}
+	if m.MinBktVersion != "" {
+		if _, ok := parseVersion(m.MinBktVersion); !ok {
+			return fmt.Errorf("min_bkt_version %q is not a semantic version", m.MinBktVersion)
+		}
+	}
+
+	entry := strings.TrimSpace(m.Entrypoint)
+	if entry == "" {
+		return fmt.Errorf("entrypoint is required")
+	}
+	clean := filepath.Clean(filepath.FromSlash(entry))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("entrypoint %q must be a path inside the extension", m.Entrypoint)
+	}
+	info, err := os.Stat(filepath.Join(dir, clean))
+	if err != nil {
+		return fmt.Errorf("entrypoint %q: %w", m.Entrypoint, err)
+	}
+	if !isExecutableInfo(info) {
+		return fmt.Errorf("entrypoint %q is not executable", m.Entrypoint)
+	}
+	m.Entrypoint = filepath.ToSlash(clean)
+
+	for _, scope := range m.Scopes {
+		if !scopePattern.MatchString(scope) {
+			return fmt.Errorf("invalid scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// entrypointPath returns the absolute path of the manifest entrypoint.
+func (m *Manifest) entrypointPath(dir string) string {
+	return filepath.Join(dir, filepath.FromSlash(m.Entrypoint))
+}
+
+// requests reports whether the manifest declares scope. Legacy extensions
+// without a manifest request nothing.
+func (m *Manifest) requests(scope string) bool {
+	if m == nil {
+		return false
+	}
+	for _, s := range m.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCompatible reports an error when the running bkt is older than the
+// manifest's min_bkt_version. Development builds are always accepted.
+func (m *Manifest) checkCompatible(bktVersion string) error {
+	if m == nil || m.MinBktVersion == "" {
+		return nil
+	}
+	current, ok := parseVersion(bktVersion)
+	if !ok {
+		return nil
+	}
+	required, _ := parseVersion(m.MinBktVersion)
+	if current.compare(required) < 0 {
+		return fmt.Errorf("extension %s requires bkt %s or newer (running %s)", m.Name, m.MinBktVersion, bktVersion)
+	}
+	return nil
+}
+
+// semver is the numeric core of a version plus whether it carries a
+// pre-release suffix, which sorts before the matching release.
+type semver struct {
+	parts      [3]int
+	prerelease bool
+}
+
+// parseVersion accepts "1", "1.2", "1.2.3" with an optional "v" prefix and
+// an optional "-pre" or "+build" suffix.
+func parseVersion(s string) (semver, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if s == "" {
+		return semver{}, false
+	}
+	var v semver
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.prerelease = true
+		s = s[:i]
+	}
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 {
+		return semver{}, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		v.parts[i] = n
+	}
+	return v, true
+}
+
+func (v semver) compare(other semver) int {
+	for i := range v.parts {
+		switch {
+		case v.parts[i] < other.parts[i]:
+			return -1
+		case v.parts[i] > other.parts[i]:
+			return 1
+		}
+	}
+	switch {
+	case v.prerelease && !other.prerelease:
+		return -1
+	case !v.prerelease && other.prerelease:
+		return 1
+	}
+	return 0
+}
+
+// latestVersionTag returns the highest version-shaped release tag, or ""
+// when none of the tags parse as a version. Pre-releases are never picked.
+func latestVersionTag(tags []string) string {
+	var (
+		best    string
+		bestVer semver
+	)
+	for _, tag := range tags {
+		v, ok := parseVersion(tag)
+		if !ok || v.prerelease {
+			continue
+		}
+		if best == "" || v.compare(bestVer) > 0 {
+			best, bestVer = tag, v
+		}
+	}
+	return best
+}
@@ -0,0 +1,130 @@
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseVersionOrdering(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "v1.2.3", b: "1.2.3", want: 0},
+		{a: "1.2", b: "1.2.0", want: 0},
+		{a: "1.10.0", b: "1.9.9", want: 1},
+		{a: "v2", b: "v1.99.99", want: 1},
+		{a: "1.0.0-rc1", b: "1.0.0", want: -1},
+		{a: "1.0.0+build5", b: "1.0.0", want: 0},
+	}
+	for _, tt := range tests {
+		a, ok := parseVersion(tt.a)
+		if !ok {
+			t.Fatalf("parseVersion(%q) failed", tt.a)
+		}
+		b, ok := parseVersion(tt.b)
+		if !ok {
+			t.Fatalf("parseVersion(%q) failed", tt.b)
+		}
+		if got := a.compare(b); got != tt.want {
+			t.Fatalf("compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "dev", "latest", "1.2.3.4", "v1.x"} {
+		if _, ok := parseVersion(bad); ok {
+			t.Fatalf("parseVersion(%q) accepted an invalid version", bad)
+		}
+	}
+}
+
+func TestLatestVersionTagSkipsPrereleasesAndNonVersions(t *testing.T) {
+	got := latestVersionTag([]string{"v1.2.0", "nightly", "v1.10.0", "v2.0.0-rc1", "1.9.0"})
+	if got != "v1.10.0" {
+		t.Fatalf("latestVersionTag = %q, want v1.10.0", got)
+	}
+	if got := latestVersionTag([]string{"nightly"}); got != "" {
+		t.Fatalf("latestVersionTag = %q, want empty", got)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("entrypoint executability relies on POSIX file modes")
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{name: "valid", manifest: "name: demo\nversion: 1.2.0\nmin_bkt_version: 0.30.0\nentrypoint: bin/bkt-demo\nscopes: [repo:read, token]\n"},
+		{name: "missing entrypoint", manifest: "name: demo\n", wantErr: "entrypoint is required"},
+		{name: "escaping entrypoint", manifest: "name: demo\nentrypoint: ../bkt-demo\n", wantErr: "must be a path inside the extension"},
+		{name: "non-executable entrypoint", manifest: "name: demo\nentrypoint: README.md\n", wantErr: "is not executable"},
+		{name: "bad name", manifest: "name: ../demo\nentrypoint: bin/bkt-demo\n", wantErr: "invalid extension name"},
+		{name: "bad version", manifest: "name: demo\nversion: latest\nentrypoint: bin/bkt-demo\n", wantErr: `version "latest" is not a semantic version`},
+		{name: "bad scope", manifest: "name: demo\nentrypoint: bin/bkt-demo\nscopes: [\"Repo Write\"]\n", wantErr: `invalid scope "Repo Write"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestFile(t, filepath.Join(dir, "bin", "bkt-demo"), "#!/bin/sh\n", 0o755)
+			writeTestFile(t, filepath.Join(dir, "README.md"), "demo\n", 0o644)
+			writeTestFile(t, filepath.Join(dir, manifestFile), tt.manifest, 0o644)
+
+			m, err := loadManifest(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("loadManifest error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadManifest: %v", err)
+			}
+			if m.Name != "demo" || m.Entrypoint != "bin/bkt-demo" || len(m.Scopes) != 2 {
+				t.Fatalf("unexpected manifest: %+v", m)
+			}
+			if got := m.entrypointPath(dir); got != filepath.Join(dir, "bin", "bkt-demo") {
+				t.Fatalf("entrypointPath = %q", got)
+			}
+		})
+	}
+}
+
+func TestLoadManifestAbsentIsLegacyExtension(t *testing.T) {
+	m, err := loadManifest(t.TempDir())
+	if err != nil || m != nil {
+		t.Fatalf("loadManifest = %+v, %v; want nil, nil", m, err)
+	}
+}
+
+func TestManifestCheckCompatible(t *testing.T) {
+	m := &Manifest{Name: "demo", MinBktVersion: "0.32.0"}
+
+	for _, version := range []string{"0.32.0", "v0.33.1", "1.0.0", "dev", ""} {
+		if err := m.checkCompatible(version); err != nil {
+			t.Fatalf("checkCompatible(%q): %v", version, err)
+		}
+	}
+
+	err := m.checkCompatible("0.31.1")
+	if err == nil || err.Error() != "extension demo requires bkt 0.32.0 or newer (running 0.31.1)" {
+		t.Fatalf("checkCompatible(0.31.1) = %v", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("WriteFile(%s): %v", path, err)
+	}
+}
@@ -133,7 +133,7 @@ func runAttachmentList(cmd *cobra.Command, f *cmdutil.Factory, opts *attachmentL
 		Attachments: summaries,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(summaries) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No attachments on issue #%d.\n", issueID)
 			return err
@@ -566,7 +566,7 @@ func runAttachmentDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *attachmen
 		Deleted:  true,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, r, func() error {
+	return cmdutil.WriteOutput(cmd, ios, r, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Deleted attachment %q from issue #%d\n", filename, issueID)
 		return err
 	})
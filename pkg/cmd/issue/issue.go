@@ -198,7 +198,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		Issues:     summaries,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(summaries) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No issues found in %s/%s.\n", workspace, repoSlug)
 			return err
@@ -377,7 +377,7 @@ func runView(cmd *cobra.Command, f *cmdutil.Factory, opts *viewOptions, issueID
 		}
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, details, func() error {
+	return cmdutil.WriteOutput(cmd, ios, details, func() error {
 		if _, err := fmt.Fprintf(ios.Out, "#%d: %s\n", details.ID, details.Title); err != nil {
 			return err
 		}
@@ -562,7 +562,7 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		URL:   issue.Links.HTML.Href,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, result, func() error {
+	return cmdutil.WriteOutput(cmd, ios, result, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Created issue #%d: %s\n%s\n",
 			result.ID, result.Title, result.URL)
 		return err
@@ -736,7 +736,7 @@ func runEdit(cmd *cobra.Command, f *cmdutil.Factory, opts *editOptions, issueID
 		URL:      issue.Links.HTML.Href,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, result, func() error {
+	return cmdutil.WriteOutput(cmd, ios, result, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Updated issue #%d: %s [%s]\n%s\n",
 			result.ID, result.Title, result.State, result.URL)
 		return err
@@ -866,7 +866,7 @@ func runStateChange(cmd *cobra.Command, f *cmdutil.Factory, workspace, repo stri
 		Action: action,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, r, func() error {
+	return cmdutil.WriteOutput(cmd, ios, r, func() error {
 		_, err := fmt.Fprintf(ios.Out, "%s issue #%d: %s\n", action, r.ID, r.Title)
 		return err
 	})
@@ -985,7 +985,7 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions, issu
 		Deleted: true,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, r, func() error {
+	return cmdutil.WriteOutput(cmd, ios, r, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Deleted issue #%d: %s\n", r.ID, r.Title)
 		return err
 	})
@@ -1106,7 +1106,7 @@ func runComment(cmd *cobra.Command, f *cmdutil.Factory, opts *commentOptions, is
 			Comments: summaries,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(summaries) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No comments on issue #%d.\n", issueID)
 				return err
@@ -1146,7 +1146,7 @@ func runComment(cmd *cobra.Command, f *cmdutil.Factory, opts *commentOptions, is
 		CreatedOn: comment.CreatedOn,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, r, func() error {
+	return cmdutil.WriteOutput(cmd, ios, r, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Added comment to issue #%d\n", issueID)
 		return err
 	})
@@ -1302,7 +1302,7 @@ func runStatus(cmd *cobra.Command, f *cmdutil.Factory, opts *statusOptions) erro
 		RecentlyUpdated: toSummary(filteredRecent),
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if _, err := fmt.Fprintf(ios.Out, "Issues for @%s in %s/%s\n\n", user.Username, workspace, repoSlug); err != nil {
 			return err
 		}
@@ -2,7 +2,16 @@
 package mcp
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -10,6 +19,7 @@ import (
 
 	"github.com/avivsinai/bitbucket-cli/internal/mcpserver"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
 )
 
 // NewCmdMCP groups MCP-related commands.
@@ -29,72 +39,291 @@ func newServeCmd(f *cmdutil.Factory) *cobra.Command {
 	return newServeCmdWithTransport(f, nil)
 }
 
+// tokenEnv lets operators pin the HTTP bearer token instead of generating a
+// fresh one on every start.
+const tokenEnv = "BKT_MCP_TOKEN"
+
+type serveOptions struct {
+	Contexts    []string
+	AllContexts bool
+	AllowWrites []string
+	AuditLog    string
+	HTTP        string
+}
+
 // newServeCmdWithTransport lets tests drive the full serve path (context
 // resolution, banner, server run) over an injected transport; nil means the
 // real stdio transport.
 func newServeCmdWithTransport(f *cmdutil.Factory, transport sdk.Transport) *cobra.Command {
+	opts := &serveOptions{}
 	cmd := &cobra.Command{
 		Use:   "serve",
-		Short: "Serve Bitbucket tools over MCP stdio (read-only)",
+		Short: "Serve Bitbucket tools over MCP stdio or HTTP (read-only by default)",
 		Long: `Start a Model Context Protocol server speaking JSON-RPC over stdio.
 
-The server pins ONE bkt context, resolved once at startup: --context selects
-a named context, otherwise the active context is used. The working directory
-never influences the served target, and configuration changes require a
-restart. Tool calls cannot switch hosts, contexts, or tokens.
+The server pins its contexts once at startup: --context selects a named
+context and may be repeated, --all-contexts serves every configured context,
+and otherwise the active context is used. The first --context (or, with
+--all-contexts, the active context) is the default. Every tool, resource, and
+prompt accepts a context argument naming one of the served contexts; calls
+without it go to the default. Each context keeps its own host and
+credentials, and a call can never reach a context that was not served. The
+working directory never influences the served targets, and configuration
+changes require a restart.
+
+For a Cloud OAuth context, an expired access token is refreshed the same way
+as for other bkt commands: the new token is written back to the credential
+store and used for the rest of the session, so long sessions survive expiry.
+If the refresh fails (for example, the OAuth consumer variables are unset),
+tool calls return auth_failed until you re-authenticate with bkt auth login.
 
-For a Cloud OAuth context, the access token is frozen at startup and is not
-refreshed from the credential store. After it expires, tool calls return
-auth_failed until the MCP server is restarted.
+By default the server is read-only. Read tools cover repositories, pull
+requests, branches, commit diffs, and, on Cloud, pipelines and issues; tools
+for features the pinned platform lacks return unsupported_on_platform. Call
+bkt_get_context to discover the target and capabilities.
 
-v1 is read-only and registers tools only for capabilities the pinned platform
-supports; call bkt_get_context to discover the target and capabilities.
+Resources expose the same data for direct attachment: bkt://pr/{scope}/{slug}/{id}
+(details plus diff), .../{id}/checks, and bkt://repo/{scope}/{slug}/file/{path}@{ref}.
+The review_pull_request and summarize_failing_checks prompts embed them.
+
+--allow-writes opts into write tools by gate: comment (add PR comments),
+approve (approve PRs), create-pr (open PRs), and resolve (resolve or reopen
+comment threads). Write tools carry honest destructiveHint/idempotentHint
+annotations, and every call is appended to a JSON-lines audit log before it
+reaches Bitbucket (default: mcp-audit.log next to config.yml; override with
+--audit-log). A call is refused if its audit record cannot be written.
 
 stdout carries only MCP protocol messages; all diagnostics go to stderr.
 
+--http ADDR serves the same pinned contexts over the streamable HTTP transport
+instead, so several clients can share one server. The MCP endpoint is /mcp;
+/healthz answers liveness probes and /inventory lists the served contexts and
+tools. Every route except /healthz requires "Authorization: Bearer <token>".
+The token is generated at startup and printed to stderr, unless BKT_MCP_TOKEN
+supplies one. A bare port or ":port" binds to 127.0.0.1; bind another
+interface only on trusted networks.
+
 Register with an MCP client, e.g. for Claude Code:
   claude mcp add bitbucket -- bkt mcp serve`,
 		Example: `  # Serve the active context
   bkt mcp serve
 
   # Serve a specific named context
-  bkt mcp serve --context work-dc`,
+  bkt mcp serve --context work-dc
+
+  # Serve two contexts; work-dc is the default
+  bkt mcp serve --context work-dc --context oss-cloud
+
+  # Serve every configured context
+  bkt mcp serve --all-contexts
+
+  # Let the agent comment on and approve pull requests
+  bkt mcp serve --allow-writes=comment,approve
+
+  # Share one server over HTTP on localhost:8765
+  bkt mcp serve --http :8765`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runServe(cmd, f, transport)
+			return runServe(cmd, f, opts, transport)
 		},
 	}
 
+	// Shadows the root --context flag so it can be repeated.
+	cmd.Flags().StringArrayVarP(&opts.Contexts, "context", "c", nil, "Serve this named context; repeat to serve several (the first is the default)")
+	cmd.Flags().BoolVar(&opts.AllContexts, "all-contexts", false, "Serve every configured context, defaulting to the active one")
+	cmd.MarkFlagsMutuallyExclusive("context", "all-contexts")
+	cmd.Flags().StringSliceVar(&opts.AllowWrites, "allow-writes", nil, "Enable write tool gates: comment, approve, create-pr, resolve")
+	cmd.Flags().StringVar(&opts.HTTP, "http", "", "Serve streamable HTTP on this address (e.g. :8765) instead of stdio")
+	cmd.Flags().StringVar(&opts.AuditLog, "audit-log", "", "Append write tool audit records to this file (default: mcp-audit.log in the config directory)")
+
 	return cmd
 }
 
-func runServe(cmd *cobra.Command, f *cmdutil.Factory, transport sdk.Transport) error {
+func runServe(cmd *cobra.Command, f *cmdutil.Factory, opts *serveOptions, transport sdk.Transport) error {
 	ios, err := f.Streams()
 	if err != nil {
 		return err
 	}
 
-	snap, err := mcpserver.ResolveSnapshot(f, cmdutil.FlagValue(cmd, "context"))
+	gates, err := mcpserver.ParseWriteGates(opts.AllowWrites)
+	if err != nil {
+		return err
+	}
+
+	snaps, err := mcpserver.ResolveSnapshots(f, opts.Contexts, opts.AllContexts)
 	if err != nil {
 		return err
 	}
 
-	server, err := mcpserver.New(snap, f.AppVersion)
+	serverOpts := mcpserver.Options{AllowWrites: gates}
+	mode := "read-only"
+	if len(gates) > 0 {
+		auditPath, err := resolveAuditLogPath(f, opts.AuditLog)
+		if err != nil {
+			return err
+		}
+		auditFile, err := os.OpenFile(auditPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("open MCP audit log: %w", err)
+		}
+		defer func() { _ = auditFile.Close() }()
+		serverOpts.AuditLog = auditFile
+
+		names := make([]string, 0, len(gates))
+		for _, gate := range gates {
+			names = append(names, string(gate))
+		}
+		mode = fmt.Sprintf("writes: %s; audit log %s", strings.Join(names, ","), auditPath)
+	}
+
+	server, err := mcpserver.New(snaps, f.AppVersion, serverOpts)
 	if err != nil {
 		return err
 	}
 
+	var httpServe func() error
+	if opts.HTTP != "" {
+		httpServe, err = prepareHTTP(cmd, ios, server, snaps, serverOpts, opts.HTTP)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Startup banner goes to stderr after construction succeeds: stdout is
 	// reserved for the protocol, and a failed server never claims to be running.
-	label := snap.ContextName
-	if label == "" {
-		label = "(env)"
+	fmt.Fprintf(ios.ErrOut, "bkt mcp serve: %s (%s; Ctrl-C to stop)\n", describeContexts(snaps), mode)
+
+	if httpServe != nil {
+		return httpServe()
 	}
-	fmt.Fprintf(ios.ErrOut, "bkt mcp serve: context %s, platform %s, host %s (read-only; Ctrl-C to stop)\n",
-		label, snap.Platform, snap.HostLabel)
 
 	if transport == nil {
 		transport = &sdk.StdioTransport{}
 	}
 	return server.Run(cmd.Context(), transport)
 }
+
+// describeContexts renders the served contexts for the startup banner; the
+// default context comes first.
+func describeContexts(snaps []*mcpserver.Snapshot) string {
+	if len(snaps) == 1 {
+		snap := snaps[0]
+		label := snap.ContextName
+		if label == "" {
+			label = "(env)"
+		}
+		return fmt.Sprintf("context %s, platform %s, host %s", label, snap.Platform, snap.HostLabel)
+	}
+	parts := make([]string, 0, len(snaps))
+	for i, snap := range snaps {
+		part := fmt.Sprintf("%s [%s, host %s]", snap.ContextName, snap.Platform, snap.HostLabel)
+		if i == 0 {
+			part += " (default)"
+		}
+		parts = append(parts, part)
+	}
+	return fmt.Sprintf("%d contexts: %s", len(snaps), strings.Join(parts, ", "))
+}
+
+// prepareHTTP binds the listener and returns a function that serves until
+// the command context ends. Binding happens before the banner so a port
+// conflict never reports a running server.
+func prepareHTTP(cmd *cobra.Command, ios *iostreams.IOStreams, server *sdk.Server, snaps []*mcpserver.Snapshot, serverOpts mcpserver.Options, rawAddr string) (func() error, error) {
+	addr, err := listenAddress(rawAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	token := strings.TrimSpace(os.Getenv(tokenEnv))
+	generated := token == ""
+	if generated {
+		if token, err = mcpserver.GenerateHTTPToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	handler, err := mcpserver.NewHTTPHandler(server, snaps, serverOpts, token)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", addr, err)
+	}
+
+	return func() error {
+		url := "http://" + listener.Addr().String() + mcpserver.HTTPEndpoint
+		fmt.Fprintf(ios.ErrOut, "Listening on %s\n", url)
+		if generated {
+			fmt.Fprintf(ios.ErrOut, "Bearer token: %s\n", token)
+		} else {
+			fmt.Fprintf(ios.ErrOut, "Bearer token: from %s\n", tokenEnv)
+		}
+		if !isLoopback(listener.Addr()) {
+			fmt.Fprintln(ios.ErrOut, "warning: listening on a non-loopback address; anyone who can reach it with the token acts with your Bitbucket credentials")
+		}
+
+		httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+		ctx := cmd.Context()
+		errCh := make(chan error, 1)
+		go func() { errCh <- httpServer.Serve(listener) }()
+
+		select {
+		case err := <-errCh:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = httpServer.Shutdown(shutdownCtx)
+			return nil
+		}
+	}, nil
+}
+
+// listenAddress defaults the host to 127.0.0.1 so a bare port or ":port"
+// never exposes the server beyond this machine.
+func listenAddress(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("--http requires an address such as :8765")
+	}
+	if !strings.Contains(raw, ":") {
+		raw = ":" + raw
+	}
+	host, port, err := net.SplitHostPort(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid --http address %q: %w", raw, err)
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("invalid --http port %q", port)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
+
+// resolveAuditLogPath returns the explicit --audit-log path or the default
+// beside config.yml.
+func resolveAuditLogPath(f *cmdutil.Factory, explicit string) (string, error) {
+	if explicit = strings.TrimSpace(explicit); explicit != "" {
+		return explicit, nil
+	}
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg.Path() == "" {
+		return "", fmt.Errorf("cannot locate the config directory for the MCP audit log; pass --audit-log")
+	}
+	return filepath.Join(filepath.Dir(cfg.Path()), "mcp-audit.log"), nil
+}
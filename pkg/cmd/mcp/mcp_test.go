@@ -6,6 +6,9 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -162,15 +165,181 @@ func TestServeCommandDoesNotPrintRunningBannerBeforeBackendConstruction(t *testi
 	}
 }
 
-func TestServeCommandDocumentsFrozenCloudOAuthCredential(t *testing.T) {
+func TestServeCommandDocumentsCloudOAuthRefresh(t *testing.T) {
 	cmd := newServeCmdWithTransport(&cmdutil.Factory{}, nil)
-	for _, want := range []string{"Cloud OAuth", "frozen at startup", "auth_failed", "restarted"} {
+	for _, want := range []string{"Cloud OAuth", "credential", "store", "auth_failed", "bkt auth login"} {
 		if !strings.Contains(cmd.Long, want) {
 			t.Fatalf("serve help missing %q:\n%s", want, cmd.Long)
 		}
 	}
 }
 
+func TestServeCommandAllowWritesOpensAuditLog(t *testing.T) {
+	cfg := &config.Config{
+		ActiveContext: "work",
+		Contexts: map[string]*config.Context{
+			"work": {Host: "dc-host", ProjectKey: "PROJ"},
+		},
+		Hosts: map[string]*config.Host{
+			"dc-host": {Kind: "dc", BaseURL: "https://bitbucket.example.com", Username: "u", Token: "t"},
+		},
+	}
+	var stderr bytes.Buffer
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: &failIfWritten{t: t}, ErrOut: &stderr},
+		Config:         func() (*config.Config, error) { return cfg, nil },
+	}
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+
+	// An immediately-closed stdin ends the session right after startup.
+	cmd := newServeCmdWithTransport(f, &sdk.IOTransport{Reader: io.NopCloser(strings.NewReader("")), Writer: nopWriteCloser{io.Discard}})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--allow-writes", "comment,approve", "--audit-log", auditPath})
+	_ = cmd.ExecuteContext(context.Background())
+
+	if !strings.Contains(stderr.String(), "writes: comment,approve; audit log "+auditPath) {
+		t.Fatalf("banner does not announce write gates: %q", stderr.String())
+	}
+	info, err := os.Stat(auditPath)
+	if err != nil {
+		t.Fatalf("audit log not created: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Fatalf("audit log mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestServeCommandServesRepeatedContexts(t *testing.T) {
+	cfg := &config.Config{
+		ActiveContext: "work",
+		Contexts: map[string]*config.Context{
+			"work": {Host: "dc-host", ProjectKey: "PROJ"},
+			"oss":  {Host: "cloud-host", Workspace: "team"},
+		},
+		Hosts: map[string]*config.Host{
+			"dc-host":    {Kind: "dc", BaseURL: "https://bitbucket.example.com", Username: "u", Token: "t"},
+			"cloud-host": {Kind: "cloud", BaseURL: "https://api.bitbucket.org/2.0", Username: "u", Token: "t"},
+		},
+	}
+	var stderr bytes.Buffer
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: &failIfWritten{t: t}, ErrOut: &stderr},
+		Config:         func() (*config.Config, error) { return cfg, nil },
+	}
+
+	cmd := newServeCmdWithTransport(f, &sdk.IOTransport{Reader: io.NopCloser(strings.NewReader("")), Writer: nopWriteCloser{io.Discard}})
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--context", "oss", "-c", "work"})
+	_ = cmd.ExecuteContext(context.Background())
+
+	want := "bkt mcp serve: 2 contexts: oss [cloud, host cloud-host] (default), work [dc, host dc-host]"
+	if !strings.Contains(stderr.String(), want) {
+		t.Fatalf("banner = %q, want %q", stderr.String(), want)
+	}
+
+	cmd = newServeCmdWithTransport(f, nil)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--context", "oss", "--all-contexts"})
+	if err := cmd.ExecuteContext(context.Background()); err == nil || !strings.Contains(err.Error(), "all-contexts") {
+		t.Fatalf("err = %v, want --context and --all-contexts to be mutually exclusive", err)
+	}
+}
+
+func TestServeCommandRejectsUnknownWriteGate(t *testing.T) {
+	var stderr bytes.Buffer
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: &failIfWritten{t: t}, ErrOut: &stderr},
+		Config:         func() (*config.Config, error) { return &config.Config{}, nil },
+	}
+	cmd := newServeCmdWithTransport(f, nil)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--allow-writes", "merge"})
+	err := cmd.ExecuteContext(context.Background())
+	if err == nil || !strings.Contains(err.Error(), `unknown write gate "merge"`) {
+		t.Fatalf("err = %v, want unknown write gate", err)
+	}
+}
+
+func TestListenAddressDefaultsToLoopback(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: ":8765", want: "127.0.0.1:8765"},
+		{in: "8765", want: "127.0.0.1:8765"},
+		{in: "localhost:9000", want: "localhost:9000"},
+		{in: "0.0.0.0:9000", want: "0.0.0.0:9000"},
+		{in: ":http", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := listenAddress(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("listenAddress(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("listenAddress(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestServeCommandHTTPStartsAndStopsWithContext(t *testing.T) {
+	t.Setenv(tokenEnv, "pinned-token")
+	cfg := &config.Config{
+		ActiveContext: "work",
+		Contexts: map[string]*config.Context{
+			"work": {Host: "dc-host", ProjectKey: "PROJ"},
+		},
+		Hosts: map[string]*config.Host{
+			"dc-host": {Kind: "dc", BaseURL: "https://bitbucket.example.com", Username: "u", Token: "t"},
+		},
+	}
+	var stderr bytes.Buffer
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: &failIfWritten{t: t}, ErrOut: &stderr},
+		Config:         func() (*config.Config, error) { return cfg, nil },
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	cmd := newServeCmdWithTransport(f, nil)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--http", "127.0.0.1:0"})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("serve --http: %v", err)
+	}
+
+	out := stderr.String()
+	for _, want := range []string{"Listening on http://127.0.0.1:", "/mcp", "Bearer token: from BKT_MCP_TOKEN"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("stderr missing %q: %q", want, out)
+		}
+	}
+	if strings.Contains(out, "pinned-token") || strings.Contains(out, "non-loopback") {
+		t.Fatalf("stderr leaked the pinned token or warned on loopback: %q", out)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
 // failIfWritten fails the test on any write: the factory's stdout stream must
 // never be used by the serve path — protocol frames flow only through the
 // transport, and diagnostics only through stderr.
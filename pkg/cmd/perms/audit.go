@@ -0,0 +1,471 @@
+package perms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
+)
+
+// auditStepTimeout bounds listing the project and auditing each repository,
+// so a large project is not cut off by one deadline for the whole matrix.
+const auditStepTimeout = 60 * time.Second
+
+type auditOptions struct {
+	Project   string
+	Workspace string
+}
+
+func newAuditCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &auditOptions{}
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show effective access across a project's repositories",
+		Long: `Print an effective-access matrix: one row per user, one column per
+repository in the project, and the highest permission (read, write, or admin)
+the user holds on each repository.
+
+On Data Center, project and repository grants to users and groups are
+combined, and group grants are expanded to the group's members. If the token
+cannot read a group's membership, the group is shown as its own "group:" row.
+Global and default project permissions are not included.
+
+On Cloud, project and repository grants to users and groups are combined and
+workspace owners are shown with admin on every repository. Bitbucket Cloud does
+not expose group membership, so group grants are shown as "group:" rows.`,
+		Example: `  # Audit a Data Center project
+  bkt perms audit --project MYPROJ
+
+  # Audit a Cloud project in a specific workspace
+  bkt perms audit --workspace my-team --project PLAT
+
+  # Export the matrix as JSON
+  bkt perms audit --project MYPROJ --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAudit(cmd, f, opts)
+		},
+	}
+	cmd.Flags().StringVar(&opts.Project, "project", "", "Project key to audit (required)")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket Cloud workspace (defaults to context)")
+	_ = cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+// accessLevel orders repository access so grants can be combined with max.
+type accessLevel int
+
+const (
+	accessNone accessLevel = iota
+	accessRead
+	accessWrite
+	accessAdmin
+)
+
+func (l accessLevel) String() string {
+	switch l {
+	case accessRead:
+		return "read"
+	case accessWrite:
+		return "write"
+	case accessAdmin:
+		return "admin"
+	}
+	return "-"
+}
+
+// parseAccessLevel maps Data Center PROJECT_*/REPO_* permissions and Cloud
+// read/write/create-repo/admin permissions onto repository access.
+func parseAccessLevel(perm string) accessLevel {
+	p := strings.ToLower(strings.TrimSpace(perm))
+	if i := strings.LastIndex(p, "_"); i >= 0 {
+		p = p[i+1:]
+	}
+	switch p {
+	case "read":
+		return accessRead
+	case "write", "create-repo":
+		return accessWrite
+	case "admin":
+		return accessAdmin
+	}
+	return accessNone
+}
+
+type auditPrincipal struct {
+	key    string
+	label  string
+	group  bool
+	access map[string]accessLevel
+}
+
+// auditMatrix accumulates the highest access each principal holds on each
+// repository.
+type auditMatrix struct {
+	repos      []string
+	principals map[string]*auditPrincipal
+	unexpanded map[string]bool
+}
+
+func newAuditMatrix(repos []string) *auditMatrix {
+	return &auditMatrix{
+		repos:      repos,
+		principals: make(map[string]*auditPrincipal),
+		unexpanded: make(map[string]bool),
+	}
+}
+
+// grant records level for a principal on repo, or on every repository when
+// repo is empty.
+func (m *auditMatrix) grant(key, label string, group bool, repo string, level accessLevel) {
+	if level == accessNone || key == "" {
+		return
+	}
+	id := key
+	if group {
+		id = "group:" + key
+	}
+	p, ok := m.principals[id]
+	if !ok {
+		p = &auditPrincipal{key: key, label: cmdutil.FirstNonEmpty(label, key), group: group, access: make(map[string]accessLevel)}
+		m.principals[id] = p
+	}
+	targets := m.repos
+	if repo != "" {
+		targets = []string{repo}
+	}
+	for _, r := range targets {
+		if level > p.access[r] {
+			p.access[r] = level
+		}
+	}
+}
+
+// sorted returns users ordered by label, followed by groups.
+func (m *auditMatrix) sorted() []*auditPrincipal {
+	out := make([]*auditPrincipal, 0, len(m.principals))
+	for _, p := range m.principals {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].group != out[j].group {
+			return !out[i].group
+		}
+		return strings.ToLower(out[i].label) < strings.ToLower(out[j].label)
+	})
+	return out
+}
+
+func runAudit(cmd *cobra.Command, f *cmdutil.Factory, opts *auditOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	override := cmdutil.FlagValue(cmd, "context")
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	payload := map[string]any{"project": opts.Project}
+	var matrix *auditMatrix
+
+	switch host.Kind {
+	case "dc":
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		matrix, err = auditDataCenter(ctx, client, opts.Project)
+		if err != nil {
+			return err
+		}
+
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		if workspace == "" {
+			return fmt.Errorf("context must supply workspace; use --workspace")
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		matrix, err = auditCloud(ctx, client, workspace, opts.Project)
+		if err != nil {
+			return err
+		}
+		payload["workspace"] = workspace
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+
+	type auditEntry struct {
+		Principal string            `json:"principal"`
+		Name      string            `json:"name"`
+		Type      string            `json:"type"`
+		Access    map[string]string `json:"access"`
+	}
+
+	rows := matrix.sorted()
+	entries := make([]auditEntry, 0, len(rows))
+	for _, p := range rows {
+		entry := auditEntry{Principal: p.key, Name: p.label, Type: "user", Access: make(map[string]string)}
+		if p.group {
+			entry.Type = "group"
+		}
+		for repo, level := range p.access {
+			entry.Access[repo] = level.String()
+		}
+		entries = append(entries, entry)
+	}
+
+	var unexpanded []string
+	for g := range matrix.unexpanded {
+		unexpanded = append(unexpanded, g)
+	}
+	sort.Strings(unexpanded)
+
+	payload["repos"] = matrix.repos
+	payload["entries"] = entries
+	if len(unexpanded) > 0 {
+		payload["unexpanded_groups"] = unexpanded
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		if len(matrix.repos) == 0 {
+			_, err := fmt.Fprintf(ios.Out, "No repositories found in project %s.\n", opts.Project)
+			return err
+		}
+		if len(rows) == 0 {
+			_, err := fmt.Fprintf(ios.Out, "No permissions found across %d repositories in project %s.\n", len(matrix.repos), opts.Project)
+			return err
+		}
+
+		labels := make([]string, len(rows))
+		nameWidth := len("USER")
+		for i, p := range rows {
+			labels[i] = p.label
+			if p.group {
+				labels[i] = "group:" + p.label
+			}
+			nameWidth = max(nameWidth, len(labels[i]))
+		}
+		widths := make([]int, len(matrix.repos))
+		for i, repo := range matrix.repos {
+			widths[i] = max(len(repo), len("admin"))
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%-*s", nameWidth, "USER")
+		for i, repo := range matrix.repos {
+			fmt.Fprintf(&b, "  %-*s", widths[i], repo)
+		}
+		if _, err := fmt.Fprintln(ios.Out, strings.TrimRight(b.String(), " ")); err != nil {
+			return err
+		}
+		for i, p := range rows {
+			b.Reset()
+			fmt.Fprintf(&b, "%-*s", nameWidth, labels[i])
+			for j, repo := range matrix.repos {
+				fmt.Fprintf(&b, "  %-*s", widths[j], p.access[repo].String())
+			}
+			if _, err := fmt.Fprintln(ios.Out, strings.TrimRight(b.String(), " ")); err != nil {
+				return err
+			}
+		}
+		if len(unexpanded) > 0 {
+			if _, err := fmt.Fprintf(ios.Out, "\nMembership of %s could not be read; those grants are shown as group rows.\n", strings.Join(unexpanded, ", ")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func auditDataCenter(ctx context.Context, client *bbdc.Client, projectKey string) (*auditMatrix, error) {
+	listCtx, cancel := context.WithTimeout(ctx, auditStepTimeout)
+	defer cancel()
+
+	repos, err := client.ListRepositories(listCtx, projectKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	slugs := make([]string, 0, len(repos))
+	for _, r := range repos {
+		slugs = append(slugs, r.Slug)
+	}
+	m := newAuditMatrix(slugs)
+
+	members := make(map[string][]bbdc.User)
+	// grantGroup expands a group grant to its members. A group whose
+	// membership the token may not read is kept as a group row; any other
+	// failure stops the audit.
+	grantGroup := func(ctx context.Context, group, repo string, level accessLevel) error {
+		users, ok := members[group]
+		if !ok && !m.unexpanded[group] {
+			var err error
+			users, err = client.ListGroupMembers(ctx, group, 0)
+			switch {
+			case err == nil:
+				members[group] = users
+			case isAccessDenied(err):
+				m.unexpanded[group] = true
+			default:
+				return fmt.Errorf("group %s: %w", group, err)
+			}
+		}
+		if m.unexpanded[group] {
+			m.grant(group, group, true, repo, level)
+			return nil
+		}
+		for _, u := range users {
+			m.grant(u.Name, u.Name, false, repo, level)
+		}
+		return nil
+	}
+
+	users, err := client.ListProjectPermissions(listCtx, projectKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range users {
+		m.grant(p.User.Name, p.User.Name, false, "", parseAccessLevel(p.Permission))
+	}
+	groups, err := client.ListProjectGroupPermissions(listCtx, projectKey, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		if err := grantGroup(listCtx, g.Group.Name, "", parseAccessLevel(g.Permission)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, slug := range slugs {
+		err := func() error {
+			ctx, cancel := context.WithTimeout(ctx, auditStepTimeout)
+			defer cancel()
+
+			users, err := client.ListRepoPermissions(ctx, projectKey, slug, 0)
+			if err != nil {
+				return err
+			}
+			for _, p := range users {
+				m.grant(p.User.Name, p.User.Name, false, slug, parseAccessLevel(p.Permission))
+			}
+			groups, err := client.ListRepoGroupPermissions(ctx, projectKey, slug, 0)
+			if err != nil {
+				return err
+			}
+			for _, g := range groups {
+				if err := grantGroup(ctx, g.Group.Name, slug, parseAccessLevel(g.Permission)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", projectKey, slug, err)
+		}
+	}
+	return m, nil
+}
+
+// isAccessDenied reports whether err is a 403 or 404 response, which is how
+// Data Center answers when the token may not read a group's members.
+func isAccessDenied(err error) bool {
+	var httpErr *httpx.HTTPError
+	return errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusNotFound)
+}
+
+func auditCloud(ctx context.Context, client *bbcloud.Client, workspace, projectKey string) (*auditMatrix, error) {
+	listCtx, cancel := context.WithTimeout(ctx, auditStepTimeout)
+	defer cancel()
+
+	var slugs []string
+	err := client.EachRepository(listCtx, workspace, 0, func(repo bbcloud.Repository) error {
+		if strings.EqualFold(repo.Project.Key, projectKey) {
+			slugs = append(slugs, repo.Slug)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m := newAuditMatrix(slugs)
+	if len(slugs) == 0 {
+		return m, nil
+	}
+
+	userKey := func(u bbcloud.User) (string, string) {
+		return cmdutil.FirstNonEmpty(u.AccountID, u.UUID), cmdutil.FirstNonEmpty(u.Display, u.Nickname, u.Username)
+	}
+	grantUsers := func(perms []bbcloud.UserPermission, repo string) {
+		for _, p := range perms {
+			key, label := userKey(p.User)
+			m.grant(key, label, false, repo, parseAccessLevel(p.Permission))
+		}
+	}
+	grantGroups := func(perms []bbcloud.GroupPermission, repo string) {
+		for _, g := range perms {
+			m.grant(cmdutil.FirstNonEmpty(g.Group.Slug, g.Group.Name), g.Group.Slug, true, repo, parseAccessLevel(g.Permission))
+		}
+	}
+
+	members, err := client.ListWorkspaceMembers(listCtx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	for _, mem := range members {
+		if strings.EqualFold(mem.Permission, "owner") {
+			key, label := userKey(mem.User)
+			m.grant(key, label, false, "", accessAdmin)
+		}
+	}
+
+	users, err := client.ListProjectUserPermissions(listCtx, workspace, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	grantUsers(users, "")
+	groups, err := client.ListProjectGroupPermissions(listCtx, workspace, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	grantGroups(groups, "")
+
+	for _, slug := range slugs {
+		err := func() error {
+			ctx, cancel := context.WithTimeout(ctx, auditStepTimeout)
+			defer cancel()
+
+			users, err := client.ListRepositoryUserPermissions(ctx, workspace, slug)
+			if err != nil {
+				return err
+			}
+			grantUsers(users, slug)
+			groups, err := client.ListRepositoryGroupPermissions(ctx, workspace, slug)
+			if err != nil {
+				return err
+			}
+			grantGroups(groups, slug)
+			return nil
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", workspace, slug, err)
+		}
+	}
+	return m, nil
+}
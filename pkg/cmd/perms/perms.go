@@ -15,26 +15,34 @@ import (
 func NewCommand(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "perms",
-		Short: "Manage Bitbucket permissions (DC only)",
-		Long: `Manage user permissions at the project and repository level on Bitbucket Data Center.
+		Short: "Manage Bitbucket permissions",
+		Long: `Manage user and group permissions on Bitbucket.
 
-Grant, revoke, and list permissions for individual users. Project-level
-permissions apply to all repositories within that project, while repository-level
-permissions override the project defaults for a specific repository.
+On Data Center, grant, revoke, and list permissions for users and groups at
+the project and repository level. Project-level permissions apply to all
+repositories within that project, while repository-level permissions override
+the project defaults for a specific repository.
 
-This command group is available for Data Center contexts only.`,
+On Cloud, grant, revoke, and list explicit repository permissions for users
+and groups, and list workspace members. "audit" reports the effective access
+of every user across the repositories of a project on either platform.`,
 		Example: `  # List who has access to a project
   bkt perms project list --project MYPROJ
 
   # Grant a user write access to a specific repository
   bkt perms repo grant --project MYPROJ --repo my-service --user jdoe --perm REPO_WRITE
 
-  # Revoke a user's project-level permission
-  bkt perms project revoke --project MYPROJ --user jdoe`,
+  # Grant a group admin access to a project
+  bkt perms project grant --project MYPROJ --group platform-admins --perm PROJECT_ADMIN
+
+  # Show who can do what across a project's repositories
+  bkt perms audit --project MYPROJ`,
 	}
 
 	cmd.AddCommand(newProjectCmd(f))
 	cmd.AddCommand(newRepoCmd(f))
+	cmd.AddCommand(newWorkspaceCmd(f))
+	cmd.AddCommand(newAuditCmd(f))
 
 	return cmd
 }
@@ -47,12 +55,14 @@ type projectListOptions struct {
 type projectGrantOptions struct {
 	Project    string
 	Username   string
+	Group      string
 	Permission string
 }
 
 type projectRevokeOptions struct {
 	Project  string
 	Username string
+	Group    string
 }
 
 func newProjectCmd(f *cmdutil.Factory) *cobra.Command {
@@ -62,15 +72,18 @@ func newProjectCmd(f *cmdutil.Factory) *cobra.Command {
 		Long: `Manage project-level permissions on Bitbucket Data Center.
 
 Project permissions control default access for all repositories within a project.
-You can list current permission entries, grant a permission level to a user, or
-revoke a user's project permission entirely. Valid permission levels are
-PROJECT_READ, PROJECT_WRITE, and PROJECT_ADMIN.`,
-		Example: `  # List all users with permissions on a project
+You can list current permission entries, grant a permission level to a user or
+group, or revoke a user's or group's project permission entirely. Valid
+permission levels are PROJECT_READ, PROJECT_WRITE, and PROJECT_ADMIN.`,
+		Example: `  # List all users and groups with permissions on a project
   bkt perms project list --project MYPROJ
 
   # Grant admin access to a user
   bkt perms project grant --project MYPROJ --user jdoe --perm PROJECT_ADMIN
 
+  # Grant write access to a group
+  bkt perms project grant --project MYPROJ --group developers --perm PROJECT_WRITE
+
   # Revoke a user's project permission
   bkt perms project revoke --project MYPROJ --user jdoe`,
 	}
@@ -81,9 +94,10 @@ PROJECT_READ, PROJECT_WRITE, and PROJECT_ADMIN.`,
 		Short: "List project permissions (DC only)",
 		Long: `List the permission entries for a Bitbucket Data Center project.
 
-Displays each user who has been granted explicit access to the project along
-with their permission level (PROJECT_READ, PROJECT_WRITE, or PROJECT_ADMIN).
-Use --limit to control how many entries are returned; set it to 0 to fetch all.`,
+Displays each user and group that has been granted explicit access to the
+project along with their permission level (PROJECT_READ, PROJECT_WRITE, or
+PROJECT_ADMIN). Groups are shown with a "group:" prefix. Use --limit to control
+how many entries of each kind are returned; set it to 0 to fetch all.`,
 		Example: `  # List permissions for a project
   bkt perms project list --project MYPROJ
 
@@ -104,42 +118,47 @@ Use --limit to control how many entries are returned; set it to 0 to fetch all.`
 	grant := &cobra.Command{
 		Use:   "grant",
 		Short: "Grant project permissions (DC only)",
-		Long: `Grant a permission level to a user on a Bitbucket Data Center project.
+		Long: `Grant a permission level to a user or group on a Bitbucket Data Center project.
 
-The user receives the specified permission for the project and inherits it
-across all repositories within that project unless overridden at the repository
-level. Valid values for --perm are PROJECT_READ, PROJECT_WRITE, and
-PROJECT_ADMIN. If --perm is omitted it defaults to PROJECT_READ.`,
+The user or group receives the specified permission for the project and
+inherits it across all repositories within that project unless overridden at
+the repository level. Valid values for --perm are PROJECT_READ, PROJECT_WRITE,
+and PROJECT_ADMIN. If --perm is omitted it defaults to PROJECT_READ.`,
 		Example: `  # Grant read access (default)
   bkt perms project grant --project MYPROJ --user jdoe
 
   # Grant write access
   bkt perms project grant --project MYPROJ --user jdoe --perm PROJECT_WRITE
 
-  # Grant admin access
-  bkt perms project grant --project MYPROJ --user jdoe --perm PROJECT_ADMIN`,
+  # Grant admin access to a group
+  bkt perms project grant --project MYPROJ --group platform-admins --perm PROJECT_ADMIN`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runProjectGrant(cmd, f, grantOpts)
 		},
 	}
 	grant.Flags().StringVar(&grantOpts.Project, "project", "", "Bitbucket project key (required)")
-	grant.Flags().StringVar(&grantOpts.Username, "user", "", "Username to grant (required)")
+	grant.Flags().StringVar(&grantOpts.Username, "user", "", "Username to grant")
+	grant.Flags().StringVar(&grantOpts.Group, "group", "", "Group name to grant")
 	grant.Flags().StringVar(&grantOpts.Permission, "perm", "PROJECT_READ", "Permission (PROJECT_READ, PROJECT_WRITE, PROJECT_ADMIN)")
 	_ = grant.MarkFlagRequired("project")
-	_ = grant.MarkFlagRequired("user")
+	markPrincipalFlags(grant)
 
 	revokeOpts := &projectRevokeOptions{}
 	revoke := &cobra.Command{
 		Use:   "revoke",
 		Short: "Revoke project permissions (DC only)",
-		Long: `Revoke a user's permission on a Bitbucket Data Center project.
+		Long: `Revoke a user's or group's permission on a Bitbucket Data Center project.
 
-Removes the explicit project-level permission entry for the specified user.
-After revocation the user loses access granted at the project level, though
-they may still have access through repository-level or global permissions.`,
+Removes the explicit project-level permission entry for the specified user or
+group. After revocation the user loses access granted at the project level,
+though they may still have access through repository-level, group, or global
+permissions.`,
 		Example: `  # Revoke a user's project permission
   bkt perms project revoke --project MYPROJ --user jdoe
 
+  # Revoke a group's project permission
+  bkt perms project revoke --project MYPROJ --group contractors
+
   # Revoke using a different context
   bkt perms project revoke --project MYPROJ --user jdoe --context my-dc`,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -147,49 +166,63 @@ they may still have access through repository-level or global permissions.`,
 		},
 	}
 	revoke.Flags().StringVar(&revokeOpts.Project, "project", "", "Bitbucket project key (required)")
-	revoke.Flags().StringVar(&revokeOpts.Username, "user", "", "Username to revoke (required)")
+	revoke.Flags().StringVar(&revokeOpts.Username, "user", "", "Username to revoke")
+	revoke.Flags().StringVar(&revokeOpts.Group, "group", "", "Group name to revoke")
 	_ = revoke.MarkFlagRequired("project")
-	_ = revoke.MarkFlagRequired("user")
+	markPrincipalFlags(revoke)
 
 	cmd.AddCommand(list, grant, revoke)
 	return cmd
 }
 
 type repoListOptions struct {
-	Project string
-	Repo    string
-	Limit   int
+	Workspace string
+	Project   string
+	Repo      string
+	Limit     int
 }
 
 type repoGrantOptions struct {
+	Workspace  string
 	Project    string
 	Repo       string
 	Username   string
+	Group      string
 	Permission string
 }
 
 type repoRevokeOptions struct {
-	Project  string
-	Repo     string
-	Username string
+	Workspace string
+	Project   string
+	Repo      string
+	Username  string
+	Group     string
 }
 
 func newRepoCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "repo",
-		Short: "Manage repository-level permissions (DC only)",
-		Long: `Manage repository-level permissions on Bitbucket Data Center.
-
-Repository permissions override the project defaults for a specific repository.
-You can list current permission entries, grant a permission level to a user, or
-revoke a user's repository permission entirely. Valid permission levels are
-REPO_READ, REPO_WRITE, and REPO_ADMIN.`,
-		Example: `  # List permissions on a repository
+		Short: "Manage repository-level permissions",
+		Long: `Manage repository-level permissions.
+
+On Data Center, repository permissions override the project defaults for a
+specific repository. Valid permission levels are REPO_READ, REPO_WRITE, and
+REPO_ADMIN. Users are identified by username and groups by name.
+
+On Cloud, these are the repository's explicit user and group permissions.
+Valid levels are read, write, and admin (REPO_READ and friends are accepted
+too). Users are identified by account ID or UUID and groups by slug.
+
+The project or workspace defaults to the active context.`,
+		Example: `  # List permissions on a Data Center repository
   bkt perms repo list --project MYPROJ --repo my-service
 
   # Grant write access to a user
   bkt perms repo grant --project MYPROJ --repo my-service --user jdoe --perm REPO_WRITE
 
+  # Grant a Cloud workspace group admin access
+  bkt perms repo grant --workspace my-team --repo my-service --group release-managers --perm admin
+
   # Revoke a user's repository permission
   bkt perms repo revoke --project MYPROJ --repo my-service --user jdoe`,
 	}
@@ -197,89 +230,120 @@ REPO_READ, REPO_WRITE, and REPO_ADMIN.`,
 	listOpts := &repoListOptions{Limit: 100}
 	list := &cobra.Command{
 		Use:   "list",
-		Short: "List repository permissions (DC only)",
-		Long: `List the permission entries for a Bitbucket Data Center repository.
+		Short: "List repository permissions",
+		Long: `List the explicit permission entries for a repository.
 
-Displays each user who has been granted explicit access to the repository along
-with their permission level (REPO_READ, REPO_WRITE, or REPO_ADMIN). Use --limit
-to control how many entries are returned; set it to 0 to fetch all.`,
+Displays each user and group that has been granted explicit access to the
+repository along with their permission level. Groups are shown with a "group:"
+prefix. On Data Center, use --limit to control how many entries of each kind
+are returned; set it to 0 to fetch all. Cloud always returns every entry.`,
 		Example: `  # List permissions for a repository
   bkt perms repo list --project MYPROJ --repo my-service
 
   # Fetch all permission entries
   bkt perms repo list --project MYPROJ --repo my-service --limit 0
 
-  # Output as JSON
-  bkt perms repo list --project MYPROJ --repo my-service --output json`,
+  # List permissions for a Cloud repository as JSON
+  bkt perms repo list --workspace my-team --repo my-service --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRepoList(cmd, f, listOpts)
 		},
 	}
-	list.Flags().StringVar(&listOpts.Project, "project", "", "Bitbucket project key (required)")
-	list.Flags().StringVar(&listOpts.Repo, "repo", "", "Repository slug (required)")
-	list.Flags().IntVar(&listOpts.Limit, "limit", listOpts.Limit, "Maximum entries to display (0 for all)")
-	_ = list.MarkFlagRequired("project")
-	_ = list.MarkFlagRequired("repo")
+	addRepoTargetFlags(list, &listOpts.Workspace, &listOpts.Project, &listOpts.Repo)
+	list.Flags().IntVar(&listOpts.Limit, "limit", listOpts.Limit, "Maximum entries to display (0 for all, Data Center only)")
 
 	grantOpts := &repoGrantOptions{}
 	grant := &cobra.Command{
 		Use:   "grant",
-		Short: "Grant repository permissions (DC only)",
-		Long: `Grant a permission level to a user on a Bitbucket Data Center repository.
-
-The user receives the specified permission for the repository, overriding any
-project-level permission they may already have. Valid values for --perm are
-REPO_READ, REPO_WRITE, and REPO_ADMIN. If --perm is omitted it defaults to
-REPO_READ.`,
+		Short: "Grant repository permissions",
+		Long: `Grant a permission level to a user or group on a repository.
+
+On Data Center the user or group receives the specified permission for the
+repository, overriding any project-level permission they may already have.
+Valid values for --perm are REPO_READ, REPO_WRITE, and REPO_ADMIN. On Cloud
+the values are read, write, and admin, and an existing grant is replaced. If
+--perm is omitted it defaults to read access.`,
 		Example: `  # Grant read access (default)
   bkt perms repo grant --project MYPROJ --repo my-service --user jdoe
 
-  # Grant write access
-  bkt perms repo grant --project MYPROJ --repo my-service --user jdoe --perm REPO_WRITE
+  # Grant write access to a group
+  bkt perms repo grant --project MYPROJ --repo my-service --group developers --perm REPO_WRITE
 
-  # Grant admin access
-  bkt perms repo grant --project MYPROJ --repo my-service --user jdoe --perm REPO_ADMIN`,
+  # Grant a Cloud user admin access by account ID
+  bkt perms repo grant --workspace my-team --repo my-service --user 557058:1b2c... --perm admin`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRepoGrant(cmd, f, grantOpts)
 		},
 	}
-	grant.Flags().StringVar(&grantOpts.Project, "project", "", "Bitbucket project key (required)")
-	grant.Flags().StringVar(&grantOpts.Repo, "repo", "", "Repository slug (required)")
-	grant.Flags().StringVar(&grantOpts.Username, "user", "", "Username to grant (required)")
-	grant.Flags().StringVar(&grantOpts.Permission, "perm", "REPO_READ", "Permission (REPO_READ, REPO_WRITE, REPO_ADMIN)")
-	_ = grant.MarkFlagRequired("project")
-	_ = grant.MarkFlagRequired("repo")
-	_ = grant.MarkFlagRequired("user")
+	addRepoTargetFlags(grant, &grantOpts.Workspace, &grantOpts.Project, &grantOpts.Repo)
+	grant.Flags().StringVar(&grantOpts.Username, "user", "", "User to grant (username on Data Center, account ID or UUID on Cloud)")
+	grant.Flags().StringVar(&grantOpts.Group, "group", "", "Group to grant (name on Data Center, slug on Cloud)")
+	grant.Flags().StringVar(&grantOpts.Permission, "perm", "REPO_READ", "Permission (REPO_READ, REPO_WRITE, REPO_ADMIN; read, write, admin on Cloud)")
+	markPrincipalFlags(grant)
 
 	revokeOpts := &repoRevokeOptions{}
 	revoke := &cobra.Command{
 		Use:   "revoke",
-		Short: "Revoke repository permissions (DC only)",
-		Long: `Revoke a user's permission on a Bitbucket Data Center repository.
+		Short: "Revoke repository permissions",
+		Long: `Revoke a user's or group's explicit permission on a repository.
 
-Removes the explicit repository-level permission entry for the specified user.
-After revocation the user may still have access through project-level or global
-permissions.`,
+Removes the explicit repository-level permission entry for the specified user
+or group. After revocation the user may still have access through
+project-level, group, workspace, or global permissions.`,
 		Example: `  # Revoke a user's repository permission
   bkt perms repo revoke --project MYPROJ --repo my-service --user jdoe
 
+  # Revoke a Cloud group's repository permission
+  bkt perms repo revoke --workspace my-team --repo my-service --group contractors
+
   # Revoke using a different context
   bkt perms repo revoke --project MYPROJ --repo my-service --user jdoe --context my-dc`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runRepoRevoke(cmd, f, revokeOpts)
 		},
 	}
-	revoke.Flags().StringVar(&revokeOpts.Project, "project", "", "Bitbucket project key (required)")
-	revoke.Flags().StringVar(&revokeOpts.Repo, "repo", "", "Repository slug (required)")
-	revoke.Flags().StringVar(&revokeOpts.Username, "user", "", "Username to revoke (required)")
-	_ = revoke.MarkFlagRequired("project")
-	_ = revoke.MarkFlagRequired("repo")
-	_ = revoke.MarkFlagRequired("user")
+	addRepoTargetFlags(revoke, &revokeOpts.Workspace, &revokeOpts.Project, &revokeOpts.Repo)
+	revoke.Flags().StringVar(&revokeOpts.Username, "user", "", "User to revoke (username on Data Center, account ID or UUID on Cloud)")
+	revoke.Flags().StringVar(&revokeOpts.Group, "group", "", "Group to revoke (name on Data Center, slug on Cloud)")
+	markPrincipalFlags(revoke)
 
 	cmd.AddCommand(list, grant, revoke)
 	return cmd
 }
 
+func addRepoTargetFlags(cmd *cobra.Command, workspace, project, repo *string) {
+	cmd.Flags().StringVar(workspace, "workspace", "", "Bitbucket Cloud workspace (defaults to context)")
+	cmd.Flags().StringVar(project, "project", "", "Bitbucket Data Center project key (defaults to context)")
+	cmd.Flags().StringVar(repo, "repo", "", "Repository slug (required)")
+	_ = cmd.MarkFlagRequired("repo")
+}
+
+// markPrincipalFlags requires exactly one of --user and --group.
+func markPrincipalFlags(cmd *cobra.Command) {
+	cmd.MarkFlagsOneRequired("user", "group")
+	cmd.MarkFlagsMutuallyExclusive("user", "group")
+}
+
+// principalLabel describes a grant target in confirmation messages.
+func principalLabel(username, group string) string {
+	if group != "" {
+		return "group " + group
+	}
+	return username
+}
+
+// cloudRepoPermission maps a --perm value onto Cloud's read, write, and admin
+// levels, accepting the Data Center REPO_* spellings as well.
+func cloudRepoPermission(perm string) (string, error) {
+	p := strings.ToLower(strings.TrimSpace(perm))
+	p = strings.TrimPrefix(p, "repo_")
+	switch p {
+	case "read", "write", "admin":
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid permission %q for Bitbucket Cloud (expected read, write, or admin)", perm)
+}
+
 func runProjectList(cmd *cobra.Command, f *cmdutil.Factory, opts *projectListOptions) error {
 	ios, err := f.Streams()
 	if err != nil {
@@ -307,19 +371,29 @@ func runProjectList(cmd *cobra.Command, f *cmdutil.Factory, opts *projectListOpt
 	if err != nil {
 		return err
 	}
+	groups, err := client.ListProjectGroupPermissions(ctx, opts.Project, opts.Limit)
+	if err != nil {
+		return err
+	}
 
 	payload := map[string]any{
 		"project":     opts.Project,
 		"permissions": perms,
+		"groups":      groups,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		for _, p := range perms {
 			if _, err := fmt.Fprintf(ios.Out, "%s\t%s\n", cmdutil.FirstNonEmpty(p.User.FullName, p.User.Name), p.Permission); err != nil {
 				return err
 			}
 		}
-		if len(perms) == 0 {
+		for _, g := range groups {
+			if _, err := fmt.Fprintf(ios.Out, "group:%s\t%s\n", g.Group.Name, g.Permission); err != nil {
+				return err
+			}
+		}
+		if len(perms) == 0 && len(groups) == 0 {
 			if _, err := fmt.Fprintln(ios.Out, "No permissions found."); err != nil {
 				return err
 			}
@@ -351,11 +425,16 @@ func runProjectGrant(cmd *cobra.Command, f *cmdutil.Factory, opts *projectGrantO
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := client.GrantProjectPermission(ctx, opts.Project, opts.Username, opts.Permission); err != nil {
+	if opts.Group != "" {
+		err = client.GrantProjectGroupPermission(ctx, opts.Project, opts.Group, opts.Permission)
+	} else {
+		err = client.GrantProjectPermission(ctx, opts.Project, opts.Username, opts.Permission)
+	}
+	if err != nil {
 		return err
 	}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Granted %s on project %s to %s\n", strings.ToUpper(opts.Permission), opts.Project, opts.Username); err != nil {
+	if _, err := fmt.Fprintf(ios.Out, "✓ Granted %s on project %s to %s\n", strings.ToUpper(opts.Permission), opts.Project, principalLabel(opts.Username, opts.Group)); err != nil {
 		return err
 	}
 	return nil
@@ -384,11 +463,16 @@ func runProjectRevoke(cmd *cobra.Command, f *cmdutil.Factory, opts *projectRevok
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := client.RevokeProjectPermission(ctx, opts.Project, opts.Username); err != nil {
+	if opts.Group != "" {
+		err = client.RevokeProjectGroupPermission(ctx, opts.Project, opts.Group)
+	} else {
+		err = client.RevokeProjectPermission(ctx, opts.Project, opts.Username)
+	}
+	if err != nil {
 		return err
 	}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Revoked project permission for %s on %s\n", opts.Username, opts.Project); err != nil {
+	if _, err := fmt.Fprintf(ios.Out, "✓ Revoked project permission for %s on %s\n", principalLabel(opts.Username, opts.Group), opts.Project); err != nil {
 		return err
 	}
 	return nil
@@ -401,15 +485,7 @@ func runRepoList(cmd *cobra.Command, f *cmdutil.Factory, opts *repoListOptions)
 	}
 
 	override := cmdutil.FlagValue(cmd, "context")
-	_, _, host, err := cmdutil.ResolveContext(f, cmd, override)
-	if err != nil {
-		return err
-	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("perms repo list currently supports Data Center contexts only")
-	}
-
-	client, err := cmdutil.NewDCClient(host)
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
 	if err != nil {
 		return err
 	}
@@ -417,24 +493,93 @@ func runRepoList(cmd *cobra.Command, f *cmdutil.Factory, opts *repoListOptions)
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	perms, err := client.ListRepoPermissions(ctx, opts.Project, opts.Repo, opts.Limit)
-	if err != nil {
-		return err
-	}
+	type entry struct {
+		Name       string
+		Permission string
+	}
+	var (
+		payload map[string]any
+		users   []entry
+		groups  []entry
+	)
+
+	switch host.Kind {
+	case "dc":
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		if projectKey == "" {
+			return fmt.Errorf("context must supply project; use --project")
+		}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		perms, err := client.ListRepoPermissions(ctx, projectKey, opts.Repo, opts.Limit)
+		if err != nil {
+			return err
+		}
+		groupPerms, err := client.ListRepoGroupPermissions(ctx, projectKey, opts.Repo, opts.Limit)
+		if err != nil {
+			return err
+		}
+		for _, p := range perms {
+			users = append(users, entry{cmdutil.FirstNonEmpty(p.User.FullName, p.User.Name), p.Permission})
+		}
+		for _, g := range groupPerms {
+			groups = append(groups, entry{g.Group.Name, g.Permission})
+		}
+		payload = map[string]any{
+			"project":     projectKey,
+			"repo":        opts.Repo,
+			"permissions": perms,
+			"groups":      groupPerms,
+		}
 
-	payload := map[string]any{
-		"project":     opts.Project,
-		"repo":        opts.Repo,
-		"permissions": perms,
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		if workspace == "" {
+			return fmt.Errorf("context must supply workspace; use --workspace")
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		perms, err := client.ListRepositoryUserPermissions(ctx, workspace, opts.Repo)
+		if err != nil {
+			return err
+		}
+		groupPerms, err := client.ListRepositoryGroupPermissions(ctx, workspace, opts.Repo)
+		if err != nil {
+			return err
+		}
+		for _, p := range perms {
+			users = append(users, entry{cmdutil.FirstNonEmpty(p.User.Display, p.User.Nickname, p.User.AccountID), p.Permission})
+		}
+		for _, g := range groupPerms {
+			groups = append(groups, entry{cmdutil.FirstNonEmpty(g.Group.Slug, g.Group.Name), g.Permission})
+		}
+		payload = map[string]any{
+			"workspace":   workspace,
+			"repo":        opts.Repo,
+			"permissions": perms,
+			"groups":      groupPerms,
+		}
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
-		for _, p := range perms {
-			if _, err := fmt.Fprintf(ios.Out, "%s\t%s\n", cmdutil.FirstNonEmpty(p.User.FullName, p.User.Name), p.Permission); err != nil {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		for _, u := range users {
+			if _, err := fmt.Fprintf(ios.Out, "%s\t%s\n", u.Name, u.Permission); err != nil {
+				return err
+			}
+		}
+		for _, g := range groups {
+			if _, err := fmt.Fprintf(ios.Out, "group:%s\t%s\n", g.Name, g.Permission); err != nil {
 				return err
 			}
 		}
-		if len(perms) == 0 {
+		if len(users) == 0 && len(groups) == 0 {
 			if _, err := fmt.Fprintln(ios.Out, "No permissions found."); err != nil {
 				return err
 			}
@@ -450,15 +595,7 @@ func runRepoGrant(cmd *cobra.Command, f *cmdutil.Factory, opts *repoGrantOptions
 	}
 
 	override := cmdutil.FlagValue(cmd, "context")
-	_, _, host, err := cmdutil.ResolveContext(f, cmd, override)
-	if err != nil {
-		return err
-	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("perms repo grant currently supports Data Center contexts only")
-	}
-
-	client, err := cmdutil.NewDCClient(host)
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
 	if err != nil {
 		return err
 	}
@@ -466,11 +603,54 @@ func runRepoGrant(cmd *cobra.Command, f *cmdutil.Factory, opts *repoGrantOptions
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := client.GrantRepoPermission(ctx, opts.Project, opts.Repo, opts.Username, opts.Permission); err != nil {
-		return err
+	var owner, permission string
+	switch host.Kind {
+	case "dc":
+		owner = cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		if owner == "" {
+			return fmt.Errorf("context must supply project; use --project")
+		}
+		permission = strings.ToUpper(opts.Permission)
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		if opts.Group != "" {
+			err = client.GrantRepoGroupPermission(ctx, owner, opts.Repo, opts.Group, permission)
+		} else {
+			err = client.GrantRepoPermission(ctx, owner, opts.Repo, opts.Username, permission)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "cloud":
+		owner = cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		if owner == "" {
+			return fmt.Errorf("context must supply workspace; use --workspace")
+		}
+		permission, err = cloudRepoPermission(opts.Permission)
+		if err != nil {
+			return err
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		if opts.Group != "" {
+			err = client.SetRepositoryGroupPermission(ctx, owner, opts.Repo, opts.Group, permission)
+		} else {
+			err = client.SetRepositoryUserPermission(ctx, owner, opts.Repo, opts.Username, permission)
+		}
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Granted %s on %s/%s to %s\n", strings.ToUpper(opts.Permission), opts.Project, opts.Repo, opts.Username); err != nil {
+	if _, err := fmt.Fprintf(ios.Out, "✓ Granted %s on %s/%s to %s\n", permission, owner, opts.Repo, principalLabel(opts.Username, opts.Group)); err != nil {
 		return err
 	}
 	return nil
@@ -483,15 +663,7 @@ func runRepoRevoke(cmd *cobra.Command, f *cmdutil.Factory, opts *repoRevokeOptio
 	}
 
 	override := cmdutil.FlagValue(cmd, "context")
-	_, _, host, err := cmdutil.ResolveContext(f, cmd, override)
-	if err != nil {
-		return err
-	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("perms repo revoke currently supports Data Center contexts only")
-	}
-
-	client, err := cmdutil.NewDCClient(host)
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
 	if err != nil {
 		return err
 	}
@@ -499,11 +671,49 @@ func runRepoRevoke(cmd *cobra.Command, f *cmdutil.Factory, opts *repoRevokeOptio
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := client.RevokeRepoPermission(ctx, opts.Project, opts.Repo, opts.Username); err != nil {
-		return err
+	var owner string
+	switch host.Kind {
+	case "dc":
+		owner = cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		if owner == "" {
+			return fmt.Errorf("context must supply project; use --project")
+		}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		if opts.Group != "" {
+			err = client.RevokeRepoGroupPermission(ctx, owner, opts.Repo, opts.Group)
+		} else {
+			err = client.RevokeRepoPermission(ctx, owner, opts.Repo, opts.Username)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "cloud":
+		owner = cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		if owner == "" {
+			return fmt.Errorf("context must supply workspace; use --workspace")
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		if opts.Group != "" {
+			err = client.DeleteRepositoryGroupPermission(ctx, owner, opts.Repo, opts.Group)
+		} else {
+			err = client.DeleteRepositoryUserPermission(ctx, owner, opts.Repo, opts.Username)
+		}
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
 
-	if _, err := fmt.Fprintf(ios.Out, "✓ Revoked repository permission for %s on %s/%s\n", opts.Username, opts.Project, opts.Repo); err != nil {
+	if _, err := fmt.Fprintf(ios.Out, "✓ Revoked repository permission for %s on %s/%s\n", principalLabel(opts.Username, opts.Group), owner, opts.Repo); err != nil {
 		return err
 	}
 	return nil
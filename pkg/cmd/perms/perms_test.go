@@ -0,0 +1,253 @@
+package perms
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
+)
+
+func runPerms(t *testing.T, kind, baseURL string, args ...string) (string, error) {
+	t.Helper()
+	cfg := &config.Config{
+		ActiveContext: "default",
+		Contexts: map[string]*config.Context{
+			"default": {Host: "main", ProjectKey: "PROJ", Workspace: "ws"},
+		},
+		Hosts: map[string]*config.Host{
+			"main": {Kind: kind, BaseURL: baseURL, Token: "test-token"},
+		},
+	}
+	stdout := &strings.Builder{}
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: stdout, ErrOut: &strings.Builder{}},
+		Config: func() (*config.Config, error) {
+			return cfg, nil
+		},
+	}
+	root := &cobra.Command{Use: "bkt", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(NewCommand(f))
+	root.SetArgs(append([]string{"perms"}, args...))
+	err := root.Execute()
+	return stdout.String(), err
+}
+
+func writeValues(w http.ResponseWriter, values any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"values": values, "isLastPage": true})
+}
+
+func TestProjectGrantGroupDataCenter(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Method + " " + r.URL.Path + "?" + r.URL.RawQuery
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runPerms(t, "dc", server.URL, "project", "grant", "--project", "PROJ", "--group", "dev team", "--perm", "project_write")
+	if err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	want := "PUT /rest/api/1.0/projects/PROJ/permissions/groups?name=dev+team&permission=PROJECT_WRITE"
+	if got != want {
+		t.Fatalf("request = %q, want %q", got, want)
+	}
+	if !strings.Contains(out, "Granted PROJECT_WRITE on project PROJ to group dev team") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	if _, err := runPerms(t, "dc", server.URL, "project", "grant", "--project", "PROJ", "--user", "jdoe", "--group", "devs"); err == nil {
+		t.Fatal("expected error when both --user and --group are set")
+	}
+}
+
+func TestRepoListDataCenterIncludesGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos/api/permissions/users":
+			writeValues(w, []map[string]any{{"user": map[string]any{"name": "jdoe", "displayName": "Jane Doe"}, "permission": "REPO_WRITE"}})
+		case "/rest/api/1.0/projects/PROJ/repos/api/permissions/groups":
+			writeValues(w, []map[string]any{{"group": map[string]any{"name": "qa"}, "permission": "REPO_READ"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runPerms(t, "dc", server.URL, "repo", "list", "--repo", "api")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if out != "Jane Doe\tREPO_WRITE\ngroup:qa\tREPO_READ\n" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+}
+
+func TestRepoGrantAndRevokeCloud(t *testing.T) {
+	var calls []string
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.EscapedPath())
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runPerms(t, "cloud", server.URL, "repo", "grant", "--repo", "api", "--user", "557058:abc", "--perm", "REPO_ADMIN")
+	if err != nil {
+		t.Fatalf("grant: %v", err)
+	}
+	if calls[0] != "PUT /repositories/ws/api/permissions-config/users/557058:abc" {
+		t.Fatalf("calls = %v", calls)
+	}
+	if body["permission"] != "admin" {
+		t.Fatalf("body = %v", body)
+	}
+	if !strings.Contains(out, "Granted admin on ws/api to 557058:abc") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	if _, err := runPerms(t, "cloud", server.URL, "repo", "revoke", "--repo", "api", "--group", "contractors"); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if calls[1] != "DELETE /repositories/ws/api/permissions-config/groups/contractors" {
+		t.Fatalf("calls = %v", calls)
+	}
+
+	if _, err := runPerms(t, "cloud", server.URL, "repo", "grant", "--repo", "api", "--group", "qa", "--perm", "PROJECT_ADMIN"); err == nil {
+		t.Fatal("expected error for a Data Center project permission on Cloud")
+	}
+}
+
+func TestWorkspaceMembersRejectsDataCenter(t *testing.T) {
+	_, err := runPerms(t, "dc", "http://127.0.0.1:1", "workspace", "members")
+	if err == nil || !strings.Contains(err.Error(), "only supported for Bitbucket Cloud") {
+		t.Fatalf("expected Cloud-only error, got %v", err)
+	}
+}
+
+func TestAuditDataCenterExpandsGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos":
+			writeValues(w, []map[string]any{{"slug": "api"}, {"slug": "web"}})
+		case "/rest/api/1.0/projects/PROJ/permissions/users":
+			writeValues(w, []map[string]any{{"user": map[string]any{"name": "alice"}, "permission": "PROJECT_ADMIN"}})
+		case "/rest/api/1.0/projects/PROJ/permissions/groups":
+			writeValues(w, []map[string]any{{"group": map[string]any{"name": "devs"}, "permission": "PROJECT_READ"}})
+		case "/rest/api/1.0/projects/PROJ/repos/api/permissions/users":
+			writeValues(w, []map[string]any{{"user": map[string]any{"name": "bob"}, "permission": "REPO_WRITE"}})
+		case "/rest/api/1.0/projects/PROJ/repos/api/permissions/groups":
+			writeValues(w, []map[string]any{})
+		case "/rest/api/1.0/projects/PROJ/repos/web/permissions/users":
+			writeValues(w, []map[string]any{})
+		case "/rest/api/1.0/projects/PROJ/repos/web/permissions/groups":
+			writeValues(w, []map[string]any{{"group": map[string]any{"name": "secops"}, "permission": "REPO_ADMIN"}})
+		case "/rest/api/1.0/admin/groups/more-members":
+			if r.URL.Query().Get("context") != "devs" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			writeValues(w, []map[string]any{{"name": "bob"}, {"name": "carol"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runPerms(t, "dc", server.URL, "audit", "--project", "PROJ")
+	if err != nil {
+		t.Fatalf("audit: %v\n%s", err, out)
+	}
+	want := "USER          api    web\n" +
+		"alice         admin  admin\n" +
+		"bob           write  read\n" +
+		"carol         read   read\n" +
+		"group:secops  -      admin\n" +
+		"\nMembership of secops could not be read; those grants are shown as group rows.\n"
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestAuditDataCenterReportsGroupMemberFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos":
+			writeValues(w, []map[string]any{{"slug": "api"}})
+		case "/rest/api/1.0/projects/PROJ/permissions/users",
+			"/rest/api/1.0/projects/PROJ/repos/api/permissions/users",
+			"/rest/api/1.0/projects/PROJ/repos/api/permissions/groups":
+			writeValues(w, []map[string]any{})
+		case "/rest/api/1.0/projects/PROJ/permissions/groups":
+			writeValues(w, []map[string]any{{"group": map[string]any{"name": "devs"}, "permission": "PROJECT_READ"}})
+		case "/rest/api/1.0/admin/groups/more-members":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runPerms(t, "dc", server.URL, "audit", "--project", "PROJ")
+	if err == nil || !strings.Contains(err.Error(), "group devs") {
+		t.Fatalf("expected group membership error, got %v\n%s", err, out)
+	}
+}
+
+func TestAuditCloudCombinesProjectRepoAndOwners(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws":
+			writeValues(w, []map[string]any{
+				{"slug": "api", "project": map[string]any{"key": "PLAT"}},
+				{"slug": "site", "project": map[string]any{"key": "WEB"}},
+			})
+		case "/workspaces/ws/permissions":
+			writeValues(w, []map[string]any{
+				{"permission": "owner", "user": map[string]any{"account_id": "1:owner", "display_name": "Olivia"}},
+				{"permission": "member", "user": map[string]any{"account_id": "1:dev", "display_name": "Dev"}},
+			})
+		case "/workspaces/ws/projects/PLAT/permissions-config/users":
+			writeValues(w, []map[string]any{{"permission": "create-repo", "user": map[string]any{"account_id": "1:dev", "display_name": "Dev"}}})
+		case "/workspaces/ws/projects/PLAT/permissions-config/groups":
+			writeValues(w, []map[string]any{{"permission": "read", "group": map[string]any{"slug": "contractors"}}})
+		case "/repositories/ws/api/permissions-config/users":
+			writeValues(w, []map[string]any{{"permission": "admin", "user": map[string]any{"account_id": "1:dev", "display_name": "Dev"}}})
+		case "/repositories/ws/api/permissions-config/groups":
+			writeValues(w, []map[string]any{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runPerms(t, "cloud", server.URL, "audit", "--project", "PLAT")
+	if err != nil {
+		t.Fatalf("audit: %v\n%s", err, out)
+	}
+	want := "USER               api\n" +
+		"Dev                admin\n" +
+		"Olivia             admin\n" +
+		"group:contractors  read\n"
+	if out != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,106 @@
+package perms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+type workspaceMembersOptions struct {
+	Workspace string
+}
+
+func newWorkspaceCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workspace",
+		Short: "Inspect workspace membership (Cloud only)",
+		Long: `Inspect Bitbucket Cloud workspace membership.
+
+Every workspace member has one of three workspace permissions: owner,
+collaborator, or member. Workspace membership is managed in the Bitbucket
+web UI; the API only exposes it for reading.`,
+		Example: `  # List members of the active workspace
+  bkt perms workspace members
+
+  # List members of another workspace as JSON
+  bkt perms workspace members --workspace my-team --json`,
+	}
+
+	opts := &workspaceMembersOptions{}
+	members := &cobra.Command{
+		Use:   "members",
+		Short: "List workspace members (Cloud only)",
+		Long: `List the members of a Bitbucket Cloud workspace and their workspace
+permission (owner, collaborator, or member). The workspace defaults to the
+active context.`,
+		Example: `  # List members of the active workspace
+  bkt perms workspace members
+
+  # List members of a specific workspace
+  bkt perms workspace members --workspace my-team`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorkspaceMembers(cmd, f, opts)
+		},
+	}
+	members.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket Cloud workspace (defaults to context)")
+
+	cmd.AddCommand(members)
+	return cmd
+}
+
+func runWorkspaceMembers(cmd *cobra.Command, f *cmdutil.Factory, opts *workspaceMembersOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	override := cmdutil.FlagValue(cmd, "context")
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	if err != nil {
+		return err
+	}
+	if host.Kind != "cloud" {
+		return fmt.Errorf("perms workspace members is only supported for Bitbucket Cloud contexts; use perms project list on Data Center")
+	}
+
+	workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+	if workspace == "" {
+		return fmt.Errorf("context must supply workspace; use --workspace")
+	}
+
+	client, err := cmdutil.NewCloudClient(host)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	members, err := client.ListWorkspaceMembers(ctx, workspace)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"workspace": workspace,
+		"members":   members,
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		for _, m := range members {
+			if _, err := fmt.Fprintf(ios.Out, "%s\t%s\t%s\n", cmdutil.FirstNonEmpty(m.User.Display, m.User.Nickname), m.User.AccountID, m.Permission); err != nil {
+				return err
+			}
+		}
+		if len(members) == 0 {
+			if _, err := fmt.Fprintln(ios.Out, "No members found."); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -84,6 +84,9 @@ The pipeline runs against the specified Git ref (branch, tag, or commit). You ca
 pass custom pipeline variables using the --var flag, which accepts KEY=VALUE pairs
 and can be repeated. This command is available for Bitbucket Cloud contexts only.
 
+A .bkt.yaml in the repository can set pipeline.ref (used when --ref is not
+given) and pipeline.variables (overridden by --var with the same key).
+
 Use --wait to poll the triggered pipeline until it completes, with exponential
 backoff and jitter. Exit codes in --wait mode: 0 = pipeline succeeded,
 1 = pipeline completed unsuccessfully, 8 = timed out while still running.`,
@@ -243,7 +246,20 @@ func runPipelineRun(cmd *cobra.Command, f *cmdutil.Factory, opts *runOptions) er
 		return err
 	}
 
+	project, err := f.ProjectConfig()
+	if err != nil {
+		return err
+	}
+
 	vars := make(map[string]string)
+	if project != nil {
+		if !cmd.Flags().Changed("ref") && project.Pipeline.Ref != "" {
+			opts.Ref = project.Pipeline.Ref
+		}
+		for k, v := range project.Pipeline.Variables {
+			vars[k] = v
+		}
+	}
 	for _, v := range opts.Variables {
 		parts := strings.SplitN(v, "=", 2)
 		if len(parts) != 2 {
@@ -290,7 +306,7 @@ func runPipelineRun(cmd *cobra.Command, f *cmdutil.Factory, opts *runOptions) er
 		"repo":      repo,
 		"pipeline":  pipeline,
 	}
-	if err := cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	if err := cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		// Without a TTY the poll loop already printed the terminal status;
 		// match pr checks and skip the duplicate final print.
 		if !ios.IsStdoutTTY() {
@@ -334,7 +350,7 @@ func runPipelineList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions)
 		"pipelines": pipelines,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(pipelines) == 0 {
 			_, err := fmt.Fprintln(ios.Out, "No pipelines found.")
 			return err
@@ -413,7 +429,7 @@ func runPipelineView(cmd *cobra.Command, f *cmdutil.Factory, opts *viewOptions)
 		"steps":    steps,
 	}
 
-	if err := cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	if err := cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if _, err := fmt.Fprintf(ios.Out, "%s\t%s\t%s\n", pipeline.UUID, pipeline.State.Name, pipeline.State.Result.Name); err != nil {
 			return err
 		}
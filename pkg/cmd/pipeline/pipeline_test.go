@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -491,3 +493,51 @@ func TestWaitForPipelineTimeout(t *testing.T) {
 		t.Errorf("stderr missing timeout notice: %q", errOut.String())
 	}
 }
+
+func TestPipelineRunUsesProjectConfigDefaults(t *testing.T) {
+	dir := t.TempDir()
+	body := "pipeline:\n  ref: develop\n  variables:\n    ENV: staging\n    DEBUG: \"false\"\n"
+	if err := os.WriteFile(filepath.Join(dir, config.ProjectFileName), []byte(body), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Chdir(dir)
+
+	var payload struct {
+		Target struct {
+			RefName string `json:"ref_name"`
+		} `json:"target"`
+		Variables []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"variables"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pipelineJSONBody("PENDING", "")))
+	}))
+	defer srv.Close()
+
+	f, _, _ := pipelineTestFactory(srv.URL)
+	cmd := newRunCmd(f)
+	registerOutputFlags(cmd)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--var", "DEBUG=true"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if payload.Target.RefName != "develop" {
+		t.Errorf("ref = %q, want develop from .bkt.yaml", payload.Target.RefName)
+	}
+	got := map[string]string{}
+	for _, v := range payload.Variables {
+		got[v.Key] = v.Value
+	}
+	if got["ENV"] != "staging" || got["DEBUG"] != "true" {
+		t.Errorf("variables = %v, want ENV=staging and --var DEBUG=true winning", got)
+	}
+}
@@ -253,7 +253,7 @@ func runAutoMergeStatus(cmd *cobra.Command, f *cmdutil.Factory, opts *autoMergeO
 		"auto_merge":   settings,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if settings == nil || !settings.Enabled {
 			_, err := fmt.Fprintf(ios.Out, "Auto-merge disabled for pull request #%d\n", opts.ID)
 			return err
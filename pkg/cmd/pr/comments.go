@@ -245,7 +245,7 @@ func runComments(cmd *cobra.Command, f *cmdutil.Factory, id int, opts *commentsO
 		}
 
 		const maxDepth = 20
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(comments) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No comments on pull request #%d\n", id)
 				return err
@@ -374,7 +374,7 @@ func runComments(cmd *cobra.Command, f *cmdutil.Factory, id int, opts *commentsO
 			"comments":  comments,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(comments) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No comments on pull request #%d\n", id)
 				return err
@@ -517,7 +517,7 @@ func runCommentThreadSetState(cmd *cobra.Command, f *cmdutil.Factory, opts *comm
 		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, result, func() error {
+	return cmdutil.WriteOutput(cmd, ios, result, func() error {
 		if already {
 			state := "resolved"
 			if !resolved {
@@ -585,7 +585,7 @@ func runCommentDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *commentsOpti
 		CommentID:   commentID,
 		Deleted:     true,
 	}
-	return cmdutil.WriteOutput(cmd, ios.Out, result, func() error {
+	return cmdutil.WriteOutput(cmd, ios, result, func() error {
 		_, err := fmt.Fprintf(ios.Out, "✓ Deleted comment %d on pull request #%d\n", commentID, prID)
 		return err
 	})
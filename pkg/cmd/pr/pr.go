@@ -8,10 +8,12 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -186,42 +188,41 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
 		defer cancel()
 
-		var prs []bbdc.PullRequest
+		listOpts := bbdc.RepoPullRequestsOptions{State: opts.State, Limit: opts.Limit}
 		if opts.Reviewer {
 			if host.Username == "" {
 				return fmt.Errorf("--reviewer requires a username; bearer-only logins must re-authenticate with --username or use the dashboard endpoint (omit --project and --repo)")
 			}
 			// Apply the REVIEWER participant filter upstream (before the limit)
 			// rather than filtering a limited page client-side.
-			prs, err = client.ListPullRequestsWithOptions(ctx, projectKey, repoSlug, bbdc.RepoPullRequestsOptions{
-				State:    opts.State,
-				Role:     "REVIEWER",
-				Username: host.Username,
-				Limit:    opts.Limit,
-			})
-			if err != nil {
-				return err
-			}
-		} else {
-			prs, err = client.ListPullRequests(ctx, projectKey, repoSlug, opts.State, opts.Limit)
-			if err != nil {
-				return err
-			}
+			listOpts.Role = "REVIEWER"
+			listOpts.Username = host.Username
+		} else if opts.Mine && host.Username == "" {
+			return fmt.Errorf("--mine requires a username; bearer-only logins must re-authenticate with --username or use the dashboard endpoint (omit --project and --repo)")
+		}
 
-			if opts.Mine {
-				if host.Username == "" {
-					return fmt.Errorf("--mine requires a username; bearer-only logins must re-authenticate with --username or use the dashboard endpoint (omit --project and --repo)")
-				}
-				filtered := prs[:0]
-				current := strings.ToLower(host.Username)
-				for _, pr := range prs {
-					author := strings.ToLower(cmdutil.FirstNonEmpty(pr.Author.User.Name, pr.Author.User.Slug))
-					if author == current {
-						filtered = append(filtered, pr)
-					}
+		stream, err := cmdutil.StreamOutput(cmd, ios.Out)
+		if err != nil {
+			return err
+		}
+
+		var prs []bbdc.PullRequest
+		current := strings.ToLower(host.Username)
+		err = client.EachPullRequest(ctx, projectKey, repoSlug, listOpts, func(pr bbdc.PullRequest) error {
+			if opts.Mine && !opts.Reviewer {
+				author := strings.ToLower(cmdutil.FirstNonEmpty(pr.Author.User.Name, pr.Author.User.Slug))
+				if author != current {
+					return nil
 				}
-				prs = filtered
 			}
+			if stream != nil {
+				return stream.Write(pr)
+			}
+			prs = append(prs, pr)
+			return nil
+		})
+		if err != nil || stream != nil {
+			return err
 		}
 
 		payload := map[string]any{
@@ -230,7 +231,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			"pull_requests": prs,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(prs) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No pull requests (%s).\n", strings.ToUpper(opts.State))
 				return err
@@ -299,13 +300,25 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			}
 		}
 
-		prs, err := client.ListPullRequests(ctx, workspace, repoSlug, bbcloud.PullRequestListOptions{
+		stream, err := cmdutil.StreamOutput(cmd, ios.Out)
+		if err != nil {
+			return err
+		}
+
+		var prs []bbcloud.PullRequest
+		err = client.EachPullRequest(ctx, workspace, repoSlug, bbcloud.PullRequestListOptions{
 			State:    opts.State,
 			Limit:    opts.Limit,
 			Mine:     mine,
 			Reviewer: reviewer,
+		}, func(pr bbcloud.PullRequest) error {
+			if stream != nil {
+				return stream.Write(pr)
+			}
+			prs = append(prs, pr)
+			return nil
 		})
-		if err != nil {
+		if err != nil || stream != nil {
 			return err
 		}
 
@@ -315,7 +328,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			"pull_requests": prs,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(prs) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No pull requests (%s).\n", strings.ToUpper(opts.State))
 				return err
@@ -366,7 +379,7 @@ func runListDashboardDC(cmd *cobra.Command, f *cmdutil.Factory, ios *iostreams.I
 		"pull_requests": prs,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(prs) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No pull requests (%s).\n", strings.ToUpper(opts.State))
 			return err
@@ -435,7 +448,7 @@ func runListWorkspaceCloud(cmd *cobra.Command, f *cmdutil.Factory, ios *iostream
 		"pull_requests": prs,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(prs) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No pull requests (%s).\n", strings.ToUpper(opts.State))
 			return err
@@ -619,7 +632,7 @@ func runView(cmd *cobra.Command, f *cmdutil.Factory, opts *viewOptions) error {
 			}
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if _, err := fmt.Fprintf(ios.Out, "Pull Request #%d: %s\n", pr.ID, pr.Title); err != nil {
 				return err
 			}
@@ -687,7 +700,7 @@ func runView(cmd *cobra.Command, f *cmdutil.Factory, opts *viewOptions) error {
 			}
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if _, err := fmt.Fprintf(ios.Out, "Pull Request #%d: %s\n", pr.ID, pr.Title); err != nil {
 				return err
 			}
@@ -759,6 +772,9 @@ type createOptions struct {
 	CloseSource          bool
 	WithDefaultReviewers bool
 	Draft                bool
+
+	// TitleTemplate comes from .bkt.yaml and renders the default title.
+	TitleTemplate string
 }
 
 // mergeCreateReviewers combines explicit reviewer names with default reviewer
@@ -1025,7 +1041,11 @@ the source branch. Each defaults to the destination project or repository when
 omitted. These flags are rejected on Bitbucket Cloud.
 
 Draft pull requests are supported on Cloud (always) and on Data Center 8.18+
-via the --draft flag.`,
+via the --draft flag.
+
+A .bkt.yaml in the repository can set pr.target_branch, pr.reviewers (used
+when no --reviewer is given), and pr.title_template, a Go template rendered
+with .Source, .Target, and .Subject when --title is omitted.`,
 		Example: `  # Create a pull request with auto-detected title
   bkt pr create
 
@@ -1100,6 +1120,12 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		return fmt.Errorf("--source-project and --source-repo are only supported on Bitbucket Data Center")
 	}
 
+	project, err := f.ProjectConfig()
+	if err != nil {
+		return err
+	}
+	applyProjectCreateDefaults(opts, project)
+
 	if err := applyCreateDefaults(cmd.Context(), opts, host); err != nil {
 		return err
 	}
@@ -1152,7 +1178,7 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		}
 
 		result := createResult{ID: pr.ID, Title: pr.Title, URL: firstPRLinkDC(pr, "self")}
-		return cmdutil.WriteOutput(cmd, ios.Out, result, func() error {
+		return cmdutil.WriteOutput(cmd, ios, result, func() error {
 			kind := "pull request"
 			if opts.Draft {
 				kind = "draft pull request"
@@ -1207,7 +1233,7 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		}
 
 		result := createResult{ID: pr.ID, Title: pr.Title, URL: firstPRLinkCloud(pr)}
-		return cmdutil.WriteOutput(cmd, ios.Out, result, func() error {
+		return cmdutil.WriteOutput(cmd, ios, result, func() error {
 			kind := "pull request"
 			if opts.Draft {
 				kind = "draft pull request"
@@ -1253,7 +1279,12 @@ func applyCreateDefaults(ctx context.Context, opts *createOptions, host *config.
 			}
 		}
 		title, err := defaultGitPRTitle(ctx, opts.Target, remoteName)
-		if err != nil {
+		if opts.TitleTemplate != "" {
+			title, err = renderPRTitleTemplate(opts.TitleTemplate, opts.Source, opts.Target, title, err)
+			if err != nil {
+				return err
+			}
+		} else if err != nil {
 			return fmt.Errorf("could not determine default --title from git history; pass --title explicitly: %w", err)
 		}
 		opts.Title = title
@@ -1262,6 +1293,47 @@ func applyCreateDefaults(ctx context.Context, opts *createOptions, host *config.
 	return nil
 }
 
+// applyProjectCreateDefaults fills pull request defaults from .bkt.yaml.
+// Explicit flags always win.
+func applyProjectCreateDefaults(opts *createOptions, project *config.ProjectConfig) {
+	if project == nil {
+		return
+	}
+	if strings.TrimSpace(opts.Target) == "" {
+		opts.Target = project.PR.TargetBranch
+	}
+	if len(opts.Reviewers) == 0 {
+		opts.Reviewers = append([]string(nil), project.PR.Reviewers...)
+	}
+	opts.TitleTemplate = project.PR.TitleTemplate
+}
+
+// renderPRTitleTemplate renders a .bkt.yaml title_template. The commit subject
+// is only required when the template references it.
+func renderPRTitleTemplate(text, source, target, subject string, subjectErr error) (string, error) {
+	tmpl, err := template.New("title_template").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse pr.title_template: %w", err)
+	}
+	if subjectErr != nil && strings.Contains(text, ".Subject") {
+		return "", fmt.Errorf("could not determine .Subject for pr.title_template from git history; pass --title explicitly: %w", subjectErr)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, map[string]string{
+		"Source":  source,
+		"Target":  target,
+		"Subject": subject,
+	}); err != nil {
+		return "", fmt.Errorf("render pr.title_template: %w", err)
+	}
+	title := strings.TrimSpace(buf.String())
+	if title == "" {
+		return "", fmt.Errorf("pr.title_template rendered an empty title; pass --title explicitly")
+	}
+	return title, nil
+}
+
 func currentGitBranch(ctx context.Context) (string, error) {
 	out, err := runGitOutput(ctx, "branch", "--show-current")
 	if err != nil {
@@ -1728,7 +1800,7 @@ func runEdit(cmd *cobra.Command, f *cmdutil.Factory, opts *editOptions) error {
 			"pull_request": updatedPR,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			_, err := fmt.Fprintf(ios.Out, "✓ Updated pull request #%d\n", updatedPR.ID)
 			return err
 		})
@@ -1808,7 +1880,7 @@ func runEdit(cmd *cobra.Command, f *cmdutil.Factory, opts *editOptions) error {
 			"pull_request": updatedPR,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			_, err := fmt.Fprintf(ios.Out, "✓ Updated pull request #%d\n", updatedPR.ID)
 			return err
 		})
@@ -2076,7 +2148,7 @@ func runDiff(cmd *cobra.Command, f *cmdutil.Factory, opts *diffOptions) error {
 				"pull_request": opts.ID,
 				"stats":        stat,
 			}
-			return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+			return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 				_, err := fmt.Fprintf(ios.Out, "Files: %d\nAdditions: %d\nDeletions: %d\n", stat.Files, stat.Additions, stat.Deletions)
 				return err
 			})
@@ -2119,7 +2191,7 @@ func runDiff(cmd *cobra.Command, f *cmdutil.Factory, opts *diffOptions) error {
 				"pull_request": opts.ID,
 				"stats":        result,
 			}
-			return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+			return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 				// Compute max path length for alignment.
 				maxLen := 0
 				for _, e := range result.Entries {
@@ -2474,7 +2546,10 @@ func newMergeCmd(f *cmdutil.Factory) *cobra.Command {
 (e.g. fast_forward, squash) and a custom merge commit message can be provided.
 
 Works on both Data Center and Cloud. On Data Center, the current PR version
-is used for optimistic locking.`,
+is used for optimistic locking. Without --strategy, pr.merge_strategy from
+.bkt.yaml is used when present. The strategy is checked against the strategies
+enabled for the repository (Data Center) or destination branch (Cloud) when
+those settings can be read.`,
 		Example: `  # Merge a pull request
   bkt pr merge 42
 
@@ -2509,6 +2584,16 @@ func runMerge(cmd *cobra.Command, f *cmdutil.Factory, id int, opts *mergeOptions
 		return err
 	}
 
+	if opts.Strategy == "" {
+		project, err := f.ProjectConfig()
+		if err != nil {
+			return err
+		}
+		if project != nil {
+			opts.Strategy = project.PR.MergeStrategy
+		}
+	}
+
 	override := cmdutil.FlagValue(cmd, "context")
 	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
 	if err != nil {
@@ -2536,6 +2621,16 @@ func runMerge(cmd *cobra.Command, f *cmdutil.Factory, id int, opts *mergeOptions
 			return err
 		}
 
+		if opts.Strategy != "" {
+			// Validation is best effort: the settings endpoint needs repository
+			// admin on some versions, so fall through to the server on errors.
+			if settings, err := client.GetPullRequestSettings(ctx, projectKey, repoSlug); err == nil {
+				if err := validateMergeStrategy(opts.Strategy, settings.MergeConfig.EnabledStrategies()); err != nil {
+					return err
+				}
+			}
+		}
+
 		if err := client.MergePullRequest(ctx, projectKey, repoSlug, id, pr.Version, bbdc.MergePROptions{
 			Message:           opts.Message,
 			Strategy:          opts.Strategy,
@@ -2564,6 +2659,18 @@ func runMerge(cmd *cobra.Command, f *cmdutil.Factory, id int, opts *mergeOptions
 		ctx, cancel := context.WithTimeout(cmd.Context(), cloudMergeTimeout)
 		defer cancel()
 
+		if opts.Strategy != "" {
+			// Cloud allows merge strategies per destination branch; skip
+			// validation when either lookup fails.
+			if pr, err := client.GetPullRequest(ctx, workspace, repoSlug, id); err == nil && pr.Destination.Branch.Name != "" {
+				if branch, err := client.GetBranch(ctx, workspace, repoSlug, pr.Destination.Branch.Name); err == nil {
+					if err := validateMergeStrategy(opts.Strategy, branch.MergeStrategies); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
 		if err := client.MergePullRequest(ctx, workspace, repoSlug, id, opts.Message, opts.Strategy, opts.CloseSource); err != nil {
 			return err
 		}
@@ -2578,6 +2685,15 @@ func runMerge(cmd *cobra.Command, f *cmdutil.Factory, id int, opts *mergeOptions
 	}
 }
 
+// validateMergeStrategy rejects a strategy missing from a non-empty list of
+// enabled strategies.
+func validateMergeStrategy(strategy string, enabled []string) error {
+	if len(enabled) == 0 || slices.Contains(enabled, strategy) {
+		return nil
+	}
+	return fmt.Errorf("merge strategy %q is not enabled for this repository; enabled strategies: %s", strategy, strings.Join(enabled, ", "))
+}
+
 type declineOptions struct {
 	Project      string
 	Workspace    string
@@ -3309,7 +3425,7 @@ func executeStatusCheck(r *checksResult) error {
 	// With TTY, alternate screen buffer means final print shows on main screen
 	skipFinalPrint := r.opts.Wait && !r.ios.IsStdoutTTY()
 
-	writeErr := cmdutil.WriteOutput(r.cmd, r.ios.Out, r.payload, func() error {
+	writeErr := cmdutil.WriteOutput(r.cmd, r.ios, r.payload, func() error {
 		if skipFinalPrint {
 			return nil
 		}
@@ -297,6 +297,46 @@ func TestPRMergeDataCenter(t *testing.T) {
 	}
 }
 
+func TestPRMergeRejectsDisabledStrategy(t *testing.T) {
+	var merged bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/pull-requests/42"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 42, "state": "OPEN", "version": 3})
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/settings/pull-requests"):
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"mergeConfig": map[string]any{
+					"defaultStrategy": map[string]any{"id": "no-ff"},
+					"strategies": []map[string]any{
+						{"id": "no-ff", "enabled": true},
+						{"id": "squash", "enabled": false},
+						{"id": "ff-only", "enabled": true},
+					},
+				},
+			})
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/pull-requests/42/merge"):
+			merged = true
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	_, _, err := runCLI(t, dcConfig(srv.URL), "pr", "merge", "42", "--strategy", "squash")
+	if err == nil {
+		t.Fatal("expected error for disabled merge strategy")
+	}
+	if !strings.Contains(err.Error(), `merge strategy "squash" is not enabled`) || !strings.Contains(err.Error(), "no-ff, ff-only") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged {
+		t.Fatal("merge endpoint should not be called")
+	}
+}
+
 func TestPRMergeCloud(t *testing.T) {
 	var mergeBody map[string]any
 
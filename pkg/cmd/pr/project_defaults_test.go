@@ -0,0 +1,66 @@
+package pr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+)
+
+func TestRenderPRTitleTemplate(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		subjectErr error
+		want       string
+		wantErr    string
+	}{
+		{"all fields", "[{{.Target}}] {{.Source}}: {{.Subject}}", nil, "[main] feat/x: Add thing", ""},
+		{"subject unused tolerates git error", "{{.Source}} -> {{.Target}}", errors.New("no commits"), "feat/x -> main", ""},
+		{"subject needed", "{{.Subject}}", errors.New("no commits"), "", "could not determine .Subject"},
+		{"unknown field", "{{.Ticket}}", nil, "", "render pr.title_template"},
+		{"parse error", "{{.Source", nil, "", "parse pr.title_template"},
+		{"empty result", "  ", nil, "", "empty title"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := renderPRTitleTemplate(tc.text, "feat/x", "main", "Add thing", tc.subjectErr)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("title = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApplyProjectCreateDefaults(t *testing.T) {
+	project := &config.ProjectConfig{PR: config.ProjectPRDefaults{
+		TargetBranch:  "develop",
+		Reviewers:     []string{"alice"},
+		TitleTemplate: "{{.Subject}}",
+	}}
+
+	opts := &createOptions{}
+	applyProjectCreateDefaults(opts, project)
+	if opts.Target != "develop" || len(opts.Reviewers) != 1 || opts.Reviewers[0] != "alice" {
+		t.Fatalf("defaults not applied: %+v", opts)
+	}
+	if opts.TitleTemplate != "{{.Subject}}" {
+		t.Fatalf("title template = %q", opts.TitleTemplate)
+	}
+
+	opts = &createOptions{Target: "main", Reviewers: []string{"bob"}}
+	applyProjectCreateDefaults(opts, project)
+	if opts.Target != "main" || len(opts.Reviewers) != 1 || opts.Reviewers[0] != "bob" {
+		t.Fatalf("flags should win over .bkt.yaml: %+v", opts)
+	}
+}
@@ -172,7 +172,7 @@ func runReactionList(cmd *cobra.Command, f *cmdutil.Factory, opts *reactionOptio
 		"reactions": reactions,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(reactions) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No reactions for comment %d\n", opts.Comment)
 			return err
@@ -134,7 +134,7 @@ func runReviewerGroupList(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewer
 		"reviewer_groups": groups,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(groups) == 0 {
 			_, err := fmt.Fprintln(ios.Out, "No reviewer groups configured.")
 			return err
@@ -103,7 +103,7 @@ func runSuggestion(cmd *cobra.Command, f *cmdutil.Factory, opts *suggestionOptio
 			"suggestion": suggestion,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			_, err := fmt.Fprintf(ios.Out, "%s\n", suggestion.Text)
 			return err
 		})
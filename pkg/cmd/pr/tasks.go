@@ -262,7 +262,7 @@ func runTaskList(cmd *cobra.Command, f *cmdutil.Factory, opts *taskOptions) erro
 
 	payload["tasks"] = tasks
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(tasks) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No tasks on pull request #%d\n", opts.ID)
 			return err
@@ -335,7 +335,7 @@ func runTaskCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *taskOptions) er
 	}
 
 	payload["task"] = view
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		_, err := fmt.Fprintf(ios.Out, "✓ Created task %d\n", view.ID)
 		return err
 	})
@@ -401,7 +401,7 @@ func runTaskSetState(cmd *cobra.Command, f *cmdutil.Factory, opts *taskOptions,
 		verb = "Completed"
 	}
 	payload["task"] = view
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		_, err := fmt.Fprintf(ios.Out, "✓ %s task %d\n", verb, view.ID)
 		return err
 	})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/accesstoken"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 )
 
@@ -32,6 +33,7 @@ other commands.`,
 
 	cmd.AddCommand(newListCmd(f))
 	cmd.AddCommand(newReviewerGroupsCmd(f))
+	cmd.AddCommand(accesstoken.NewCmdToken(f, accesstoken.ScopeProject))
 
 	return cmd
 }
@@ -143,7 +145,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		Projects: summaries,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(summaries) == 0 {
 			_, err := fmt.Fprintf(ios.Out, "No projects visible on host %s.\n", baseURL)
 			return err
@@ -298,3 +298,158 @@ func TestProjectReviewerGroupsListRejectsCloud(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+// reviewerGroupStore serves one reviewer group collection and records writes.
+type reviewerGroupStore struct {
+	base   string
+	groups []map[string]any
+	bodies []map[string]any
+	calls  []string
+}
+
+func (s *reviewerGroupStore) handler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls = append(s.calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == s.base:
+			_ = json.NewEncoder(w).Encode(map[string]any{"values": s.groups, "isLastPage": true})
+		case r.Method == http.MethodPost && r.URL.Path == s.base,
+			r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, s.base+"/"):
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			s.bodies = append(s.bodies, body)
+			body["id"] = 5
+			_ = json.NewEncoder(w).Encode(body)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, s.base+"/"):
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func bodyUsernames(body map[string]any) []string {
+	var names []string
+	for _, u := range body["users"].([]any) {
+		names = append(names, u.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func newReviewerGroupStore() *reviewerGroupStore {
+	return &reviewerGroupStore{
+		base: "/rest/api/1.0/projects/PROJ/settings/reviewer-groups",
+		groups: []map[string]any{{
+			"id":          5,
+			"name":        "backend",
+			"description": "Backend reviewers",
+			"users":       []map[string]any{{"name": "alice", "id": 1}, {"name": "bob", "id": 2}},
+		}},
+	}
+}
+
+func TestProjectReviewerGroupsCreate(t *testing.T) {
+	store := newReviewerGroupStore()
+	srv := httptest.NewServer(store.handler(t))
+	t.Cleanup(srv.Close)
+
+	f, stdout, _ := newTestFactory(dcConfig(srv.URL))
+	if err := runProjectCmd(t, f, "reviewer-groups", "create", "frontend", "--member", "carol", "--member", "dave", "--description", "UI"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(store.bodies) != 1 {
+		t.Fatalf("expected one write, got %v", store.calls)
+	}
+	body := store.bodies[0]
+	if body["name"] != "frontend" || body["description"] != "UI" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	if got := strings.Join(bodyUsernames(body), ","); got != "carol,dave" {
+		t.Fatalf("users = %s", got)
+	}
+	if !strings.Contains(stdout.String(), "Created reviewer group frontend (id: 5, members: 2) in project PROJ") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+}
+
+func TestProjectReviewerGroupsEditReplacesMembers(t *testing.T) {
+	store := newReviewerGroupStore()
+	srv := httptest.NewServer(store.handler(t))
+	t.Cleanup(srv.Close)
+
+	f, _, _ := newTestFactory(dcConfig(srv.URL))
+	if err := runProjectCmd(t, f, "reviewer-groups", "edit", "5", "--name", "backend-core", "--members", "bob,erin"); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	want := "PUT " + store.base + "/5"
+	if store.calls[len(store.calls)-1] != want {
+		t.Fatalf("calls = %v, want last %s", store.calls, want)
+	}
+	body := store.bodies[0]
+	if body["name"] != "backend-core" || body["description"] != "Backend reviewers" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	if got := strings.Join(bodyUsernames(body), ","); got != "bob,erin" {
+		t.Fatalf("users = %s", got)
+	}
+}
+
+func TestProjectReviewerGroupsMembersRepoScope(t *testing.T) {
+	store := newReviewerGroupStore()
+	store.base = "/rest/api/1.0/projects/PROJ/repos/api/settings/reviewer-groups"
+	srv := httptest.NewServer(store.handler(t))
+	t.Cleanup(srv.Close)
+
+	f, stdout, _ := newTestFactory(dcConfig(srv.URL))
+	if err := runProjectCmd(t, f, "reviewer-groups", "members", "add", "backend", "carol", "alice", "--repo", "api"); err != nil {
+		t.Fatalf("members add: %v", err)
+	}
+	if got := strings.Join(bodyUsernames(store.bodies[0]), ","); got != "alice,bob,carol" {
+		t.Fatalf("users after add = %s", got)
+	}
+	if !strings.Contains(stdout.String(), "Added 1 member(s)") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+
+	if err := runProjectCmd(t, f, "reviewer-groups", "members", "remove", "backend", "alice", "--repo", "api"); err != nil {
+		t.Fatalf("members remove: %v", err)
+	}
+	if got := strings.Join(bodyUsernames(store.bodies[1]), ","); got != "bob" {
+		t.Fatalf("users after remove = %s", got)
+	}
+
+	err := runProjectCmd(t, f, "reviewer-groups", "members", "remove", "backend", "alice", "bob", "--repo", "api")
+	if err == nil || !strings.Contains(err.Error(), "delete the group instead") {
+		t.Fatalf("expected error removing every member, got %v", err)
+	}
+	err = runProjectCmd(t, f, "reviewer-groups", "members", "remove", "backend", "zoe", "--repo", "api")
+	if err == nil || !strings.Contains(err.Error(), "zoe is not a member") {
+		t.Fatalf("expected non-member error, got %v", err)
+	}
+}
+
+func TestProjectReviewerGroupsDelete(t *testing.T) {
+	store := newReviewerGroupStore()
+	srv := httptest.NewServer(store.handler(t))
+	t.Cleanup(srv.Close)
+
+	f, stdout, _ := newTestFactory(dcConfig(srv.URL))
+	if err := runProjectCmd(t, f, "reviewer-groups", "delete", "Backend"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	want := "DELETE " + store.base + "/5"
+	if store.calls[len(store.calls)-1] != want {
+		t.Fatalf("calls = %v, want last %s", store.calls, want)
+	}
+	if !strings.Contains(stdout.String(), "Deleted reviewer group backend (id: 5)") {
+		t.Fatalf("unexpected output: %s", stdout.String())
+	}
+
+	err := runProjectCmd(t, f, "reviewer-groups", "delete", "missing")
+	if err == nil || !strings.Contains(err.Error(), `reviewer group "missing" not found in project PROJ`) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
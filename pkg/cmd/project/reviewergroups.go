@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 )
 
@@ -16,27 +17,68 @@ func newReviewerGroupsCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:     "reviewer-groups",
 		Aliases: []string{"reviewer-group"},
 		Short:   "Work with project reviewer groups (DC only)",
-		Long: `List the reviewer groups defined in a project's settings.
+		Long: `List and manage the reviewer groups defined in a project's or repository's
+settings.
 
 Reviewer groups are named sets of users that can be added as default reviewers
-on repositories within the project. Data Center only.`,
+on repositories within the project. Groups are project-scoped unless --repo
+selects a repository's own groups. Data Center only.`,
 		Example: `  # List reviewer groups for the active context project
   bkt project reviewer-groups list
 
-  # List reviewer groups for a specific project
-  bkt project reviewer-groups list --project PLATFORM`,
+  # Create a group, then replace its members from a team roster
+  bkt project reviewer-groups create backend --member alice --member bob
+  bkt project reviewer-groups edit backend --members alice,carol,dave
+
+  # Add a member to a repository-scoped group
+  bkt project reviewer-groups members add api-owners carol --repo api`,
 	}
 
 	cmd.AddCommand(newReviewerGroupsListCmd(f))
+	cmd.AddCommand(newReviewerGroupsCreateCmd(f))
+	cmd.AddCommand(newReviewerGroupsEditCmd(f))
+	cmd.AddCommand(newReviewerGroupsDeleteCmd(f))
+	cmd.AddCommand(newReviewerGroupsMembersCmd(f))
 
 	return cmd
 }
 
 type reviewerGroupsListOptions struct {
 	Project string
+	Repo    string
 	Limit   int
 }
 
+type reviewerGroupMember struct {
+	DisplayName string `json:"display_name"`
+	Username    string `json:"username"`
+	ID          int    `json:"id"`
+}
+
+type reviewerGroupSummary struct {
+	ID          int                   `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Members     []reviewerGroupMember `json:"members"`
+}
+
+func summarizeReviewerGroup(g bbdc.ProjectReviewerGroup) reviewerGroupSummary {
+	members := []reviewerGroupMember{}
+	for _, u := range g.Users {
+		members = append(members, reviewerGroupMember{
+			DisplayName: u.FullName,
+			Username:    u.Name,
+			ID:          u.ID,
+		})
+	}
+	return reviewerGroupSummary{
+		ID:          g.ID,
+		Name:        g.Name,
+		Description: strings.TrimSpace(g.Description),
+		Members:     members,
+	}
+}
+
 func newReviewerGroupsListCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &reviewerGroupsListOptions{
 		Limit: 30,
@@ -47,8 +89,8 @@ func newReviewerGroupsListCmd(f *cmdutil.Factory) *cobra.Command {
 		Short:   "List project reviewer groups (DC only)",
 		Long: `List the reviewer groups defined in a Bitbucket Data Center project's
 settings, including each group's members. The project is resolved from the
-active context unless overridden with --project. Use --limit to control the
-number of results returned.
+active context unless overridden with --project; --repo lists a repository's
+own groups instead. Use --limit to control the number of results returned.
 
 This command is only available for Data Center hosts. Attempting to run it
 against a Cloud context will return an error.`,
@@ -69,6 +111,7 @@ against a Cloud context will return an error.`,
 	}
 
 	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "List the reviewer groups of this repository")
 	cmd.Flags().IntVar(&opts.Limit, "limit", opts.Limit, "Maximum reviewer groups to display (0 for all)")
 
 	return cmd
@@ -80,22 +123,7 @@ func runReviewerGroupsList(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewe
 		return err
 	}
 
-	override := cmdutil.FlagValue(cmd, "context")
-	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
-	if err != nil {
-		return err
-	}
-
-	if host.Kind != "dc" {
-		return fmt.Errorf("project reviewer groups are only supported for Bitbucket Data Center hosts")
-	}
-
-	projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-	if projectKey == "" {
-		return fmt.Errorf("context must supply a project; use --project if needed")
-	}
-
-	client, err := cmdutil.NewDCClient(host)
+	scope, err := resolveReviewerGroupScope(cmd, f, opts.Project, opts.Repo)
 	if err != nil {
 		return err
 	}
@@ -103,57 +131,33 @@ func runReviewerGroupsList(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewe
 	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
 	defer cancel()
 
-	groups, err := client.ListProjectReviewerGroups(ctx, projectKey, opts.Limit)
+	groups, err := scope.list(ctx, opts.Limit)
 	if err != nil {
 		return err
 	}
 
-	type memberSummary struct {
-		DisplayName string `json:"display_name"`
-		Username    string `json:"username"`
-		ID          int    `json:"id"`
-	}
-
-	type groupSummary struct {
-		ID          int             `json:"id"`
-		Name        string          `json:"name"`
-		Description string          `json:"description,omitempty"`
-		Members     []memberSummary `json:"members"`
-	}
-
-	var summaries []groupSummary
+	var summaries []reviewerGroupSummary
 	for _, g := range groups {
-		var members []memberSummary
-		for _, u := range g.Users {
-			members = append(members, memberSummary{
-				DisplayName: u.FullName,
-				Username:    u.Name,
-				ID:          u.ID,
-			})
-		}
-		summaries = append(summaries, groupSummary{
-			ID:          g.ID,
-			Name:        g.Name,
-			Description: strings.TrimSpace(g.Description),
-			Members:     members,
-		})
+		summaries = append(summaries, summarizeReviewerGroup(g))
 	}
 
 	payload := struct {
-		Project        string         `json:"project"`
-		ReviewerGroups []groupSummary `json:"reviewer_groups"`
+		Project        string                 `json:"project"`
+		Repo           string                 `json:"repo,omitempty"`
+		ReviewerGroups []reviewerGroupSummary `json:"reviewer_groups"`
 	}{
-		Project:        projectKey,
+		Project:        scope.project,
+		Repo:           scope.repo,
 		ReviewerGroups: summaries,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if len(summaries) == 0 {
-			_, err := fmt.Fprintf(ios.Out, "No reviewer groups defined for project %s.\n", projectKey)
+			_, err := fmt.Fprintf(ios.Out, "No reviewer groups defined for %s.\n", scope)
 			return err
 		}
 
-		if _, err := fmt.Fprintf(ios.Out, "Reviewer groups for project %s:\n", projectKey); err != nil {
+		if _, err := fmt.Fprintf(ios.Out, "Reviewer groups for %s:\n", scope); err != nil {
 			return err
 		}
 		for _, g := range summaries {
@@ -0,0 +1,423 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// reviewerGroupScope is the project, or repository when repo is set, whose
+// reviewer groups a command works on.
+type reviewerGroupScope struct {
+	client  *bbdc.Client
+	project string
+	repo    string
+}
+
+func (s *reviewerGroupScope) String() string {
+	if s.repo == "" {
+		return "project " + s.project
+	}
+	return "repository " + s.project + "/" + s.repo
+}
+
+func resolveReviewerGroupScope(cmd *cobra.Command, f *cmdutil.Factory, project, repo string) (*reviewerGroupScope, error) {
+	override := cmdutil.FlagValue(cmd, "context")
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	if err != nil {
+		return nil, err
+	}
+
+	if host.Kind != "dc" {
+		return nil, fmt.Errorf("project reviewer groups are only supported for Bitbucket Data Center hosts")
+	}
+
+	projectKey := cmdutil.FirstNonEmpty(project, ctxCfg.ProjectKey)
+	if projectKey == "" {
+		return nil, fmt.Errorf("context must supply a project; use --project if needed")
+	}
+
+	client, err := cmdutil.NewDCClient(host)
+	if err != nil {
+		return nil, err
+	}
+	return &reviewerGroupScope{client: client, project: projectKey, repo: repo}, nil
+}
+
+func (s *reviewerGroupScope) list(ctx context.Context, limit int) ([]bbdc.ProjectReviewerGroup, error) {
+	if s.repo == "" {
+		return s.client.ListProjectReviewerGroups(ctx, s.project, limit)
+	}
+	return s.client.ListRepositoryReviewerGroups(ctx, s.project, s.repo, limit)
+}
+
+func (s *reviewerGroupScope) create(ctx context.Context, in bbdc.ReviewerGroupInput) (*bbdc.ProjectReviewerGroup, error) {
+	if s.repo == "" {
+		return s.client.CreateProjectReviewerGroup(ctx, s.project, in)
+	}
+	return s.client.CreateRepositoryReviewerGroup(ctx, s.project, s.repo, in)
+}
+
+func (s *reviewerGroupScope) update(ctx context.Context, id int, in bbdc.ReviewerGroupInput) (*bbdc.ProjectReviewerGroup, error) {
+	if s.repo == "" {
+		return s.client.UpdateProjectReviewerGroup(ctx, s.project, id, in)
+	}
+	return s.client.UpdateRepositoryReviewerGroup(ctx, s.project, s.repo, id, in)
+}
+
+func (s *reviewerGroupScope) delete(ctx context.Context, id int) error {
+	if s.repo == "" {
+		return s.client.DeleteProjectReviewerGroup(ctx, s.project, id)
+	}
+	return s.client.DeleteRepositoryReviewerGroup(ctx, s.project, s.repo, id)
+}
+
+// find returns the group named by ref, which is a group name or numeric ID.
+func (s *reviewerGroupScope) find(ctx context.Context, ref string) (*bbdc.ProjectReviewerGroup, error) {
+	groups, err := s.list(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	id, idErr := strconv.Atoi(ref)
+	for i := range groups {
+		if (idErr == nil && groups[i].ID == id) || strings.EqualFold(groups[i].Name, ref) {
+			return &groups[i], nil
+		}
+	}
+	return nil, fmt.Errorf("reviewer group %q not found in %s", ref, s)
+}
+
+type reviewerGroupEditOptions struct {
+	Project     string
+	Repo        string
+	Name        string
+	Description string
+	Members     []string
+}
+
+func addReviewerGroupScopeFlags(cmd *cobra.Command, opts *reviewerGroupEditOptions) {
+	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Work on the reviewer groups of this repository")
+}
+
+func newReviewerGroupsCreateCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &reviewerGroupEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a reviewer group (DC only)",
+		Long: `Create a reviewer group in a project's settings, or in a repository's with
+--repo. A group needs at least one member; pass --member once per username.`,
+		Example: `  # Create a project reviewer group
+  bkt project reviewer-groups create backend --member alice --member bob --description "Backend reviewers"
+
+  # Create a group for one repository
+  bkt project reviewer-groups create api-owners --member carol --repo api`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Name = args[0]
+			return runReviewerGroupsCreate(cmd, f, opts)
+		},
+	}
+	addReviewerGroupScopeFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.Description, "description", "", "Group description")
+	cmd.Flags().StringSliceVar(&opts.Members, "member", nil, "Member username (repeatable)")
+	return cmd
+}
+
+func newReviewerGroupsEditCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &reviewerGroupEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "edit <group>",
+		Short: "Rename a reviewer group or replace its members (DC only)",
+		Long: `Change a reviewer group, identified by name or ID. --name renames it,
+--description replaces its description, and --members replaces the whole
+member list, which keeps a group in sync with an external roster.`,
+		Example: `  # Rename a group
+  bkt project reviewer-groups edit backend --name backend-core
+
+  # Replace the members of a group
+  bkt project reviewer-groups edit backend --members alice,bob,dave`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReviewerGroupsEdit(cmd, f, opts, args[0])
+		},
+	}
+	addReviewerGroupScopeFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.Name, "name", "", "New group name")
+	cmd.Flags().StringVar(&opts.Description, "description", "", "New group description")
+	cmd.Flags().StringSliceVar(&opts.Members, "members", nil, "Complete list of member usernames")
+	return cmd
+}
+
+func newReviewerGroupsDeleteCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &reviewerGroupEditOptions{}
+	cmd := &cobra.Command{
+		Use:     "delete <group>",
+		Aliases: []string{"rm"},
+		Short:   "Delete a reviewer group (DC only)",
+		Long:    `Delete a reviewer group, identified by name or ID.`,
+		Example: `  # Delete a project reviewer group
+  bkt project reviewer-groups delete backend`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReviewerGroupsDelete(cmd, f, opts, args[0])
+		},
+	}
+	addReviewerGroupScopeFlags(cmd, opts)
+	return cmd
+}
+
+func newReviewerGroupsMembersCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "Add or remove reviewer group members (DC only)",
+		Long: `Add users to or remove users from a reviewer group, identified by name or
+ID. Other members are left unchanged.`,
+	}
+	cmd.AddCommand(newReviewerGroupsMembersChangeCmd(f, true))
+	cmd.AddCommand(newReviewerGroupsMembersChangeCmd(f, false))
+	return cmd
+}
+
+func newReviewerGroupsMembersChangeCmd(f *cmdutil.Factory, add bool) *cobra.Command {
+	opts := &reviewerGroupEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "add <group> <username>...",
+		Short: "Add members to a reviewer group",
+		Example: `  # Add two members to a group
+  bkt project reviewer-groups members add backend carol dave`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReviewerGroupsMembers(cmd, f, opts, args[0], args[1:], add)
+		},
+	}
+	if !add {
+		cmd.Use = "remove <group> <username>..."
+		cmd.Short = "Remove members from a reviewer group"
+		cmd.Example = `  # Remove a member from a group
+  bkt project reviewer-groups members remove backend dave`
+	}
+	addReviewerGroupScopeFlags(cmd, opts)
+	return cmd
+}
+
+func runReviewerGroupsCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewerGroupEditOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	members := cleanUsernames(opts.Members)
+	if len(members) == 0 {
+		return fmt.Errorf("at least one --member is required")
+	}
+
+	scope, err := resolveReviewerGroupScope(cmd, f, opts.Project, opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	group, err := scope.create(ctx, bbdc.ReviewerGroupInput{
+		Name:        opts.Name,
+		Description: opts.Description,
+		Users:       members,
+	})
+	if err != nil {
+		return err
+	}
+
+	summary := summarizeReviewerGroup(*group)
+	return cmdutil.WriteOutput(cmd, ios, summary, func() error {
+		_, err := fmt.Fprintf(ios.Out, "✓ Created reviewer group %s (id: %d, members: %d) in %s\n", summary.Name, summary.ID, len(summary.Members), scope)
+		return err
+	})
+}
+
+func runReviewerGroupsEdit(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewerGroupEditOptions, ref string) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if !flags.Changed("name") && !flags.Changed("description") && !flags.Changed("members") {
+		return fmt.Errorf("nothing to change; use --name, --description, or --members")
+	}
+	if flags.Changed("name") && strings.TrimSpace(opts.Name) == "" {
+		return fmt.Errorf("--name cannot be empty")
+	}
+	if flags.Changed("members") && len(cleanUsernames(opts.Members)) == 0 {
+		return fmt.Errorf("--members cannot be empty; delete the group instead")
+	}
+
+	scope, err := resolveReviewerGroupScope(cmd, f, opts.Project, opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	group, err := scope.find(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	in := reviewerGroupInput(group)
+	if flags.Changed("name") {
+		in.Name = strings.TrimSpace(opts.Name)
+	}
+	if flags.Changed("description") {
+		in.Description = opts.Description
+	}
+	if flags.Changed("members") {
+		in.Users = cleanUsernames(opts.Members)
+	}
+
+	updated, err := scope.update(ctx, group.ID, in)
+	if err != nil {
+		return err
+	}
+
+	summary := summarizeReviewerGroup(*updated)
+	return cmdutil.WriteOutput(cmd, ios, summary, func() error {
+		_, err := fmt.Fprintf(ios.Out, "✓ Updated reviewer group %s (id: %d, members: %d)\n", summary.Name, summary.ID, len(summary.Members))
+		return err
+	})
+}
+
+func runReviewerGroupsDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewerGroupEditOptions, ref string) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	scope, err := resolveReviewerGroupScope(cmd, f, opts.Project, opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	group, err := scope.find(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if err := scope.delete(ctx, group.ID); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(ios.Out, "✓ Deleted reviewer group %s (id: %d) from %s\n", group.Name, group.ID, scope)
+	return err
+}
+
+func runReviewerGroupsMembers(cmd *cobra.Command, f *cmdutil.Factory, opts *reviewerGroupEditOptions, ref string, usernames []string, add bool) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	usernames = cleanUsernames(usernames)
+	if len(usernames) == 0 {
+		return fmt.Errorf("at least one username is required")
+	}
+
+	scope, err := resolveReviewerGroupScope(cmd, f, opts.Project, opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	group, err := scope.find(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	in := reviewerGroupInput(group)
+	changed := 0
+	if add {
+		for _, u := range usernames {
+			if !containsFold(in.Users, u) {
+				in.Users = append(in.Users, u)
+				changed++
+			}
+		}
+	} else {
+		for _, u := range usernames {
+			if !containsFold(in.Users, u) {
+				return fmt.Errorf("%s is not a member of reviewer group %s", u, group.Name)
+			}
+		}
+		kept := in.Users[:0]
+		for _, u := range in.Users {
+			if !containsFold(usernames, u) {
+				kept = append(kept, u)
+			}
+		}
+		changed = len(in.Users) - len(kept)
+		in.Users = kept
+		if len(in.Users) == 0 {
+			return fmt.Errorf("cannot remove every member of reviewer group %s; delete the group instead", group.Name)
+		}
+	}
+
+	summary := summarizeReviewerGroup(*group)
+	if changed > 0 {
+		updated, err := scope.update(ctx, group.ID, in)
+		if err != nil {
+			return err
+		}
+		summary = summarizeReviewerGroup(*updated)
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, summary, func() error {
+		verb := "Added"
+		if !add {
+			verb = "Removed"
+		}
+		_, err := fmt.Fprintf(ios.Out, "✓ %s %d member(s); reviewer group %s has %d members\n", verb, changed, summary.Name, len(summary.Members))
+		return err
+	})
+}
+
+// reviewerGroupInput returns the group's current definition, ready to modify
+// and send back.
+func reviewerGroupInput(g *bbdc.ProjectReviewerGroup) bbdc.ReviewerGroupInput {
+	in := bbdc.ReviewerGroupInput{Name: g.Name, Description: g.Description}
+	for _, u := range g.Users {
+		in.Users = append(in.Users, u.Name)
+	}
+	return in
+}
+
+func cleanUsernames(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" && !containsFold(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
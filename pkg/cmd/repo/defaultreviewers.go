@@ -22,14 +22,21 @@ type defaultReviewersOptions struct {
 func newDefaultReviewersCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "default-reviewers",
-		Short: "List effective default reviewers for a repository",
+		Short: "Manage default reviewers for repositories and projects",
 		Long: `Manage default reviewers configured for a repository.
 
-On Cloud, returns the effective default reviewers (merged from workspace and
-repository-level settings). On Data Center, returns the effective default
-reviewers for a pull request from --source to --target.`,
+"list" shows the effective default reviewers: on Cloud, merged from workspace
+and repository-level settings; on Data Center, for a pull request from
+--source to --target. "conditions" shows the configuration itself, and
+"add", "set", and "remove" change it. On Data Center default reviewers are
+pull request conditions at repository or project scope; on Cloud they are a
+list of users per repository.`,
 	}
 	cmd.AddCommand(newDefaultReviewersListCmd(f))
+	cmd.AddCommand(newDefaultReviewersConditionsCmd(f))
+	cmd.AddCommand(newDefaultReviewersAddCmd(f))
+	cmd.AddCommand(newDefaultReviewersSetCmd(f))
+	cmd.AddCommand(newDefaultReviewersRemoveCmd(f))
 	return cmd
 }
 
@@ -129,7 +136,7 @@ func runDefaultReviewersList(cmd *cobra.Command, f *cmdutil.Factory, opts *defau
 			Reviewers: summaries,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(summaries) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No default reviewers configured for %s/%s.\n", workspace, repoSlug)
 				return err
@@ -199,7 +206,7 @@ func runDefaultReviewersList(cmd *cobra.Command, f *cmdutil.Factory, opts *defau
 			Reviewers: summaries,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(summaries) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No default reviewers configured for %s/%s.\n", projectKey, repoSlug)
 				return err
@@ -0,0 +1,902 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// reviewerPolicy is the file read by --from-file.
+type reviewerPolicy struct {
+	Conditions []reviewerPolicyCondition `yaml:"conditions"`
+}
+
+// reviewerPolicyCondition declares one default reviewer condition.
+type reviewerPolicyCondition struct {
+	Source    string   `yaml:"source,omitempty"`
+	Target    string   `yaml:"target,omitempty"`
+	Reviewers []string `yaml:"reviewers,omitempty"`
+	Groups    []string `yaml:"groups,omitempty"`
+	Approvals int      `yaml:"approvals,omitempty"`
+}
+
+// reviewerCondition is a default reviewer condition in platform-neutral
+// form. Source and Target are ref matchers as accepted by --source/--target;
+// on Cloud each reviewer is a condition of its own without matchers.
+type reviewerCondition struct {
+	ID        int      `json:"id,omitempty"`
+	Scope     string   `json:"scope,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	Target    string   `json:"target,omitempty"`
+	Reviewers []string `json:"reviewers"`
+	Approvals int      `json:"approvals"`
+
+	sourceType, sourceID string
+	targetType, targetID string
+	reviewerIDs          []int
+	// aliases holds every identifier of a current Cloud reviewer so a policy
+	// may name them by any of them; ref is the one used to remove them.
+	aliases map[string]bool
+	ref     string
+}
+
+func (c reviewerCondition) String() string {
+	reviewers := strings.Join(c.Reviewers, ", ")
+	if c.Source == "" && c.Target == "" {
+		return "reviewer " + reviewers
+	}
+	return fmt.Sprintf("%s → %s: %s (%d required)", c.Source, c.Target, reviewers, c.Approvals)
+}
+
+// satisfies reports whether the current condition c implements want.
+func (c reviewerCondition) satisfies(want reviewerCondition) bool {
+	if c.Source != want.Source || c.Target != want.Target || c.Approvals != want.Approvals {
+		return false
+	}
+	if c.aliases != nil {
+		if len(c.Reviewers) != len(want.Reviewers) {
+			return false
+		}
+		for _, r := range want.Reviewers {
+			if !c.aliases[r] {
+				return false
+			}
+		}
+		return true
+	}
+	if len(c.reviewerIDs) != len(want.reviewerIDs) {
+		return false
+	}
+	for i := range c.reviewerIDs {
+		if c.reviewerIDs[i] != want.reviewerIDs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// reviewerPlan is the change set for one repository or project.
+type reviewerPlan struct {
+	Target string              `json:"target"`
+	Create []reviewerCondition `json:"create"`
+	Delete []reviewerCondition `json:"delete"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// reviewerTarget is a repository, or a Data Center project when Repo is empty.
+type reviewerTarget struct {
+	Owner string
+	Repo  string
+}
+
+func (t reviewerTarget) String() string {
+	if t.Repo == "" {
+		return t.Owner
+	}
+	return t.Owner + "/" + t.Repo
+}
+
+type defaultReviewersEditOptions struct {
+	Scope     string
+	Project   string
+	Workspace string
+	Repo      string
+	Repos     []string
+	File      string
+	DryRun    bool
+
+	Source    string
+	Target    string
+	Reviewers []string
+	Groups    []string
+	Approvals int
+}
+
+func newDefaultReviewersConditionsCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &defaultReviewersEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "conditions",
+		Short: "List configured default reviewer conditions",
+		Long: `List the default reviewer configuration itself rather than its effect.
+
+On Data Center, prints the pull request conditions of a repository (including
+those inherited from its project) or, with --scope project, of a project. The
+IDs are accepted by "default-reviewers remove". On Cloud, prints the default
+reviewers configured on the repository, excluding workspace defaults.`,
+		Example: `  # List the conditions of the context repository
+  bkt repo default-reviewers conditions
+
+  # List project-wide conditions on Data Center
+  bkt repo default-reviewers conditions --scope project --project PLATFORM`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDefaultReviewersConditions(cmd, f, opts)
+		},
+	}
+	addDefaultReviewersTargetFlags(cmd, opts)
+	return cmd
+}
+
+func newDefaultReviewersAddCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &defaultReviewersEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add default reviewers",
+		Long: `Add default reviewers to one or more repositories, or to a project.
+
+On Data Center, add creates a pull request condition: reviewers (--reviewer
+usernames and the members of --group project reviewer groups) are added to
+pull requests from --source to --target, and --approvals of them must approve.
+Matchers are "any" (the default), a branch name, a glob such as release/*,
+model:development, model:production, or category:feature (bugfix, hotfix,
+release). --scope project creates the condition on the project instead.
+
+On Cloud, add adds each --reviewer (username or {UUID}) to the repository's
+default reviewers; matchers, groups, and approvals are not supported.
+
+--from-file reads conditions from YAML instead of flags:
+
+  conditions:
+    - target: main
+      reviewers: [alice, bob]
+      groups: [backend-leads]   # Data Center only
+      approvals: 1              # Data Center only
+    - source: hotfix/*
+      target: model:production
+      reviewers: [release-bot]
+
+--repos applies the change to every repository matching PROJECT/GLOB or
+WORKSPACE/GLOB (repeatable). Conditions that already exist are skipped, so
+rerunning add is safe.`,
+		Example: `  # Require one of two reviewers on pull requests into main (Data Center)
+  bkt repo default-reviewers add --target main --reviewer alice --reviewer bob --approvals 1
+
+  # Add a Cloud default reviewer
+  bkt repo default-reviewers add --reviewer alice
+
+  # Apply conditions from a file across every repository in a project
+  bkt repo default-reviewers add --from-file reviewers.yaml --repos 'PROJ/*'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDefaultReviewersApply(cmd, f, opts, false)
+		},
+	}
+	addDefaultReviewersTargetFlags(cmd, opts)
+	addDefaultReviewersConditionFlags(cmd, opts)
+	return cmd
+}
+
+func newDefaultReviewersSetCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &defaultReviewersEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Replace default reviewers",
+		Long: `Make the default reviewers of one or more repositories, or of a project,
+exactly match the given conditions. Missing conditions are created and any
+other condition at that scope is deleted; on Data Center, repository scope
+leaves conditions inherited from the project alone.
+
+Conditions are given with the flags of "default-reviewers add" or read with
+--from-file; --repos selects repositories as for add. Rerunning set against
+unchanged input makes no changes. Use --dry-run to print the plan.`,
+		Example: `  # Preview syncing every repository in a project with a file
+  bkt repo default-reviewers set --from-file reviewers.yaml --repos 'PROJ/*' --dry-run
+
+  # Make alice and bob the only Cloud default reviewers
+  bkt repo default-reviewers set --reviewer alice --reviewer bob`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDefaultReviewersApply(cmd, f, opts, true)
+		},
+	}
+	addDefaultReviewersTargetFlags(cmd, opts)
+	addDefaultReviewersConditionFlags(cmd, opts)
+	return cmd
+}
+
+func newDefaultReviewersRemoveCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &defaultReviewersEditOptions{}
+	cmd := &cobra.Command{
+		Use:   "remove [<condition-id>...]",
+		Short: "Remove default reviewers",
+		Long: `Remove default reviewers.
+
+On Data Center, pass the IDs of the conditions to delete (see
+"default-reviewers conditions"); --scope project deletes project conditions.
+On Cloud, pass --reviewer for each user to remove.`,
+		Example: `  # Delete a Data Center condition
+  bkt repo default-reviewers remove 12
+
+  # Remove a Cloud default reviewer
+  bkt repo default-reviewers remove --reviewer alice`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDefaultReviewersRemove(cmd, f, opts, args)
+		},
+	}
+	addDefaultReviewersTargetFlags(cmd, opts)
+	cmd.Flags().StringSliceVar(&opts.Reviewers, "reviewer", nil, "Cloud reviewer username or {UUID} to remove (repeatable)")
+	return cmd
+}
+
+func addDefaultReviewersTargetFlags(cmd *cobra.Command, opts *defaultReviewersEditOptions) {
+	cmd.Flags().StringVar(&opts.Scope, "scope", "repo", "Configuration scope: repo or project (Data Center)")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket Cloud workspace override")
+	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+}
+
+func addDefaultReviewersConditionFlags(cmd *cobra.Command, opts *defaultReviewersEditOptions) {
+	cmd.Flags().StringVar(&opts.Source, "source", "any", "Source ref matcher (Data Center)")
+	cmd.Flags().StringVar(&opts.Target, "target", "any", "Target ref matcher (Data Center)")
+	cmd.Flags().StringSliceVar(&opts.Reviewers, "reviewer", nil, "Reviewer username, or {UUID} on Cloud (repeatable)")
+	cmd.Flags().StringSliceVar(&opts.Groups, "group", nil, "Project reviewer group whose members to add (Data Center, repeatable)")
+	cmd.Flags().IntVar(&opts.Approvals, "approvals", 0, "Required approvals from the reviewers (Data Center)")
+	cmd.Flags().StringVar(&opts.File, "from-file", "", "Read conditions from a YAML file (- for stdin)")
+	cmd.Flags().StringSliceVar(&opts.Repos, "repos", nil, "Repositories as PROJECT/GLOB or WORKSPACE/GLOB (repeatable)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Print the plan without changing anything")
+}
+
+func runDefaultReviewersConditions(cmd *cobra.Command, f *cmdutil.Factory, opts *defaultReviewersEditOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	applier, _, target, err := resolveReviewerApplier(cmd, f, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	conditions, err := applier.list(ctx, target, true)
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"target":     target.String(),
+		"conditions": conditions,
+	}
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		if len(conditions) == 0 {
+			_, err := fmt.Fprintf(ios.Out, "No default reviewers configured for %s.\n", target)
+			return err
+		}
+		for _, c := range conditions {
+			line := c.String()
+			if c.ID > 0 {
+				line = fmt.Sprintf("#%d\t%s\t%s", c.ID, strings.ToLower(c.Scope), line)
+			}
+			if _, err := fmt.Fprintln(ios.Out, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runDefaultReviewersApply(cmd *cobra.Command, f *cmdutil.Factory, opts *defaultReviewersEditOptions, prune bool) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	policy, err := reviewerPolicyFromOptions(cmd, opts, ios.In)
+	if err != nil {
+		return err
+	}
+
+	applier, listRepos, target, err := resolveReviewerApplier(cmd, f, opts)
+	if err != nil {
+		return err
+	}
+
+	targets := []reviewerTarget{target}
+	if len(opts.Repos) > 0 {
+		if target.Repo == "" {
+			return fmt.Errorf("--repos cannot be combined with --scope project")
+		}
+		repos, err := cmdutil.ExpandRepoPatterns(cmd.Context(), opts.Repos, listRepos)
+		if err != nil {
+			return err
+		}
+		targets = targets[:0]
+		for _, repo := range repos {
+			owner, slug, _ := strings.Cut(repo, "/")
+			targets = append(targets, reviewerTarget{Owner: owner, Repo: slug})
+		}
+	}
+
+	// Reviewers and groups resolve per project or workspace; a policy that
+	// cannot be resolved fails the whole run.
+	desiredByOwner := map[string][]reviewerCondition{}
+	for _, t := range targets {
+		if _, ok := desiredByOwner[t.Owner]; ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+		desired, err := applier.desired(ctx, t.Owner, policy)
+		cancel()
+		if err != nil {
+			return err
+		}
+		desiredByOwner[t.Owner] = desired
+	}
+
+	var plans []reviewerPlan
+	failed := 0
+	for _, t := range targets {
+		plan := reviewerPlan{Target: t.String(), Create: []reviewerCondition{}, Delete: []reviewerCondition{}}
+		err := func() error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), 60*time.Second)
+			defer cancel()
+
+			desired := desiredByOwner[t.Owner]
+			current, err := applier.list(ctx, t, false)
+			if err != nil {
+				return err
+			}
+			plan.Create, plan.Delete = diffReviewerConditions(current, desired, prune)
+			if opts.DryRun {
+				return nil
+			}
+			for _, c := range plan.Create {
+				if err := applier.create(ctx, t, c); err != nil {
+					return fmt.Errorf("create %s: %w", c, err)
+				}
+			}
+			for _, c := range plan.Delete {
+				if err := applier.remove(ctx, t, c); err != nil {
+					return fmt.Errorf("delete %s: %w", c, err)
+				}
+			}
+			return nil
+		}()
+		if err != nil {
+			plan.Error = err.Error()
+			failed++
+		}
+		plans = append(plans, plan)
+	}
+
+	payload := map[string]any{
+		"dry_run": opts.DryRun,
+		"targets": plans,
+	}
+	if err := cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		return writeReviewerPlans(ios.Out, plans, opts.DryRun)
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("default reviewer update failed for %d of %d targets", failed, len(plans))
+	}
+	return nil
+}
+
+func runDefaultReviewersRemove(cmd *cobra.Command, f *cmdutil.Factory, opts *defaultReviewersEditOptions, args []string) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	applier, _, target, err := resolveReviewerApplier(cmd, f, opts)
+	if err != nil {
+		return err
+	}
+
+	var conditions []reviewerCondition
+	switch applier.(type) {
+	case *dcReviewerApplier:
+		if len(opts.Reviewers) > 0 {
+			return fmt.Errorf("data center default reviewers are removed by condition ID; see bkt repo default-reviewers conditions")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("at least one condition ID is required")
+		}
+		for _, arg := range args {
+			id, err := strconv.Atoi(arg)
+			if err != nil || id <= 0 {
+				return fmt.Errorf("invalid condition id %q", arg)
+			}
+			conditions = append(conditions, reviewerCondition{ID: id})
+		}
+	default:
+		if len(args) > 0 {
+			return fmt.Errorf("cloud default reviewers are removed with --reviewer, not by ID")
+		}
+		if len(opts.Reviewers) == 0 {
+			return fmt.Errorf("at least one --reviewer is required")
+		}
+		for _, r := range opts.Reviewers {
+			conditions = append(conditions, reviewerCondition{Reviewers: []string{r}, ref: r})
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	for _, c := range conditions {
+		if err := applier.remove(ctx, target, c); err != nil {
+			return err
+		}
+		if c.ID > 0 {
+			_, err = fmt.Fprintf(ios.Out, "✓ Deleted default reviewer condition #%d from %s\n", c.ID, target)
+		} else {
+			_, err = fmt.Fprintf(ios.Out, "✓ Removed %s from the default reviewers of %s\n", c.ref, target)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewerPolicyFromOptions reads --from-file, or builds a single condition
+// from the condition flags.
+func reviewerPolicyFromOptions(cmd *cobra.Command, opts *defaultReviewersEditOptions, stdin io.Reader) (*reviewerPolicy, error) {
+	flags := cmd.Flags()
+	if opts.File == "" {
+		if len(opts.Reviewers) == 0 && len(opts.Groups) == 0 {
+			return nil, fmt.Errorf("at least one --reviewer or --group is required, or use --from-file")
+		}
+		cond := reviewerPolicyCondition{
+			Reviewers: opts.Reviewers,
+			Groups:    opts.Groups,
+			Approvals: opts.Approvals,
+		}
+		if flags.Changed("source") {
+			cond.Source = opts.Source
+		}
+		if flags.Changed("target") {
+			cond.Target = opts.Target
+		}
+		return &reviewerPolicy{Conditions: []reviewerPolicyCondition{cond}}, nil
+	}
+
+	for _, name := range []string{"source", "target", "reviewer", "group", "approvals"} {
+		if flags.Changed(name) {
+			return nil, fmt.Errorf("--%s cannot be combined with --from-file", name)
+		}
+	}
+
+	data, err := cmdutil.ReadPolicyFile(opts.File, stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	var policy reviewerPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", opts.File, err)
+	}
+	if len(policy.Conditions) == 0 {
+		return nil, fmt.Errorf("%s defines no conditions", opts.File)
+	}
+	for i, c := range policy.Conditions {
+		if len(c.Reviewers) == 0 && len(c.Groups) == 0 {
+			return nil, fmt.Errorf("condition %d: reviewers or groups is required", i+1)
+		}
+	}
+	return &policy, nil
+}
+
+// resolveReviewerApplier picks the platform applier, the lister --repos
+// expands over, and the target named by --scope and the repository flags or
+// context.
+func resolveReviewerApplier(cmd *cobra.Command, f *cmdutil.Factory, opts *defaultReviewersEditOptions) (reviewerApplier, cmdutil.RepoLister, reviewerTarget, error) {
+	if opts.Scope != "repo" && opts.Scope != "project" {
+		return nil, nil, reviewerTarget{}, fmt.Errorf("invalid --scope %q; use repo or project", opts.Scope)
+	}
+
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, cmdutil.FlagValue(cmd, "context"))
+	if err != nil {
+		return nil, nil, reviewerTarget{}, err
+	}
+
+	switch host.Kind {
+	case "dc":
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		target := reviewerTarget{Owner: projectKey}
+		if opts.Scope == "project" {
+			if opts.Repo != "" {
+				return nil, nil, reviewerTarget{}, fmt.Errorf("--repo cannot be combined with --scope project")
+			}
+			if projectKey == "" {
+				return nil, nil, reviewerTarget{}, fmt.Errorf("context must supply a project; use --project if needed")
+			}
+		} else {
+			target.Repo = cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+			if len(opts.Repos) == 0 && (projectKey == "" || target.Repo == "") {
+				return nil, nil, reviewerTarget{}, fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+			}
+		}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return nil, nil, reviewerTarget{}, err
+		}
+		return &dcReviewerApplier{client: client}, cmdutil.DCRepoLister(client), target, nil
+
+	case "cloud":
+		if opts.Scope != "repo" {
+			return nil, nil, reviewerTarget{}, fmt.Errorf("--scope %s is only supported on Data Center; Cloud default reviewers are set per repository", opts.Scope)
+		}
+		target := reviewerTarget{
+			Owner: cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace),
+			Repo:  cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo),
+		}
+		if len(opts.Repos) == 0 && (target.Owner == "" || target.Repo == "") {
+			return nil, nil, reviewerTarget{}, fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return nil, nil, reviewerTarget{}, err
+		}
+		return &cloudReviewerApplier{client: client}, cmdutil.CloudRepoLister(client), target, nil
+
+	default:
+		return nil, nil, reviewerTarget{}, fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+}
+
+// diffReviewerConditions returns the desired conditions missing from current
+// and, when prune is set, the current conditions no desired one accounts for.
+func diffReviewerConditions(current, desired []reviewerCondition, prune bool) (create, remove []reviewerCondition) {
+	create, remove = []reviewerCondition{}, []reviewerCondition{}
+	used := make([]bool, len(current))
+	for _, want := range desired {
+		found := false
+		for i, have := range current {
+			if !used[i] && have.satisfies(want) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			create = append(create, want)
+		}
+	}
+	if prune {
+		for i, have := range current {
+			if !used[i] {
+				remove = append(remove, have)
+			}
+		}
+	}
+	return create, remove
+}
+
+func writeReviewerPlans(w io.Writer, plans []reviewerPlan, dryRun bool) error {
+	for _, plan := range plans {
+		create := make([]string, 0, len(plan.Create))
+		for _, c := range plan.Create {
+			create = append(create, c.String())
+		}
+		remove := make([]string, 0, len(plan.Delete))
+		for _, c := range plan.Delete {
+			line := c.String()
+			if c.ID > 0 {
+				line = fmt.Sprintf("#%d %s", c.ID, line)
+			}
+			remove = append(remove, line)
+		}
+		if err := cmdutil.WritePlan(w, plan.Target, create, remove, plan.Error, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewerApplier adapts one platform's default reviewer API.
+type reviewerApplier interface {
+	desired(ctx context.Context, owner string, policy *reviewerPolicy) ([]reviewerCondition, error)
+	// list returns the conditions configured at the target's scope;
+	// inherited adds Data Center project conditions to a repository's.
+	list(ctx context.Context, t reviewerTarget, inherited bool) ([]reviewerCondition, error)
+	create(ctx context.Context, t reviewerTarget, c reviewerCondition) error
+	remove(ctx context.Context, t reviewerTarget, c reviewerCondition) error
+}
+
+type dcReviewerApplier struct {
+	client *bbdc.Client
+	users  map[string]bbdc.User
+}
+
+func (a *dcReviewerApplier) desired(ctx context.Context, projectKey string, policy *reviewerPolicy) ([]reviewerCondition, error) {
+	var groups []bbdc.ProjectReviewerGroup
+	var conditions []reviewerCondition
+	for i, p := range policy.Conditions {
+		c := reviewerCondition{Approvals: p.Approvals}
+		var err error
+		if c.sourceType, c.sourceID, err = parseRefMatcher(p.Source); err != nil {
+			return nil, fmt.Errorf("condition %d: %w", i+1, err)
+		}
+		if c.targetType, c.targetID, err = parseRefMatcher(p.Target); err != nil {
+			return nil, fmt.Errorf("condition %d: %w", i+1, err)
+		}
+		c.Source = formatRefMatcher(c.sourceType, c.sourceID)
+		c.Target = formatRefMatcher(c.targetType, c.targetID)
+
+		members := map[int]string{}
+		for _, name := range p.Reviewers {
+			user, err := a.user(ctx, name)
+			if err != nil {
+				return nil, fmt.Errorf("condition %d: reviewer %q: %w", i+1, name, err)
+			}
+			members[user.ID] = user.Name
+		}
+		if len(p.Groups) > 0 && groups == nil {
+			if groups, err = a.client.ListProjectReviewerGroups(ctx, projectKey, 0); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range p.Groups {
+			group := findReviewerGroup(groups, name)
+			if group == nil {
+				return nil, fmt.Errorf("condition %d: reviewer group %q not found in project %s", i+1, name, projectKey)
+			}
+			for _, u := range group.Users {
+				members[u.ID] = u.Name
+			}
+		}
+
+		for id, name := range members {
+			c.reviewerIDs = append(c.reviewerIDs, id)
+			c.Reviewers = append(c.Reviewers, name)
+		}
+		sort.Ints(c.reviewerIDs)
+		sort.Strings(c.Reviewers)
+		if c.Approvals < 0 || c.Approvals > len(c.reviewerIDs) {
+			return nil, fmt.Errorf("condition %d: approvals must be between 0 and the number of reviewers (%d)", i+1, len(c.reviewerIDs))
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+func (a *dcReviewerApplier) user(ctx context.Context, name string) (bbdc.User, error) {
+	if u, ok := a.users[name]; ok {
+		return u, nil
+	}
+	u, err := a.client.CurrentUser(ctx, name)
+	if err != nil {
+		return bbdc.User{}, err
+	}
+	if a.users == nil {
+		a.users = map[string]bbdc.User{}
+	}
+	a.users[name] = *u
+	return *u, nil
+}
+
+func (a *dcReviewerApplier) list(ctx context.Context, t reviewerTarget, inherited bool) ([]reviewerCondition, error) {
+	var (
+		found []bbdc.DefaultReviewerCondition
+		err   error
+	)
+	if t.Repo == "" {
+		found, err = a.client.ListProjectDefaultReviewerConditions(ctx, t.Owner)
+	} else {
+		found, err = a.client.ListDefaultReviewerConditions(ctx, t.Owner, t.Repo)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []reviewerCondition{}
+	for _, f := range found {
+		if t.Repo != "" && !inherited && f.Scope.Type == "PROJECT" {
+			continue
+		}
+		c := reviewerCondition{
+			ID:         f.ID,
+			Scope:      f.Scope.Type,
+			Approvals:  f.RequiredApprovals,
+			sourceType: f.SourceRefMatcher.Type.ID,
+			sourceID:   f.SourceRefMatcher.ID,
+			targetType: f.TargetRefMatcher.Type.ID,
+			targetID:   f.TargetRefMatcher.ID,
+		}
+		c.Source = formatRefMatcher(c.sourceType, c.sourceID)
+		c.Target = formatRefMatcher(c.targetType, c.targetID)
+		for _, u := range f.Reviewers {
+			c.reviewerIDs = append(c.reviewerIDs, u.ID)
+			c.Reviewers = append(c.Reviewers, u.Name)
+		}
+		sort.Ints(c.reviewerIDs)
+		sort.Strings(c.Reviewers)
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+func (a *dcReviewerApplier) create(ctx context.Context, t reviewerTarget, c reviewerCondition) error {
+	in := bbdc.DefaultReviewerConditionInput{
+		SourceType:        c.sourceType,
+		SourceID:          c.sourceID,
+		TargetType:        c.targetType,
+		TargetID:          c.targetID,
+		ReviewerIDs:       c.reviewerIDs,
+		RequiredApprovals: c.Approvals,
+	}
+	var err error
+	if t.Repo == "" {
+		_, err = a.client.CreateProjectDefaultReviewerCondition(ctx, t.Owner, in)
+	} else {
+		_, err = a.client.CreateDefaultReviewerCondition(ctx, t.Owner, t.Repo, in)
+	}
+	return err
+}
+
+func (a *dcReviewerApplier) remove(ctx context.Context, t reviewerTarget, c reviewerCondition) error {
+	if t.Repo == "" {
+		return a.client.DeleteProjectDefaultReviewerCondition(ctx, t.Owner, c.ID)
+	}
+	return a.client.DeleteDefaultReviewerCondition(ctx, t.Owner, t.Repo, c.ID)
+}
+
+type cloudReviewerApplier struct {
+	client *bbcloud.Client
+}
+
+func (a *cloudReviewerApplier) desired(_ context.Context, _ string, policy *reviewerPolicy) ([]reviewerCondition, error) {
+	seen := map[string]bool{}
+	var conditions []reviewerCondition
+	for i, p := range policy.Conditions {
+		if !isAnyRef(p.Source) || !isAnyRef(p.Target) {
+			return nil, fmt.Errorf("condition %d: source and target matchers are not supported on Cloud", i+1)
+		}
+		if len(p.Groups) > 0 {
+			return nil, fmt.Errorf("condition %d: groups are not supported on Cloud", i+1)
+		}
+		if p.Approvals != 0 {
+			return nil, fmt.Errorf("condition %d: required approvals are not supported on Cloud; use bkt branch protect add --type require-approvals", i+1)
+		}
+		for _, r := range p.Reviewers {
+			if bbcloud.LooksLikeUUID(r) {
+				r = bbcloud.NormalizeUUID(r)
+			}
+			if !seen[r] {
+				seen[r] = true
+				conditions = append(conditions, reviewerCondition{Reviewers: []string{r}, ref: r})
+			}
+		}
+	}
+	return conditions, nil
+}
+
+func (a *cloudReviewerApplier) list(ctx context.Context, t reviewerTarget, _ bool) ([]reviewerCondition, error) {
+	users, err := a.client.ListDefaultReviewers(ctx, t.Owner, t.Repo)
+	if err != nil {
+		return nil, err
+	}
+	conditions := []reviewerCondition{}
+	for _, u := range users {
+		aliases := map[string]bool{}
+		for _, id := range []string{u.Username, u.Nickname, u.AccountID, u.UUID} {
+			if id != "" {
+				aliases[id] = true
+			}
+		}
+		conditions = append(conditions, reviewerCondition{
+			Reviewers: []string{cmdutil.FirstNonEmpty(u.Username, u.Nickname, u.Display, u.UUID)},
+			aliases:   aliases,
+			ref:       cmdutil.FirstNonEmpty(u.UUID, u.Username),
+		})
+	}
+	return conditions, nil
+}
+
+func (a *cloudReviewerApplier) create(ctx context.Context, t reviewerTarget, c reviewerCondition) error {
+	_, err := a.client.AddDefaultReviewer(ctx, t.Owner, t.Repo, c.ref)
+	return err
+}
+
+func (a *cloudReviewerApplier) remove(ctx context.Context, t reviewerTarget, c reviewerCondition) error {
+	return a.client.RemoveDefaultReviewer(ctx, t.Owner, t.Repo, c.ref)
+}
+
+func findReviewerGroup(groups []bbdc.ProjectReviewerGroup, name string) *bbdc.ProjectReviewerGroup {
+	for i := range groups {
+		if strings.EqualFold(groups[i].Name, name) {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+func isAnyRef(matcher string) bool {
+	matcher = strings.TrimSpace(matcher)
+	return matcher == "" || strings.EqualFold(matcher, "any")
+}
+
+// parseRefMatcher converts a --source/--target value into a Data Center ref
+// matcher type and ID.
+func parseRefMatcher(matcher string) (string, string, error) {
+	matcher = strings.TrimSpace(matcher)
+	kind, value, hasKind := strings.Cut(matcher, ":")
+	if !hasKind {
+		kind, value = "", matcher
+	}
+	switch {
+	case isAnyRef(matcher):
+		return "ANY_REF", "ANY_REF_MATCHER_ID", nil
+	case kind == "model":
+		value = strings.ToLower(value)
+		if value != "development" && value != "production" {
+			return "", "", fmt.Errorf("invalid matcher %q; model branches are development and production", matcher)
+		}
+		return "MODEL_BRANCH", value, nil
+	case kind == "category":
+		if value == "" {
+			return "", "", fmt.Errorf("invalid matcher %q; expected category:TYPE", matcher)
+		}
+		return "MODEL_CATEGORY", strings.ToUpper(value), nil
+	case kind == "pattern":
+		return "PATTERN", value, nil
+	case kind == "branch":
+		matcher = value
+	case strings.ContainsAny(matcher, "*?"):
+		return "PATTERN", matcher, nil
+	}
+	if matcher == "" {
+		return "", "", fmt.Errorf("branch matcher cannot be empty")
+	}
+	if !strings.HasPrefix(matcher, "refs/") {
+		matcher = "refs/heads/" + matcher
+	}
+	return "BRANCH", matcher, nil
+}
+
+// formatRefMatcher is the inverse of parseRefMatcher.
+func formatRefMatcher(typeID, id string) string {
+	switch typeID {
+	case "ANY_REF":
+		return "any"
+	case "MODEL_BRANCH":
+		return "model:" + strings.ToLower(id)
+	case "MODEL_CATEGORY":
+		return "category:" + strings.ToLower(id)
+	case "PATTERN":
+		if strings.ContainsAny(id, "*?") {
+			return id
+		}
+		return "pattern:" + id
+	default:
+		return strings.TrimPrefix(id, "refs/heads/")
+	}
+}
@@ -0,0 +1,310 @@
+package repo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseRefMatcher(t *testing.T) {
+	tests := []struct {
+		in, wantType, wantID, display string
+	}{
+		{"", "ANY_REF", "ANY_REF_MATCHER_ID", "any"},
+		{"any", "ANY_REF", "ANY_REF_MATCHER_ID", "any"},
+		{"main", "BRANCH", "refs/heads/main", "main"},
+		{"branch:release", "BRANCH", "refs/heads/release", "release"},
+		{"release/*", "PATTERN", "release/*", "release/*"},
+		{"pattern:release", "PATTERN", "release", "pattern:release"},
+		{"model:Production", "MODEL_BRANCH", "production", "model:production"},
+		{"category:feature", "MODEL_CATEGORY", "FEATURE", "category:feature"},
+	}
+	for _, tt := range tests {
+		typeID, id, err := parseRefMatcher(tt.in)
+		if err != nil {
+			t.Fatalf("parseRefMatcher(%q): %v", tt.in, err)
+		}
+		if typeID != tt.wantType || id != tt.wantID {
+			t.Fatalf("parseRefMatcher(%q) = %s %s, want %s %s", tt.in, typeID, id, tt.wantType, tt.wantID)
+		}
+		if got := formatRefMatcher(typeID, id); got != tt.display {
+			t.Fatalf("formatRefMatcher(%s, %s) = %q, want %q", typeID, id, got, tt.display)
+		}
+	}
+
+	if _, _, err := parseRefMatcher("model:staging"); err == nil {
+		t.Fatal("expected error for unknown model branch")
+	}
+}
+
+// dcReviewerServer is a Data Center stand-in holding one repository's
+// conditions, including one inherited from the project.
+type dcReviewerServer struct {
+	mu         sync.Mutex
+	nextID     int
+	conditions []map[string]any
+	created    []map[string]any
+	deleted    []string
+}
+
+func newDCReviewerServer(t *testing.T) (*dcReviewerServer, *httptest.Server) {
+	s := &dcReviewerServer{
+		nextID: 10,
+		conditions: []map[string]any{
+			dcCondition(1, "PROJECT", "ANY_REF", "ANY_REF_MATCHER_ID", "ANY_REF", "ANY_REF_MATCHER_ID", 0, 7),
+			dcCondition(2, "REPOSITORY", "ANY_REF", "ANY_REF_MATCHER_ID", "BRANCH", "refs/heads/main", 1, 7, 8),
+			dcCondition(3, "REPOSITORY", "ANY_REF", "ANY_REF_MATCHER_ID", "BRANCH", "refs/heads/develop", 0, 8),
+		},
+	}
+	users := map[string]int{"alice": 7, "bob": 8, "carol": 9}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		const base = "/rest/default-reviewers/1.0/projects/PROJ/repos/repo"
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/rest/api/1.0/users/"):
+			name := strings.TrimPrefix(r.URL.Path, "/rest/api/1.0/users/")
+			id, ok := users[name]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": name, "slug": name, "id": id})
+		case r.URL.Path == "/rest/api/1.0/projects/PROJ/settings/reviewer-groups":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"isLastPage": true,
+				"values": []map[string]any{{
+					"id":    1,
+					"name":  "leads",
+					"users": []map[string]any{{"name": "carol", "id": 9}},
+				}},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == base+"/conditions":
+			_ = json.NewEncoder(w).Encode(s.conditions)
+		case r.Method == http.MethodPost && r.URL.Path == base+"/condition":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			s.created = append(s.created, body)
+			var ids []int
+			for _, rv := range body["reviewers"].([]any) {
+				ids = append(ids, int(rv.(map[string]any)["id"].(float64)))
+			}
+			src := body["sourceMatcher"].(map[string]any)
+			tgt := body["targetMatcher"].(map[string]any)
+			c := dcCondition(s.nextID, "REPOSITORY",
+				src["type"].(map[string]any)["id"].(string), src["id"].(string),
+				tgt["type"].(map[string]any)["id"].(string), tgt["id"].(string),
+				int(body["requiredApprovals"].(float64)), ids...)
+			s.nextID++
+			s.conditions = append(s.conditions, c)
+			_ = json.NewEncoder(w).Encode(c)
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, base+"/condition/"):
+			id := strings.TrimPrefix(r.URL.Path, base+"/condition/")
+			s.deleted = append(s.deleted, id)
+			kept := s.conditions[:0]
+			for _, c := range s.conditions {
+				if jsonID(c) != id {
+					kept = append(kept, c)
+				}
+			}
+			s.conditions = kept
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func dcCondition(id int, scope, srcType, srcID, tgtType, tgtID string, approvals int, reviewerIDs ...int) map[string]any {
+	names := map[int]string{7: "alice", 8: "bob", 9: "carol"}
+	var reviewers []map[string]any
+	for _, rid := range reviewerIDs {
+		reviewers = append(reviewers, map[string]any{"id": rid, "name": names[rid]})
+	}
+	return map[string]any{
+		"id":                id,
+		"scope":             map[string]any{"type": scope},
+		"sourceRefMatcher":  map[string]any{"id": srcID, "type": map[string]any{"id": srcType}},
+		"targetRefMatcher":  map[string]any{"id": tgtID, "type": map[string]any{"id": tgtType}},
+		"reviewers":         reviewers,
+		"requiredApprovals": approvals,
+	}
+}
+
+func jsonID(c map[string]any) string {
+	b, _ := json.Marshal(c["id"])
+	return string(b)
+}
+
+func runDefaultReviewers(t *testing.T, kind, baseURL string, args ...string) (string, error) {
+	t.Helper()
+	stdout := &strings.Builder{}
+	root := &cobra.Command{Use: "bkt", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().Bool("json", false, "")
+	root.AddCommand(newDefaultReviewersCmd(settingsFactory(kind, baseURL, stdout)))
+	root.SetArgs(append([]string{"default-reviewers"}, args...))
+	err := root.Execute()
+	return stdout.String(), err
+}
+
+func TestDefaultReviewersSetDataCenterSyncsFile(t *testing.T) {
+	state, server := newDCReviewerServer(t)
+
+	file := filepath.Join(t.TempDir(), "reviewers.yaml")
+	policy := `conditions:
+  - target: main
+    reviewers: [bob, alice]
+    approvals: 1
+  - source: hotfix/*
+    target: model:production
+    reviewers: [alice]
+    groups: [leads]
+`
+	if err := os.WriteFile(file, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runDefaultReviewers(t, "dc", server.URL, "set", "--from-file", file)
+	if err != nil {
+		t.Fatalf("set: %v\n%s", err, out)
+	}
+
+	if len(state.created) != 1 {
+		t.Fatalf("created %d conditions, want 1: %+v", len(state.created), state.created)
+	}
+	created := state.created[0]
+	if src := created["sourceMatcher"].(map[string]any); src["id"] != "hotfix/*" || src["type"].(map[string]any)["id"] != "PATTERN" {
+		t.Fatalf("sourceMatcher = %+v", src)
+	}
+	if tgt := created["targetMatcher"].(map[string]any); tgt["id"] != "production" || tgt["type"].(map[string]any)["id"] != "MODEL_BRANCH" {
+		t.Fatalf("targetMatcher = %+v", tgt)
+	}
+	if reviewers := created["reviewers"].([]any); len(reviewers) != 2 {
+		t.Fatalf("reviewers = %+v, want alice and carol from the group", reviewers)
+	}
+	// The develop condition is stale; the inherited project condition stays.
+	if len(state.deleted) != 1 || state.deleted[0] != "3" {
+		t.Fatalf("deleted = %v, want [3]", state.deleted)
+	}
+	if !strings.Contains(out, "PROJ/repo\t- #3 any → develop: bob (0 required)") {
+		t.Fatalf("missing delete line:\n%s", out)
+	}
+	if !strings.Contains(out, "PROJ/repo: 1 created, 1 deleted") {
+		t.Fatalf("missing summary:\n%s", out)
+	}
+
+	out, err = runDefaultReviewers(t, "dc", server.URL, "set", "--from-file", file)
+	if err != nil {
+		t.Fatalf("second set: %v", err)
+	}
+	if !strings.Contains(out, "PROJ/repo: up to date") {
+		t.Fatalf("rerun should be a no-op:\n%s", out)
+	}
+}
+
+func TestDefaultReviewersAddDataCenterSkipsExisting(t *testing.T) {
+	state, server := newDCReviewerServer(t)
+
+	out, err := runDefaultReviewers(t, "dc", server.URL, "add", "--target", "main", "--reviewer", "alice", "--reviewer", "bob", "--approvals", "1")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if len(state.created) != 0 || len(state.deleted) != 0 {
+		t.Fatalf("expected no changes, created=%v deleted=%v", state.created, state.deleted)
+	}
+	if !strings.Contains(out, "up to date") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	if _, err := runDefaultReviewers(t, "dc", server.URL, "add", "--reviewer", "alice", "--approvals", "2"); err == nil ||
+		!strings.Contains(err.Error(), "approvals must be between 0 and the number of reviewers") {
+		t.Fatalf("expected approvals error, got %v", err)
+	}
+}
+
+func TestDefaultReviewersSetCloud(t *testing.T) {
+	var added, removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		const base = "/repositories/ws/repo/default-reviewers"
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == base:
+			_ = json.NewEncoder(w).Encode(map[string]any{"values": []map[string]any{
+				{"username": "alice", "uuid": "{11111111-1111-1111-1111-111111111111}"},
+				{"username": "bob", "uuid": "{22222222-2222-2222-2222-222222222222}"},
+			}})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, base+"/"):
+			added = append(added, strings.TrimPrefix(r.URL.Path, base+"/"))
+			_ = json.NewEncoder(w).Encode(map[string]any{})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, base+"/"):
+			removed = append(removed, strings.TrimPrefix(r.URL.Path, base+"/"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	out, err := runDefaultReviewers(t, "cloud", server.URL, "set", "--reviewer", "alice", "--reviewer", "carol")
+	if err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if len(added) != 1 || added[0] != "carol" {
+		t.Fatalf("added = %v, want [carol]", added)
+	}
+	if len(removed) != 1 || removed[0] != "{22222222-2222-2222-2222-222222222222}" {
+		t.Fatalf("removed = %v, want bob's UUID", removed)
+	}
+	if !strings.Contains(out, "ws/repo: 1 created, 1 deleted") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestDefaultReviewersValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		kind          string
+		args          []string
+		errorContains string
+	}{
+		{"cloud project scope", "cloud", []string{"add", "--scope", "project", "--reviewer", "alice"}, "only supported on Data Center"},
+		{"cloud matchers", "cloud", []string{"add", "--target", "main", "--reviewer", "alice"}, "matchers are not supported on Cloud"},
+		{"no reviewers", "dc", []string{"add", "--target", "main"}, "at least one --reviewer or --group"},
+		{"file and flags", "dc", []string{"set", "--from-file", "x.yaml", "--reviewer", "alice"}, "--reviewer cannot be combined with --from-file"},
+		{"repos with project scope", "dc", []string{"add", "--scope", "project", "--repos", "PROJ/*", "--reviewer", "alice"}, "--repos cannot be combined with --scope project"},
+		{"dc remove by reviewer", "dc", []string{"remove", "--reviewer", "alice"}, "removed by condition ID"},
+		{"cloud remove by id", "cloud", []string{"remove", "3"}, "removed with --reviewer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				http.NotFound(w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			_, err := runDefaultReviewers(t, tt.kind, server.URL, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Fatalf("error = %v, want substring %q", err, tt.errorContains)
+			}
+			if hits != 0 {
+				t.Fatalf("expected validation to avoid HTTP requests, got %d", hits)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
 	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmd/accesstoken"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 )
 
@@ -33,6 +34,8 @@ Cloud hosts when the active context does not define defaults.`,
 	cmd.AddCommand(newCloneCmd(f))
 	cmd.AddCommand(newBrowseCmd(f))
 	cmd.AddCommand(newDefaultReviewersCmd(f))
+	cmd.AddCommand(newSettingsCmd(f))
+	cmd.AddCommand(accesstoken.NewCmdToken(f, accesstoken.ScopeRepository))
 
 	return cmd
 }
@@ -124,11 +127,6 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
 		defer cancel()
 
-		repos, err := client.ListRepositories(ctx, projectKey, opts.Limit)
-		if err != nil {
-			return err
-		}
-
 		type repoSummary struct {
 			Project string   `json:"project"`
 			Slug    string   `json:"slug"`
@@ -138,16 +136,29 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			Clone   []string `json:"clone_urls,omitempty"`
 		}
 
+		stream, err := cmdutil.StreamOutput(cmd, ios.Out)
+		if err != nil {
+			return err
+		}
+
 		var summaries []repoSummary
-		for _, repo := range repos {
-			summaries = append(summaries, repoSummary{
+		err = client.EachRepository(ctx, projectKey, opts.Limit, func(repo bbdc.Repository) error {
+			summary := repoSummary{
 				Project: repo.Project.Key,
 				Slug:    repo.Slug,
 				Name:    repo.Name,
 				ID:      repo.ID,
 				WebURL:  firstLinkDC(repo, "web"),
 				Clone:   cloneLinksDC(repo),
-			})
+			}
+			if stream != nil {
+				return stream.Write(summary)
+			}
+			summaries = append(summaries, summary)
+			return nil
+		})
+		if err != nil || stream != nil {
+			return err
 		}
 
 		payload := struct {
@@ -158,7 +169,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			Repos:   summaries,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(summaries) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No repositories found in project %s.\n", projectKey)
 				return err
@@ -199,11 +210,6 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
 		defer cancel()
 
-		repos, err := client.ListRepositories(ctx, workspace, opts.Limit)
-		if err != nil {
-			return err
-		}
-
 		type repoSummary struct {
 			Workspace string   `json:"workspace"`
 			Slug      string   `json:"slug"`
@@ -213,16 +219,29 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			Clone     []string `json:"clone_urls,omitempty"`
 		}
 
+		stream, err := cmdutil.StreamOutput(cmd, ios.Out)
+		if err != nil {
+			return err
+		}
+
 		var summaries []repoSummary
-		for _, repo := range repos {
-			summaries = append(summaries, repoSummary{
+		err = client.EachRepository(ctx, workspace, opts.Limit, func(repo bbcloud.Repository) error {
+			summary := repoSummary{
 				Workspace: workspace,
 				Slug:      repo.Slug,
 				Name:      repo.Name,
 				UUID:      strings.Trim(repo.UUID, "{}"),
 				WebURL:    firstLinkCloud(repo),
 				Clone:     cloneLinksCloud(repo),
-			})
+			}
+			if stream != nil {
+				return stream.Write(summary)
+			}
+			summaries = append(summaries, summary)
+			return nil
+		})
+		if err != nil || stream != nil {
+			return err
 		}
 
 		payload := struct {
@@ -233,7 +252,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			Repos:     summaries,
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(summaries) == 0 {
 				_, err := fmt.Fprintf(ios.Out, "No repositories found in workspace %s.\n", workspace)
 				return err
@@ -380,7 +399,7 @@ func runView(cmd *cobra.Command, f *cmdutil.Factory, opts *viewOptions) error {
 			Clone:   cloneLinksDC(*repo),
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, details, func() error {
+		return cmdutil.WriteOutput(cmd, ios, details, func() error {
 			if _, err := fmt.Fprintf(ios.Out, "%s/%s (%d)\n", details.Project, details.Slug, details.ID); err != nil {
 				return err
 			}
@@ -450,7 +469,7 @@ func runView(cmd *cobra.Command, f *cmdutil.Factory, opts *viewOptions) error {
 			Clone:     cloneLinksCloud(*repo),
 		}
 
-		return cmdutil.WriteOutput(cmd, ios.Out, details, func() error {
+		return cmdutil.WriteOutput(cmd, ios, details, func() error {
 			if _, err := fmt.Fprintf(ios.Out, "%s/%s (%s)\n", details.Workspace, details.Slug, details.UUID); err != nil {
 				return err
 			}
@@ -520,7 +539,7 @@ func runClone(cmd *cobra.Command, f *cmdutil.Factory, opts *cloneOptions) error
 			return err
 		}
 
-		cloneURL, err := selectCloneURLDC(*repo, opts.UseSSH)
+		cloneURL, err := SelectCloneURLDC(*repo, opts.UseSSH)
 		if err != nil {
 			return err
 		}
@@ -557,7 +576,7 @@ func runClone(cmd *cobra.Command, f *cmdutil.Factory, opts *cloneOptions) error
 			return err
 		}
 
-		cloneURL, err := selectCloneURLCloud(*repo, opts.UseSSH)
+		cloneURL, err := SelectCloneURLCloud(*repo, opts.UseSSH)
 		if err != nil {
 			return err
 		}
@@ -970,7 +989,9 @@ func cloneLinksCloud(repo bbcloud.Repository) []string {
 	return urls
 }
 
-func selectCloneURLDC(repo bbdc.Repository, useSSH bool) (string, error) {
+// SelectCloneURLDC returns the repository's ssh clone URL when useSSH is set,
+// otherwise its https (or http) one.
+func SelectCloneURLDC(repo bbdc.Repository, useSSH bool) (string, error) {
 	if useSSH {
 		for _, link := range repo.Links.Clone {
 			if strings.EqualFold(link.Name, "ssh") {
@@ -989,7 +1010,8 @@ func selectCloneURLDC(repo bbdc.Repository, useSSH bool) (string, error) {
 	return "", fmt.Errorf("no https clone URL available")
 }
 
-func selectCloneURLCloud(repo bbcloud.Repository, useSSH bool) (string, error) {
+// SelectCloneURLCloud returns the repository's ssh or https clone URL.
+func SelectCloneURLCloud(repo bbcloud.Repository, useSSH bool) (string, error) {
 	desired := "https"
 	if useSSH {
 		desired = "ssh"
@@ -8,6 +8,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+
 	"github.com/avivsinai/bitbucket-cli/internal/config"
 	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
@@ -24,9 +26,9 @@ func TestSelectCloneURLDCPrefersHTTPS(t *testing.T) {
 		{Href: "https://bitbucket.example.com/scm/PROJ/repo.git", Name: "https"},
 	}
 
-	got, err := selectCloneURLDC(r, false)
+	got, err := SelectCloneURLDC(r, false)
 	if err != nil {
-		t.Fatalf("selectCloneURLDC returned error: %v", err)
+		t.Fatalf("SelectCloneURLDC returned error: %v", err)
 	}
 	if got != "https://bitbucket.example.com/scm/PROJ/repo.git" {
 		t.Fatalf("got %q, want https link", got)
@@ -42,9 +44,9 @@ func TestSelectCloneURLDCHttpAlias(t *testing.T) {
 		{Href: "http://bitbucket.example.com/scm/PROJ/repo.git", Name: "http"},
 	}
 
-	got, err := selectCloneURLDC(r, false)
+	got, err := SelectCloneURLDC(r, false)
 	if err != nil {
-		t.Fatalf("selectCloneURLDC returned error: %v", err)
+		t.Fatalf("SelectCloneURLDC returned error: %v", err)
 	}
 	if got != "http://bitbucket.example.com/scm/PROJ/repo.git" {
 		t.Fatalf("got %q, want http link", got)
@@ -61,9 +63,9 @@ func TestSelectCloneURLDCSsh(t *testing.T) {
 		{Href: "https://bitbucket.example.com/scm/PROJ/repo.git", Name: "https"},
 	}
 
-	got, err := selectCloneURLDC(r, true)
+	got, err := SelectCloneURLDC(r, true)
 	if err != nil {
-		t.Fatalf("selectCloneURLDC returned error: %v", err)
+		t.Fatalf("SelectCloneURLDC returned error: %v", err)
 	}
 	if !strings.HasPrefix(got, "ssh://") {
 		t.Fatalf("got %q, want ssh link", got)
@@ -79,7 +81,7 @@ func TestSelectCloneURLDCMissing(t *testing.T) {
 		{Href: "https://bitbucket.example.com/scm/PROJ/repo.git", Name: "https"},
 	}
 
-	_, err := selectCloneURLDC(r, true)
+	_, err := SelectCloneURLDC(r, true)
 	if err == nil {
 		t.Fatalf("expected error when ssh clone missing")
 	}
@@ -375,3 +377,53 @@ func TestRepoCreateRejectsHostNoOpFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestListStreamsNDJSONWithFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PROJ/repos" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		repos := []map[string]any{
+			{"slug": "api", "name": "API", "id": 1, "project": map[string]any{"key": "PROJ"}},
+			{"slug": "web", "name": "Web", "id": 2, "project": map[string]any{"key": "PROJ"}},
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"values": repos, "isLastPage": true})
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := &config.Config{
+		ActiveContext: "default",
+		Contexts: map[string]*config.Context{
+			"default": {Host: "main", ProjectKey: "PROJ"},
+		},
+		Hosts: map[string]*config.Host{
+			"main": {Kind: "dc", BaseURL: server.URL, Token: "test-token"},
+		},
+	}
+
+	stdout := &strings.Builder{}
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: stdout, ErrOut: &strings.Builder{}},
+		Config: func() (*config.Config, error) {
+			return cfg, nil
+		},
+	}
+
+	root := &cobra.Command{Use: "bkt", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().String("format", "", "")
+	root.PersistentFlags().StringSlice("fields", nil, "")
+	root.AddCommand(newListCmd(f))
+	root.SetArgs([]string{"list", "--format", "ndjson", "--fields", "slug,name"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "{\"slug\":\"api\",\"name\":\"API\"}\n{\"slug\":\"web\",\"name\":\"Web\"}\n"
+	if stdout.String() != want {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+	}
+}
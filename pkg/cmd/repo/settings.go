@@ -0,0 +1,645 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+type settingsOptions struct {
+	Project   string
+	Workspace string
+	Repo      string
+	Branch    string
+
+	DevelopmentBranch    string
+	ProductionBranch     string
+	Prefixes             []string
+	MinApprovals         int
+	RequireTasksResolved bool
+	MinSuccessfulBuilds  int
+	MergeStrategies      []string
+	DefaultMergeStrategy string
+}
+
+type settingsSummary struct {
+	Project         string                 `json:"project,omitempty"`
+	Workspace       string                 `json:"workspace,omitempty"`
+	Repo            string                 `json:"repo"`
+	BranchModel     branchModelSummary     `json:"branch_model"`
+	MergeChecks     *mergeChecksSummary    `json:"merge_checks,omitempty"`
+	MergeStrategies mergeStrategiesSummary `json:"merge_strategies"`
+}
+
+type branchModelSummary struct {
+	Development *modelBranchSummary `json:"development,omitempty"`
+	Production  *modelBranchSummary `json:"production,omitempty"`
+	Types       []branchTypeSummary `json:"types"`
+}
+
+type modelBranchSummary struct {
+	Branch     string `json:"branch,omitempty"`
+	UseDefault bool   `json:"use_default"`
+}
+
+type branchTypeSummary struct {
+	Type    string `json:"type"`
+	Prefix  string `json:"prefix,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+type mergeChecksSummary struct {
+	MinApprovals         int  `json:"min_approvals"`
+	RequireAllApprovers  bool `json:"require_all_approvers"`
+	RequireTasksResolved bool `json:"require_tasks_resolved"`
+	MinSuccessfulBuilds  int  `json:"min_successful_builds"`
+}
+
+type mergeStrategiesSummary struct {
+	Branch  string   `json:"branch,omitempty"`
+	Default string   `json:"default,omitempty"`
+	Enabled []string `json:"enabled"`
+}
+
+func newSettingsCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "settings",
+		Short: "View and change repository settings",
+		Long: `View and change a repository's branching model, merge checks, and merge
+strategies.
+
+On Data Center, the branching model, merge checks (minimum approvals, resolved
+tasks, successful builds), and enabled merge strategies can all be viewed and
+changed. On Cloud, the branching model can be viewed and changed, and the merge
+strategies allowed for a branch can be viewed. Cloud merge checks are branch
+restrictions; manage them with bkt branch protect.`,
+	}
+	cmd.AddCommand(newSettingsViewCmd(f))
+	cmd.AddCommand(newSettingsSetCmd(f))
+	return cmd
+}
+
+func newSettingsViewCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &settingsOptions{}
+	cmd := &cobra.Command{
+		Use:   "view",
+		Short: "Show the branching model, merge checks, and merge strategies",
+		Long: `Show a repository's branching model, merge checks, and enabled merge
+strategies.
+
+On Cloud, merge strategies are reported for the repository's main branch unless
+--branch selects another branch.`,
+		Example: `  # Show settings for the active context repository
+  bkt repo settings view
+
+  # Show settings for a Data Center repository as JSON
+  bkt repo settings view --project PLATFORM --repo backend --json
+
+  # Show the merge strategies allowed for a Cloud release branch
+  bkt repo settings view --workspace my-team --repo api-service --branch release/1.x`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSettingsView(cmd, f, opts)
+		},
+	}
+	addSettingsRepoFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Cloud branch whose merge strategies to show (defaults to the main branch)")
+	return cmd
+}
+
+func newSettingsSetCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &settingsOptions{}
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Change the branching model, merge checks, or merge strategies",
+		Long: `Change repository settings. Only the settings named by flags are changed.
+
+Branching model (Data Center and Cloud):
+  --development-branch NAME   Development branch; "default" follows the default branch
+  --production-branch NAME    Production branch; "default" follows the default branch
+                              and an empty value disables it
+  --prefix TYPE=PREFIX        Branch type prefix (bugfix, feature, hotfix, release);
+                              an empty prefix disables the type
+
+Merge checks (Data Center only):
+  --min-approvals N, --require-tasks-resolved, --min-successful-builds N
+
+Merge strategies (Data Center only):
+  --merge-strategies IDS      Enabled strategy IDs (e.g. no-ff,squash)
+  --default-merge-strategy ID Default strategy; defaults to the first enabled one
+
+On Cloud, merge checks are branch restrictions (see bkt branch protect) and merge
+strategies can only be changed in the repository settings page.`,
+		Example: `  # Use develop as the development branch and disable hotfix branches
+  bkt repo settings set --development-branch develop --prefix hotfix=
+
+  # Require two approvals and resolved tasks before merging (Data Center)
+  bkt repo settings set --min-approvals 2 --require-tasks-resolved
+
+  # Allow only squash and no-ff merges, defaulting to squash (Data Center)
+  bkt repo settings set --merge-strategies squash,no-ff`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSettingsSet(cmd, f, opts)
+		},
+	}
+	addSettingsRepoFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.DevelopmentBranch, "development-branch", "", `Development branch ("default" for the default branch)`)
+	cmd.Flags().StringVar(&opts.ProductionBranch, "production-branch", "", `Production branch ("default" for the default branch, empty to disable)`)
+	cmd.Flags().StringArrayVar(&opts.Prefixes, "prefix", nil, "Branch type prefix in TYPE=PREFIX form; empty PREFIX disables the type (repeatable)")
+	cmd.Flags().IntVar(&opts.MinApprovals, "min-approvals", 0, "Minimum approvals required to merge (Data Center)")
+	cmd.Flags().BoolVar(&opts.RequireTasksResolved, "require-tasks-resolved", false, "Require all tasks to be resolved before merging (Data Center)")
+	cmd.Flags().IntVar(&opts.MinSuccessfulBuilds, "min-successful-builds", 0, "Minimum successful builds required to merge (Data Center)")
+	cmd.Flags().StringSliceVar(&opts.MergeStrategies, "merge-strategies", nil, "Enabled merge strategy IDs (Data Center)")
+	cmd.Flags().StringVar(&opts.DefaultMergeStrategy, "default-merge-strategy", "", "Default merge strategy ID (Data Center)")
+	return cmd
+}
+
+func addSettingsRepoFlags(cmd *cobra.Command, opts *settingsOptions) {
+	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket Data Center project key override")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket Cloud workspace override")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+}
+
+func runSettingsView(cmd *cobra.Command, f *cmdutil.Factory, opts *settingsOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	override := cmdutil.FlagValue(cmd, "context")
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 15*time.Second)
+	defer cancel()
+
+	var summary *settingsSummary
+	switch host.Kind {
+	case "dc":
+		if opts.Branch != "" {
+			return fmt.Errorf("--branch is only supported for Cloud; Data Center merge strategies apply to the whole repository")
+		}
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if projectKey == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+		}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+		summary, err = dcSettingsSummary(ctx, client, projectKey, repoSlug)
+		if err != nil {
+			return err
+		}
+
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if workspace == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+		summary, err = cloudSettingsSummary(ctx, client, workspace, repoSlug, opts.Branch)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, summary, func() error {
+		return writeSettingsSummary(ios.Out, summary)
+	})
+}
+
+func runSettingsSet(cmd *cobra.Command, f *cmdutil.Factory, opts *settingsOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	branchModelChanged := flags.Changed("development-branch") || flags.Changed("production-branch") || flags.Changed("prefix")
+	mergeChecksChanged := flags.Changed("min-approvals") || flags.Changed("require-tasks-resolved") || flags.Changed("min-successful-builds")
+	strategiesChanged := flags.Changed("merge-strategies") || flags.Changed("default-merge-strategy")
+	if !branchModelChanged && !mergeChecksChanged && !strategiesChanged {
+		return fmt.Errorf("no settings to change; see bkt repo settings set --help")
+	}
+	if flags.Changed("development-branch") && strings.TrimSpace(opts.DevelopmentBranch) == "" {
+		return fmt.Errorf("--development-branch cannot be empty")
+	}
+	if opts.MinApprovals < 0 || opts.MinSuccessfulBuilds < 0 {
+		return fmt.Errorf("--min-approvals and --min-successful-builds cannot be negative")
+	}
+	prefixes, err := parseBranchTypePrefixes(opts.Prefixes)
+	if err != nil {
+		return err
+	}
+
+	override := cmdutil.FlagValue(cmd, "context")
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+	defer cancel()
+
+	var summary *settingsSummary
+	switch host.Kind {
+	case "dc":
+		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if projectKey == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+		}
+		client, err := cmdutil.NewDCClient(host)
+		if err != nil {
+			return err
+		}
+
+		if branchModelChanged {
+			cfg, err := client.GetBranchModelConfig(ctx, projectKey, repoSlug)
+			if err != nil {
+				return err
+			}
+			if flags.Changed("development-branch") {
+				cfg.Development = dcModelBranch(opts.DevelopmentBranch)
+			}
+			if flags.Changed("production-branch") {
+				cfg.Production = nil
+				if strings.TrimSpace(opts.ProductionBranch) != "" {
+					cfg.Production = dcModelBranch(opts.ProductionBranch)
+				}
+			}
+			for _, p := range prefixes {
+				idx := -1
+				for i, t := range cfg.Types {
+					if strings.EqualFold(t.ID, p.Type) {
+						idx = i
+						break
+					}
+				}
+				if idx < 0 {
+					return unknownBranchTypeError(p.Type, len(cfg.Types), func(i int) string { return cfg.Types[i].ID })
+				}
+				cfg.Types[idx].Prefix = p.Prefix
+				cfg.Types[idx].Enabled = p.Prefix != ""
+			}
+			if _, err := client.UpdateBranchModelConfig(ctx, projectKey, repoSlug, *cfg); err != nil {
+				return err
+			}
+		}
+
+		if mergeChecksChanged || strategiesChanged {
+			var in bbdc.PullRequestSettingsInput
+			if flags.Changed("min-approvals") {
+				in.RequiredApprovers = &opts.MinApprovals
+			}
+			if flags.Changed("require-tasks-resolved") {
+				in.RequiredAllTasksComplete = &opts.RequireTasksResolved
+			}
+			if flags.Changed("min-successful-builds") {
+				in.RequiredSuccessfulBuilds = &opts.MinSuccessfulBuilds
+			}
+			if strategiesChanged {
+				in.Strategies, in.DefaultStrategy, err = dcStrategySelection(ctx, client, projectKey, repoSlug, opts)
+				if err != nil {
+					return err
+				}
+			}
+			if _, err := client.UpdatePullRequestSettings(ctx, projectKey, repoSlug, in); err != nil {
+				return err
+			}
+		}
+
+		summary, err = dcSettingsSummary(ctx, client, projectKey, repoSlug)
+		if err != nil {
+			return err
+		}
+
+	case "cloud":
+		if mergeChecksChanged {
+			return fmt.Errorf("merge checks are branch restrictions on Cloud; use bkt branch protect add --type require-approvals, require-tasks-completed, or require-passing-builds")
+		}
+		if strategiesChanged {
+			return fmt.Errorf("merge strategies cannot be changed through the Bitbucket Cloud API; use Repository settings → Merge strategies in the web UI")
+		}
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if workspace == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+		client, err := cmdutil.NewCloudClient(host)
+		if err != nil {
+			return err
+		}
+
+		model, err := client.GetBranchingModelSettings(ctx, workspace, repoSlug)
+		if err != nil {
+			return err
+		}
+		if flags.Changed("development-branch") {
+			model.Development = cloudModelBranch(opts.DevelopmentBranch)
+		}
+		if flags.Changed("production-branch") {
+			enabled := strings.TrimSpace(opts.ProductionBranch) != ""
+			if enabled {
+				model.Production = cloudModelBranch(opts.ProductionBranch)
+			}
+			model.Production.Enabled = &enabled
+		}
+		for _, p := range prefixes {
+			idx := -1
+			for i, t := range model.BranchTypes {
+				if strings.EqualFold(t.Kind, p.Type) {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				return unknownBranchTypeError(p.Type, len(model.BranchTypes), func(i int) string { return model.BranchTypes[i].Kind })
+			}
+			model.BranchTypes[idx].Prefix = p.Prefix
+			model.BranchTypes[idx].Enabled = p.Prefix != ""
+		}
+		if _, err := client.UpdateBranchingModelSettings(ctx, workspace, repoSlug, *model); err != nil {
+			return err
+		}
+
+		summary, err = cloudSettingsSummary(ctx, client, workspace, repoSlug, "")
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+
+	return cmdutil.WriteOutput(cmd, ios, summary, func() error {
+		if _, err := fmt.Fprintf(ios.Out, "✓ Updated settings for %s/%s\n\n", cmdutil.FirstNonEmpty(summary.Project, summary.Workspace), summary.Repo); err != nil {
+			return err
+		}
+		return writeSettingsSummary(ios.Out, summary)
+	})
+}
+
+// dcStrategySelection resolves the strategy list and default to send. When
+// only --default-merge-strategy is given, the current enabled strategies are
+// kept and the default is added to them if needed.
+func dcStrategySelection(ctx context.Context, client *bbdc.Client, projectKey, repoSlug string, opts *settingsOptions) ([]string, string, error) {
+	var strategies []string
+	for _, s := range opts.MergeStrategies {
+		if s = strings.TrimSpace(s); s != "" {
+			strategies = append(strategies, s)
+		}
+	}
+	def := strings.TrimSpace(opts.DefaultMergeStrategy)
+
+	if len(strategies) == 0 {
+		if def == "" {
+			return nil, "", fmt.Errorf("--merge-strategies requires at least one strategy ID")
+		}
+		current, err := client.GetPullRequestSettings(ctx, projectKey, repoSlug)
+		if err != nil {
+			return nil, "", err
+		}
+		strategies = current.MergeConfig.EnabledStrategies()
+	}
+	if def == "" {
+		def = strategies[0]
+	}
+	if !slices.Contains(strategies, def) {
+		if len(opts.MergeStrategies) > 0 {
+			return nil, "", fmt.Errorf("default merge strategy %q must be one of --merge-strategies", def)
+		}
+		strategies = append(strategies, def)
+	}
+	return strategies, def, nil
+}
+
+func dcSettingsSummary(ctx context.Context, client *bbdc.Client, projectKey, repoSlug string) (*settingsSummary, error) {
+	model, err := client.GetBranchModelConfig(ctx, projectKey, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+	prSettings, err := client.GetPullRequestSettings(ctx, projectKey, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &settingsSummary{
+		Project: projectKey,
+		Repo:    repoSlug,
+		MergeChecks: &mergeChecksSummary{
+			MinApprovals:         prSettings.RequiredApprovers,
+			RequireAllApprovers:  prSettings.RequiredAllApprovers,
+			RequireTasksResolved: prSettings.RequiredAllTasksComplete,
+			MinSuccessfulBuilds:  prSettings.RequiredSuccessfulBuilds,
+		},
+		MergeStrategies: mergeStrategiesSummary{
+			Enabled: prSettings.MergeConfig.EnabledStrategies(),
+		},
+	}
+	if prSettings.MergeConfig.DefaultStrategy != nil {
+		summary.MergeStrategies.Default = prSettings.MergeConfig.DefaultStrategy.ID
+	}
+	if model.Development != nil {
+		summary.BranchModel.Development = &modelBranchSummary{
+			Branch:     strings.TrimPrefix(model.Development.RefID, "refs/heads/"),
+			UseDefault: model.Development.UseDefault,
+		}
+	}
+	if model.Production != nil {
+		summary.BranchModel.Production = &modelBranchSummary{
+			Branch:     strings.TrimPrefix(model.Production.RefID, "refs/heads/"),
+			UseDefault: model.Production.UseDefault,
+		}
+	}
+	for _, t := range model.Types {
+		summary.BranchModel.Types = append(summary.BranchModel.Types, branchTypeSummary{
+			Type:    strings.ToLower(t.ID),
+			Prefix:  t.Prefix,
+			Enabled: t.Enabled,
+		})
+	}
+	return summary, nil
+}
+
+func cloudSettingsSummary(ctx context.Context, client *bbcloud.Client, workspace, repoSlug, branch string) (*settingsSummary, error) {
+	model, err := client.GetBranchingModelSettings(ctx, workspace, repoSlug)
+	if err != nil {
+		return nil, err
+	}
+	if branch == "" {
+		repo, err := client.GetRepository(ctx, workspace, repoSlug)
+		if err != nil {
+			return nil, err
+		}
+		branch = repo.MainBranch.Name
+	}
+
+	summary := &settingsSummary{
+		Workspace: workspace,
+		Repo:      repoSlug,
+		BranchModel: branchModelSummary{
+			Development: &modelBranchSummary{
+				Branch:     model.Development.Name,
+				UseDefault: model.Development.UseMainbranch,
+			},
+		},
+		MergeStrategies: mergeStrategiesSummary{Branch: branch},
+	}
+	if model.Production.Enabled != nil && *model.Production.Enabled {
+		summary.BranchModel.Production = &modelBranchSummary{
+			Branch:     model.Production.Name,
+			UseDefault: model.Production.UseMainbranch,
+		}
+	}
+	for _, t := range model.BranchTypes {
+		summary.BranchModel.Types = append(summary.BranchModel.Types, branchTypeSummary{
+			Type:    t.Kind,
+			Prefix:  t.Prefix,
+			Enabled: t.Enabled,
+		})
+	}
+
+	if branch != "" {
+		b, err := client.GetBranch(ctx, workspace, repoSlug, branch)
+		if err != nil {
+			return nil, err
+		}
+		summary.MergeStrategies.Default = b.DefaultMergeStrategy
+		summary.MergeStrategies.Enabled = b.MergeStrategies
+	}
+	return summary, nil
+}
+
+func writeSettingsSummary(w io.Writer, s *settingsSummary) error {
+	lines := []string{
+		"Branching model",
+		fmt.Sprintf("  Development: %s", formatModelBranch(s.BranchModel.Development)),
+		fmt.Sprintf("  Production:  %s", formatModelBranch(s.BranchModel.Production)),
+	}
+	for _, t := range s.BranchModel.Types {
+		prefix := t.Prefix
+		if !t.Enabled {
+			prefix = "(disabled)"
+		}
+		lines = append(lines, fmt.Sprintf("  %-11s  %s", t.Type+":", prefix))
+	}
+
+	if s.MergeChecks != nil {
+		approvals := fmt.Sprintf("%d", s.MergeChecks.MinApprovals)
+		if s.MergeChecks.RequireAllApprovers {
+			approvals = "all reviewers"
+		}
+		lines = append(lines,
+			"",
+			"Merge checks",
+			fmt.Sprintf("  Minimum approvals:         %s", approvals),
+			fmt.Sprintf("  All tasks resolved:        %s", yesNo(s.MergeChecks.RequireTasksResolved)),
+			fmt.Sprintf("  Minimum successful builds: %d", s.MergeChecks.MinSuccessfulBuilds),
+		)
+	}
+
+	heading := "Merge strategies"
+	if s.MergeStrategies.Branch != "" {
+		heading = fmt.Sprintf("Merge strategies (%s)", s.MergeStrategies.Branch)
+	}
+	enabled := "(none reported)"
+	if len(s.MergeStrategies.Enabled) > 0 {
+		names := make([]string, 0, len(s.MergeStrategies.Enabled))
+		for _, id := range s.MergeStrategies.Enabled {
+			if id == s.MergeStrategies.Default {
+				id += " (default)"
+			}
+			names = append(names, id)
+		}
+		enabled = strings.Join(names, ", ")
+	}
+	lines = append(lines, "", heading, "  Enabled: "+enabled)
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func formatModelBranch(b *modelBranchSummary) string {
+	switch {
+	case b == nil:
+		return "(none)"
+	case b.UseDefault:
+		return "(default branch)"
+	case b.Branch == "":
+		return "(unset)"
+	default:
+		return b.Branch
+	}
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+type branchTypePrefix struct {
+	Type   string
+	Prefix string
+}
+
+func parseBranchTypePrefixes(values []string) ([]branchTypePrefix, error) {
+	var out []branchTypePrefix
+	for _, v := range values {
+		typ, prefix, ok := strings.Cut(v, "=")
+		typ = strings.TrimSpace(typ)
+		if !ok || typ == "" {
+			return nil, fmt.Errorf("invalid --prefix %q; expected TYPE=PREFIX", v)
+		}
+		out = append(out, branchTypePrefix{Type: typ, Prefix: strings.TrimSpace(prefix)})
+	}
+	return out, nil
+}
+
+func unknownBranchTypeError(typ string, n int, name func(int) string) error {
+	known := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		known = append(known, strings.ToLower(name(i)))
+	}
+	return fmt.Errorf("unknown branch type %q; expected one of: %s", typ, strings.Join(known, ", "))
+}
+
+func dcModelBranch(value string) *bbdc.BranchModelBranch {
+	value = strings.TrimSpace(value)
+	if value == "default" {
+		return &bbdc.BranchModelBranch{UseDefault: true}
+	}
+	if !strings.HasPrefix(value, "refs/") {
+		value = "refs/heads/" + value
+	}
+	return &bbdc.BranchModelBranch{RefID: value}
+}
+
+func cloudModelBranch(value string) bbcloud.BranchingModelBranch {
+	value = strings.TrimSpace(value)
+	if value == "default" {
+		return bbcloud.BranchingModelBranch{UseMainbranch: true}
+	}
+	return bbcloud.BranchingModelBranch{Name: strings.TrimPrefix(value, "refs/heads/")}
+}
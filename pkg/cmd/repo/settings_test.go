@@ -0,0 +1,285 @@
+package repo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
+)
+
+func settingsFactory(kind, baseURL string, stdout *strings.Builder) *cmdutil.Factory {
+	cfg := &config.Config{
+		ActiveContext: "default",
+		Contexts: map[string]*config.Context{
+			"default": {Host: "main", ProjectKey: "PROJ", Workspace: "ws", DefaultRepo: "repo"},
+		},
+		Hosts: map[string]*config.Host{
+			"main": {Kind: kind, BaseURL: baseURL, Token: "test-token"},
+		},
+	}
+	return &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: stdout, ErrOut: &strings.Builder{}},
+		Config: func() (*config.Config, error) {
+			return cfg, nil
+		},
+	}
+}
+
+func TestSettingsViewDataCenter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/branch-utils/1.0/projects/PROJ/repos/repo/branchmodel/configuration":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"development": map[string]any{"refId": "refs/heads/develop", "useDefault": false},
+				"types": []map[string]any{
+					{"id": "BUGFIX", "enabled": true, "prefix": "bugfix/"},
+					{"id": "HOTFIX", "enabled": false, "prefix": "hotfix/"},
+				},
+			})
+		case "/rest/api/1.0/projects/PROJ/repos/repo/settings/pull-requests":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"requiredApprovers":        2,
+				"requiredAllTasksComplete": true,
+				"mergeConfig": map[string]any{
+					"defaultStrategy": map[string]any{"id": "squash"},
+					"strategies": []map[string]any{
+						{"id": "no-ff", "enabled": true},
+						{"id": "squash", "enabled": true},
+						{"id": "ff-only", "enabled": false},
+					},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	stdout := &strings.Builder{}
+	cmd := newSettingsViewCmd(settingsFactory("dc", server.URL, stdout))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"Development: develop",
+		"Production:  (none)",
+		"bugfix:      bugfix/",
+		"hotfix:      (disabled)",
+		"Minimum approvals:         2",
+		"All tasks resolved:        yes",
+		"Enabled: no-ff, squash (default)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSettingsSetDataCenter(t *testing.T) {
+	var modelBody, prBody map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/rest/branch-utils/1.0/projects/PROJ/repos/repo/branchmodel/configuration":
+			if r.Method == http.MethodPut {
+				_ = json.NewDecoder(r.Body).Decode(&modelBody)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"development": map[string]any{"useDefault": true},
+				"types": []map[string]any{
+					{"id": "FEATURE", "enabled": true, "prefix": "feature/"},
+					{"id": "HOTFIX", "enabled": true, "prefix": "hotfix/"},
+				},
+			})
+		case r.URL.Path == "/rest/api/1.0/projects/PROJ/repos/repo/settings/pull-requests":
+			if r.Method == http.MethodPost {
+				_ = json.NewDecoder(r.Body).Decode(&prBody)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"requiredApprovers": 1})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	stdout := &strings.Builder{}
+	cmd := newSettingsSetCmd(settingsFactory("dc", server.URL, stdout))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{
+		"--development-branch", "develop",
+		"--production-branch", "default",
+		"--prefix", "hotfix=",
+		"--prefix", "FEATURE=feat/",
+		"--min-approvals", "1",
+		"--merge-strategies", "squash,no-ff",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dev := modelBody["development"].(map[string]any); dev["refId"] != "refs/heads/develop" || dev["useDefault"] != false {
+		t.Fatalf("development = %+v", dev)
+	}
+	if prod := modelBody["production"].(map[string]any); prod["useDefault"] != true {
+		t.Fatalf("production = %+v", prod)
+	}
+	types := modelBody["types"].([]any)
+	if feature := types[0].(map[string]any); feature["prefix"] != "feat/" || feature["enabled"] != true {
+		t.Fatalf("feature type = %+v", feature)
+	}
+	if hotfix := types[1].(map[string]any); hotfix["enabled"] != false {
+		t.Fatalf("hotfix type = %+v", hotfix)
+	}
+
+	if prBody["requiredApprovers"] != float64(1) {
+		t.Fatalf("requiredApprovers = %v", prBody["requiredApprovers"])
+	}
+	if _, ok := prBody["requiredSuccessfulBuilds"]; ok {
+		t.Fatalf("unchanged settings should be omitted: %+v", prBody)
+	}
+	mergeConfig := prBody["mergeConfig"].(map[string]any)
+	if def := mergeConfig["defaultStrategy"].(map[string]any); def["id"] != "squash" {
+		t.Fatalf("defaultStrategy = %+v", def)
+	}
+	if strategies := mergeConfig["strategies"].([]any); len(strategies) != 2 {
+		t.Fatalf("strategies = %+v", strategies)
+	}
+	if !strings.Contains(stdout.String(), "Updated settings for PROJ/repo") {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
+
+func TestSettingsSetValidation(t *testing.T) {
+	tests := []struct {
+		name          string
+		hostKind      string
+		args          []string
+		errorContains string
+	}{
+		{
+			name:          "no flags",
+			hostKind:      "dc",
+			args:          []string{},
+			errorContains: "no settings to change",
+		},
+		{
+			name:          "malformed prefix",
+			hostKind:      "dc",
+			args:          []string{"--prefix", "feature"},
+			errorContains: "expected TYPE=PREFIX",
+		},
+		{
+			name:          "default not in strategies",
+			hostKind:      "dc",
+			args:          []string{"--merge-strategies", "no-ff", "--default-merge-strategy", "squash"},
+			errorContains: "must be one of --merge-strategies",
+		},
+		{
+			name:          "cloud merge checks",
+			hostKind:      "cloud",
+			args:          []string{"--min-approvals", "2"},
+			errorContains: "bkt branch protect add",
+		},
+		{
+			name:          "cloud merge strategies",
+			hostKind:      "cloud",
+			args:          []string{"--merge-strategies", "squash"},
+			errorContains: "cannot be changed through the Bitbucket Cloud API",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits++
+				http.NotFound(w, r)
+			}))
+			t.Cleanup(server.Close)
+
+			cmd := newSettingsSetCmd(settingsFactory(tt.hostKind, server.URL, &strings.Builder{}))
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Fatalf("error = %v, want substring %q", err, tt.errorContains)
+			}
+			if hits != 0 {
+				t.Fatalf("expected validation to avoid HTTP requests, got %d", hits)
+			}
+		})
+	}
+}
+
+func TestSettingsCloudBranchingModel(t *testing.T) {
+	var putBody map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repositories/ws/repo/branching-model/settings":
+			if r.Method == http.MethodPut {
+				_ = json.NewDecoder(r.Body).Decode(&putBody)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"development": map[string]any{"use_mainbranch": true, "is_valid": true},
+				"production":  map[string]any{"name": "production", "use_mainbranch": false, "enabled": false},
+				"branch_types": []map[string]any{
+					{"kind": "release", "enabled": true, "prefix": "release/"},
+				},
+			})
+		case "/repositories/ws/repo":
+			_ = json.NewEncoder(w).Encode(map[string]any{"slug": "repo", "mainbranch": map[string]any{"name": "main"}})
+		case "/repositories/ws/repo/refs/branches/main":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"name":                   "main",
+				"merge_strategies":       []string{"merge_commit", "squash"},
+				"default_merge_strategy": "squash",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	stdout := &strings.Builder{}
+	cmd := newSettingsSetCmd(settingsFactory("cloud", server.URL, stdout))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	cmd.SetArgs([]string{"--production-branch", "prod", "--prefix", "release=rel/"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dev := putBody["development"].(map[string]any); dev["use_mainbranch"] != true || dev["is_valid"] != nil {
+		t.Fatalf("development = %+v", dev)
+	}
+	if prod := putBody["production"].(map[string]any); prod["name"] != "prod" || prod["enabled"] != true {
+		t.Fatalf("production = %+v", prod)
+	}
+	if release := putBody["branch_types"].([]any)[0].(map[string]any); release["prefix"] != "rel/" {
+		t.Fatalf("release type = %+v", release)
+	}
+	if !strings.Contains(stdout.String(), "Merge strategies (main)") || !strings.Contains(stdout.String(), "merge_commit, squash (default)") {
+		t.Fatalf("unexpected output: %q", stdout.String())
+	}
+}
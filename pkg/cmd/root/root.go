@@ -49,9 +49,10 @@ Common flows:
 	root.PersistentFlags().StringP("context", "c", "", "Active Bitbucket context name")
 	root.PersistentFlags().Bool("json", false, "Output in JSON format when supported")
 	root.PersistentFlags().Bool("yaml", false, "Output in YAML format when supported")
-	root.PersistentFlags().String("format", "", "Output format: json or yaml (alias for --json/--yaml)")
-	root.PersistentFlags().String("jq", "", "Apply a jq expression to JSON output (requires --json or --format json)")
+	root.PersistentFlags().String("format", "", "Output format: json, yaml, csv, tsv, or ndjson")
+	root.PersistentFlags().String("jq", "", "Apply a jq expression to structured output (requires --json or --format json, csv, tsv, or ndjson)")
 	root.PersistentFlags().String("template", "", "Render output using Go templates")
+	root.PersistentFlags().StringSlice("fields", nil, "Columns for csv, tsv, or ndjson output; nested fields use dotted paths (e.g. author.name)")
 
 	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		_, err := cmdutil.ResolveOutputSettings(cmd)
@@ -3,13 +3,13 @@ package status
 import (
 	"context"
 	"fmt"
-	"io"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
 	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
 )
 
 func newRateLimitCmd(f *cmdutil.Factory) *cobra.Command {
@@ -61,7 +61,7 @@ func runRateLimit(cmd *cobra.Command, f *cmdutil.Factory) error {
 			return err
 		}
 		rl := client.RateLimit()
-		return renderRateLimit(cmd, ios.Out, rl)
+		return renderRateLimit(cmd, ios, rl)
 	case "cloud":
 		client, err := cmdutil.NewCloudClient(host)
 		if err != nil {
@@ -71,13 +71,13 @@ func runRateLimit(cmd *cobra.Command, f *cmdutil.Factory) error {
 			return err
 		}
 		rl := client.RateLimit()
-		return renderRateLimit(cmd, ios.Out, rl)
+		return renderRateLimit(cmd, ios, rl)
 	default:
 		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
 }
 
-func renderRateLimit(cmd *cobra.Command, out io.Writer, rl httpx.RateLimit) error {
+func renderRateLimit(cmd *cobra.Command, ios *iostreams.IOStreams, rl httpx.RateLimit) error {
 	payload := map[string]any{
 		"limit":     rl.Limit,
 		"remaining": rl.Remaining,
@@ -85,7 +85,8 @@ func renderRateLimit(cmd *cobra.Command, out io.Writer, rl httpx.RateLimit) erro
 		"source":    rl.Source,
 	}
 
-	return cmdutil.WriteOutput(cmd, out, payload, func() error {
+	out := ios.Out
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if _, err := fmt.Fprintf(out, "Limit: %d\n", rl.Limit); err != nil {
 			return err
 		}
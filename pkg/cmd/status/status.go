@@ -3,15 +3,15 @@ package status
 import (
 	"context"
 	"fmt"
-	"io"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
 	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
+	"github.com/avivsinai/bitbucket-cli/pkg/types"
 )
 
 // NewCmdStatus exposes commit and PR status commands.
@@ -20,12 +20,12 @@ func NewCmdStatus(f *cmdutil.Factory) *cobra.Command {
 		Use:   "status",
 		Short: "Inspect commit and pull request statuses",
 		Long: `Inspect build and CI statuses attached to commits and pull requests. Subcommands
-cover Data Center commit statuses, pull request head-commit statuses, Cloud
-pipeline runs, and API rate-limit telemetry.`,
-		Example: `  # Show build statuses for a commit (Data Center)
+cover commit statuses, pull request head-commit statuses, Cloud pipeline runs,
+and API rate-limit telemetry.`,
+		Example: `  # Show build statuses for a commit
   bkt status commit abc1234
 
-  # Show build statuses for a pull request (Data Center)
+  # Show build statuses for a pull request
   bkt status pr 42
 
   # Show a Cloud pipeline run
@@ -43,54 +43,54 @@ pipeline runs, and API rate-limit telemetry.`,
 	return cmd
 }
 
+type commitOptions struct {
+	Workspace string
+	Repo      string
+}
+
 func newCommitCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &commitOptions{}
 	cmd := &cobra.Command{
 		Use:   "commit <sha>",
-		Short: "Show the build statuses for a commit (DC only)",
+		Short: "Show the build statuses for a commit",
 		Long: `Display the CI/build statuses reported against a specific commit SHA. Each
-status includes the state (SUCCESSFUL, FAILED, INPROGRESS), the build key,
-name, optional description, and a link to the build.
-
-Currently supports Data Center contexts only. The commit does not need to
-belong to any particular branch or pull request.`,
+status includes the state (SUCCESSFUL, FAILED, INPROGRESS, STOPPED), the build
+key, name, optional description, and a link to the build.
+
+On Data Center the commit does not need to belong to any particular branch or
+pull request. On Bitbucket Cloud the statuses reported against the commit are
+combined with the Pipelines runs on it, each shown as a "pipeline-<number>"
+status; the workspace and repository come from the active context or
+--workspace and --repo. Both platforms produce the same output shape.`,
 		Example: `  # Show statuses for a commit
   bkt status commit abc1234def5678
 
   # Show statuses using a full 40-character SHA
   bkt status commit 6f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a
 
+  # Cloud: include pipelines from another repository
+  bkt status commit abc1234 --workspace myteam --repo backend
+
   # Output as JSON
-  bkt status commit abc1234 --output json`,
+  bkt status commit abc1234 --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCommit(cmd, f, args[0])
+			return runCommit(cmd, f, args[0], opts)
 		},
 	}
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket Cloud workspace override")
+	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override (Cloud)")
 	return cmd
 }
 
-func runCommit(cmd *cobra.Command, f *cmdutil.Factory, sha string) error {
+func runCommit(cmd *cobra.Command, f *cmdutil.Factory, sha string, opts *commitOptions) error {
 	ios, err := f.Streams()
 	if err != nil {
 		return err
 	}
 
 	override := cmdutil.FlagValue(cmd, "context")
-	_, _, host, err := cmdutil.ResolveContext(f, cmd, override)
-	if err != nil {
-		return err
-	}
-
-	if host.Kind != "dc" {
-		return fmt.Errorf("status commit currently supports Data Center contexts only")
-	}
-
-	client, err := bbdc.New(bbdc.Options{
-		BaseURL:    host.BaseURL,
-		Username:   host.Username,
-		Token:      host.Token,
-		AuthMethod: host.AuthMethod,
-	})
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
 	if err != nil {
 		return err
 	}
@@ -98,39 +98,72 @@ func runCommit(cmd *cobra.Command, f *cmdutil.Factory, sha string) error {
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	statuses, err := client.CommitStatuses(ctx, sha)
-	if err != nil {
-		return err
-	}
+	switch host.Kind {
+	case "dc":
+		client, err := f.DCClient(host)
+		if err != nil {
+			return err
+		}
+
+		statuses, err := client.CommitStatuses(ctx, sha)
+		if err != nil {
+			return err
+		}
+		return renderStatuses(cmd, ios, sha, statuses, nil)
 
-	return renderStatuses(cmd, f, ios.Out, sha, statuses, nil)
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if workspace == "" || repoSlug == "" {
+			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+
+		client, err := f.CloudClient(host)
+		if err != nil {
+			return err
+		}
+
+		statuses, err := client.CommitBuildStatuses(ctx, workspace, repoSlug, sha)
+		if err != nil {
+			return err
+		}
+		return renderStatuses(cmd, ios, sha, statuses, nil)
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
 }
 
 type prOptions struct {
-	Project string
-	Repo    string
+	Project   string
+	Workspace string
+	Repo      string
 }
 
 func newPullRequestCmd(f *cmdutil.Factory) *cobra.Command {
 	opts := &prOptions{}
 	cmd := &cobra.Command{
 		Use:   "pr <id>",
-		Short: "Show the build statuses for a pull request head commit (DC only)",
+		Short: "Show the build statuses for a pull request head commit",
 		Long: `Look up the head (latest) commit of a pull request and display all CI/build
 statuses attached to it. The output includes the pull request title and the
 resolved commit SHA alongside the status details.
 
-The project and repository are resolved from the active context or can be
-overridden with --project and --repo. Currently supports Data Center
-contexts only.`,
+The project (Data Center) or workspace (Cloud) and the repository are resolved
+from the active context or can be overridden with --project, --workspace, and
+--repo. On Bitbucket Cloud the Pipelines runs on the head commit are listed
+alongside its reported statuses, exactly as for "bkt status commit".`,
 		Example: `  # Show statuses for pull request #42
   bkt status pr 42
 
-  # Specify project and repo explicitly
+  # Specify project and repo explicitly (Data Center)
   bkt status pr 42 --project MYPROJ --repo my-service
 
+  # Specify workspace and repo explicitly (Cloud)
+  bkt status pr 42 --workspace myteam --repo my-service
+
   # Output as JSON
-  bkt status pr 42 --output json`,
+  bkt status pr 42 --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, err := strconv.Atoi(args[0])
@@ -140,7 +173,8 @@ contexts only.`,
 			return runPullRequest(cmd, f, id, opts)
 		},
 	}
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
+	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override (Data Center)")
+	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
 	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
 	return cmd
 }
@@ -157,66 +191,95 @@ func runPullRequest(cmd *cobra.Command, f *cmdutil.Factory, prID int, opts *prOp
 		return err
 	}
 
-	if host.Kind != "dc" {
-		return fmt.Errorf("status pr currently supports Data Center contexts only")
-	}
+	repoSlug := cmdutil.FirstNonEmpty(strings.TrimSpace(opts.Repo), ctxCfg.DefaultRepo)
 
-	projectKey := strings.TrimSpace(opts.Project)
-	if projectKey == "" {
-		projectKey = ctxCfg.ProjectKey
-	}
-	if projectKey == "" {
-		return fmt.Errorf("project key required; set with --project or configure the context default")
-	}
-	projectKey = strings.ToUpper(projectKey)
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
 
-	repoSlug := strings.TrimSpace(opts.Repo)
-	if repoSlug == "" {
-		repoSlug = ctxCfg.DefaultRepo
-	}
-	if repoSlug == "" {
-		return fmt.Errorf("repository slug required; pass --repo or set the context default")
-	}
+	switch host.Kind {
+	case "dc":
+		projectKey := cmdutil.FirstNonEmpty(strings.TrimSpace(opts.Project), ctxCfg.ProjectKey)
+		if projectKey == "" {
+			return fmt.Errorf("project key required; set with --project or configure the context default")
+		}
+		projectKey = strings.ToUpper(projectKey)
+		if repoSlug == "" {
+			return fmt.Errorf("repository slug required; pass --repo or set the context default")
+		}
 
-	client, err := bbdc.New(bbdc.Options{
-		BaseURL:    host.BaseURL,
-		Username:   host.Username,
-		Token:      host.Token,
-		AuthMethod: host.AuthMethod,
-	})
-	if err != nil {
-		return err
-	}
+		client, err := f.DCClient(host)
+		if err != nil {
+			return err
+		}
 
-	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
-	defer cancel()
+		pr, err := client.GetPullRequest(ctx, projectKey, repoSlug, prID)
+		if err != nil {
+			return err
+		}
 
-	pr, err := client.GetPullRequest(ctx, projectKey, repoSlug, prID)
-	if err != nil {
-		return err
-	}
+		commit := pr.FromRef.LatestCommit
+		statuses, err := client.CommitStatuses(ctx, commit)
+		if err != nil {
+			return err
+		}
 
-	statuses, err := client.CommitStatuses(ctx, pr.FromRef.LatestCommit)
-	if err != nil {
-		return err
+		return renderStatuses(cmd, ios, commit, statuses, pullRequestMetadata(pr.ID, pr.Title, commit, map[string]any{
+			"project": projectKey,
+			"repo":    repoSlug,
+		}))
+
+	case "cloud":
+		workspace := cmdutil.FirstNonEmpty(strings.TrimSpace(opts.Workspace), ctxCfg.Workspace)
+		if workspace == "" {
+			return fmt.Errorf("workspace required; set with --workspace or configure the context default")
+		}
+		if repoSlug == "" {
+			return fmt.Errorf("repository slug required; pass --repo or set the context default")
+		}
+
+		client, err := f.CloudClient(host)
+		if err != nil {
+			return err
+		}
+
+		pr, err := client.GetPullRequest(ctx, workspace, repoSlug, prID)
+		if err != nil {
+			return err
+		}
+
+		commit := pr.Source.Commit.Hash
+		if commit == "" {
+			return fmt.Errorf("pull request #%d has no source commit", prID)
+		}
+		statuses, err := client.CommitBuildStatuses(ctx, workspace, repoSlug, commit)
+		if err != nil {
+			return err
+		}
+
+		return renderStatuses(cmd, ios, commit, statuses, pullRequestMetadata(pr.ID, pr.Title, commit, map[string]any{
+			"workspace": workspace,
+			"repo":      repoSlug,
+		}))
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", host.Kind)
 	}
+}
 
-	info := map[string]any{
+func pullRequestMetadata(id int, title, commit string, location map[string]any) map[string]any {
+	return map[string]any{
 		"pull_request": map[string]any{
-			"id":    pr.ID,
-			"title": pr.Title,
+			"id":    id,
+			"title": title,
 		},
-		"context": map[string]any{
-			"project": projectKey,
-			"repo":    repoSlug,
-		},
-		"commit": pr.FromRef.LatestCommit,
+		"context": location,
+		"commit":  commit,
 	}
-
-	return renderStatuses(cmd, f, ios.Out, pr.FromRef.LatestCommit, statuses, info)
 }
 
-func renderStatuses(cmd *cobra.Command, f *cmdutil.Factory, out io.Writer, commit string, statuses []bbdc.CommitStatus, metadata map[string]any) error {
+// renderStatuses prints statuses from either platform through the shared
+// types.CommitStatus model, so the output shape does not depend on the host.
+func renderStatuses(cmd *cobra.Command, ios *iostreams.IOStreams, commit string, statuses []types.CommitStatus, metadata map[string]any) error {
 	type statusSummary struct {
 		State       string `json:"state"`
 		Key         string `json:"key"`
@@ -244,7 +307,8 @@ func renderStatuses(cmd *cobra.Command, f *cmdutil.Factory, out io.Writer, commi
 		payload[k] = v
 	}
 
-	return cmdutil.WriteOutput(cmd, out, payload, func() error {
+	out := ios.Out
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		if metadata != nil {
 			if pr, ok := metadata["pull_request"].(map[string]any); ok {
 				if _, err := fmt.Fprintf(out, "Pull request #%d: %s\n", pr["id"], pr["title"]); err != nil {
@@ -252,7 +316,11 @@ func renderStatuses(cmd *cobra.Command, f *cmdutil.Factory, out io.Writer, commi
 				}
 			}
 			if ctx, ok := metadata["context"].(map[string]any); ok {
-				if _, err := fmt.Fprintf(out, "Project %s / Repo %s\n", ctx["project"], ctx["repo"]); err != nil {
+				scope := fmt.Sprintf("Project %s", ctx["project"])
+				if workspace, ok := ctx["workspace"]; ok {
+					scope = fmt.Sprintf("Workspace %s", workspace)
+				}
+				if _, err := fmt.Fprintf(out, "%s / Repo %s\n", scope, ctx["repo"]); err != nil {
 					return err
 				}
 			}
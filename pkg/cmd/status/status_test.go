@@ -0,0 +1,212 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
+)
+
+func runStatus(t *testing.T, kind, baseURL string, args ...string) (string, error) {
+	t.Helper()
+	cfg := &config.Config{
+		ActiveContext: "default",
+		Contexts: map[string]*config.Context{
+			"default": {Host: "main", ProjectKey: "PROJ", Workspace: "ws", DefaultRepo: "api"},
+		},
+		Hosts: map[string]*config.Host{
+			"main": {Kind: kind, BaseURL: baseURL, Token: "test-token"},
+		},
+	}
+	stdout := &strings.Builder{}
+	f := &cmdutil.Factory{
+		AppVersion:     "test",
+		ExecutableName: "bkt",
+		IOStreams:      &iostreams.IOStreams{Out: stdout, ErrOut: &strings.Builder{}},
+		Config: func() (*config.Config, error) {
+			return cfg, nil
+		},
+	}
+	root := &cobra.Command{Use: "bkt", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().StringP("context", "c", "", "")
+	root.PersistentFlags().Bool("json", false, "")
+	root.AddCommand(NewCmdStatus(f))
+	root.SetArgs(append([]string{"status"}, args...))
+	err := root.Execute()
+	return stdout.String(), err
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func dcStatusServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/build-status/1.0/commits/abc123":
+			writeJSON(w, map[string]any{"values": []map[string]any{
+				{"state": "SUCCESSFUL", "key": "ci", "name": "Build", "url": "https://ci.example.com/1"},
+			}})
+		case "/rest/build-status/1.0/commits/fff000":
+			writeJSON(w, map[string]any{"values": []map[string]any{}})
+		case "/rest/api/1.0/projects/PROJ/repos/api/pull-requests/42":
+			writeJSON(w, map[string]any{"id": 42, "title": "Add feature", "fromRef": map[string]any{"latestCommit": "abc123"}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func cloudStatusServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repositories/ws/api/commit/abc123/statuses":
+			writeJSON(w, map[string]any{"values": []map[string]any{
+				{"state": "SUCCESSFUL", "key": "ci", "name": "Build", "url": "https://ci.example.com/1"},
+			}})
+		case "/repositories/ws/api/commit/fff000/statuses":
+			writeJSON(w, map[string]any{"values": []map[string]any{}})
+		case "/repositories/ws/api/pipelines/":
+			var values []map[string]any
+			if r.URL.Query().Get("target.commit.hash") == "abc123" {
+				values = append(values, map[string]any{
+					"uuid":         "{9}",
+					"build_number": 9,
+					"state":        map[string]any{"name": "IN_PROGRESS"},
+					"target":       map[string]any{"commit": map[string]any{"hash": "abc123"}},
+				})
+			}
+			writeJSON(w, map[string]any{"values": values})
+		case "/repositories/ws/api/pullrequests/42":
+			writeJSON(w, map[string]any{"id": 42, "title": "Add feature", "source": map[string]any{"commit": map[string]any{"hash": "abc123"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCommitStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		server func(*testing.T) *httptest.Server
+		sha    string
+		want   string
+	}{
+		{
+			name:   "data center",
+			kind:   "dc",
+			server: dcStatusServer,
+			sha:    "abc123",
+			want:   "Commit abc123\nSUCCESSFUL ci                   Build\n    https://ci.example.com/1\n",
+		},
+		{
+			name:   "cloud with pipelines",
+			kind:   "cloud",
+			server: cloudStatusServer,
+			sha:    "abc123",
+			want: "Commit abc123\nSUCCESSFUL ci                   Build\n    https://ci.example.com/1\n" +
+				"INPROGRESS pipeline-9           Pipeline #9 — IN_PROGRESS\n    https://bitbucket.org/ws/api/pipelines/results/9\n",
+		},
+		{
+			name:   "data center without statuses",
+			kind:   "dc",
+			server: dcStatusServer,
+			sha:    "fff000",
+			want:   "Commit fff000\nNo statuses reported.\n",
+		},
+		{
+			name:   "cloud without pipelines",
+			kind:   "cloud",
+			server: cloudStatusServer,
+			sha:    "fff000",
+			want:   "Commit fff000\nNo statuses reported.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runStatus(t, tt.kind, tt.server(t).URL, "commit", tt.sha)
+			if err != nil {
+				t.Fatalf("status commit: %v", err)
+			}
+			if out != tt.want {
+				t.Fatalf("output:\n%q\nwant:\n%q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequestStatuses(t *testing.T) {
+	tests := []struct {
+		name   string
+		kind   string
+		server func(*testing.T) *httptest.Server
+		scope  string
+		want   string
+	}{
+		{
+			name:   "data center",
+			kind:   "dc",
+			server: dcStatusServer,
+			scope:  "Project PROJ / Repo api",
+		},
+		{
+			name:   "cloud",
+			kind:   "cloud",
+			server: cloudStatusServer,
+			scope:  "Workspace ws / Repo api",
+			want:   "INPROGRESS pipeline-9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runStatus(t, tt.kind, tt.server(t).URL, "pr", "42")
+			if err != nil {
+				t.Fatalf("status pr: %v", err)
+			}
+			for _, want := range []string{"Pull request #42: Add feature\n", tt.scope + "\n", "Commit abc123\n", "SUCCESSFUL ci", tt.want} {
+				if !strings.Contains(out, want) {
+					t.Fatalf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
+
+func TestPullRequestStatusesJSON(t *testing.T) {
+	server := cloudStatusServer(t)
+	out, err := runStatus(t, "cloud", server.URL, "pr", "42", "--json")
+	if err != nil {
+		t.Fatalf("status pr: %v", err)
+	}
+	var payload struct {
+		Commit      string `json:"commit"`
+		PullRequest struct {
+			ID int `json:"id"`
+		} `json:"pull_request"`
+		Statuses []struct {
+			State string `json:"state"`
+			Key   string `json:"key"`
+		} `json:"statuses"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if payload.Commit != "abc123" || payload.PullRequest.ID != 42 || len(payload.Statuses) != 2 || payload.Statuses[1].Key != "pipeline-9" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
@@ -234,7 +234,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		Variables:  summaries,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		location := workspace
 		switch scope {
 		case scopeRepository:
@@ -421,7 +421,7 @@ func runGet(cmd *cobra.Command, f *cmdutil.Factory, opts *getOptions, variableNa
 		Scope:      scope,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		value := found.Value
 		if found.Secured {
 			value = "********"
@@ -616,7 +616,7 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions, vari
 		Scope:      scope,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		_, err := fmt.Fprintf(ios.Out, "Deleted variable %q from %s.\n", found.Key, location)
 		return err
 	})
@@ -908,7 +908,7 @@ func runSet(cmd *cobra.Command, f *cmdutil.Factory, opts *setOptions, variableNa
 		Scope:      scope,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		_, err := fmt.Fprintf(ios.Out, "%s variable %q in %s.\n", action, result.Key, location)
 		return err
 	})
@@ -1137,7 +1137,7 @@ func runSetFromEnvFile(cmd *cobra.Command, f *cmdutil.Factory, opts *setOptions)
 		Variables:  results,
 	}
 
-	return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 		created := 0
 		updated := 0
 		for _, r := range results {
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+type deliveriesOptions struct {
+	targetOptions
+	ID      int
+	Event   string
+	Outcome string
+}
+
+func newDeliveriesCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &deliveriesOptions{}
+	cmd := &cobra.Command{
+		Use:   "deliveries <id>",
+		Short: "Show recent deliveries of a webhook (Data Center)",
+		Long: `Show delivery statistics for a Data Center webhook together with its most
+recent delivery: the request that was sent, and the status code and body the
+consumer answered with. Use it to find out why a consumer is failing.
+
+Narrow the latest delivery with --event (e.g. pr:opened) and --outcome
+(success, failure, or error). Bitbucket Data Center only keeps the latest
+invocation per outcome, not a full history. Pass --scope project for a project
+webhook.`,
+		Example: `  # Show statistics and the latest delivery
+  bkt webhook deliveries 42
+
+  # Show the latest failed pr:opened delivery
+  bkt webhook deliveries 42 --event pr:opened --outcome failure
+
+  # Inspect a project webhook
+  bkt webhook deliveries 12 --scope project --project MYPROJ`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid webhook id %q", args[0])
+			}
+			opts.ID = id
+			return runDeliveries(cmd, f, opts)
+		},
+	}
+
+	opts.addFlags(cmd, true)
+	cmd.Flags().StringVar(&opts.Event, "event", "", "Only consider deliveries of this event key")
+	cmd.Flags().StringVar(&opts.Outcome, "outcome", "", "Only consider deliveries with this outcome: success, failure, or error")
+
+	return cmd
+}
+
+func runDeliveries(cmd *cobra.Command, f *cmdutil.Factory, opts *deliveriesOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	outcome := strings.ToUpper(strings.TrimSpace(opts.Outcome))
+	switch outcome {
+	case "", "SUCCESS", "FAILURE", "ERROR":
+	default:
+		return fmt.Errorf("invalid --outcome %q; use success, failure, or error", opts.Outcome)
+	}
+
+	t, err := resolveTarget(cmd, f, &opts.targetOptions, "webhook deliveries")
+	if err != nil {
+		return err
+	}
+
+	client, err := cmdutil.NewDCClient(t.host)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+	defer cancel()
+
+	stats, err := t.statisticsDC(ctx, client, opts.ID, opts.Event)
+	if err != nil {
+		return err
+	}
+	latest, err := t.latestDC(ctx, client, opts.ID, opts.Event, outcome)
+	if err != nil {
+		return err
+	}
+
+	payload := t.payload()
+	payload["webhook"] = opts.ID
+	payload["statistics"] = stats
+	payload["latest"] = latest
+
+	return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+		counts := stats.Counts
+		window := ""
+		if counts.Window > 0 {
+			window = fmt.Sprintf(" in the last %s", time.Duration(counts.Window)*time.Millisecond)
+		}
+		if _, err := fmt.Fprintf(ios.Out, "Webhook #%d: %d succeeded, %d failed, %d errored%s\n",
+			opts.ID, counts.Successes, counts.Failures, counts.Errors, window); err != nil {
+			return err
+		}
+		for _, last := range []struct {
+			label      string
+			invocation *bbdc.WebhookInvocation
+		}{
+			{"Last success", stats.LastSuccess},
+			{"Last failure", stats.LastFailure},
+			{"Last error", stats.LastError},
+		} {
+			if last.invocation == nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(ios.Out, "%-13s %s  %s  %s\n", last.label+":",
+				formatInvocationTime(last.invocation.Start), last.invocation.Event, invocationResult(last.invocation)); err != nil {
+				return err
+			}
+		}
+
+		if latest == nil {
+			_, err := fmt.Fprintln(ios.Out, "\nNo matching deliveries.")
+			return err
+		}
+		return writeInvocation(ios.Out, latest)
+	})
+}
+
+func writeInvocation(w io.Writer, inv *bbdc.WebhookInvocation) error {
+	fmt.Fprintln(w, "\nLatest delivery:")
+	fmt.Fprintf(w, "  Event:    %s\n", inv.Event)
+	fmt.Fprintf(w, "  Started:  %s (%dms)\n", formatInvocationTime(inv.Start), inv.Duration)
+	fmt.Fprintf(w, "  Request:  %s %s\n", cmdutil.FirstNonEmpty(inv.Request.Method, "POST"), inv.Request.URL)
+	fmt.Fprintf(w, "  Result:   %s\n", invocationResult(inv))
+
+	if err := writeInvocationBody(w, "Request body", inv.Request.Body); err != nil {
+		return err
+	}
+	return writeInvocationBody(w, "Response body", inv.Result.Body)
+}
+
+func writeInvocationBody(w io.Writer, label, body string) error {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		_, err := fmt.Fprintf(w, "\n%s: (empty)\n", label)
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "\n%s:\n", label); err != nil {
+		return err
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// invocationResult renders the outcome with the consumer's HTTP status code,
+// or Bitbucket's description when no response was received.
+func invocationResult(inv *bbdc.WebhookInvocation) string {
+	result := cmdutil.FirstNonEmpty(inv.Result.Outcome, "UNKNOWN")
+	switch {
+	case inv.Result.StatusCode != 0:
+		result += fmt.Sprintf(" (HTTP %d)", inv.Result.StatusCode)
+	case inv.Result.Description != "":
+		result += " (" + inv.Result.Description + ")"
+	}
+	return result
+}
+
+func formatInvocationTime(ms int64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
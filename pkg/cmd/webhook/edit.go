@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+type editOptions struct {
+	targetOptions
+	Identifier string
+	Name       string
+	URL        string
+	Events     []string
+	Active     bool
+	Secret     string
+}
+
+func newEditCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &editOptions{}
+	cmd := &cobra.Command{
+		Use:   "edit <id|uuid>",
+		Short: "Update an existing webhook",
+		Long: `Change a webhook in place, keeping its ID (Data Center) or UUID (Cloud) so
+consumers and delivery history stay attached.
+
+Only the flags you pass are changed; everything else is kept from the current
+definition. --event replaces the whole event list. --secret rotates the secret
+used for the X-Hub-Signature header. Cloud keeps the current secret otherwise;
+Data Center does not return secrets and replaces the whole definition, so an
+edit without --secret clears the secret there and a warning is printed.
+--name sets the Data Center name or the Cloud description. Use --scope to edit a
+Data Center project or Cloud workspace webhook.`,
+		Example: `  # Point a webhook at a new URL
+  bkt webhook edit 42 --url https://ci.example.com/v2/hook
+
+  # Replace the subscribed events
+  bkt webhook edit 42 --event repo:refs_changed --event pr:merged
+
+  # Rotate the secret and re-enable a Cloud webhook
+  bkt webhook edit {a1b2c3d4-e5f6-7890-abcd-ef1234567890} --secret "$NEW_SECRET" --active
+
+  # Disable a project webhook (Data Center)
+  bkt webhook edit 12 --scope project --project MYPROJ --active=false`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Identifier = args[0]
+			return runEdit(cmd, f, opts)
+		},
+	}
+
+	opts.addFlags(cmd, false)
+	cmd.Flags().StringVar(&opts.Name, "name", "", "New webhook name (Cloud: description)")
+	cmd.Flags().StringVar(&opts.URL, "url", "", "New callback URL")
+	cmd.Flags().StringSliceVar(&opts.Events, "event", nil, "Events to subscribe to, replacing the current list (repeatable)")
+	cmd.Flags().BoolVar(&opts.Active, "active", false, "Enable or disable the webhook (--active=false disables)")
+	cmd.Flags().StringVar(&opts.Secret, "secret", "", "New webhook secret")
+
+	return cmd
+}
+
+func runEdit(cmd *cobra.Command, f *cmdutil.Factory, opts *editOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	if !flags.Changed("name") && !flags.Changed("url") && !flags.Changed("event") && !flags.Changed("active") && !flags.Changed("secret") {
+		return fmt.Errorf("nothing to update; pass --url, --name, --event, --active, or --secret")
+	}
+	if flags.Changed("event") && len(opts.Events) == 0 {
+		return fmt.Errorf("--event requires at least one event")
+	}
+	if flags.Changed("secret") && opts.Secret == "" {
+		return fmt.Errorf("--secret cannot be empty")
+	}
+
+	t, err := resolveTarget(cmd, f, &opts.targetOptions, "")
+	if err != nil {
+		return err
+	}
+
+	switch t.host.Kind {
+	case "dc":
+		id, err := strconv.Atoi(opts.Identifier)
+		if err != nil {
+			return fmt.Errorf("invalid webhook id %q", opts.Identifier)
+		}
+
+		client, err := cmdutil.NewDCClient(t.host)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
+
+		current, err := t.getDC(ctx, client, id)
+		if err != nil {
+			return err
+		}
+
+		in := bbdc.UpdateWebhookInput{
+			Name:          current.Name,
+			URL:           current.URL,
+			Events:        current.Events,
+			Active:        current.Active,
+			Configuration: make(map[string]any, len(current.Configuration)+1),
+		}
+		for k, v := range current.Configuration {
+			in.Configuration[k] = v
+		}
+		if flags.Changed("name") {
+			in.Name = opts.Name
+		}
+		if flags.Changed("url") {
+			in.URL = opts.URL
+		}
+		if flags.Changed("event") {
+			in.Events = opts.Events
+		}
+		if flags.Changed("active") {
+			in.Active = opts.Active
+		}
+		if flags.Changed("secret") {
+			in.Configuration["secret"] = opts.Secret
+		}
+		secretKept := in.Configuration["secret"] != nil && in.Configuration["secret"] != ""
+
+		hook, err := t.updateDC(ctx, client, id, in)
+		if err != nil {
+			return err
+		}
+		if !secretKept {
+			if _, err := fmt.Fprintf(ios.ErrOut, "warning: Data Center does not return webhook secrets; any secret on webhook #%d was cleared. Pass --secret to keep signing deliveries.\n", hook.ID); err != nil {
+				return err
+			}
+		}
+
+		payload := t.payload()
+		payload["webhook"] = redactDCWebhook(*hook)
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+			_, err := fmt.Fprintf(ios.Out, "✓ Updated webhook #%d (%s)\n", hook.ID, hook.Name)
+			return err
+		})
+
+	case "cloud":
+		client, err := cmdutil.NewCloudClient(t.host)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		defer cancel()
+
+		current, err := t.getCloud(ctx, client, opts.Identifier)
+		if err != nil {
+			return err
+		}
+
+		in := bbcloud.WebhookInput{
+			Description: current.Description,
+			URL:         current.URL,
+			Events:      current.Events,
+			Active:      current.Active,
+		}
+		if flags.Changed("name") {
+			in.Description = opts.Name
+		}
+		if flags.Changed("url") {
+			in.URL = opts.URL
+		}
+		if flags.Changed("event") {
+			in.Events = opts.Events
+		}
+		if flags.Changed("active") {
+			in.Active = opts.Active
+		}
+		if flags.Changed("secret") {
+			in.Secret = opts.Secret
+		}
+
+		hook, err := t.updateCloud(ctx, client, opts.Identifier, in)
+		if err != nil {
+			return err
+		}
+
+		payload := t.payload()
+		payload["webhook"] = hook
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
+			_, err := fmt.Fprintf(ios.Out, "✓ Updated webhook %s\n", hook.UUID)
+			return err
+		})
+
+	default:
+		return fmt.Errorf("unsupported host kind %q", t.host.Kind)
+	}
+}
+
+// redactDCWebhook hides the webhook secret from command output.
+func redactDCWebhook(hook bbdc.Webhook) bbdc.Webhook {
+	if _, ok := hook.Configuration["secret"]; !ok {
+		return hook
+	}
+	config := make(map[string]any, len(hook.Configuration))
+	for k, v := range hook.Configuration {
+		config[k] = v
+	}
+	config["secret"] = "********"
+	hook.Configuration = config
+	return hook
+}
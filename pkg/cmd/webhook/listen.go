@@ -0,0 +1,566 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// secretEnv supplies the webhook secret without putting it on the command
+// line (and in shell history).
+const secretEnv = "BKT_WEBHOOK_SECRET"
+
+// maxPayloadBytes bounds a single delivery; Bitbucket payloads are far smaller.
+const maxPayloadBytes = 10 << 20
+
+// signatureHeader carries the HMAC of the body on Data Center and Cloud.
+const signatureHeader = "X-Hub-Signature"
+
+// droppedHeaders are not recorded or forwarded: hop-by-hop headers, and
+// credentials a proxy in front of the receiver may have added.
+var droppedHeaders = map[string]bool{
+	"Authorization":     true,
+	"Cookie":            true,
+	"Connection":        true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Accept-Encoding":   true,
+	"Transfer-Encoding": true,
+}
+
+type listenOptions struct {
+	Port    int
+	Bind    string
+	Secret  string
+	Forward string
+	Record  string
+	Compact bool
+}
+
+func newListenCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &listenOptions{Port: 8080, Bind: "127.0.0.1"}
+	cmd := &cobra.Command{
+		Use:   "listen",
+		Short: "Receive webhook deliveries locally",
+		Long: `Run a local HTTP receiver for Bitbucket webhook deliveries, so webhook
+consumers can be developed without exposing a public URL. Point a webhook at
+the receiver (directly or through a tunnel) and each delivery is printed as a
+one-line summary keyed by its event (pr:opened, repo:refs_changed, repo:push,
+pullrequest:created, ...) followed by the indented payload.
+
+With --secret (or BKT_WEBHOOK_SECRET), the X-Hub-Signature HMAC of every
+delivery is verified and mismatches are rejected with 401. --forward re-posts
+accepted deliveries, headers included, to a local service and answers
+Bitbucket with that service's status code. --record writes each delivery to a
+directory for "bkt webhook replay".
+
+The receiver runs until interrupted.`,
+		Example: `  # Print deliveries received on port 8080
+  bkt webhook listen
+
+  # Verify signatures and forward to a local consumer
+  BKT_WEBHOOK_SECRET=s3cret bkt webhook listen --port 8080 --forward http://localhost:3000/hook
+
+  # Record deliveries for offline replay, printing summaries only
+  bkt webhook listen --record ./events --compact`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListen(cmd, f, opts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Port, "port", opts.Port, "Port to listen on (0 picks a free port)")
+	cmd.Flags().StringVar(&opts.Bind, "bind", opts.Bind, "Address to bind; use 0.0.0.0 to accept remote deliveries")
+	cmd.Flags().StringVar(&opts.Secret, "secret", "", "Webhook secret for X-Hub-Signature verification (default $"+secretEnv+")")
+	cmd.Flags().StringVar(&opts.Forward, "forward", "", "URL to forward accepted deliveries to")
+	cmd.Flags().StringVar(&opts.Record, "record", "", "Directory to record deliveries into")
+	cmd.Flags().BoolVar(&opts.Compact, "compact", false, "Print one summary line per delivery without the payload")
+
+	return cmd
+}
+
+func runListen(cmd *cobra.Command, f *cmdutil.Factory, opts *listenOptions) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+
+	if opts.Port < 0 || opts.Port > 65535 {
+		return fmt.Errorf("invalid --port %d", opts.Port)
+	}
+	if opts.Forward != "" {
+		if err := validateTargetURL("--forward", opts.Forward); err != nil {
+			return err
+		}
+	}
+	if opts.Record != "" {
+		if err := os.MkdirAll(opts.Record, 0o700); err != nil {
+			return fmt.Errorf("create record directory: %w", err)
+		}
+	}
+
+	recv := &receiver{
+		out:       ios.Out,
+		secret:    []byte(cmdutil.FirstNonEmpty(opts.Secret, os.Getenv(secretEnv))),
+		forward:   opts.Forward,
+		recordDir: opts.Record,
+		compact:   opts.Compact,
+		client:    &http.Client{Timeout: 30 * time.Second},
+		now:       time.Now,
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(opts.Bind, strconv.Itoa(opts.Port)))
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(ios.ErrOut, "Listening for webhooks on http://%s\n", ln.Addr())
+	if len(recv.secret) == 0 {
+		fmt.Fprintln(ios.ErrOut, "  signatures: not verified (set --secret or "+secretEnv+")")
+	} else {
+		fmt.Fprintln(ios.ErrOut, "  signatures: verified")
+	}
+	if opts.Forward != "" {
+		fmt.Fprintf(ios.ErrOut, "  forward:    %s\n", opts.Forward)
+	}
+	if opts.Record != "" {
+		fmt.Fprintf(ios.ErrOut, "  record:     %s\n", opts.Record)
+	}
+
+	return serveUntilDone(ctx, ln, recv)
+}
+
+// serveUntilDone serves deliveries on ln until ctx is cancelled, then drains
+// in-flight requests.
+func serveUntilDone(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(ln) }()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// receiver handles webhook deliveries for "bkt webhook listen".
+type receiver struct {
+	out       io.Writer
+	secret    []byte
+	forward   string
+	recordDir string
+	compact   bool
+	client    *http.Client
+	now       func() time.Time
+
+	mu  sync.Mutex // serialises output and record numbering, never held while forwarding
+	seq int
+}
+
+// recordedEvent is the file format written by --record and read by replay.
+// Body holds the exact bytes received so recorded signatures stay valid.
+type recordedEvent struct {
+	ReceivedAt time.Time         `json:"received_at"`
+	EventKey   string            `json:"event_key"`
+	Method     string            `json:"method"`
+	Path       string            `json:"path"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "webhook deliveries must use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxPayloadBytes+1))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPayloadBytes {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	event := recordedEvent{
+		ReceivedAt: r.now(),
+		Method:     req.Method,
+		Path:       req.URL.RequestURI(),
+		Headers:    recordableHeaders(req.Header),
+		Body:       string(body),
+	}
+	event.EventKey = eventKey(req.Header, body)
+
+	if len(r.secret) > 0 {
+		if err := verifySignature(r.secret, req.Header.Get(signatureHeader), body); err != nil {
+			r.printf("%s ✗ rejected %s: %v\n", event.ReceivedAt.Format("15:04:05"), event.EventKey, err)
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	r.accept(event, body)
+
+	if r.forward == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// Forwarding runs outside the lock so a slow target never holds up
+	// other deliveries; the result line names the event it belongs to.
+	resp, err := deliver(req.Context(), r.client, r.forward, event.Headers, body)
+	if err != nil {
+		r.printf("  ✗ forward %s failed: %v\n", event.EventKey, err)
+		http.Error(w, "forward failed", http.StatusBadGateway)
+		return
+	}
+	r.printf("  → %s %s %s\n", event.EventKey, r.forward, resp.Status)
+	w.WriteHeader(resp.StatusCode)
+}
+
+// accept numbers, prints, and records a verified delivery under the lock so
+// concurrent deliveries never interleave their output or share a number.
+func (r *receiver) accept(event recordedEvent, body []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+
+	r.print(event, body)
+
+	if r.recordDir != "" {
+		path, err := r.record(event, r.seq)
+		if err != nil {
+			fmt.Fprintf(r.out, "  ✗ record failed: %v\n", err)
+		} else {
+			fmt.Fprintf(r.out, "  recorded %s\n", path)
+		}
+	}
+}
+
+func (r *receiver) printf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fmt.Fprintf(r.out, format, args...)
+}
+
+func (r *receiver) print(event recordedEvent, body []byte) {
+	line := event.ReceivedAt.Format("15:04:05") + " " + event.EventKey
+	var payload map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err == nil {
+		if summary := summarizeEvent(event.EventKey, payload); summary != "" {
+			line += "  " + summary
+		}
+	}
+	fmt.Fprintln(r.out, line)
+
+	if r.compact || len(body) == 0 {
+		return
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, body, "  ", "  "); err != nil {
+		fmt.Fprintf(r.out, "  %s\n", body)
+		return
+	}
+	fmt.Fprintf(r.out, "  %s\n", pretty.String())
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+func (r *receiver) record(event recordedEvent, seq int) (string, error) {
+	name := fmt.Sprintf("%s-%03d-%s.json",
+		event.ReceivedAt.UTC().Format("20060102T150405Z"),
+		seq,
+		unsafeFileChars.ReplaceAllString(cmdutil.FirstNonEmpty(event.EventKey, "event"), "_"),
+	)
+	data, err := json.MarshalIndent(event, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.recordDir, name)
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// eventKey prefers the X-Event-Key header both platforms send, falling back
+// to the eventKey field of Data Center payloads.
+func eventKey(header http.Header, body []byte) string {
+	if key := strings.TrimSpace(header.Get("X-Event-Key")); key != "" {
+		return key
+	}
+	var payload struct {
+		EventKey string `json:"eventKey"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.EventKey != "" {
+		return payload.EventKey
+	}
+	return "unknown"
+}
+
+func recordableHeaders(header http.Header) map[string]string {
+	out := make(map[string]string, len(header))
+	for key, values := range header {
+		key = http.CanonicalHeaderKey(key)
+		if droppedHeaders[key] || len(values) == 0 {
+			continue
+		}
+		out[key] = values[0]
+	}
+	return out
+}
+
+// verifySignature checks an X-Hub-Signature value of the form
+// "sha256=<hex>" (or the legacy "sha1=<hex>") against body.
+func verifySignature(secret []byte, signature string, body []byte) error {
+	if signature == "" {
+		return fmt.Errorf("missing %s header", signatureHeader)
+	}
+	algo, digest, ok := strings.Cut(signature, "=")
+	if !ok {
+		return fmt.Errorf("malformed %s header", signatureHeader)
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(algo) {
+	case "sha256":
+		newHash = sha256.New
+	case "sha1":
+		newHash = sha1.New
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", algo)
+	}
+
+	want, err := hex.DecodeString(digest)
+	if err != nil {
+		return fmt.Errorf("malformed %s header", signatureHeader)
+	}
+	mac := hmac.New(newHash, secret)
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), want) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// signBody returns the sha256 X-Hub-Signature value for body.
+func signBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliver POSTs body to target with the recorded delivery headers.
+func deliver(ctx context.Context, client *http.Client, target string, headers map[string]string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if droppedHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		req.Header.Set(key, headers[key])
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxPayloadBytes))
+	_ = resp.Body.Close()
+	return resp, nil
+}
+
+func validateTargetURL(flag, raw string) error {
+	if !strings.HasPrefix(raw, "http://") && !strings.HasPrefix(raw, "https://") {
+		return fmt.Errorf("%s must be an http:// or https:// URL, got %q", flag, raw)
+	}
+	return nil
+}
+
+// summarizeEvent renders the interesting parts of a Data Center or Cloud
+// payload on one line: repository, pull request or changed refs, and actor.
+func summarizeEvent(key string, payload map[string]any) string {
+	var parts []string
+
+	if repo := repositoryName(payload); repo != "" {
+		parts = append(parts, repo)
+	}
+
+	pr := asMap(payload["pullRequest"]) // Data Center
+	if pr == nil {
+		pr = asMap(payload["pullrequest"]) // Cloud
+	}
+	if pr != nil {
+		parts = append(parts, fmt.Sprintf("PR #%v %q", pr["id"], stringValue(pr["title"])))
+	}
+
+	if refs := changedRefs(payload); len(refs) > 0 {
+		parts = append(parts, strings.Join(refs, ", "))
+	}
+
+	if comment := asMap(payload["comment"]); comment != nil {
+		text := stringValue(comment["text"]) // Data Center
+		if content := asMap(comment["content"]); content != nil {
+			text = stringValue(content["raw"]) // Cloud
+		}
+		if text != "" {
+			parts = append(parts, fmt.Sprintf("comment %q", truncateText(text, 60)))
+		}
+	}
+
+	if actor := actorName(payload); actor != "" {
+		parts = append(parts, "by "+actor)
+	}
+
+	if key == "diagnostics:ping" && len(parts) == 0 {
+		return "test delivery"
+	}
+	return strings.Join(parts, "  ")
+}
+
+func repositoryName(payload map[string]any) string {
+	repo := asMap(payload["repository"])
+	if repo == nil {
+		if pr := asMap(payload["pullRequest"]); pr != nil {
+			repo = asMap(asMap(pr["toRef"])["repository"])
+		}
+	}
+	if repo == nil {
+		return ""
+	}
+	if full := stringValue(repo["full_name"]); full != "" {
+		return full
+	}
+	slug := stringValue(repo["slug"])
+	if project := stringValue(asMap(repo["project"])["key"]); project != "" && slug != "" {
+		return project + "/" + slug
+	}
+	return slug
+}
+
+func changedRefs(payload map[string]any) []string {
+	var refs []string
+	// Data Center: changes[].ref.displayId with an ADD/UPDATE/DELETE type.
+	for _, change := range asSlice(payload["changes"]) {
+		c := asMap(change)
+		ref := stringValue(asMap(c["ref"])["displayId"])
+		if ref == "" {
+			ref = stringValue(c["refId"])
+		}
+		if ref != "" {
+			refs = append(refs, fmt.Sprintf("%s (%s)", ref, strings.ToLower(stringValue(c["type"]))))
+		}
+	}
+	// Cloud: push.changes[] with new/old ref objects.
+	for _, change := range asSlice(asMap(payload["push"])["changes"]) {
+		c := asMap(change)
+		switch {
+		case asMap(c["new"]) != nil && asMap(c["old"]) == nil:
+			refs = append(refs, stringValue(asMap(c["new"])["name"])+" (add)")
+		case asMap(c["new"]) == nil && asMap(c["old"]) != nil:
+			refs = append(refs, stringValue(asMap(c["old"])["name"])+" (delete)")
+		case asMap(c["new"]) != nil:
+			refs = append(refs, stringValue(asMap(c["new"])["name"])+" (update)")
+		}
+	}
+	return refs
+}
+
+func actorName(payload map[string]any) string {
+	actor := asMap(payload["actor"])
+	if actor == nil {
+		return ""
+	}
+	return cmdutil.FirstNonEmpty(
+		stringValue(actor["name"]),         // Data Center
+		stringValue(actor["nickname"]),     // Cloud
+		stringValue(actor["display_name"]), // Cloud
+		stringValue(actor["displayName"]),  // Data Center
+	)
+}
+
+func asMap(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func stringValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func truncateText(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
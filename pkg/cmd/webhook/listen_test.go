@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const dcPROpened = `{"eventKey":"pr:opened","actor":{"name":"alice"},"pullRequest":{"id":12,"title":"Add login","toRef":{"repository":{"slug":"api","project":{"key":"PROJ"}}}}}`
+
+func newTestReceiver(t *testing.T, secret string) (*receiver, *bytes.Buffer) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	return &receiver{
+		out:    out,
+		secret: []byte(secret),
+		client: http.DefaultClient,
+		now:    func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}, out
+}
+
+func postDelivery(r http.Handler, headers map[string]string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiverVerifiesSignature(t *testing.T) {
+	recv, out := newTestReceiver(t, "s3cret")
+
+	rec := postDelivery(recv, map[string]string{
+		"X-Event-Key":   "pr:opened",
+		signatureHeader: signBody([]byte("s3cret"), []byte(dcPROpened)),
+	}, dcPROpened)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("signed delivery status = %d, want 204", rec.Code)
+	}
+	if !strings.Contains(out.String(), `03:04:05 pr:opened  PROJ/api  PR #12 "Add login"  by alice`) {
+		t.Fatalf("unexpected summary:\n%s", out.String())
+	}
+
+	for name, header := range map[string]string{
+		"missing":  "",
+		"wrong":    signBody([]byte("other"), []byte(dcPROpened)),
+		"sha1 bad": "sha1=00",
+	} {
+		out.Reset()
+		headers := map[string]string{"X-Event-Key": "pr:opened"}
+		if header != "" {
+			headers[signatureHeader] = header
+		}
+		rec := postDelivery(recv, headers, dcPROpened)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s signature: status = %d, want 401", name, rec.Code)
+		}
+		if !strings.Contains(out.String(), "✗ rejected pr:opened") {
+			t.Fatalf("%s signature: expected rejection line, got:\n%s", name, out.String())
+		}
+	}
+}
+
+func TestReceiverRecordsAndForwards(t *testing.T) {
+	var forwarded struct {
+		body  string
+		event string
+		sig   string
+	}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		forwarded.body = string(data)
+		forwarded.event = r.Header.Get("X-Event-Key")
+		forwarded.sig = r.Header.Get(signatureHeader)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(target.Close)
+
+	recv, out := newTestReceiver(t, "")
+	recv.forward = target.URL
+	recv.recordDir = t.TempDir()
+	recv.compact = true
+
+	body := `{"actor":{"nickname":"bob"},"repository":{"full_name":"team/web"},"push":{"changes":[{"new":{"name":"main"},"old":{"name":"main"}}]}}`
+	rec := postDelivery(recv, map[string]string{
+		"X-Event-Key":   "repo:push",
+		signatureHeader: "sha256=abc",
+		"Authorization": "Bearer leak",
+	}, body)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want target's 202", rec.Code)
+	}
+	if forwarded.body != body || forwarded.event != "repo:push" || forwarded.sig != "sha256=abc" {
+		t.Fatalf("unexpected forward: %+v", forwarded)
+	}
+	if !strings.Contains(out.String(), "repo:push  team/web  main (update)  by bob") {
+		t.Fatalf("unexpected summary:\n%s", out.String())
+	}
+
+	files, err := filepath.Glob(filepath.Join(recv.recordDir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("recorded files = %v, %v", files, err)
+	}
+	if got := filepath.Base(files[0]); got != "20250102T030405Z-001-repo_push.json" {
+		t.Fatalf("record name = %s", got)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event recordedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("decode record: %v", err)
+	}
+	if event.Body != body || event.EventKey != "repo:push" {
+		t.Fatalf("unexpected record: %+v", event)
+	}
+	if _, ok := event.Headers["Authorization"]; ok {
+		t.Fatal("Authorization header must not be recorded")
+	}
+}
+
+func TestReceiverForwardsWithoutHoldingLock(t *testing.T) {
+	release := make(chan struct{})
+	arrived := make(chan struct{}, 2)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		if r.Header.Get("X-Event-Key") == "slow" {
+			<-release
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(target.Close)
+
+	recv, _ := newTestReceiver(t, "")
+	recv.forward = target.URL
+	recv.compact = true
+
+	slow := make(chan int, 1)
+	go func() {
+		slow <- postDelivery(recv, map[string]string{"X-Event-Key": "slow"}, "{}").Code
+	}()
+	<-arrived
+
+	done := make(chan int, 1)
+	go func() {
+		done <- postDelivery(recv, map[string]string{"X-Event-Key": "fast"}, "{}").Code
+	}()
+	select {
+	case code := <-done:
+		if code != http.StatusOK {
+			t.Fatalf("fast delivery status = %d, want 200", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a slow forward blocked the next delivery")
+	}
+
+	close(release)
+	if code := <-slow; code != http.StatusOK {
+		t.Fatalf("slow delivery status = %d, want 200", code)
+	}
+}
+
+func TestReceiverRejectsNonPost(t *testing.T) {
+	recv, _ := newTestReceiver(t, "")
+	rec := httptest.NewRecorder()
+	recv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+}
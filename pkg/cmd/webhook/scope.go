@@ -0,0 +1,222 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/internal/config"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+	"github.com/avivsinai/bitbucket-cli/pkg/cmdutil"
+)
+
+// Webhook owners selectable with --scope.
+const (
+	scopeRepo      = "repo"
+	scopeProject   = "project"
+	scopeWorkspace = "workspace"
+)
+
+// targetOptions holds the flags that select the webhook owner.
+type targetOptions struct {
+	Scope     string
+	Project   string
+	Workspace string
+	Repo      string
+}
+
+// addFlags registers the owner flags. Data Center-only commands get no
+// --workspace flag and no workspace scope.
+func (o *targetOptions) addFlags(cmd *cobra.Command, dcOnly bool) {
+	if dcOnly {
+		cmd.Flags().StringVar(&o.Scope, "scope", scopeRepo, "Webhook owner: repo or project")
+		cmd.Flags().StringVar(&o.Project, "project", "", "Bitbucket project key override")
+	} else {
+		cmd.Flags().StringVar(&o.Scope, "scope", scopeRepo, "Webhook owner: repo, project (Data Center), or workspace (Cloud)")
+		cmd.Flags().StringVar(&o.Project, "project", "", "Bitbucket project key override (Data Center)")
+		cmd.Flags().StringVar(&o.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
+	}
+	cmd.Flags().StringVar(&o.Repo, "repo", "", "Repository slug override (--scope repo)")
+}
+
+// target is a resolved webhook owner. repo is empty for project and
+// workspace webhooks.
+type target struct {
+	host      *config.Host
+	scope     string
+	project   string
+	workspace string
+	repo      string
+}
+
+// resolveTarget validates --scope against the context's host kind and fills
+// in the owner from flags and context defaults. A non-empty dcOnly names the
+// command in the error returned for Cloud contexts.
+func resolveTarget(cmd *cobra.Command, f *cmdutil.Factory, opts *targetOptions, dcOnly string) (target, error) {
+	scope := strings.ToLower(strings.TrimSpace(opts.Scope))
+	switch scope {
+	case "":
+		scope = scopeRepo
+	case scopeRepo, scopeProject, scopeWorkspace:
+	default:
+		return target{}, fmt.Errorf("invalid --scope %q; use repo, project, or workspace", opts.Scope)
+	}
+	if scope != scopeRepo && cmd.Flags().Changed("repo") {
+		return target{}, fmt.Errorf("--repo cannot be combined with --scope %s", scope)
+	}
+
+	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, cmdutil.FlagValue(cmd, "context"))
+	if err != nil {
+		return target{}, err
+	}
+	if dcOnly != "" && host.Kind != "dc" {
+		return target{}, fmt.Errorf("%s is supported for Data Center contexts only", dcOnly)
+	}
+
+	t := target{host: host, scope: scope}
+	switch host.Kind {
+	case "dc":
+		if scope == scopeWorkspace {
+			return target{}, fmt.Errorf("--scope workspace is supported for Cloud contexts only; use --scope project on Data Center")
+		}
+		t.project = cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
+		if scope == scopeProject {
+			if t.project == "" {
+				return target{}, fmt.Errorf("context must supply a project; use --project if needed")
+			}
+			return t, nil
+		}
+		t.repo = cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if t.project == "" || t.repo == "" {
+			return target{}, fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
+		}
+	case "cloud":
+		if scope == scopeProject {
+			return target{}, fmt.Errorf("--scope project is supported for Data Center contexts only; use --scope workspace on Cloud")
+		}
+		t.workspace = cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
+		if scope == scopeWorkspace {
+			if t.workspace == "" {
+				return target{}, fmt.Errorf("context must supply a workspace; use --workspace if needed")
+			}
+			return t, nil
+		}
+		t.repo = cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
+		if t.workspace == "" || t.repo == "" {
+			return target{}, fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
+		}
+	default:
+		return target{}, fmt.Errorf("unsupported host kind %q", host.Kind)
+	}
+	return t, nil
+}
+
+// payload returns the owner fields included in structured output.
+func (t target) payload() map[string]any {
+	p := map[string]any{"scope": t.scope}
+	if t.host.Kind == "dc" {
+		p["project"] = t.project
+	} else {
+		p["workspace"] = t.workspace
+	}
+	if t.repo != "" {
+		p["repo"] = t.repo
+	}
+	return p
+}
+
+// The methods below dispatch to the repository or owner-level client call.
+
+func (t target) listDC(ctx context.Context, client *bbdc.Client) ([]bbdc.Webhook, error) {
+	if t.repo == "" {
+		return client.ListProjectWebhooks(ctx, t.project)
+	}
+	return client.ListWebhooks(ctx, t.project, t.repo)
+}
+
+func (t target) createDC(ctx context.Context, client *bbdc.Client, in bbdc.CreateWebhookInput) (*bbdc.Webhook, error) {
+	if t.repo == "" {
+		return client.CreateProjectWebhook(ctx, t.project, in)
+	}
+	return client.CreateWebhook(ctx, t.project, t.repo, in)
+}
+
+func (t target) getDC(ctx context.Context, client *bbdc.Client, id int) (*bbdc.Webhook, error) {
+	if t.repo == "" {
+		return client.GetProjectWebhook(ctx, t.project, id)
+	}
+	return client.GetWebhook(ctx, t.project, t.repo, id)
+}
+
+func (t target) updateDC(ctx context.Context, client *bbdc.Client, id int, in bbdc.UpdateWebhookInput) (*bbdc.Webhook, error) {
+	if t.repo == "" {
+		return client.UpdateProjectWebhook(ctx, t.project, id, in)
+	}
+	return client.UpdateWebhook(ctx, t.project, t.repo, id, in)
+}
+
+func (t target) deleteDC(ctx context.Context, client *bbdc.Client, id int) error {
+	if t.repo == "" {
+		return client.DeleteProjectWebhook(ctx, t.project, id)
+	}
+	return client.DeleteWebhook(ctx, t.project, t.repo, id)
+}
+
+func (t target) testDC(ctx context.Context, client *bbdc.Client, id int) error {
+	if t.repo == "" {
+		return client.TestProjectWebhook(ctx, t.project, id)
+	}
+	return client.TestWebhook(ctx, t.project, t.repo, id)
+}
+
+func (t target) statisticsDC(ctx context.Context, client *bbdc.Client, id int, event string) (*bbdc.WebhookStatistics, error) {
+	if t.repo == "" {
+		return client.ProjectWebhookStatistics(ctx, t.project, id, event)
+	}
+	return client.WebhookStatistics(ctx, t.project, t.repo, id, event)
+}
+
+func (t target) latestDC(ctx context.Context, client *bbdc.Client, id int, event, outcome string) (*bbdc.WebhookInvocation, error) {
+	if t.repo == "" {
+		return client.LatestProjectWebhookInvocation(ctx, t.project, id, event, outcome)
+	}
+	return client.LatestWebhookInvocation(ctx, t.project, t.repo, id, event, outcome)
+}
+
+func (t target) listCloud(ctx context.Context, client *bbcloud.Client) ([]bbcloud.Webhook, error) {
+	if t.repo == "" {
+		return client.ListWorkspaceWebhooks(ctx, t.workspace)
+	}
+	return client.ListWebhooks(ctx, t.workspace, t.repo)
+}
+
+func (t target) createCloud(ctx context.Context, client *bbcloud.Client, in bbcloud.WebhookInput) (*bbcloud.Webhook, error) {
+	if t.repo == "" {
+		return client.CreateWorkspaceWebhook(ctx, t.workspace, in)
+	}
+	return client.CreateWebhook(ctx, t.workspace, t.repo, in)
+}
+
+func (t target) getCloud(ctx context.Context, client *bbcloud.Client, uuid string) (*bbcloud.Webhook, error) {
+	if t.repo == "" {
+		return client.GetWorkspaceWebhook(ctx, t.workspace, uuid)
+	}
+	return client.GetWebhook(ctx, t.workspace, t.repo, uuid)
+}
+
+func (t target) updateCloud(ctx context.Context, client *bbcloud.Client, uuid string, in bbcloud.WebhookInput) (*bbcloud.Webhook, error) {
+	if t.repo == "" {
+		return client.UpdateWorkspaceWebhook(ctx, t.workspace, uuid, in)
+	}
+	return client.UpdateWebhook(ctx, t.workspace, t.repo, uuid, in)
+}
+
+func (t target) deleteCloud(ctx context.Context, client *bbcloud.Client, uuid string) error {
+	if t.repo == "" {
+		return client.DeleteWorkspaceWebhook(ctx, t.workspace, uuid)
+	}
+	return client.DeleteWebhook(ctx, t.workspace, t.repo, uuid)
+}
@@ -18,35 +18,57 @@ func NewCommand(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "webhook",
 		Short: "Manage Bitbucket webhooks",
-		Long: `Create, list, delete, and test webhooks on Bitbucket repositories.
+		Long: `Create, list, edit, delete, and test webhooks on Bitbucket repositories,
+Data Center projects, and Cloud workspaces.
 
 Webhooks notify external services when events occur in a repository (e.g. push,
 pull-request creation). Both Data Center and Cloud are supported, though the
 identifier format differs: Data Center uses numeric IDs while Cloud uses UUIDs.
 
-The test subcommand is available for Data Center only.`,
+Webhooks belong to the current repository by default. Pass --scope project
+(Data Center) or --scope workspace (Cloud) to manage a single webhook that
+fires for every repository of the project or workspace.
+
+The test and deliveries subcommands are available for Data Center only. The
+listen and replay subcommands run locally: listen receives deliveries
+(verifying signatures, forwarding, and recording them), and replay re-posts a
+recorded delivery.`,
 		Example: `  # List all webhooks on the current repository
   bkt webhook list
 
   # Create a webhook for push events
   bkt webhook create --name ci-trigger --url https://ci.example.com/hook --event repo:refs_changed
 
+  # Create one webhook for every repository of a Data Center project
+  bkt webhook create --scope project --project PLAT --name audit --url https://audit.example.com/hook --event repo:refs_changed
+
+  # Change the callback URL of an existing webhook
+  bkt webhook edit 42 --url https://ci.example.com/v2/hook
+
+  # Inspect the latest delivery of a failing webhook (Data Center)
+  bkt webhook deliveries 42 --outcome failure
+
   # Delete a webhook by ID (Data Center) or UUID (Cloud)
-  bkt webhook delete 42`,
+  bkt webhook delete 42
+
+  # Receive deliveries locally and forward them to a consumer
+  bkt webhook listen --port 8080 --forward http://localhost:3000/hook`,
 	}
 
 	cmd.AddCommand(newListCmd(f))
 	cmd.AddCommand(newCreateCmd(f))
+	cmd.AddCommand(newEditCmd(f))
 	cmd.AddCommand(newDeleteCmd(f))
 	cmd.AddCommand(newTestCmd(f))
+	cmd.AddCommand(newDeliveriesCmd(f))
+	cmd.AddCommand(newListenCmd(f))
+	cmd.AddCommand(newReplayCmd(f))
 
 	return cmd
 }
 
 type listOptions struct {
-	Project   string
-	Workspace string
-	Repo      string
+	targetOptions
 }
 
 func newListCmd(f *cmdutil.Factory) *cobra.Command {
@@ -55,7 +77,8 @@ func newListCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:     "list",
 		Aliases: []string{"ls"},
 		Short:   "List configured webhooks",
-		Long: `List all webhooks configured on a Bitbucket repository.
+		Long: `List all webhooks configured on a Bitbucket repository, or with --scope on a
+Data Center project or Cloud workspace.
 
 For Data Center, the output includes the numeric webhook ID, status, name, and
 callback URL. For Cloud, the output includes the webhook UUID, status, and URL.
@@ -69,14 +92,15 @@ the values from the current context.`,
   bkt webhook list --project MYPROJ --repo my-repo
 
   # List webhooks for a specific Cloud repository
-  bkt webhook list --workspace myteam --repo my-repo`,
+  bkt webhook list --workspace myteam --repo my-repo
+
+  # List the webhooks of a Cloud workspace
+  bkt webhook list --scope workspace --workspace myteam`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runList(cmd, f, opts)
 		},
 	}
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override (Data Center)")
-	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+	opts.addFlags(cmd, false)
 	return cmd
 }
 
@@ -86,21 +110,14 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		return err
 	}
 
-	override := cmdutil.FlagValue(cmd, "context")
-	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	t, err := resolveTarget(cmd, f, &opts.targetOptions, "")
 	if err != nil {
 		return err
 	}
 
-	switch host.Kind {
+	switch t.host.Kind {
 	case "dc":
-		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-		if projectKey == "" || repoSlug == "" {
-			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
-		}
-
-		client, err := cmdutil.NewDCClient(host)
+		client, err := cmdutil.NewDCClient(t.host)
 		if err != nil {
 			return err
 		}
@@ -108,18 +125,15 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 		defer cancel()
 
-		hooks, err := client.ListWebhooks(ctx, projectKey, repoSlug)
+		hooks, err := t.listDC(ctx, client)
 		if err != nil {
 			return err
 		}
 
-		payload := map[string]any{
-			"project":  projectKey,
-			"repo":     repoSlug,
-			"webhooks": hooks,
-		}
+		payload := t.payload()
+		payload["webhooks"] = hooks
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(hooks) == 0 {
 				_, err := fmt.Fprintln(ios.Out, "No webhooks configured.")
 				return err
@@ -137,13 +151,7 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			return nil
 		})
 	case "cloud":
-		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
-		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-		if workspace == "" || repoSlug == "" {
-			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
-		}
-
-		client, err := cmdutil.NewCloudClient(host)
+		client, err := cmdutil.NewCloudClient(t.host)
 		if err != nil {
 			return err
 		}
@@ -151,18 +159,15 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 		defer cancel()
 
-		hooks, err := client.ListWebhooks(ctx, workspace, repoSlug)
+		hooks, err := t.listCloud(ctx, client)
 		if err != nil {
 			return err
 		}
 
-		payload := map[string]any{
-			"workspace": workspace,
-			"repo":      repoSlug,
-			"webhooks":  hooks,
-		}
+		payload := t.payload()
+		payload["webhooks"] = hooks
 
-		return cmdutil.WriteOutput(cmd, ios.Out, payload, func() error {
+		return cmdutil.WriteOutput(cmd, ios, payload, func() error {
 			if len(hooks) == 0 {
 				_, err := fmt.Fprintln(ios.Out, "No webhooks configured.")
 				return err
@@ -179,18 +184,16 @@ func runList(cmd *cobra.Command, f *cmdutil.Factory, opts *listOptions) error {
 			return nil
 		})
 	default:
-		return fmt.Errorf("unsupported host kind %q", host.Kind)
+		return fmt.Errorf("unsupported host kind %q", t.host.Kind)
 	}
 }
 
 type createOptions struct {
-	Project   string
-	Workspace string
-	Repo      string
-	Name      string
-	URL       string
-	Events    []string
-	Active    bool
+	targetOptions
+	Name   string
+	URL    string
+	Events []string
+	Active bool
 }
 
 func newCreateCmd(f *cmdutil.Factory) *cobra.Command {
@@ -198,7 +201,8 @@ func newCreateCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "create",
 		Short: "Create a new webhook",
-		Long: `Create a new webhook on a Bitbucket repository.
+		Long: `Create a new webhook on a Bitbucket repository, or with --scope on a Data
+Center project or Cloud workspace.
 
 You must specify a name, a callback URL, and at least one event to subscribe to.
 The webhook is created active by default; pass --active=false to create it in a
@@ -214,15 +218,16 @@ repo:push, pullrequest:created, and similar.`,
   bkt webhook create --name slack-notify --url https://hooks.slack.com/abc --event repo:push --event pullrequest:created
 
   # Create a webhook in a disabled state
-  bkt webhook create --name staging-hook --url https://staging.example.com/hook --event repo:push --active=false`,
+  bkt webhook create --name staging-hook --url https://staging.example.com/hook --event repo:push --active=false
+
+  # Create a webhook for every repository in a Cloud workspace
+  bkt webhook create --scope workspace --name audit --url https://audit.example.com/hook --event repo:push`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runCreate(cmd, f, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override (Data Center)")
-	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+	opts.addFlags(cmd, false)
 	cmd.Flags().StringVar(&opts.Name, "name", "", "Webhook name (required)")
 	cmd.Flags().StringVar(&opts.URL, "url", "", "Webhook callback URL (required)")
 	cmd.Flags().StringSliceVar(&opts.Events, "event", nil, "Events to subscribe to (repeatable)")
@@ -241,21 +246,14 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		return err
 	}
 
-	override := cmdutil.FlagValue(cmd, "context")
-	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	t, err := resolveTarget(cmd, f, &opts.targetOptions, "")
 	if err != nil {
 		return err
 	}
 
-	switch host.Kind {
+	switch t.host.Kind {
 	case "dc":
-		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-		if projectKey == "" || repoSlug == "" {
-			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
-		}
-
-		client, err := cmdutil.NewDCClient(host)
+		client, err := cmdutil.NewDCClient(t.host)
 		if err != nil {
 			return err
 		}
@@ -263,7 +261,7 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 		defer cancel()
 
-		hook, err := client.CreateWebhook(ctx, projectKey, repoSlug, bbdc.CreateWebhookInput{
+		hook, err := t.createDC(ctx, client, bbdc.CreateWebhookInput{
 			Name:   opts.Name,
 			URL:    opts.URL,
 			Events: opts.Events,
@@ -278,13 +276,7 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		}
 		return nil
 	case "cloud":
-		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
-		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-		if workspace == "" || repoSlug == "" {
-			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
-		}
-
-		client, err := cmdutil.NewCloudClient(host)
+		client, err := cmdutil.NewCloudClient(t.host)
 		if err != nil {
 			return err
 		}
@@ -292,7 +284,7 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 		defer cancel()
 
-		hook, err := client.CreateWebhook(ctx, workspace, repoSlug, bbcloud.WebhookInput{
+		hook, err := t.createCloud(ctx, client, bbcloud.WebhookInput{
 			Description: opts.Name,
 			URL:         opts.URL,
 			Events:      opts.Events,
@@ -307,21 +299,18 @@ func runCreate(cmd *cobra.Command, f *cmdutil.Factory, opts *createOptions) erro
 		}
 		return nil
 	default:
-		return fmt.Errorf("unsupported host kind %q", host.Kind)
+		return fmt.Errorf("unsupported host kind %q", t.host.Kind)
 	}
 }
 
 type deleteOptions struct {
-	Project    string
-	Workspace  string
-	Repo       string
+	targetOptions
 	Identifier string
 }
 
 type testOptions struct {
-	Project string
-	Repo    string
-	ID      string
+	targetOptions
+	ID string
 }
 
 func newDeleteCmd(f *cmdutil.Factory) *cobra.Command {
@@ -330,7 +319,8 @@ func newDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 		Use:     "delete <id|uuid>",
 		Aliases: []string{"rm"},
 		Short:   "Delete a webhook",
-		Long: `Delete a webhook from a Bitbucket repository.
+		Long: `Delete a webhook from a Bitbucket repository, or with --scope from a Data
+Center project or Cloud workspace.
 
 For Data Center, pass the numeric webhook ID (shown by "bkt webhook list").
 For Cloud, pass the webhook UUID. The webhook is removed immediately and cannot
@@ -342,7 +332,10 @@ be recovered.`,
   bkt webhook delete {a1b2c3d4-e5f6-7890-abcd-ef1234567890}
 
   # Delete a webhook from a specific repository
-  bkt webhook delete 7 --project MYPROJ --repo my-repo`,
+  bkt webhook delete 7 --project MYPROJ --repo my-repo
+
+  # Delete a project webhook (Data Center)
+  bkt webhook delete 12 --scope project --project MYPROJ`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.Identifier = args[0]
@@ -350,9 +343,7 @@ be recovered.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override (Data Center)")
-	cmd.Flags().StringVar(&opts.Workspace, "workspace", "", "Bitbucket workspace override (Cloud)")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+	opts.addFlags(cmd, false)
 
 	return cmd
 }
@@ -363,26 +354,19 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions) erro
 		return err
 	}
 
-	override := cmdutil.FlagValue(cmd, "context")
-	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	t, err := resolveTarget(cmd, f, &opts.targetOptions, "")
 	if err != nil {
 		return err
 	}
 
-	switch host.Kind {
+	switch t.host.Kind {
 	case "dc":
-		projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-		if projectKey == "" || repoSlug == "" {
-			return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
-		}
-
 		id, err := strconv.Atoi(opts.Identifier)
 		if err != nil {
 			return fmt.Errorf("invalid webhook id %q", opts.Identifier)
 		}
 
-		client, err := cmdutil.NewDCClient(host)
+		client, err := cmdutil.NewDCClient(t.host)
 		if err != nil {
 			return err
 		}
@@ -390,7 +374,7 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions) erro
 		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 		defer cancel()
 
-		if err := client.DeleteWebhook(ctx, projectKey, repoSlug, id); err != nil {
+		if err := t.deleteDC(ctx, client, id); err != nil {
 			return err
 		}
 
@@ -399,13 +383,7 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions) erro
 		}
 		return nil
 	case "cloud":
-		workspace := cmdutil.FirstNonEmpty(opts.Workspace, ctxCfg.Workspace)
-		repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-		if workspace == "" || repoSlug == "" {
-			return fmt.Errorf("context must supply workspace and repo; use --workspace/--repo if needed")
-		}
-
-		client, err := cmdutil.NewCloudClient(host)
+		client, err := cmdutil.NewCloudClient(t.host)
 		if err != nil {
 			return err
 		}
@@ -413,7 +391,7 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions) erro
 		ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 		defer cancel()
 
-		if err := client.DeleteWebhook(ctx, workspace, repoSlug, opts.Identifier); err != nil {
+		if err := t.deleteCloud(ctx, client, opts.Identifier); err != nil {
 			return err
 		}
 
@@ -422,7 +400,7 @@ func runDelete(cmd *cobra.Command, f *cmdutil.Factory, opts *deleteOptions) erro
 		}
 		return nil
 	default:
-		return fmt.Errorf("unsupported host kind %q", host.Kind)
+		return fmt.Errorf("unsupported host kind %q", t.host.Kind)
 	}
 }
 
@@ -433,14 +411,18 @@ func newTestCmd(f *cmdutil.Factory) *cobra.Command {
 		Short: "Trigger a webhook test delivery",
 		Long: `Send a test payload to a webhook's callback URL to verify connectivity.
 
-This command is supported for Data Center only. It triggers a diagnostic POST
+This command is supported for Data Center only, for repository webhooks and,
+with --scope project, project webhooks. It triggers a diagnostic POST
 request from the Bitbucket server to the webhook's configured URL and reports
 whether the delivery succeeded.`,
 		Example: `  # Test a webhook by ID
   bkt webhook test 42
 
   # Test a webhook in a specific repository
-  bkt webhook test 7 --project MYPROJ --repo my-repo`,
+  bkt webhook test 7 --project MYPROJ --repo my-repo
+
+  # Test a project webhook
+  bkt webhook test 12 --scope project --project MYPROJ`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.ID = args[0]
@@ -448,8 +430,7 @@ whether the delivery succeeded.`,
 		},
 	}
 
-	cmd.Flags().StringVar(&opts.Project, "project", "", "Bitbucket project key override")
-	cmd.Flags().StringVar(&opts.Repo, "repo", "", "Repository slug override")
+	opts.addFlags(cmd, true)
 
 	return cmd
 }
@@ -460,27 +441,17 @@ func runTest(cmd *cobra.Command, f *cmdutil.Factory, opts *testOptions) error {
 		return err
 	}
 
-	override := cmdutil.FlagValue(cmd, "context")
-	_, ctxCfg, host, err := cmdutil.ResolveContext(f, cmd, override)
+	t, err := resolveTarget(cmd, f, &opts.targetOptions, "webhook test")
 	if err != nil {
 		return err
 	}
-	if host.Kind != "dc" {
-		return fmt.Errorf("webhook test is supported for Data Center contexts only")
-	}
-
-	projectKey := cmdutil.FirstNonEmpty(opts.Project, ctxCfg.ProjectKey)
-	repoSlug := cmdutil.FirstNonEmpty(opts.Repo, ctxCfg.DefaultRepo)
-	if projectKey == "" || repoSlug == "" {
-		return fmt.Errorf("context must supply project and repo; use --project/--repo if needed")
-	}
 
 	id, err := strconv.Atoi(opts.ID)
 	if err != nil {
 		return fmt.Errorf("invalid webhook id %q", opts.ID)
 	}
 
-	client, err := cmdutil.NewDCClient(host)
+	client, err := cmdutil.NewDCClient(t.host)
 	if err != nil {
 		return err
 	}
@@ -488,7 +459,7 @@ func runTest(cmd *cobra.Command, f *cmdutil.Factory, opts *testOptions) error {
 	ctx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := client.TestWebhook(ctx, projectKey, repoSlug, id); err != nil {
+	if err := t.testDC(ctx, client, id); err != nil {
 		return err
 	}
 
@@ -3,10 +3,15 @@ package webhook_test
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -393,6 +398,303 @@ func TestWebhookTestDataCenter(t *testing.T) {
 	}
 }
 
+func TestWebhookEdit(t *testing.T) {
+	t.Run("merges flags into the data center definition", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/rest/api/1.0/projects/PROJ/repos/my-repo/webhooks/42" {
+				t.Fatalf("unexpected path: %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Method {
+			case http.MethodGet:
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"id":            42,
+					"name":          "ci-trigger",
+					"url":           "https://ci.example.com/hook",
+					"events":        []string{"repo:refs_changed"},
+					"active":        true,
+					"configuration": map[string]any{"createdBy": "bkt"},
+				})
+			case http.MethodPut:
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body["name"] != "ci-trigger" || body["url"] != "https://ci.example.com/v2" {
+					t.Fatalf("unexpected request body: %+v", body)
+				}
+				if body["active"] != false {
+					t.Fatalf("expected active=false, got %+v", body["active"])
+				}
+				events, ok := body["events"].([]any)
+				if !ok || len(events) != 1 || events[0] != "repo:refs_changed" {
+					t.Fatalf("events should be kept, got %+v", body["events"])
+				}
+				cfg, _ := body["configuration"].(map[string]any)
+				if cfg["secret"] != "s3cret" || cfg["createdBy"] != "bkt" {
+					t.Fatalf("unexpected configuration: %+v", body["configuration"])
+				}
+				body["id"] = 42
+				_ = json.NewEncoder(w).Encode(body)
+			default:
+				t.Fatalf("unexpected method %s", r.Method)
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		stdout, stderr, err := runCLI(t, dcConfig(srv.URL),
+			"webhook", "edit", "42",
+			"--url", "https://ci.example.com/v2",
+			"--active=false",
+			"--secret", "s3cret",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		if !strings.Contains(stdout, "✓ Updated webhook #42 (ci-trigger)") {
+			t.Fatalf("unexpected output: %s", stdout)
+		}
+		if strings.Contains(stderr, "warning") {
+			t.Fatalf("unexpected warning with --secret: %s", stderr)
+		}
+	})
+
+	t.Run("warns that a data center edit without --secret clears the secret", func(t *testing.T) {
+		// Data Center stores the secret but never returns it, and a PUT
+		// replaces the whole definition.
+		storedSecret := "s3cret"
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			hook := map[string]any{
+				"id":            42,
+				"name":          "ci-trigger",
+				"url":           "https://ci.example.com/hook",
+				"events":        []string{"repo:refs_changed"},
+				"active":        true,
+				"configuration": map[string]any{"createdBy": "bkt"},
+			}
+			switch r.Method {
+			case http.MethodGet:
+			case http.MethodPut:
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				cfg, _ := body["configuration"].(map[string]any)
+				storedSecret, _ = cfg["secret"].(string)
+				hook["url"] = body["url"]
+			default:
+				t.Fatalf("unexpected method %s", r.Method)
+			}
+			_ = json.NewEncoder(w).Encode(hook)
+		}))
+		t.Cleanup(srv.Close)
+
+		_, stderr, err := runCLI(t, dcConfig(srv.URL), "webhook", "edit", "42", "--url", "https://ci.example.com/v2")
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		if storedSecret != "" {
+			t.Fatalf("fixture should model Data Center dropping the secret, still has %q", storedSecret)
+		}
+		if !strings.Contains(stderr, "warning: Data Center does not return webhook secrets") || !strings.Contains(stderr, "--secret") {
+			t.Fatalf("expected secret warning, got stderr=%q", stderr)
+		}
+	})
+
+	t.Run("updates cloud webhook events", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/repositories/myworkspace/my-repo/hooks/55555555-5555-5555-5555-555555555555" {
+				t.Fatalf("unexpected path: %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			switch r.Method {
+			case http.MethodGet:
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"uuid":        "{55555555-5555-5555-5555-555555555555}",
+					"description": "slack-notify",
+					"url":         "https://hooks.slack.com/abc",
+					"events":      []string{"repo:push"},
+					"active":      true,
+				})
+			case http.MethodPut:
+				var body map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Fatalf("decode request body: %v", err)
+				}
+				if body["description"] != "slack-notify" || body["active"] != true {
+					t.Fatalf("unexpected request body: %+v", body)
+				}
+				events, ok := body["events"].([]any)
+				if !ok || len(events) != 2 || events[0] != "pullrequest:created" {
+					t.Fatalf("unexpected events payload: %+v", body["events"])
+				}
+				if _, ok := body["secret"]; ok {
+					t.Fatalf("secret should not be sent unless --secret is passed: %+v", body)
+				}
+				body["uuid"] = "{55555555-5555-5555-5555-555555555555}"
+				_ = json.NewEncoder(w).Encode(body)
+			default:
+				t.Fatalf("unexpected method %s", r.Method)
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		stdout, stderr, err := runCLI(t, cloudConfig(srv.URL),
+			"webhook", "edit", "{55555555-5555-5555-5555-555555555555}",
+			"--event", "pullrequest:created",
+			"--event", "pullrequest:fulfilled",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		if !strings.Contains(stdout, "✓ Updated webhook {55555555-5555-5555-5555-555555555555}") {
+			t.Fatalf("unexpected output: %s", stdout)
+		}
+	})
+
+	t.Run("requires a change", func(t *testing.T) {
+		_, _, err := runCLI(t, dcConfig("http://localhost"), "webhook", "edit", "42")
+		if err == nil || !strings.Contains(err.Error(), "nothing to update") {
+			t.Fatalf("expected nothing to update error, got %v", err)
+		}
+	})
+}
+
+func TestWebhookDeliveriesDataCenter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PROJ/repos/my-repo/webhooks/42/statistics":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"counts": map[string]any{"successes": 3, "failures": 1, "errors": 0, "window": 86400000},
+				"lastFailure": map[string]any{
+					"id": 9, "event": "pr:opened", "start": 1735732800000,
+					"result": map[string]any{"outcome": "FAILURE", "statusCode": 500},
+				},
+			})
+		case "/rest/api/1.0/projects/PROJ/repos/my-repo/webhooks/42/latest":
+			if got := r.URL.Query().Get("outcome"); got != "FAILURE" {
+				t.Fatalf("outcome = %q, want FAILURE", got)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id": 9, "event": "pr:opened", "start": 1735732800000, "duration": 120,
+				"request": map[string]any{"method": "POST", "url": "https://ci.example.com/hook", "body": `{"eventKey":"pr:opened"}`},
+				"result":  map[string]any{"outcome": "FAILURE", "statusCode": 500, "body": "boom"},
+			})
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	stdout, stderr, err := runCLI(t, dcConfig(srv.URL), "webhook", "deliveries", "42", "--outcome", "failure")
+	if err != nil {
+		t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+	}
+	for _, want := range []string{
+		"Webhook #42: 3 succeeded, 1 failed, 0 errored in the last 24h0m0s",
+		"Last failure: 2025-01-01T12:00:00Z  pr:opened  FAILURE (HTTP 500)",
+		"Request:  POST https://ci.example.com/hook",
+		`  {"eventKey":"pr:opened"}`,
+		"Response body:\n  boom",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Fatalf("output missing %q:\n%s", want, stdout)
+		}
+	}
+
+	_, _, err = runCLI(t, cloudConfig("http://localhost"), "webhook", "deliveries", "42")
+	if err == nil || !strings.Contains(err.Error(), "Data Center contexts only") {
+		t.Fatalf("expected cloud rejection, got %v", err)
+	}
+}
+
+func TestWebhookScope(t *testing.T) {
+	t.Run("lists data center project webhooks", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/rest/api/1.0/projects/PLAT/webhooks" {
+				t.Fatalf("unexpected path: %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"values": []map[string]any{{"id": 3, "name": "audit", "url": "https://audit.example.com", "active": true}},
+			})
+		}))
+		t.Cleanup(srv.Close)
+
+		stdout, stderr, err := runCLI(t, dcConfig(srv.URL), "webhook", "list", "--scope", "project", "--project", "PLAT", "--json")
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal([]byte(stdout), &payload); err != nil {
+			t.Fatalf("decode json: %v\n%s", err, stdout)
+		}
+		if payload["scope"] != "project" || payload["project"] != "PLAT" {
+			t.Fatalf("unexpected payload: %+v", payload)
+		}
+		if _, ok := payload["repo"]; ok {
+			t.Fatalf("project payload should not include repo: %+v", payload)
+		}
+	})
+
+	t.Run("creates cloud workspace webhook", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/workspaces/myworkspace/hooks" {
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"uuid": "{44444444-4444-4444-4444-444444444444}"})
+		}))
+		t.Cleanup(srv.Close)
+
+		stdout, stderr, err := runCLI(t, cloudConfig(srv.URL),
+			"webhook", "create", "--scope", "workspace",
+			"--name", "audit", "--url", "https://audit.example.com", "--event", "repo:push",
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		if !strings.Contains(stdout, "✓ Created webhook {44444444-4444-4444-4444-444444444444}") {
+			t.Fatalf("unexpected output: %s", stdout)
+		}
+	})
+
+	t.Run("tests data center project webhook", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/rest/api/1.0/projects/PROJ/webhooks/12/test" {
+				t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		if _, stderr, err := runCLI(t, dcConfig(srv.URL), "webhook", "test", "12", "--scope", "project"); err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		cfg  *config.Config
+		args []string
+		want string
+	}{
+		{"unknown scope", dcConfig("http://localhost"), []string{"webhook", "list", "--scope", "org"}, `invalid --scope "org"`},
+		{"workspace on data center", dcConfig("http://localhost"), []string{"webhook", "list", "--scope", "workspace"}, "Cloud contexts only"},
+		{"project on cloud", cloudConfig("http://localhost"), []string{"webhook", "list", "--scope", "project"}, "use --scope workspace on Cloud"},
+		{"repo with owner scope", dcConfig("http://localhost"), []string{"webhook", "delete", "3", "--scope", "project", "--repo", "x"}, "--repo cannot be combined with --scope project"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := runCLI(t, tc.cfg, tc.args...)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
 func cloudConfig(baseURL string) *config.Config {
 	return &config.Config{
 		ActiveContext: "test",
@@ -471,3 +773,72 @@ func runCLI(t *testing.T, cfg *config.Config, args ...string) (string, string, e
 	err = rootCmd.ExecuteContext(context.Background())
 	return stdout.String(), stderr.String(), err
 }
+
+func TestWebhookReplay(t *testing.T) {
+	var got struct {
+		body, event, sig string
+	}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got.body = string(data)
+		got.event = r.Header.Get("X-Event-Key")
+		got.sig = r.Header.Get("X-Hub-Signature")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(target.Close)
+
+	dir := t.TempDir()
+
+	t.Run("recorded delivery is sent unchanged", func(t *testing.T) {
+		path := filepath.Join(dir, "recorded.json")
+		record := `{"event_key":"pr:opened","method":"POST","path":"/hook","headers":{"X-Event-Key":"pr:opened","X-Hub-Signature":"sha256=orig"},"body":"{\"eventKey\":\"pr:opened\"}"}`
+		if err := os.WriteFile(path, []byte(record), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		stdout, stderr, err := runCLI(t, dcConfig("http://localhost"), "webhook", "replay", path, "--target", target.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		if got.body != `{"eventKey":"pr:opened"}` || got.event != "pr:opened" || got.sig != "sha256=orig" {
+			t.Fatalf("unexpected delivery: %+v", got)
+		}
+		if !strings.Contains(stdout, "✓ Replayed pr:opened to "+target.URL+" (200 OK)") {
+			t.Fatalf("unexpected output: %s", stdout)
+		}
+	})
+
+	t.Run("bare payload is re-signed", func(t *testing.T) {
+		path := filepath.Join(dir, "push.json")
+		if err := os.WriteFile(path, []byte(`{"push":{}}`+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, stderr, err := runCLI(t, dcConfig("http://localhost"), "webhook", "replay", path,
+			"--target", target.URL, "--event", "repo:push", "--secret", "s3cret")
+		if err != nil {
+			t.Fatalf("unexpected error: %v (stderr=%s)", err, stderr)
+		}
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write([]byte(`{"push":{}}`))
+		if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.sig != want {
+			t.Fatalf("signature = %q, want %q", got.sig, want)
+		}
+		if got.event != "repo:push" || got.body != `{"push":{}}` {
+			t.Fatalf("unexpected delivery: %+v", got)
+		}
+	})
+
+	t.Run("non-2xx response is an error", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(failing.Close)
+
+		path := filepath.Join(dir, "push.json")
+		_, _, err := runCLI(t, dcConfig("http://localhost"), "webhook", "replay", path, "--target", failing.URL)
+		if err == nil || !strings.Contains(err.Error(), "500 Internal Server Error") {
+			t.Fatalf("expected target error, got %v", err)
+		}
+	})
+}
@@ -44,21 +44,6 @@ func NewDCClient(host *config.Host) (*bbdc.Client, error) {
 // When the host uses OAuth authentication, a TokenRefresher is wired to
 // transparently refresh expired tokens on 401.
 func NewCloudClient(host *config.Host) (*bbcloud.Client, error) {
-	return newCloudClient(host, true)
-}
-
-// NewFrozenCloudClient constructs a Bitbucket Cloud client that uses exactly
-// the supplied credential for its lifetime. OAuth credentials use bearer auth,
-// but the client never reopens the credential store or refreshes on 401.
-func NewFrozenCloudClient(host *config.Host) (*bbcloud.Client, error) {
-	if host == nil {
-		return nil, fmt.Errorf("missing host configuration")
-	}
-	frozen := *host
-	return newCloudClient(&frozen, false)
-}
-
-func newCloudClient(host *config.Host, enableOAuthRefresh bool) (*bbcloud.Client, error) {
 	if host == nil {
 		return nil, fmt.Errorf("missing host configuration")
 	}
@@ -80,13 +65,11 @@ func newCloudClient(host *config.Host, enableOAuthRefresh bool) (*bbcloud.Client
 	if host.AuthMethod != "oauth" {
 		opts.AuthMethod = host.AuthMethod
 	}
-	if host.AuthMethod == "oauth" && (!enableOAuthRefresh || secret.TokenFromEnv() == "") {
-		opts.AuthMethod = "bearer"
-	}
-	if enableOAuthRefresh && host.AuthMethod == "oauth" && secret.TokenFromEnv() == "" {
+	if host.AuthMethod == "oauth" && secret.TokenFromEnv() == "" {
 		// Keyring-stored OAuth tokens use Bearer auth + auto-refresh.
 		// When BKT_TOKEN overrides, the caller controls the token type
 		// and auth method defaults to basic (matching API-token behavior).
+		opts.AuthMethod = "bearer"
 		hostKey, err := HostKeyFromURL(host.BaseURL)
 		if err != nil {
 			return nil, fmt.Errorf("resolve host key: %w", err)
@@ -167,6 +150,36 @@ func oauthTokenRefresher(hostKey string, host *config.Host) func(ctx context.Con
 	}
 }
 
+// FreshAccessToken returns the host's credential for handing to another
+// process. A Cloud OAuth access token that expires within minValidity is
+// refreshed (and persisted) first; other credentials are returned as is.
+func FreshAccessToken(ctx context.Context, host *config.Host, minValidity time.Duration) (string, error) {
+	if host == nil {
+		return "", fmt.Errorf("missing host configuration")
+	}
+	if host.AuthMethod != "oauth" || secret.TokenFromEnv() != "" || host.OAuthExpiresAt.IsZero() {
+		return host.Token, nil
+	}
+	if time.Until(host.OAuthExpiresAt) > minValidity {
+		return host.Token, nil
+	}
+	baseURL := host.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.bitbucket.org/2.0"
+	}
+	hostKey, err := HostKeyFromURL(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("resolve host key: %w", err)
+	}
+	if oauth.CloudClientID() == "" || oauth.CloudClientSecret() == "" {
+		if time.Now().Before(host.OAuthExpiresAt) {
+			return host.Token, nil
+		}
+		return "", oauthMissingCredsError(hostKey, host.OAuthExpiresAt)
+	}
+	return oauthTokenRefresher(hostKey, host)(ctx)
+}
+
 func preflightExpiredOAuth(hostKey string, host *config.Host) error {
 	if host == nil || host.OAuthExpiresAt.IsZero() || time.Now().Before(host.OAuthExpiresAt) {
 		return nil
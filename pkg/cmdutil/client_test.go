@@ -3,17 +3,14 @@ package cmdutil
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
-	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/avivsinai/bitbucket-cli/internal/config"
 	"github.com/avivsinai/bitbucket-cli/internal/secret"
-	"github.com/avivsinai/bitbucket-cli/pkg/httpx"
 	"github.com/avivsinai/bitbucket-cli/pkg/oauth"
 )
 
@@ -130,71 +127,74 @@ func TestNewCloudClientOAuthExpiredMissingCredsPreflight(t *testing.T) {
 	}
 }
 
-func TestNewFrozenCloudClientOAuthNeverRefreshes(t *testing.T) {
+func TestFreshAccessTokenPassesThroughUsableCredentials(t *testing.T) {
 	t.Setenv(secret.EnvToken, "")
 	t.Setenv("BKT_OAUTH_CLIENT_ID", "")
 	t.Setenv("BKT_OAUTH_CLIENT_SECRET", "")
+
+	tests := []struct {
+		name string
+		host *config.Host
+	}{
+		{name: "api token", host: &config.Host{Kind: "dc", AuthMethod: "basic", Token: "pat"}},
+		{name: "fresh oauth", host: &config.Host{Kind: "cloud", AuthMethod: "oauth", Token: "pat", OAuthExpiresAt: time.Now().Add(time.Hour)}},
+		{name: "expiring oauth without consumer", host: &config.Host{Kind: "cloud", AuthMethod: "oauth", Token: "pat", OAuthExpiresAt: time.Now().Add(time.Minute)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FreshAccessToken(context.Background(), tt.host, 5*time.Minute)
+			if err != nil || got != "pat" {
+				t.Fatalf("FreshAccessToken = %q, %v; want pat", got, err)
+			}
+		})
+	}
+
+	expired := &config.Host{Kind: "cloud", AuthMethod: "oauth", Token: "old", OAuthExpiresAt: time.Now().Add(-time.Minute)}
+	if _, err := FreshAccessToken(context.Background(), expired, 5*time.Minute); err == nil || !strings.Contains(err.Error(), "expired at") {
+		t.Fatalf("FreshAccessToken(expired) error = %v, want expiry error", err)
+	}
+}
+
+func TestFreshAccessTokenRefreshesExpiringOAuthToken(t *testing.T) {
+	t.Setenv(secret.EnvToken, "")
+	t.Setenv("BKT_OAUTH_CLIENT_ID", "client-id")
+	t.Setenv("BKT_OAUTH_CLIENT_SECRET", "client-secret")
 	t.Setenv("BKT_ALLOW_INSECURE_STORE", "1")
 	t.Setenv("BKT_KEYRING_PASSPHRASE", "test-pass")
 	t.Setenv("KEYRING_BACKEND", "file")
 	fileDir := t.TempDir()
 	t.Setenv("KEYRING_FILE_DIR", fileDir)
-	var requests atomic.Int32
-	var refreshedRequests atomic.Int32
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requests.Add(1)
-		switch got := r.Header.Get("Authorization"); got {
-		case "Bearer frozen-access":
-		case "Bearer newer-keyring-access":
-			refreshedRequests.Add(1)
-		default:
-			t.Errorf("Authorization = %q, want frozen bearer token", got)
-		}
-		w.WriteHeader(http.StatusUnauthorized)
-	}))
-	t.Cleanup(server.Close)
-	hostKey, err := HostKeyFromURL(server.URL)
-	if err != nil {
-		t.Fatal(err)
-	}
-	newer := oauth.FromResponse("newer-keyring-access", "newer-refresh", 7200)
-	blob, err := newer.Marshal()
+
+	blob, err := oauth.FromResponse("new-access", "new-refresh", 7200).Marshal()
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("marshal: %v", err)
 	}
 	store, err := secret.Open(secret.WithAllowFileFallback(true), secret.WithPassphrase("test-pass"), secret.WithFileDir(fileDir))
 	if err != nil {
-		t.Fatal(err)
+		t.Fatalf("open store: %v", err)
 	}
-	if err := store.Set(secret.TokenKey(hostKey), blob); err != nil {
-		t.Fatal(err)
+	if err := store.Set(secret.TokenKey("api.bitbucket.org"), blob); err != nil {
+		t.Fatalf("store.Set: %v", err)
 	}
+
 	host := &config.Host{
 		Kind:               "cloud",
-		BaseURL:            server.URL,
+		BaseURL:            "https://api.bitbucket.org/2.0",
 		AuthMethod:         "oauth",
-		Token:              "frozen-access",
-		OAuthExpiresAt:     time.Now().Add(time.Hour),
+		Token:              "old-access",
 		AllowInsecureStore: true,
+		OAuthExpiresAt:     time.Now().Add(time.Minute),
 	}
 
-	client, err := NewFrozenCloudClient(host)
+	got, err := FreshAccessToken(context.Background(), host, 5*time.Minute)
 	if err != nil {
-		t.Fatalf("NewFrozenCloudClient: %v", err)
-	}
-	_, err = client.CurrentUser(context.Background())
-	var httpErr *httpx.HTTPError
-	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
-		t.Fatalf("CurrentUser error = %T %v, want frozen 401 HTTPError", err, err)
-	}
-	if got := requests.Load(); got != 1 {
-		t.Fatalf("requests = %d, want one request with no refresh retry", got)
+		t.Fatalf("FreshAccessToken: %v", err)
 	}
-	if got := refreshedRequests.Load(); got != 0 {
-		t.Fatalf("requests with refreshed keyring token = %d, want zero", got)
+	if got != "new-access" || host.Token != "new-access" {
+		t.Fatalf("token = %q (host %q), want new-access", got, host.Token)
 	}
-	if host.Token != "frozen-access" {
-		t.Fatalf("host token mutated to %q", host.Token)
+	if time.Until(host.OAuthExpiresAt) < time.Hour {
+		t.Fatalf("OAuthExpiresAt = %s, want the refreshed expiry", host.OAuthExpiresAt)
 	}
 }
 
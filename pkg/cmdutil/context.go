@@ -1,6 +1,7 @@
 package cmdutil
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -38,7 +39,30 @@ func resolveContext(f *Factory, override string, remoteDefaults bool) (string, *
 		return "", nil, nil, err
 	}
 
+	// .bkt.yaml depends on the working directory, so it is honoured exactly
+	// when git remote detection is.
+	var project *config.ProjectConfig
+	if remoteDefaults {
+		if project, err = f.ProjectConfig(); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
 	contextName := override
+	fromProject := false
+	if contextName == "" && project != nil && os.Getenv(secret.EnvHost) == "" {
+		switch {
+		case project.Context != "":
+			contextName = project.Context
+			fromProject = true
+		case project.Host != "":
+			if active, ok := cfg.Contexts[cfg.ActiveContext]; ok && active.Host == project.Host {
+				contextName = cfg.ActiveContext
+			} else {
+				return resolveProjectHost(f, cfg, project)
+			}
+		}
+	}
 	if contextName == "" {
 		contextName = cfg.ActiveContext
 	}
@@ -63,6 +87,7 @@ func resolveContext(f *Factory, override string, remoteDefaults bool) (string, *
 			ctx.Host = envKey
 			if remoteDefaults {
 				applyRemoteDefaults(ctx, envHost)
+				applyProjectDefaults(ctx, project)
 			}
 			return "", ctx, envHost, nil
 		}
@@ -77,6 +102,9 @@ func resolveContext(f *Factory, override string, remoteDefaults bool) (string, *
 
 	ctx, err := cfg.Context(contextName)
 	if err != nil {
+		if fromProject {
+			return "", nil, nil, fmt.Errorf("%s: context %q: %w", project.Path(), contextName, err)
+		}
 		return "", nil, nil, err
 	}
 
@@ -94,12 +122,34 @@ func resolveContext(f *Factory, override string, remoteDefaults bool) (string, *
 	}
 
 	if remoteDefaults {
+		// Work on a copy so directory-derived defaults never leak into the
+		// persisted context.
+		local := *ctx
+		ctx = &local
 		applyRemoteDefaults(ctx, host)
+		applyProjectDefaults(ctx, project)
 	}
 
 	return contextName, ctx, host, nil
 }
 
+// resolveProjectHost builds an ephemeral context for a .bkt.yaml that pins a
+// host but no context.
+func resolveProjectHost(f *Factory, cfg *config.Config, project *config.ProjectConfig) (string, *config.Context, *config.Host, error) {
+	host, err := cfg.Host(project.Host)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("%s: host %q is not configured; run `%s auth login` first", project.Path(), project.Host, f.ExecutableName)
+	}
+	if err := loadHostToken(f.ExecutableName, project.Host, host); err != nil {
+		return "", nil, nil, err
+	}
+	ctx := contextFromEnv()
+	ctx.Host = project.Host
+	applyRemoteDefaults(ctx, host)
+	applyProjectDefaults(ctx, project)
+	return "", ctx, host, nil
+}
+
 // ResolveHost locates a host configuration using optional context or host overrides.
 // When neither override is provided it falls back to the active context, then to a
 // single configured host. This enables commands to function prior to context setup.
@@ -286,6 +336,15 @@ func loadHostToken(executable, hostKey string, host *config.Host) error {
 		return nil
 	}
 
+	if command := strings.TrimSpace(host.TokenCommand); command != "" {
+		token, err := secret.TokenFromCommand(context.Background(), command)
+		if err != nil {
+			return fmt.Errorf("token_command for host %q: %w", hostKey, err)
+		}
+		host.Token = token
+		return nil
+	}
+
 	opts := []secret.Option{}
 	if host.AllowInsecureStore {
 		opts = append(opts, secret.WithAllowFileFallback(true))
@@ -465,6 +524,23 @@ func applyRemoteDefaults(ctx *config.Context, host *config.Host) {
 	}
 }
 
+// applyProjectDefaults layers .bkt.yaml repository pins over the context and
+// git remote detection. BKT_PROJECT, BKT_WORKSPACE, and BKT_REPO still win.
+func applyProjectDefaults(ctx *config.Context, project *config.ProjectConfig) {
+	if ctx == nil || project == nil {
+		return
+	}
+	if project.Project != "" && os.Getenv(secret.EnvProject) == "" {
+		ctx.ProjectKey = project.Project
+	}
+	if project.Workspace != "" && os.Getenv(secret.EnvWorkspace) == "" {
+		ctx.Workspace = project.Workspace
+	}
+	if project.Repo != "" && os.Getenv(secret.EnvRepo) == "" {
+		ctx.DefaultRepo = project.Repo
+	}
+}
+
 // LocatorMatchesHost reports whether a remote locator points at the same
 // server as the supplied host configuration.
 func LocatorMatchesHost(host *config.Host, loc remote.Locator) bool {
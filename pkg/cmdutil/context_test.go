@@ -3,6 +3,7 @@ package cmdutil
 import (
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -1207,3 +1208,160 @@ func TestResolveHostMultipleHostsErrorWhenNoEnvHost(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+func TestLoadHostTokenUsesTokenCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	t.Setenv(secret.EnvToken, "")
+	// Any keyring access would fail: no backend is permitted.
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("BKT_ALLOW_INSECURE_STORE", "")
+
+	command := "printf 'from-command\\n'"
+	t.Cleanup(func() { secret.ForgetTokenCommand(command) })
+
+	host := &config.Host{
+		Kind:         "dc",
+		BaseURL:      "https://bitbucket.example.com",
+		TokenCommand: command,
+	}
+	if err := loadHostToken("bkt", "bitbucket.example.com", host); err != nil {
+		t.Fatalf("loadHostToken returned error: %v", err)
+	}
+	if host.Token != "from-command" {
+		t.Fatalf("token = %q, want from-command", host.Token)
+	}
+}
+
+func TestLoadHostTokenEnvTokenOverridesTokenCommand(t *testing.T) {
+	t.Setenv(secret.EnvToken, "env-token")
+
+	host := &config.Host{
+		Kind:         "dc",
+		BaseURL:      "https://bitbucket.example.com",
+		TokenCommand: "exit 1",
+	}
+	if err := loadHostToken("bkt", "bitbucket.example.com", host); err != nil {
+		t.Fatalf("loadHostToken returned error: %v", err)
+	}
+	if host.Token != "env-token" {
+		t.Fatalf("token = %q, want env-token", host.Token)
+	}
+}
+
+func TestLoadHostTokenReportsTokenCommandFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses /bin/sh")
+	}
+	t.Setenv(secret.EnvToken, "")
+
+	host := &config.Host{
+		Kind:         "dc",
+		BaseURL:      "https://bitbucket.example.com",
+		TokenCommand: "echo 'vault: not logged in' >&2; exit 3",
+	}
+	err := loadHostToken("bkt", "bitbucket.example.com", host)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), `token_command for host "bitbucket.example.com"`) || !strings.Contains(err.Error(), "not logged in") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// --- .bkt.yaml project config tests ---
+
+func chdirProjectConfig(t *testing.T, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(dir+"/"+config.ProjectFileName, []byte(body), 0o644); err != nil {
+		t.Fatalf("write project config: %v", err)
+	}
+	t.Chdir(dir)
+}
+
+func projectConfigTestConfig() *config.Config {
+	return &config.Config{
+		ActiveContext: "personal",
+		Contexts: map[string]*config.Context{
+			"personal": {Host: "bitbucket.org", Workspace: "me", DefaultRepo: "dotfiles"},
+			"work":     {Host: "bitbucket.example.com", ProjectKey: "WORK", DefaultRepo: "svc"},
+		},
+		Hosts: map[string]*config.Host{
+			"bitbucket.org":         {Kind: "cloud", BaseURL: "https://api.bitbucket.org/2.0", Token: "t1"},
+			"bitbucket.example.com": {Kind: "dc", BaseURL: "https://bitbucket.example.com", Token: "t2"},
+		},
+	}
+}
+
+func TestResolveContextUsesProjectConfigContext(t *testing.T) {
+	chdirProjectConfig(t, "context: work\nrepo: api\n")
+
+	name, ctx, host, err := ResolveContext(newTestFactory(projectConfigTestConfig()), nil, "")
+	if err != nil {
+		t.Fatalf("ResolveContext: %v", err)
+	}
+	if name != "work" || host.Kind != "dc" {
+		t.Fatalf("context = %q kind = %q, want work/dc", name, host.Kind)
+	}
+	if ctx.ProjectKey != "WORK" || ctx.DefaultRepo != "api" {
+		t.Fatalf("project/repo = %q/%q, want WORK/api", ctx.ProjectKey, ctx.DefaultRepo)
+	}
+}
+
+func TestResolveContextFlagBeatsProjectConfig(t *testing.T) {
+	chdirProjectConfig(t, "context: work\n")
+
+	name, _, _, err := ResolveContext(newTestFactory(projectConfigTestConfig()), nil, "personal")
+	if err != nil {
+		t.Fatalf("ResolveContext: %v", err)
+	}
+	if name != "personal" {
+		t.Fatalf("context = %q, want personal", name)
+	}
+}
+
+func TestResolveContextEnvBeatsProjectConfigRepo(t *testing.T) {
+	chdirProjectConfig(t, "project: FILE\nrepo: api\n")
+	t.Setenv(secret.EnvToken, "test-token")
+	t.Setenv(secret.EnvHost, "https://bitbucket.example.com")
+	t.Setenv(secret.EnvRepo, "from-env")
+
+	f := newTestFactory(&config.Config{
+		Contexts: map[string]*config.Context{},
+		Hosts:    map[string]*config.Host{},
+	})
+
+	_, ctx, _, err := ResolveContext(f, nil, "")
+	if err != nil {
+		t.Fatalf("ResolveContext: %v", err)
+	}
+	if ctx.DefaultRepo != "from-env" {
+		t.Fatalf("repo = %q, want from-env", ctx.DefaultRepo)
+	}
+	if ctx.ProjectKey != "FILE" {
+		t.Fatalf("project = %q, want FILE", ctx.ProjectKey)
+	}
+}
+
+func TestResolveContextProjectConfigUnknownContext(t *testing.T) {
+	chdirProjectConfig(t, "context: missing\n")
+
+	_, _, _, err := ResolveContext(newTestFactory(projectConfigTestConfig()), nil, "")
+	if err == nil || !strings.Contains(err.Error(), config.ProjectFileName) {
+		t.Fatalf("expected error naming %s, got %v", config.ProjectFileName, err)
+	}
+}
+
+func TestResolveContextStaticIgnoresProjectConfig(t *testing.T) {
+	chdirProjectConfig(t, "context: work\n")
+
+	name, _, _, err := ResolveContextStatic(newTestFactory(projectConfigTestConfig()), "")
+	if err != nil {
+		t.Fatalf("ResolveContextStatic: %v", err)
+	}
+	if name != "personal" {
+		t.Fatalf("context = %q, want personal", name)
+	}
+}
@@ -1,6 +1,7 @@
 package cmdutil
 
 import (
+	"os"
 	"sync"
 
 	"github.com/avivsinai/bitbucket-cli/internal/config"
@@ -37,6 +38,11 @@ type Factory struct {
 	}
 	cfg    *config.Config
 	cfgErr error
+
+	projectOnce sync.Once
+	project     *config.ProjectConfig
+	projectErr  error
+
 	ioOnce sync.Once
 	ios    *iostreams.IOStreams
 }
@@ -53,6 +59,19 @@ func (f *Factory) ResolveConfig() (*config.Config, error) {
 	return f.cfg, f.cfgErr
 }
 
+// ProjectConfig returns the .bkt.yaml governing the working directory, or nil
+// when there is none. The result is cached.
+func (f *Factory) ProjectConfig() (*config.ProjectConfig, error) {
+	f.projectOnce.Do(func() {
+		wd, err := os.Getwd()
+		if err != nil {
+			return
+		}
+		f.project, f.projectErr = config.FindProjectConfig(wd)
+	})
+	return f.project, f.projectErr
+}
+
 // Streams returns process IO streams, initialising them lazily.
 func (f *Factory) Streams() (*iostreams.IOStreams, error) {
 	f.ioOnce.Do(func() {
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/avivsinai/bitbucket-cli/pkg/format"
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
 )
 
 // OutputSettings captures structured output preferences.
@@ -15,8 +16,12 @@ type OutputSettings struct {
 	Format   string
 	JQ       string
 	Template string
+	Fields   []string
 }
 
+// tabularFormats are the record-per-line formats that accept --fields.
+var tabularFormats = map[string]bool{"csv": true, "tsv": true, "ndjson": true}
+
 // OutputSettings extracts flags from the command hierarchy with validation.
 func ResolveOutputSettings(cmd *cobra.Command) (OutputSettings, error) {
 	root := cmd.Root()
@@ -34,6 +39,10 @@ func ResolveOutputSettings(cmd *cobra.Command) (OutputSettings, error) {
 	formatVal := strings.ToLower(lookup("format"))
 	jqExpr := lookup("jq")
 	tmpl := lookup("template")
+	var fields []string
+	if root.PersistentFlags().Lookup("fields") != nil {
+		fields, _ = root.PersistentFlags().GetStringSlice("fields")
+	}
 
 	if jsonEnabled && yamlEnabled {
 		return OutputSettings{}, fmt.Errorf("cannot use --json and --yaml simultaneously")
@@ -43,16 +52,24 @@ func ResolveOutputSettings(cmd *cobra.Command) (OutputSettings, error) {
 		return OutputSettings{}, fmt.Errorf("cannot use --format and --json/--yaml simultaneously")
 	}
 
-	if formatVal != "" && formatVal != "json" && formatVal != "yaml" {
-		return OutputSettings{}, fmt.Errorf("--format %q is not supported; use json or yaml", formatVal)
+	if formatVal != "" && formatVal != "json" && formatVal != "yaml" && !tabularFormats[formatVal] {
+		return OutputSettings{}, fmt.Errorf("--format %q is not supported; use json, yaml, csv, tsv, or ndjson", formatVal)
+	}
+
+	if len(fields) > 0 && !tabularFormats[formatVal] {
+		return OutputSettings{}, fmt.Errorf("--fields requires --format csv, tsv, or ndjson")
+	}
+
+	if tmpl != "" && tabularFormats[formatVal] {
+		return OutputSettings{}, fmt.Errorf("cannot use --template with --format %s", formatVal)
 	}
 
 	if jqExpr != "" && tmpl != "" {
 		return OutputSettings{}, fmt.Errorf("cannot use --jq and --template simultaneously")
 	}
 
-	if jqExpr != "" && !jsonEnabled && formatVal != "json" {
-		return OutputSettings{}, fmt.Errorf("--jq requires --json or --format json")
+	if jqExpr != "" && !jsonEnabled && formatVal != "json" && !tabularFormats[formatVal] {
+		return OutputSettings{}, fmt.Errorf("--jq requires --json or --format json, csv, tsv, or ndjson")
 	}
 
 	format := formatVal
@@ -68,6 +85,7 @@ func ResolveOutputSettings(cmd *cobra.Command) (OutputSettings, error) {
 		Format:   format,
 		JQ:       jqExpr,
 		Template: tmpl,
+		Fields:   fields,
 	}, nil
 }
 
@@ -81,13 +99,36 @@ func OutputFormat(cmd *cobra.Command) (string, error) {
 	return settings.Format, nil
 }
 
-// WriteOutput writes structured output according to user preferences and runs
-// fallback when no structured output is requested.
-func WriteOutput(cmd *cobra.Command, w io.Writer, data any, fallback func() error) error {
+// WriteOutput writes structured output to ios.Out according to user
+// preferences and runs fallback when no structured output is requested.
+// Template helpers (color, hyperlink) follow the colour and terminal state
+// of ios.
+func WriteOutput(cmd *cobra.Command, ios *iostreams.IOStreams, data any, fallback func() error) error {
 	settings, err := ResolveOutputSettings(cmd)
 	if err != nil {
 		return err
 	}
-	opts := format.Options{Format: settings.Format, JQ: settings.JQ, Template: settings.Template}
-	return format.Write(w, opts, data, fallback)
+	return format.Write(ios.Out, format.Options{
+		Format:   settings.Format,
+		JQ:       settings.JQ,
+		Template: settings.Template,
+		Fields:   settings.Fields,
+		Color:    ios.ColorEnabled(),
+		Terminal: ios.IsStdoutTTY(),
+	}, data, fallback)
+}
+
+// StreamOutput returns an NDJSON stream when the user asked for
+// --format ndjson, letting list commands print each item as its page arrives.
+// It returns nil for every other output mode, including --jq, which needs
+// the complete result; callers then collect items and use WriteOutput.
+func StreamOutput(cmd *cobra.Command, w io.Writer) (*format.NDJSONStream, error) {
+	settings, err := ResolveOutputSettings(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if settings.Format != "ndjson" || settings.JQ != "" {
+		return nil, nil
+	}
+	return format.NewNDJSONStream(w, settings.Fields), nil
 }
@@ -1,10 +1,13 @@
 package cmdutil
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/iostreams"
 )
 
 func newTestCommand(flags map[string]string) *cobra.Command {
@@ -14,6 +17,7 @@ func newTestCommand(flags map[string]string) *cobra.Command {
 	root.PersistentFlags().String("jq", "", "")
 	root.PersistentFlags().String("template", "", "")
 	root.PersistentFlags().String("format", "", "")
+	root.PersistentFlags().StringSlice("fields", nil, "")
 
 	child := &cobra.Command{Use: "test"}
 	root.AddCommand(child)
@@ -183,6 +187,62 @@ func TestResolveOutputSettingsFormatEmpty(t *testing.T) {
 	}
 }
 
+func TestResolveOutputSettingsTabularFormats(t *testing.T) {
+	for _, val := range []string{"csv", "TSV", "ndjson"} {
+		cmd := newTestCommand(map[string]string{"format": val, "fields": "id,author.name", "jq": ".values"})
+		settings, err := ResolveOutputSettings(cmd)
+		if err != nil {
+			t.Fatalf("ResolveOutputSettings(%s): %v", val, err)
+		}
+		if settings.Format != strings.ToLower(val) {
+			t.Fatalf("format = %q, want %q", settings.Format, strings.ToLower(val))
+		}
+		if len(settings.Fields) != 2 || settings.Fields[1] != "author.name" {
+			t.Fatalf("fields = %v", settings.Fields)
+		}
+	}
+}
+
+func TestResolveOutputSettingsFieldsRequireTabularFormat(t *testing.T) {
+	cmd := newTestCommand(map[string]string{"json": "true", "fields": "id"})
+	_, err := ResolveOutputSettings(cmd)
+	if err == nil || !strings.Contains(err.Error(), "--fields requires") {
+		t.Fatalf("expected --fields error, got %v", err)
+	}
+}
+
+func TestResolveOutputSettingsRejectsTemplateWithCSV(t *testing.T) {
+	cmd := newTestCommand(map[string]string{"format": "csv", "template": "{{.}}"})
+	_, err := ResolveOutputSettings(cmd)
+	if err == nil || !strings.Contains(err.Error(), "--template") {
+		t.Fatalf("expected --template error, got %v", err)
+	}
+}
+
+func TestStreamOutputOnlyForNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	stream, err := StreamOutput(newTestCommand(map[string]string{"format": "csv"}), &buf)
+	if err != nil || stream != nil {
+		t.Fatalf("StreamOutput(csv) = %v, %v; want nil, nil", stream, err)
+	}
+	stream, err = StreamOutput(newTestCommand(map[string]string{"format": "ndjson", "jq": ".x"}), &buf)
+	if err != nil || stream != nil {
+		t.Fatalf("StreamOutput(ndjson+jq) = %v, %v; want nil, nil", stream, err)
+	}
+
+	stream, err = StreamOutput(newTestCommand(map[string]string{"format": "ndjson", "fields": "slug"}), &buf)
+	if err != nil || stream == nil {
+		t.Fatalf("StreamOutput(ndjson) = %v, %v", stream, err)
+	}
+	if err := stream.Write(map[string]string{"slug": "api", "name": "API"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != "{\"slug\":\"api\"}\n" {
+		t.Fatalf("stream output = %q", got)
+	}
+}
+
 func TestOutputFormat(t *testing.T) {
 	cmd := newTestCommand(map[string]string{"yaml": "true"})
 	format, err := OutputFormat(cmd)
@@ -193,3 +253,25 @@ func TestOutputFormat(t *testing.T) {
 		t.Fatalf("format = %q, want yaml", format)
 	}
 }
+
+func TestWriteOutputFollowsStreamColour(t *testing.T) {
+	cmd := newTestCommand(map[string]string{"template": `{{color "green" "ok"}}`})
+
+	var coloured strings.Builder
+	ios := &iostreams.IOStreams{Out: &coloured}
+	ios.SetColorEnabled(true)
+	if err := WriteOutput(cmd, ios, nil, nil); err != nil {
+		t.Fatalf("WriteOutput: %v", err)
+	}
+	if coloured.String() != "\x1b[32mok\x1b[0m" {
+		t.Fatalf("colour stream = %q, want coloured output", coloured.String())
+	}
+
+	var plain strings.Builder
+	if err := WriteOutput(cmd, &iostreams.IOStreams{Out: &plain}, nil, nil); err != nil {
+		t.Fatalf("WriteOutput: %v", err)
+	}
+	if plain.String() != "ok" {
+		t.Fatalf("plain stream = %q, want plain output", plain.String())
+	}
+}
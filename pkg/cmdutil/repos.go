@@ -0,0 +1,130 @@
+package cmdutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/avivsinai/bitbucket-cli/pkg/bbcloud"
+	"github.com/avivsinai/bitbucket-cli/pkg/bbdc"
+)
+
+// RepoLister returns the repository slugs of one Data Center project or
+// Cloud workspace.
+type RepoLister func(ctx context.Context, owner string) ([]string, error)
+
+// DCRepoLister lists the repository slugs of a Data Center project.
+func DCRepoLister(client *bbdc.Client) RepoLister {
+	return func(ctx context.Context, projectKey string) ([]string, error) {
+		repos, err := client.ListRepositories(ctx, projectKey, 0)
+		if err != nil {
+			return nil, err
+		}
+		slugs := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			slugs = append(slugs, repo.Slug)
+		}
+		return slugs, nil
+	}
+}
+
+// CloudRepoLister lists the repository slugs of a Cloud workspace.
+func CloudRepoLister(client *bbcloud.Client) RepoLister {
+	return func(ctx context.Context, workspace string) ([]string, error) {
+		repos, err := client.ListRepositories(ctx, workspace, 0)
+		if err != nil {
+			return nil, err
+		}
+		slugs := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			slugs = append(slugs, repo.Slug)
+		}
+		return slugs, nil
+	}
+}
+
+// ExpandRepoPatterns expands OWNER/GLOB patterns (a Data Center project key or
+// Cloud workspace, then a glob over repository slugs) into sorted,
+// de-duplicated OWNER/SLUG names. A pattern that matches nothing is an error.
+func ExpandRepoPatterns(ctx context.Context, patterns []string, list RepoLister) ([]string, error) {
+	seen := map[string]bool{}
+	var repos []string
+	for _, pattern := range patterns {
+		owner, glob, ok := strings.Cut(pattern, "/")
+		if !ok || owner == "" || glob == "" {
+			return nil, fmt.Errorf("invalid --repos %q; use PROJECT/GLOB or WORKSPACE/GLOB", pattern)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid --repos %q: %w", pattern, err)
+		}
+
+		listCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		slugs, err := list(listCtx, owner)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+
+		matched := 0
+		for _, slug := range slugs {
+			if ok, _ := path.Match(glob, slug); !ok {
+				continue
+			}
+			matched++
+			name := owner + "/" + slug
+			if !seen[name] {
+				seen[name] = true
+				repos = append(repos, name)
+			}
+		}
+		if matched == 0 {
+			return nil, fmt.Errorf("no repositories match %q", pattern)
+		}
+	}
+	sort.Strings(repos)
+	return repos, nil
+}
+
+// ReadPolicyFile reads the policy file of an apply command; "-" reads stdin.
+func ReadPolicyFile(file string, stdin io.Reader) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(file)
+}
+
+// WritePlan renders the plan of an apply command for one target: a "+" line
+// per change to create, a "-" line per change to delete, then a summary
+// ("N to create, N to delete" on a dry run, "N created, N deleted"
+// otherwise, "up to date", or the error that stopped the target).
+func WritePlan(w io.Writer, target string, create, remove []string, planErr string, dryRun bool) error {
+	for _, line := range create {
+		if _, err := fmt.Fprintf(w, "%s\t+ %s\n", target, line); err != nil {
+			return err
+		}
+	}
+	for _, line := range remove {
+		if _, err := fmt.Fprintf(w, "%s\t- %s\n", target, line); err != nil {
+			return err
+		}
+	}
+
+	var summary string
+	switch {
+	case planErr != "":
+		summary = "error: " + planErr
+	case len(create) == 0 && len(remove) == 0:
+		summary = "up to date"
+	case dryRun:
+		summary = fmt.Sprintf("%d to create, %d to delete", len(create), len(remove))
+	default:
+		summary = fmt.Sprintf("%d created, %d deleted", len(create), len(remove))
+	}
+	_, err := fmt.Fprintf(w, "%s: %s\n", target, summary)
+	return err
+}
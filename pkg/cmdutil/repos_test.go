@@ -0,0 +1,67 @@
+package cmdutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExpandRepoPatterns(t *testing.T) {
+	list := func(_ context.Context, owner string) ([]string, error) {
+		return []string{"api", "svc-a", "svc-b"}, nil
+	}
+	got, err := ExpandRepoPatterns(context.Background(), []string{"PROJ/svc-*", "PROJ/api", "PROJ/svc-a"}, list)
+	if err != nil {
+		t.Fatalf("ExpandRepoPatterns: %v", err)
+	}
+	if want := "PROJ/api,PROJ/svc-a,PROJ/svc-b"; strings.Join(got, ",") != want {
+		t.Fatalf("repos = %v, want %s", got, want)
+	}
+
+	if _, err := ExpandRepoPatterns(context.Background(), []string{"PROJ/web-*"}, list); err == nil || !strings.Contains(err.Error(), "no repositories match") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+}
+
+func TestReadPolicyFileFromStdin(t *testing.T) {
+	data, err := ReadPolicyFile("-", strings.NewReader("restrictions: []\n"))
+	if err != nil {
+		t.Fatalf("ReadPolicyFile: %v", err)
+	}
+	if string(data) != "restrictions: []\n" {
+		t.Fatalf("data = %q", data)
+	}
+}
+
+func TestWritePlan(t *testing.T) {
+	tests := []struct {
+		name    string
+		create  []string
+		remove  []string
+		planErr string
+		dryRun  bool
+		want    string
+	}{
+		{name: "up to date", want: "PROJ/api: up to date\n"},
+		{
+			name:   "dry run",
+			create: []string{"no-deletes on main"},
+			remove: []string{"#2 read-only on main"},
+			dryRun: true,
+			want:   "PROJ/api\t+ no-deletes on main\nPROJ/api\t- #2 read-only on main\nPROJ/api: 1 to create, 1 to delete\n",
+		},
+		{name: "applied", create: []string{"no-deletes on main"}, want: "PROJ/api\t+ no-deletes on main\nPROJ/api: 1 created, 0 deleted\n"},
+		{name: "error", planErr: "boom", want: "PROJ/api: error: boom\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			if err := WritePlan(&out, "PROJ/api", tt.create, tt.remove, tt.planErr, tt.dryRun); err != nil {
+				t.Fatalf("WritePlan: %v", err)
+			}
+			if out.String() != tt.want {
+				t.Fatalf("output:\n%q\nwant:\n%q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"text/template"
 
 	"github.com/itchyny/gojq"
 	"gopkg.in/yaml.v3"
@@ -17,6 +16,15 @@ type Options struct {
 	Format   string
 	JQ       string
 	Template string
+	// Fields selects and orders the columns of csv, tsv, and ndjson output.
+	// Nested values are addressed with dotted paths such as "author.name".
+	Fields []string
+
+	// Color enables ANSI styling from the template color helper.
+	Color bool
+	// Terminal reports that output goes to a terminal, which enables OSC 8
+	// links from the template hyperlink helper.
+	Terminal bool
 }
 
 // Write serializes data according to the chosen options. When no structured
@@ -41,11 +49,7 @@ func Write(w io.Writer, opts Options, data any, fallback func() error) error {
 	}
 
 	if opts.Template != "" {
-		tmpl, err := template.New("output").Parse(opts.Template)
-		if err != nil {
-			return fmt.Errorf("parse template: %w", err)
-		}
-		return tmpl.Execute(w, value)
+		return executeTemplate(w, opts, value)
 	}
 
 	switch opts.Format {
@@ -65,6 +69,10 @@ func Write(w io.Writer, opts Options, data any, fallback func() error) error {
 		}
 		_, err = w.Write(out)
 		return err
+	case "csv", "tsv":
+		return writeDelimited(w, opts, value)
+	case "ndjson":
+		return writeNDJSON(w, opts, value)
 	default:
 		return fmt.Errorf("unsupported format %q", opts.Format)
 	}
@@ -0,0 +1,326 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// now is replaced in tests so relative times are deterministic.
+var now = time.Now
+
+var ansiColors = map[string]string{
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+	"grey":      "90",
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+}
+
+// escapeSequence matches SGR colour codes and OSC 8 hyperlink wrappers so
+// table columns align on visible width.
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*m|\x1b\]8;;[^\x1b]*\x1b\\`)
+
+// TemplateFuncs returns the helper functions available to --template. Color
+// and hyperlink output follow opts.Color and opts.Terminal, so the same
+// template renders plain text when output is piped. Rows collected with
+// tablerow are printed, aligned, by tablerender.
+//
+//	timeago <time>              "3 hours ago"; accepts time.Time, RFC 3339
+//	                            strings, and epoch milliseconds (Data Center)
+//	truncate <length> <text>    shortens text to length runes, ending in "..."
+//	color <style> <text>        ANSI style such as "green" or "red+bold"
+//	tablerow <fields...>        queues a table row
+//	tablerender                 prints queued rows as aligned columns
+//	join <sep> <list>           joins list elements
+//	pluck <field> <list>        collects one field from each element
+//	hyperlink <url> <text>      OSC 8 terminal hyperlink
+func TemplateFuncs(opts Options) template.FuncMap {
+	return newTemplateFuncs(opts, &tableBuffer{})
+}
+
+func newTemplateFuncs(opts Options, table *tableBuffer) template.FuncMap {
+	return template.FuncMap{
+		"timeago":  timeAgo,
+		"truncate": truncate,
+		"color": func(style string, text any) (string, error) {
+			return colorize(opts.Color, style, fmt.Sprint(text))
+		},
+		"tablerow": func(fields ...any) string {
+			table.add(fields)
+			return ""
+		},
+		"tablerender": func() string {
+			return table.render()
+		},
+		"join":  join,
+		"pluck": pluck,
+		"hyperlink": func(url string, text any) string {
+			label := fmt.Sprint(text)
+			if !opts.Terminal || url == "" {
+				return label
+			}
+			return "\x1b]8;;" + url + "\x1b\\" + label + "\x1b]8;;\x1b\\"
+		},
+	}
+}
+
+func executeTemplate(w io.Writer, opts Options, value any) error {
+	table := &tableBuffer{}
+	tmpl, err := template.New("output").Funcs(newTemplateFuncs(opts, table)).Parse(opts.Template)
+	if err != nil {
+		return fmt.Errorf("parse template: %w", err)
+	}
+	if err := tmpl.Execute(w, value); err != nil {
+		return err
+	}
+	// Rows queued without a closing tablerender are still printed.
+	_, err = io.WriteString(w, table.render())
+	return err
+}
+
+func timeAgo(value any) (string, error) {
+	t, ok, err := toTime(value)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	delta := now().Sub(t)
+	future := delta < 0
+	if future {
+		delta = -delta
+	}
+
+	var phrase string
+	switch {
+	case delta < time.Minute:
+		return "just now", nil
+	case delta < time.Hour:
+		phrase = plural(int(delta/time.Minute), "minute")
+	case delta < 24*time.Hour:
+		phrase = plural(int(delta/time.Hour), "hour")
+	case delta < 30*24*time.Hour:
+		phrase = plural(int(delta/(24*time.Hour)), "day")
+	case delta < 365*24*time.Hour:
+		phrase = plural(int(delta/(30*24*time.Hour)), "month")
+	default:
+		phrase = plural(int(delta/(365*24*time.Hour)), "year")
+	}
+	if future {
+		return "in " + phrase, nil
+	}
+	return phrase + " ago", nil
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// toTime converts the timestamp shapes found in API payloads. Zero and
+// empty values report ok=false so templates print nothing for them.
+func toTime(value any) (time.Time, bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return v, !v.IsZero(), nil
+	case *time.Time:
+		if v == nil {
+			return time.Time{}, false, nil
+		}
+		return *v, !v.IsZero(), nil
+	case string:
+		if v == "" {
+			return time.Time{}, false, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("timeago: %q is not an RFC 3339 time", v)
+		}
+		return t, true, nil
+	case json.Number:
+		ms, err := v.Int64()
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("timeago: %q is not epoch milliseconds", v)
+		}
+		return time.UnixMilli(ms), ms != 0, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return time.UnixMilli(rv.Int()), rv.Int() != 0, nil
+	case reflect.Float64:
+		return time.UnixMilli(int64(rv.Float())), rv.Float() != 0, nil
+	}
+	return time.Time{}, false, fmt.Errorf("timeago: unsupported value of type %T", value)
+}
+
+func truncate(length int, value any) string {
+	text := fmt.Sprint(value)
+	if length <= 0 || utf8.RuneCountInString(text) <= length {
+		return text
+	}
+	runes := []rune(text)
+	if length <= 3 {
+		return string(runes[:length])
+	}
+	return string(runes[:length-3]) + "..."
+}
+
+func colorize(enabled bool, style, text string) (string, error) {
+	var codes []string
+	for _, name := range strings.FieldsFunc(strings.ToLower(style), func(r rune) bool { return r == '+' || r == ',' }) {
+		code, ok := ansiColors[name]
+		if !ok {
+			return "", fmt.Errorf("color: unknown style %q", name)
+		}
+		codes = append(codes, code)
+	}
+	if !enabled || len(codes) == 0 {
+		return text, nil
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + text + "\x1b[0m", nil
+}
+
+func join(sep string, list any) (string, error) {
+	items, err := listItems("join", list)
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// pluck reads field from every element of list. Elements may be maps or
+// structs; struct fields match by Go name or JSON name.
+func pluck(field string, list any) ([]any, error) {
+	items, err := listItems("pluck", list)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]any, 0, len(items))
+	for _, item := range items {
+		if v, ok := fieldValue(reflect.ValueOf(item), field); ok {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func listItems(fn string, list any) ([]any, error) {
+	if list == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(list)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%s: expected a list, got %T", fn, list)
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, nil
+}
+
+func fieldValue(v reflect.Value, field string) (any, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		entry := v.MapIndex(reflect.ValueOf(field).Convert(v.Type().Key()))
+		if !entry.IsValid() {
+			return nil, false
+		}
+		return entry.Interface(), true
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if sf.Name == field || name == field {
+				return v.Field(i).Interface(), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// tableBuffer accumulates tablerow output until tablerender flushes it.
+type tableBuffer struct {
+	rows [][]string
+}
+
+func (t *tableBuffer) add(fields []any) {
+	row := make([]string, len(fields))
+	for i, field := range fields {
+		row[i] = fmt.Sprint(field)
+	}
+	t.rows = append(t.rows, row)
+}
+
+func (t *tableBuffer) render() string {
+	if len(t.rows) == 0 {
+		return ""
+	}
+
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := visibleWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range t.rows {
+		for i, cell := range row {
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(cell)+2))
+			}
+		}
+		b.WriteByte('\n')
+	}
+	t.rows = nil
+	return b.String()
+}
+
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(escapeSequence.ReplaceAllString(s, ""))
+}
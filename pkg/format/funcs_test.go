@@ -0,0 +1,116 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func renderTemplate(t *testing.T, opts Options, tmpl string, data any) string {
+	t.Helper()
+	opts.Template = tmpl
+	buf := new(bytes.Buffer)
+	if err := Write(buf, opts, data, nil); err != nil {
+		t.Fatalf("Write(%q): %v", tmpl, err)
+	}
+	return buf.String()
+}
+
+func TestTemplateTimeago(t *testing.T) {
+	fixed := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	orig := now
+	now = func() time.Time { return fixed }
+	t.Cleanup(func() { now = orig })
+
+	tests := []struct {
+		value any
+		want  string
+	}{
+		{value: fixed.Add(-20 * time.Second), want: "just now"},
+		{value: fixed.Add(-time.Minute), want: "1 minute ago"},
+		{value: fixed.Add(-3 * time.Hour).Format(time.RFC3339), want: "3 hours ago"},
+		{value: fixed.Add(-48 * time.Hour).UnixMilli(), want: "2 days ago"},
+		{value: json.Number("1760788800000"), want: "1 year ago"},
+		{value: fixed.Add(2 * time.Hour), want: "in 2 hours"},
+		{value: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := renderTemplate(t, Options{}, "{{timeago .}}", tt.value); got != tt.want {
+			t.Fatalf("timeago(%v) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+
+	err := Write(new(bytes.Buffer), Options{Template: "{{timeago .}}"}, "yesterday", nil)
+	if err == nil || !strings.Contains(err.Error(), `timeago: "yesterday" is not an RFC 3339 time`) {
+		t.Fatalf("expected timeago parse error, got %v", err)
+	}
+}
+
+func TestTemplateTruncateJoinAndPluck(t *testing.T) {
+	data := struct {
+		Title string
+		Items []sample
+		Tags  []string
+	}{
+		Title: "Add extension manifests",
+		Items: []sample{{Name: "api", Count: 1}, {Name: "web", Count: 2}},
+		Tags:  []string{"a", "b"},
+	}
+
+	got := renderTemplate(t, Options{}, `{{truncate 12 .Title}}|{{join ", " (pluck "name" .Items)}}|{{join "+" (pluck "Count" .Items)}}|{{join "" .Tags}}`, data)
+	if want := "Add exten...|api, web|1+2|ab"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	got = renderTemplate(t, Options{}, `{{join "," (pluck "id" .)}}`, []map[string]any{{"id": 1}, {"other": 2}, {"id": 3}})
+	if got != "1,3" {
+		t.Fatalf("pluck over maps = %q, want 1,3", got)
+	}
+}
+
+func TestTemplateColorFollowsOptions(t *testing.T) {
+	if got := renderTemplate(t, Options{}, `{{color "green" "ok"}}`, nil); got != "ok" {
+		t.Fatalf("color without colour support = %q, want plain text", got)
+	}
+	if got := renderTemplate(t, Options{Color: true}, `{{color "red+bold" "fail"}}`, nil); got != "\x1b[31;1mfail\x1b[0m" {
+		t.Fatalf("color = %q", got)
+	}
+
+	err := Write(new(bytes.Buffer), Options{Template: `{{color "mauve" "x"}}`}, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), `color: unknown style "mauve"`) {
+		t.Fatalf("expected unknown style error, got %v", err)
+	}
+}
+
+func TestTemplateHyperlinkFollowsTerminal(t *testing.T) {
+	tmpl := `{{hyperlink "https://bitbucket.org/x" "PR 7"}}`
+	if got := renderTemplate(t, Options{}, tmpl, nil); got != "PR 7" {
+		t.Fatalf("hyperlink when piped = %q, want label", got)
+	}
+	want := "\x1b]8;;https://bitbucket.org/x\x1b\\PR 7\x1b]8;;\x1b\\"
+	if got := renderTemplate(t, Options{Terminal: true}, tmpl, nil); got != want {
+		t.Fatalf("hyperlink on terminal = %q, want %q", got, want)
+	}
+}
+
+func TestTemplateTableAlignsVisibleWidth(t *testing.T) {
+	data := []sample{{Name: "api", Count: 1}, {Name: "frontend", Count: 22}}
+	tmpl := `{{range .}}{{tablerow (color "green" .Name) .Count "x"}}{{end}}{{tablerender}}`
+
+	want := "api       1   x\nfrontend  22  x\n"
+	if got := renderTemplate(t, Options{}, tmpl, data); got != want {
+		t.Fatalf("table = %q, want %q", got, want)
+	}
+
+	colored := renderTemplate(t, Options{Color: true}, tmpl, data)
+	if plain := escapeSequence.ReplaceAllString(colored, ""); plain != want {
+		t.Fatalf("coloured table misaligned: %q", plain)
+	}
+
+	// Rows without a closing tablerender are flushed after execution.
+	if got := renderTemplate(t, Options{}, `{{range .}}{{tablerow .Name .Count}}{{end}}`, data); got != "api       1\nfrontend  22\n" {
+		t.Fatalf("implicit flush = %q", got)
+	}
+}
@@ -0,0 +1,342 @@
+package format
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// object is a JSON object that remembers key order, so columns follow the
+// field order of the Go structs commands emit.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func newObject() *object {
+	return &object{values: make(map[string]any)}
+}
+
+func (o *object) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON writes the object with its original key order.
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toOrdered round-trips value through encoding/json, keeping object key
+// order and number precision.
+func toOrdered(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("prepare output: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	out, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, fmt.Errorf("prepare output: %w", err)
+	}
+	return out, nil
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := newObject()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.set(key, v)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj, nil
+		case '[':
+			list := []any{}
+			for dec.More() {
+				v, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	default:
+		return tok, nil
+	}
+}
+
+// listRows picks the records to render. A top-level list is used as is; an
+// object wrapping exactly one list (the usual command payload, such as
+// {"project": ..., "repositories": [...]}) yields that list; any other
+// object is a single record.
+func listRows(value any) ([]any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return v, nil
+	case *object:
+		var lists []string
+		for _, key := range v.keys {
+			if _, ok := v.values[key].([]any); ok {
+				lists = append(lists, key)
+			}
+		}
+		switch len(lists) {
+		case 0:
+			return []any{v}, nil
+		case 1:
+			return v.values[lists[0]].([]any), nil
+		default:
+			return nil, fmt.Errorf("output contains several lists (%s); select one with --jq", strings.Join(lists, ", "))
+		}
+	default:
+		return []any{v}, nil
+	}
+}
+
+// flatten turns nested objects into dotted keys: {"author": {"name": "x"}}
+// becomes "author.name". Lists stay whole and render as JSON.
+func flatten(value any) *object {
+	out := newObject()
+	obj, ok := value.(*object)
+	if !ok {
+		out.set("value", value)
+		return out
+	}
+	var walk func(prefix string, o *object)
+	walk = func(prefix string, o *object) {
+		for _, key := range o.keys {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			if nested, ok := o.values[key].(*object); ok && len(nested.keys) > 0 {
+				walk(path, nested)
+				continue
+			}
+			out.set(path, o.values[key])
+		}
+	}
+	walk("", obj)
+	return out
+}
+
+// lookupField resolves a dotted path against a record. Paths that stop at a
+// nested object or list return that value whole.
+func lookupField(record any, flat *object, field string) (any, bool) {
+	if v, ok := flat.values[field]; ok {
+		return v, true
+	}
+	current := record
+	for _, part := range strings.Split(field, ".") {
+		obj, ok := current.(*object)
+		if !ok {
+			return nil, false
+		}
+		current, ok = obj.values[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// selectFields builds a flat record restricted to fields, in field order.
+func selectFields(record any, fields []string) *object {
+	flat := flatten(record)
+	if len(fields) == 0 {
+		return flat
+	}
+	out := newObject()
+	for _, field := range fields {
+		v, _ := lookupField(record, flat, field)
+		out.set(field, v)
+	}
+	return out
+}
+
+func cellText(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// writeDelimited renders records as CSV or TSV with a header row. Without
+// --fields the columns are every flattened key, in first-seen order.
+func writeDelimited(w io.Writer, opts Options, value any) error {
+	ordered, err := toOrdered(value)
+	if err != nil {
+		return err
+	}
+	rows, err := listRows(ordered)
+	if err != nil {
+		return err
+	}
+
+	records := make([]*object, len(rows))
+	columns := opts.Fields
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		records[i] = selectFields(row, opts.Fields)
+		if len(opts.Fields) == 0 {
+			for _, key := range records[i].keys {
+				if !seen[key] {
+					seen[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+	}
+
+	lines := make([][]string, 0, len(records)+1)
+	lines = append(lines, columns)
+	for _, record := range records {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			if line[i], err = cellText(record.values[column]); err != nil {
+				return err
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	if opts.Format == "tsv" {
+		for _, line := range lines {
+			for i, cell := range line {
+				line[i] = tsvEscaper.Replace(cell)
+			}
+			if _, err := io.WriteString(w, strings.Join(line, "\t")+"\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(lines); err != nil {
+		return fmt.Errorf("encode csv: %w", err)
+	}
+	return nil
+}
+
+// tsvEscaper keeps every record on one line; TSV has no quoting.
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func writeNDJSON(w io.Writer, opts Options, value any) error {
+	ordered, err := toOrdered(value)
+	if err != nil {
+		return err
+	}
+	rows, err := listRows(ordered)
+	if err != nil {
+		return err
+	}
+	stream := NewNDJSONStream(w, opts.Fields)
+	for _, row := range rows {
+		if err := stream.writeOrdered(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NDJSONStream writes one JSON record per line as items arrive, so list
+// commands can emit results page by page instead of buffering them.
+type NDJSONStream struct {
+	w      io.Writer
+	fields []string
+}
+
+// NewNDJSONStream returns a stream writing to w. With fields, each record is
+// reduced to those dotted paths.
+func NewNDJSONStream(w io.Writer, fields []string) *NDJSONStream {
+	return &NDJSONStream{w: w, fields: fields}
+}
+
+// Write encodes item as one line.
+func (s *NDJSONStream) Write(item any) error {
+	ordered, err := toOrdered(item)
+	if err != nil {
+		return err
+	}
+	return s.writeOrdered(ordered)
+}
+
+func (s *NDJSONStream) writeOrdered(record any) error {
+	var out any = record
+	if len(s.fields) > 0 {
+		out = selectFields(record, s.fields)
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return fmt.Errorf("encode ndjson: %w", err)
+	}
+	data = append(data, '\n')
+	_, err = s.w.Write(data)
+	return err
+}
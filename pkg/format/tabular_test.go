@@ -0,0 +1,120 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type tabularAuthor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type tabularPR struct {
+	ID     int           `json:"id"`
+	Title  string        `json:"title"`
+	Author tabularAuthor `json:"author"`
+	Labels []string      `json:"labels,omitempty"`
+}
+
+type tabularPayload struct {
+	Repo         string      `json:"repo"`
+	PullRequests []tabularPR `json:"pull_requests"`
+}
+
+func samplePayload() tabularPayload {
+	return tabularPayload{
+		Repo: "demo",
+		PullRequests: []tabularPR{
+			{ID: 1, Title: "Add, with comma", Author: tabularAuthor{Name: "Ada", Email: "ada@example.com"}},
+			{ID: 2, Title: "Fix\ttabs", Author: tabularAuthor{Name: "Linus"}, Labels: []string{"bug", "ui"}},
+		},
+	}
+}
+
+func TestWriteCSVFlattensNestedFieldsInStructOrder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := Write(buf, Options{Format: "csv"}, samplePayload(), nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	want := "id,title,author.name,author.email,labels\n" +
+		"1,\"Add, with comma\",Ada,ada@example.com,\n" +
+		"2,Fix\ttabs,Linus,,\"[\"\"bug\"\",\"\"ui\"\"]\"\n"
+	if buf.String() != want {
+		t.Fatalf("csv output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTSVSelectsFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	opts := Options{Format: "tsv", Fields: []string{"title", "author.name", "author", "missing"}}
+	if err := Write(buf, opts, samplePayload(), nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	want := "title\tauthor.name\tauthor\tmissing\n" +
+		"Add, with comma\tAda\t{\"name\":\"Ada\",\"email\":\"ada@example.com\"}\t\n" +
+		"Fix tabs\tLinus\t{\"name\":\"Linus\",\"email\":\"\"}\t\n"
+	if buf.String() != want {
+		t.Fatalf("tsv output:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestWriteNDJSONEmitsOneRecordPerLine(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := Write(buf, Options{Format: "ndjson", Fields: []string{"id", "author.name"}}, samplePayload(), nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	want := "{\"id\":1,\"author.name\":\"Ada\"}\n{\"id\":2,\"author.name\":\"Linus\"}\n"
+	if buf.String() != want {
+		t.Fatalf("ndjson output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTabularSingleObjectAndScalars(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := Write(buf, Options{Format: "csv"}, tabularAuthor{Name: "Ada"}, nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != "name,email\nAda,\n" {
+		t.Fatalf("single object csv = %q", got)
+	}
+
+	buf.Reset()
+	if err := Write(buf, Options{Format: "csv", JQ: "[.pull_requests[].id]"}, samplePayload(), nil); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != "value\n1\n2\n" {
+		t.Fatalf("scalar csv = %q", got)
+	}
+}
+
+func TestWriteTabularRejectsAmbiguousLists(t *testing.T) {
+	data := map[string]any{"a": []int{1}, "b": []int{2}}
+	err := Write(new(bytes.Buffer), Options{Format: "csv"}, data, nil)
+	if err == nil || !strings.Contains(err.Error(), "select one with --jq") {
+		t.Fatalf("expected ambiguous list error, got %v", err)
+	}
+}
+
+func TestNDJSONStreamWritesIncrementally(t *testing.T) {
+	buf := new(bytes.Buffer)
+	stream := NewNDJSONStream(buf, nil)
+
+	if err := stream.Write(tabularPR{ID: 7, Title: "first"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := buf.String(); got != "{\"id\":7,\"title\":\"first\",\"author\":{\"name\":\"\",\"email\":\"\"}}\n" {
+		t.Fatalf("first record = %q", got)
+	}
+
+	if err := stream.Write(tabularPR{ID: 8}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Fatalf("expected 2 lines, got %d", lines)
+	}
+}
@@ -356,8 +356,16 @@ func (c *Client) applyRequestHook(req *http.Request) {
 
 // Do executes the HTTP request and decodes the response into v when provided.
 func (c *Client) Do(req *http.Request, v any) error {
+	_, err := c.DoWithHeader(req, v)
+	return err
+}
+
+// DoWithHeader behaves like Do and additionally returns the headers of the
+// final successful response, for callers that need metadata such as
+// X-OAuth-Scopes that is not part of the body.
+func (c *Client) DoWithHeader(req *http.Request, v any) (http.Header, error) {
 	if req == nil {
-		return fmt.Errorf("request is nil")
+		return nil, fmt.Errorf("request is nil")
 	}
 
 	attempts := 0
@@ -365,7 +373,7 @@ func (c *Client) Do(req *http.Request, v any) error {
 	for {
 		attemptReq, err := cloneRequest(req)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if c.enableCache && attemptReq.Method == http.MethodGet {
@@ -384,18 +392,18 @@ func (c *Client) Do(req *http.Request, v any) error {
 				if c.debug {
 					fmt.Fprintf(os.Stderr, "<-- network error: %v\n", err)
 				}
-				return err
+				return nil, err
 			}
 			attempts++
 			continueRetry, waitErr := c.backoff(req.Context(), attempts, resp)
 			if waitErr != nil {
-				return waitErr
+				return nil, waitErr
 			}
 			if !continueRetry {
 				if c.debug {
 					fmt.Fprintf(os.Stderr, "<-- retry abort after error: %v\n", err)
 				}
-				return err
+				return nil, err
 			}
 			continue
 		}
@@ -403,7 +411,7 @@ func (c *Client) Do(req *http.Request, v any) error {
 		c.updateRateLimit(resp)
 		if err := c.applyAdaptiveThrottle(req.Context()); err != nil {
 			_ = resp.Body.Close()
-			return err
+			return nil, err
 		}
 
 		if c.debug {
@@ -413,9 +421,9 @@ func (c *Client) Do(req *http.Request, v any) error {
 		if resp.StatusCode == http.StatusNotModified && c.enableCache && attemptReq.Method == http.MethodGet {
 			_ = resp.Body.Close()
 			if err := c.applyCachedResponse(attemptReq, v); err != nil {
-				return err
+				return nil, err
 			}
-			return nil
+			return resp.Header, nil
 		}
 
 		if shouldRetryStatus(resp.StatusCode) {
@@ -426,18 +434,18 @@ func (c *Client) Do(req *http.Request, v any) error {
 				if len(bodyBytes) > 0 {
 					resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 				}
-				return decodeError(resp)
+				return nil, decodeError(resp)
 			}
 			attempts++
 			continueRetry, waitErr := c.backoff(req.Context(), attempts, resp)
 			if waitErr != nil {
-				return waitErr
+				return nil, waitErr
 			}
 			if !continueRetry {
 				if len(bodyBytes) > 0 {
 					resp.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 				}
-				return decodeError(resp)
+				return nil, decodeError(resp)
 			}
 			continue
 		}
@@ -445,7 +453,7 @@ func (c *Client) Do(req *http.Request, v any) error {
 		if resp.StatusCode == http.StatusUnauthorized && c.tokenRefresher != nil && !tokenRefreshed {
 			_ = resp.Body.Close()
 			if refreshErr := c.refreshCredentials(req.Context(), attemptReq.Header.Get("Authorization")); refreshErr != nil {
-				return fmt.Errorf("refresh token: %w", refreshErr)
+				return nil, &TokenRefreshError{Err: refreshErr}
 			}
 			c.applyAuth(req) // update auth header on original request for next clone
 			tokenRefreshed = true
@@ -456,7 +464,7 @@ func (c *Client) Do(req *http.Request, v any) error {
 			defer func() {
 				_ = resp.Body.Close()
 			}()
-			return decodeError(resp)
+			return nil, decodeError(resp)
 		}
 
 		if v == nil {
@@ -466,19 +474,19 @@ func (c *Client) Do(req *http.Request, v any) error {
 			if c.enableCache && attemptReq.Method == http.MethodGet {
 				c.storeCache(attemptReq, nil, resp.Header.Get("ETag"))
 			}
-			return nil
+			return resp.Header, nil
 		}
 
 		if writer, ok := v.(io.Writer); ok {
 			_, err := io.Copy(writer, resp.Body)
 			_ = resp.Body.Close()
-			return err
+			return resp.Header, err
 		}
 
 		bodyBytes, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		if c.enableCache && attemptReq.Method == http.MethodGet && resp.Header.Get("ETag") != "" {
@@ -486,13 +494,13 @@ func (c *Client) Do(req *http.Request, v any) error {
 		}
 
 		if len(bodyBytes) == 0 {
-			return nil
+			return resp.Header, nil
 		}
 
 		if err := json.Unmarshal(bodyBytes, v); err != nil {
-			return err
+			return nil, err
 		}
-		return nil
+		return resp.Header, nil
 	}
 }
 
@@ -625,6 +633,21 @@ func (e *HTTPError) Error() string {
 	return e.text
 }
 
+// TokenRefreshError reports that a 401 could not be recovered because the
+// TokenRefresher failed, so callers can classify it as an authentication
+// failure rather than a transport one.
+type TokenRefreshError struct {
+	Err error
+}
+
+func (e *TokenRefreshError) Error() string {
+	return "refresh token: " + e.Err.Error()
+}
+
+func (e *TokenRefreshError) Unwrap() error {
+	return e.Err
+}
+
 func newHTTPError(resp *http.Response, message ...string) *HTTPError {
 	text := resp.Status
 	if len(message) > 0 {
@@ -988,3 +1011,13 @@ func (c *Client) NewMultipartRequest(ctx context.Context, method, path string, f
 
 	return req, nil
 }
+
+// EscapePathSegments escapes each "/"-separated segment of a repository file
+// path for use in a URL path, keeping the separators intact.
+func EscapePathSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
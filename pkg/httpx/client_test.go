@@ -1433,9 +1433,13 @@ func TestTokenRefresherErrorPropagated(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error when refresh fails")
 	}
-	if !strings.Contains(err.Error(), "refresh failed") {
+	if !strings.Contains(err.Error(), "refresh token: refresh failed") {
 		t.Errorf("expected refresh error message, got %v", err)
 	}
+	var refreshErr *TokenRefreshError
+	if !errors.As(err, &refreshErr) {
+		t.Errorf("error = %T, want *TokenRefreshError", err)
+	}
 }
 
 func TestTokenRefresherNotCalledTwice(t *testing.T) {
@@ -1704,3 +1708,40 @@ func TestFollowerRetriesWhenLeaderContextCancelled(t *testing.T) {
 		t.Fatalf("TokenRefresher called %d times, want 2 (failed leader + follower replacement)", got)
 	}
 }
+
+func TestDoWithHeaderReturnsResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-OAuth-Scopes", "repository, pullrequest:write")
+		_ = json.NewEncoder(w).Encode(payload{Message: "hello"})
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := New(Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New client: %v", err)
+	}
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/user", nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+
+	var out payload
+	header, err := client.DoWithHeader(req, &out)
+	if err != nil {
+		t.Fatalf("DoWithHeader: %v", err)
+	}
+	if out.Message != "hello" {
+		t.Fatalf("expected hello, got %q", out.Message)
+	}
+	if got := header.Get("X-OAuth-Scopes"); got != "repository, pullrequest:write" {
+		t.Fatalf("X-OAuth-Scopes = %q", got)
+	}
+}
+
+func TestEscapePathSegments(t *testing.T) {
+	got := EscapePathSegments("docs/a b/100%.md")
+	if got != "docs/a%20b/100%25.md" {
+		t.Fatalf("EscapePathSegments = %q", got)
+	}
+}